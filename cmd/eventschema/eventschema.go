@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+)
+
+func main() {
+	dir := flag.String("dir", "docs/events", "Directory holding one <event type>.json schema per event type")
+	check := flag.Bool("check", false, "Fail instead of writing when a schema in the directory is missing or out of date")
+	flag.Parse()
+
+	if !*check {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			log.Fatalf("Failed to create %s: %v", *dir, err)
+		}
+	}
+
+	stale := 0
+	for _, eventType := range event.Types() {
+		schema, err := event.Schema(eventType)
+		if err != nil {
+			log.Fatalf("Failed to generate the %s schema: %v", eventType, err)
+		}
+
+		path := filepath.Join(*dir, eventType+".json")
+
+		if *check {
+			current, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(current, schema) {
+				fmt.Printf("%s is out of date\n", path)
+				stale++
+			}
+			continue
+		}
+
+		if err := os.WriteFile(path, schema, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	if stale > 0 {
+		fmt.Println("Run `make event-schemas` and review the changes: a field may only be added as optional without a new schema version")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/IskenT/money-transfer/internal/application"
+	"github.com/IskenT/money-transfer/internal/infra/iso20022"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only validate the message; valid transactions are reported as ACTC")
+	output := flag.String("o", "", "Write the pain.002 status report to this file instead of stdout")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 1 {
+		fmt.Println("Usage: pain001 [-dry-run] [-o report.xml] <pain.001 file | ->")
+		os.Exit(1)
+	}
+
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", args[0], err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	doc, err := iso20022.ParsePain001(in)
+	if err != nil {
+		log.Fatalf("Failed to parse %s: %v", args[0], err)
+	}
+
+	app := application.NewApplication()
+	defer app.DB().Close()
+
+	report, err := app.Services().PaymentInitiationService.Process(doc, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to process %s: %v", args[0], err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := report.WriteXML(out); err != nil {
+		log.Fatalf("Failed to write status report: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Message %s: %s, %d accepted, %d pending, %d rejected\n",
+		report.OriginalMessageID, report.Status,
+		report.Count(iso20022.StatusAccepted)+report.Count(iso20022.StatusAcceptedTechnical),
+		report.Count(iso20022.StatusPendingReview),
+		report.Count(iso20022.StatusRejected))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/config"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	repository "github.com/IskenT/money-transfer/internal/infra/repository/factory"
+)
+
+func main() {
+	limit := flag.Int("limit", 50, "Show at most this many mismatched rows; 0 shows all")
+	flag.Parse()
+
+	cfg := config.NewConfig()
+
+	db, err := database.NewDBWithRetry(database.NewDBConfig(cfg), 5, 3*time.Second)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Only the event store and projection repositories are needed, not the
+	// application with its providers and background processors
+	txManager := database.NewTransactionManager(db, database.RetryPolicy{
+		MaxAttempts: cfg.Transaction.MaxAttempts,
+		BaseDelay:   cfg.Transaction.RetryBaseDelay,
+		MaxDelay:    cfg.Transaction.RetryMaxDelay,
+	})
+	repoFactory := repository.NewFactory(txManager)
+
+	eventStore, pgEventStore := repoFactory.CreateEventStore()
+	projectionRepo, pgProjectionRepo := repoFactory.CreateProjectionRepository()
+	projectionService := service.NewProjectionService(
+		eventStore, projectionRepo, txManager, pgEventStore, pgProjectionRepo,
+	)
+
+	report, err := projectionService.Rebuild(*limit)
+	if err != nil {
+		log.Fatalf("Failed to rebuild projections: %v", err)
+	}
+
+	fmt.Printf("Replayed %d events into %d transfers and %d balances\n",
+		report.Events, report.Transfers, report.Balances)
+
+	if report.Consistent() {
+		fmt.Println("Projections match the live tables")
+		return
+	}
+
+	for _, m := range report.Mismatches {
+		fmt.Printf("%s %s\n", m.Table, m.Key)
+		fmt.Printf("  live:      %s\n", orMissing(m.Live))
+		fmt.Printf("  projected: %s\n", orMissing(m.Projected))
+	}
+	fmt.Printf("%d mismatched rows shown\n", len(report.Mismatches))
+	os.Exit(1)
+}
+
+// orMissing
+func orMissing(row string) string {
+	if row == "" {
+		return "(missing)"
+	}
+	return row
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 
 	_ "github.com/IskenT/money-transfer/docs"
-	"github.com/IskenT/money-transfer/internal/app/service/processor"
 	"github.com/IskenT/money-transfer/internal/application"
 )
 
@@ -17,12 +16,7 @@ import (
 func main() {
 	app := application.NewApplication()
 
-	outboxProcessor := processor.NewOutboxProcessor(app.DB())
-	outboxProcessor.Start()
-
 	defer func() {
-		outboxProcessor.Stop()
-
 		if err := app.Stop(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error stopping application: %v\n", err)
 		}
@@ -15,29 +15,46 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/transfers": {
+        "/api/admin/risk-reviews": {
             "get": {
-                "description": "Get a list of all transfers",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get transfers held by risk screening and how their reviews ended, oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transfers"
+                    "admin"
+                ],
+                "summary": "List risk reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PENDING, APPROVED, REJECTED or EXPIRED; all reviews when omitted",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of reviews, 50 by default and at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List all transfers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.RiskReviewResponse"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -45,9 +62,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/transfers/{id}/approve": {
             "post": {
-                "description": "Transfer money from one user to another",
+                "description": "Let a transfer held by risk screening go ahead. A balance transfer completes, an authorization becomes PENDING until captured and a card payment is sent to the acquirer.",
                 "consumes": [
                     "application/json"
                 ],
@@ -55,23 +74,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transfers"
+                    "admin"
                 ],
-                "summary": "Create a new money transfer",
+                "summary": "Approve a held transfer",
                 "parameters": [
                     {
-                        "description": "Transfer details",
-                        "name": "transfer",
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review decision",
+                        "name": "review",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferRequest"
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ReviewRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
                         }
@@ -82,12 +108,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -97,9 +141,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/transfers/{id}": {
-            "get": {
-                "description": "Get transfer details by ID",
+        "/api/admin/transfers/{id}/reject": {
+            "post": {
+                "description": "Cancel a transfer held by risk screening and release the sender's funds",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,9 +151,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transfers"
+                    "admin"
                 ],
-                "summary": "Get a specific transfer",
+                "summary": "Reject a held transfer",
                 "parameters": [
                     {
                         "type": "string",
@@ -117,6 +161,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Review decision",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ReviewRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -126,12 +179,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -141,9 +206,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users": {
+        "/api/admin/transfers/{id}/review": {
             "get": {
-                "description": "Get a list of all users",
+                "description": "Get why a transfer was held by risk screening and how the review ended",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a transfer's risk review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.RiskReviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/deposits": {
+            "post": {
+                "description": "Collect funds from a card or bank account through the funding provider. The user is credited when the provider confirms; a deposit the provider has not settled yet is returned as PENDING with status 202.",
                 "consumes": [
                     "application/json"
                 ],
@@ -151,16 +257,127 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "fundings"
                 ],
-                "summary": "List all users",
+                "summary": "Deposit funds",
+                "parameters": [
+                    {
+                        "description": "Deposit details",
+                        "name": "deposit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/deposits/{id}": {
+            "get": {
+                "description": "Get a deposit and its current state by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fundings"
+                ],
+                "summary": "Get a deposit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deposit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/fee-schedules": {
+            "get": {
+                "description": "Get every configured fee schedule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "List fee schedules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse"
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FeeScheduleResponse"
                             }
                         }
                     },
@@ -173,9 +390,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}": {
+        "/api/fx/quotes": {
+            "post": {
+                "description": "Lock an exchange rate between two currencies for a short time. Pass the quote ID to a transfer to make it cross-currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "Create an FX quote",
+                "parameters": [
+                    {
+                        "description": "Currency pair",
+                        "name": "quote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.QuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.QuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/fx/quotes/{id}": {
             "get": {
-                "description": "Get user details by ID",
+                "description": "Get quote details by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -183,13 +452,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "fx"
                 ],
-                "summary": "Get a specific user",
+                "summary": "Get an FX quote",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Quote ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -199,7 +468,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse"
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.QuoteResponse"
                         }
                     },
                     "404": {
@@ -216,15 +485,2922 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse": {
+        },
+        "/api/ledger/consistency": {
+            "get": {
+                "description": "Verify every journal sums to zero per currency and every cached balance equals the sum of its postings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Check ledger consistency",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.LedgerConsistencyResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment-initiations": {
+            "post": {
+                "description": "Validate an ISO 20022 pain.001 customer credit transfer initiation and create a transfer for each credit transfer. Accounts are user IDs given as Othr/Id. The response is a pain.002 status report with a status and reason code per transaction. Submitting the same message again does not pay twice. A message with more than PAIN001_MAX_TRANSACTIONS transactions is refused with 413.",
+                "consumes": [
+                    "text/xml"
+                ],
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "payment-initiations"
+                ],
+                "summary": "Submit a pain.001 payment initiation",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only validate; valid transactions are reported as ACTC",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "pain.002.001.10 status report",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/fee-revenue": {
+            "get": {
+                "description": "Sum the fees credited to the house revenue account per currency. The period defaults to the current month",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "Fee revenue report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the period, inclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, exclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FeeRevenueReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduled-transfers": {
+            "get": {
+                "description": "Get a list of all scheduled transfers, latest execution time first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-transfers"
+                ],
+                "summary": "List scheduled transfers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ScheduledTransferResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduled-transfers/{id}": {
+            "get": {
+                "description": "Get scheduled transfer details by ID, including the executed transfer or the failure reason",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-transfers"
+                ],
+                "summary": "Get a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ScheduledTransferResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduled-transfers/{id}/cancel": {
+            "post": {
+                "description": "Cancel a scheduled transfer that has not started executing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-transfers"
+                ],
+                "summary": "Cancel a scheduled transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ScheduledTransferResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/standing-orders": {
+            "get": {
+                "description": "Get a list of all standing orders, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-orders"
+                ],
+                "summary": "List standing orders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Repeat a transfer daily, weekly, monthly on a given day, or on the last business day of each month",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-orders"
+                ],
+                "summary": "Create a standing order",
+                "parameters": [
+                    {
+                        "description": "Standing order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/standing-orders/{id}": {
+            "get": {
+                "description": "Get standing order details by ID, including the transfers its runs made",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-orders"
+                ],
+                "summary": "Get a standing order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/standing-orders/{id}/pause": {
+            "post": {
+                "description": "Stop an active standing order from running until it is resumed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-orders"
+                ],
+                "summary": "Pause a standing order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/standing-orders/{id}/resume": {
+            "post": {
+                "description": "Restart a paused standing order from its next date on or after today",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-orders"
+                ],
+                "summary": "Resume a standing order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer-batches": {
+            "post": {
+                "description": "Submit many transfers in one call. In ATOMIC mode all items complete or none do; in BEST_EFFORT mode each item gets its own result. The batch runs in the background; poll it by ID for the per-item results.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer-batches"
+                ],
+                "summary": "Submit a transfer batch",
+                "parameters": [
+                    {
+                        "description": "Batch details",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfer-batches/{id}": {
+            "get": {
+                "description": "Get a transfer batch by ID with the status, transfer and error of each item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer-batches"
+                ],
+                "summary": "Get a transfer batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers": {
+            "get": {
+                "description": "Get one page of transfers, newest first by default. Pass next_cursor back as cursor, with the same filters, to get the next page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "List transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only transfers sent or received by this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "incoming or outgoing, relative to user_id",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated transfer states",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum amount in minor units",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum amount in minor units",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC3339 or YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC3339 or YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created_at (default) or amount",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (default) or asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, default 50, at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Transfer money from one user to another. With execute_at the transfer is scheduled instead and 202 is returned. With card_id the sender's card pays instead of their balance; a card payment the acquirer has not answered yet is returned as PENDING with status 202. A transfer over one of the sender's limits is rejected with 422 and a LimitExceededResponse naming the limit and when it resets. A transfer flagged by risk screening is returned as HELD with status 202 until it is reviewed, or declined with 403.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Create a new money transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the same request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Transfer details",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ScheduledTransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/authorize": {
+            "post": {
+                "description": "Place a hold on the sender's funds; the transfer stays PENDING until it is captured, voided or expires. A transfer flagged by risk screening is returned as HELD with status 202 and becomes PENDING once approved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Authorize a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the same request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Transfer details",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}": {
+            "get": {
+                "description": "Get transfer details by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Get a specific transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}/capture": {
+            "post": {
+                "description": "Complete a PENDING transfer, moving the held funds to the recipient",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Capture an authorized transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}/reverse": {
+            "post": {
+                "description": "Move funds back from the recipient to the sender, fully or partially, as a linked transfer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Reverse a completed transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reversal details",
+                        "name": "reversal",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ReversalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}/void": {
+            "post": {
+                "description": "Cancel a PENDING transfer and release the hold on the sender's funds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Void an authorized transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "description": "Get a list of all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Open an ACTIVE account with a zero balance in each requested currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a user",
+                "parameters": [
+                    {
+                        "description": "User details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "description": "Get user details by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a specific user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the name, tier or status of an account. Freezing blocks all transfers from and to the account.\nClosing requires a zero balance and no pending authorizations, deposits, withdrawals or card payments, or sweep_to to move the remaining funds to another user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/cards": {
+            "get": {
+                "description": "Get the cards registered to a user, with masked numbers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "List a user's cards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.CardResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Validate a card (Luhn check and expiry) and register it with the acquirer. Only the acquirer's token and the masked number are stored; registering the same card again returns the existing card.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "Register a card",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Card details",
+                        "name": "card",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.CardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.CardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/limits": {
+            "get": {
+                "description": "Get the limits on the user's outgoing transfers in a currency and what has been used of them in the current UTC hour, day and month. A maximum of zero means no limit.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user's transfer limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency, USD by default",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferLimitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/statement": {
+            "get": {
+                "description": "Get the opening balance, every debit and credit with the running balance, and the closing balance of one of the user's currencies. The period defaults to the current month",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get an account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO-4217 currency code, defaults to USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period, inclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, exclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StatementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/statement/export": {
+            "get": {
+                "description": "Render the statement as CSV, OFX 2.2 or ISO 20022 camt.053 for import into accounting tools. The format query parameter takes precedence over the Accept header; the default is CSV",
+                "produces": [
+                    "text/csv",
+                    "application/x-ofx",
+                    "application/xml"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Export an account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, ofx or camt053",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO-4217 currency code, defaults to USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the period, inclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, exclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks": {
+            "get": {
+                "description": "Get every webhook subscription, without secrets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a URL that events are POSTed to as a versioned JSON envelope. Each request carries X-Webhook-ID, X-Webhook-Timestamp and X-Webhook-Signature, \"v1=\" and the hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with the secret.\nThe secret is only returned here; one is generated when none is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Subscribe to events",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}": {
+            "get": {
+                "description": "Get a webhook subscription by ID, without its secret",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a subscription together with its deliveries and their attempt log",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the URL, event types or secret, or pause and resume deliveries. Deliveries of a paused subscription wait until it is resumed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get a subscription's deliveries newest first, each with the log of its attempts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "PENDING, DELIVERED or FAILED; all deliveries when omitted",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries, 50 by default and at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookDeliveryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Send a delivery again on the next dispatch with a fresh set of attempts, whether it was delivered, failed or is still pending. The delivery keeps its ID, so receivers that drop duplicates should still answer 2xx.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookDeliveryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/withdrawals": {
+            "post": {
+                "description": "Pay funds out to a card or bank account through the funding provider. The user is debited at once and the funds are returned if the payout fails; a withdrawal the provider has not settled yet is returned as PENDING with status 202. Withdrawals count towards the user's outgoing transfer limits.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fundings"
+                ],
+                "summary": "Withdraw funds",
+                "parameters": [
+                    {
+                        "description": "Withdrawal details",
+                        "name": "withdrawal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.LimitExceededResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/withdrawals/{id}": {
+            "get": {
+                "description": "Get a withdrawal and its current state by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fundings"
+                ],
+                "summary": "Get a withdrawal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Withdrawal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_IskenT_money-transfer_internal_infra_http_model.BalanceMismatchResponse": {
+            "type": "object",
+            "properties": {
+                "cached_balance": {
+                    "type": "integer",
+                    "example": 8000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "ledger_balance": {
+                    "type": "integer",
+                    "example": 7000
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "amount_formatted": {
+                    "type": "string",
+                    "example": "$100.00"
+                },
+                "available": {
+                    "type": "integer",
+                    "example": 8000
+                },
+                "available_formatted": {
+                    "type": "string",
+                    "example": "$80.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "held": {
+                    "type": "integer",
+                    "example": 2000
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.CardPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "auth_code": {
+                    "type": "string",
+                    "example": "3FA9C1"
+                },
+                "expiry": {
+                    "type": "string",
+                    "example": "12/29"
+                },
+                "holder_name": {
+                    "type": "string",
+                    "example": "MARK SMITH"
+                },
+                "masked_pan": {
+                    "type": "string",
+                    "example": "424242******4242"
+                },
+                "response_code": {
+                    "type": "string",
+                    "example": "00"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "MT000001"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.CardRequest": {
+            "type": "object",
+            "properties": {
+                "expiry_month": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "expiry_year": {
+                    "type": "integer",
+                    "example": 2029
+                },
+                "holder_name": {
+                    "type": "string",
+                    "example": "MARK SMITH"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "4242424242424242"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.CardResponse": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string",
+                    "example": "VISA"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "expiry": {
+                    "type": "string",
+                    "example": "12/29"
+                },
+                "holder_name": {
+                    "type": "string",
+                    "example": "MARK SMITH"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "CRD7"
+                },
+                "masked_pan": {
+                    "type": "string",
+                    "example": "424242******4242"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "USD",
+                        "EUR"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lucy"
+                },
+                "tier": {
+                    "type": "string",
+                    "example": "STANDARD"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transfer_completed",
+                        "deposit_*"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_3f9a1c0e7b2d4a6f8e0c1b3d5f7a9c2e"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/money-transfer"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "insufficient funds"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.FeeRevenueReportResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2023-04-01T00:00:00Z"
+                },
+                "revenue": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FeeRevenueResponse"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2023-05-01T00:00:00Z"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.FeeRevenueResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1250
+                },
+                "amount_formatted": {
+                    "type": "string",
+                    "example": "$12.50"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "transfers": {
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.FeeScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "account_tier": {
+                    "type": "string",
+                    "example": "PREMIUM"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "flat_amount": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "id": {
+                    "type": "string",
+                    "example": "FEE-USD-STANDARD"
+                },
+                "max_fee": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "min_fee": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "name": {
+                    "type": "string",
+                    "example": "USD standard"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "TRANSFER"
+                },
+                "percentage_bps": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.FeeTierResponse"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "PERCENTAGE"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.FeeTierResponse": {
+            "type": "object",
+            "properties": {
+                "flat_amount": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "percentage_bps": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "up_to": {
+                    "type": "integer",
+                    "example": 10000
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.FundingRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 5000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "external_account": {
+                    "type": "string",
+                    "example": "card-4242"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "CARD"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.FundingResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 5000
+                },
+                "amount_formatted": {
+                    "type": "string",
+                    "example": "$50.00"
+                },
+                "completed_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "external_account": {
+                    "type": "string",
+                    "example": "card-4242"
+                },
+                "failure_reason": {
+                    "type": "string",
+                    "example": "declined by the provider"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "FND42"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "CARD"
+                },
+                "provider_ref": {
+                    "type": "string",
+                    "example": "FAKE-FND42"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "COMPLETED"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransactionResponse"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "DEPOSIT"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.JournalImbalanceResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "journal_id": {
+                    "type": "string",
+                    "example": "JRN42"
+                },
+                "sum": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.LedgerConsistencyResponse": {
+            "type": "object",
+            "properties": {
+                "balance_mismatches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.BalanceMismatchResponse"
+                    }
+                },
+                "checked_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "consistent": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "journal_imbalances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.JournalImbalanceResponse"
+                    }
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.LimitExceededResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "transfer limit exceeded: MAX_DAILY_AMOUNT is 1000000 in USD, resets at 2023-04-11T00:00:00Z"
+                },
+                "limit": {
+                    "type": "string",
+                    "example": "MAX_DAILY_AMOUNT"
+                },
+                "max": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "resets_at": {
+                    "type": "string",
+                    "example": "2023-04-11T00:00:00Z"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.LimitUsageResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 2500
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "period": {
+                    "type": "string",
+                    "example": "DAY"
+                },
+                "period_start": {
+                    "type": "string",
+                    "example": "2023-04-10T00:00:00Z"
+                },
+                "resets_at": {
+                    "type": "string",
+                    "example": "2023-04-11T00:00:00Z"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.QuoteRequest": {
+            "type": "object",
+            "properties": {
+                "from_currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "to_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.QuoteResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:35:26Z"
+                },
+                "from_currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "QTE42"
+                },
+                "mid_rate": {
+                    "type": "string",
+                    "example": "0.92150000"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.91689250"
+                },
+                "spread_bps": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "to_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "used": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.ReversalRequest": {
             "type": "object",
             "properties": {
-                "error": {
+                "amount": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "force": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Customer refund"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.ReviewRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Confirmed with the customer by phone"
+                },
+                "reviewer": {
+                    "type": "string",
+                    "example": "jane.doe"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.RiskReviewResponse": {
+            "type": "object",
+            "properties": {
+                "authorize": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "RRV42"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Confirmed with the customer by phone"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "new_payee_large_amount: first transfer to this recipient is a large amount"
+                    ]
+                },
+                "reviewed_at": {
+                    "type": "string",
+                    "example": "2023-04-10T13:02:11Z"
+                },
+                "reviewer": {
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "PENDING"
+                },
+                "transfer_id": {
+                    "type": "string",
+                    "example": "TRF1647881234567"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.ScheduledTransferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "amount_formatted": {
+                    "type": "string",
+                    "example": "$10.00"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "execute_at": {
+                    "type": "string",
+                    "example": "2023-05-01T09:00:00Z"
+                },
+                "executed_at": {
+                    "type": "string",
+                    "example": "2023-05-01T09:00:04Z"
+                },
+                "failure_reason": {
+                    "type": "string",
+                    "example": "insufficient funds"
+                },
+                "from_user_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "SCH42"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "SCHEDULED"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "transfer_id": {
+                    "type": "string",
+                    "example": "TRF1647881234567"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "catch_up": {
+                    "type": "string",
+                    "example": "SKIP"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2023-12-31"
+                },
+                "frequency": {
+                    "type": "string",
+                    "example": "MONTHLY"
+                },
+                "from_user_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "max_occurrences": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2023-05-01"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "2"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.StandingOrderResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "amount_formatted": {
+                    "type": "string",
+                    "example": "$10.00"
+                },
+                "catch_up": {
+                    "type": "string",
+                    "example": "SKIP"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2023-12-31"
+                },
+                "frequency": {
+                    "type": "string",
+                    "example": "MONTHLY"
+                },
+                "from_user_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "held_transfer_id": {
+                    "type": "string",
+                    "example": "TRF1647881234567"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "STO42"
+                },
+                "last_failure_reason": {
                     "type": "string",
                     "example": "insufficient funds"
+                },
+                "last_run_at": {
+                    "type": "string",
+                    "example": "2023-07-15T00:00:04Z"
+                },
+                "max_occurrences": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "next_run_at": {
+                    "type": "string",
+                    "example": "2023-08-15T00:00:00Z"
+                },
+                "occurrences": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2023-05-01"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ACTIVE"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "transfer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TRF1647881234567"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.StatementLineResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "balance": {
+                    "type": "integer",
+                    "example": 8975
+                },
+                "balance_formatted": {
+                    "type": "string",
+                    "example": "$89.75"
+                },
+                "counterparty_id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Transfer to Jane"
+                },
+                "fee": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "net": {
+                    "type": "integer",
+                    "example": -1025
+                },
+                "net_formatted": {
+                    "type": "string",
+                    "example": "-$10.25"
+                },
+                "posted_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "reversal_of": {
+                    "type": "string",
+                    "example": "TRF1647881234000"
+                },
+                "stan": {
+                    "type": "string",
+                    "example": "TRX42"
+                },
+                "transfer_id": {
+                    "type": "string",
+                    "example": "TRF1647881234567"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "DEBIT"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.StatementResponse": {
+            "type": "object",
+            "properties": {
+                "closing_balance": {
+                    "type": "integer",
+                    "example": 8975
+                },
+                "closing_balance_formatted": {
+                    "type": "string",
+                    "example": "$89.75"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2023-04-01T00:00:00Z"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.StatementLineResponse"
+                    }
+                },
+                "opening_balance": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "opening_balance_formatted": {
+                    "type": "string",
+                    "example": "$100.00"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2023-05-01T00:00:00Z"
+                },
+                "total_credits": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total_debits": {
+                    "type": "integer",
+                    "example": 1025
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "user_name": {
+                    "type": "string",
+                    "example": "Mark"
                 }
             }
         },
@@ -239,10 +3415,29 @@ const docTemplate = `{
                     "type": "string",
                     "example": "$10.00"
                 },
+                "card": {
+                    "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.CardPaymentResponse"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-04-10T12:34:56Z"
                 },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "fee": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "fx_rate": {
+                    "type": "string",
+                    "example": "0.91689250"
+                },
+                "fx_spread_bps": {
+                    "type": "integer",
+                    "example": 50
+                },
                 "note": {
                     "type": "string",
                     "example": "Transfer to Jane"
@@ -269,6 +3464,184 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchItemRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "from_user_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "2"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchItemResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "amount_formatted": {
+                    "type": "string",
+                    "example": "$10.00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "insufficient funds"
+                },
+                "from_user_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "string",
+                    "example": "COMPLETED"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "transfer_id": {
+                    "type": "string",
+                    "example": "TRF1647881234567"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchItemRequest"
+                    }
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "ATOMIC"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:57Z"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "id": {
+                    "type": "string",
+                    "example": "BAT42"
+                },
+                "item_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferBatchItemResponse"
+                    }
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "ATOMIC"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "COMPLETED"
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:57Z"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.TransferLimitResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "limit_id": {
+                    "type": "string",
+                    "example": "LIM-USD-STANDARD"
+                },
+                "max_daily": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "max_monthly": {
+                    "type": "integer",
+                    "example": 5000000
+                },
+                "max_per_hour": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_single": {
+                    "type": "integer",
+                    "example": 500000
+                },
+                "usage": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.LimitUsageResponse"
+                    }
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.TransferListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJrIjoiMjAyMy0wNC0xMFQxMjozNDo1NloiLCJpZCI6NDJ9"
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransferResponse"
+                    }
+                }
+            }
+        },
         "github_com_IskenT_money-transfer_internal_infra_http_model.TransferRequest": {
             "type": "object",
             "properties": {
@@ -276,10 +3649,26 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1000
                 },
+                "card_id": {
+                    "type": "string",
+                    "example": "CRD7"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "execute_at": {
+                    "type": "string",
+                    "example": "2023-05-01T09:00:00Z"
+                },
                 "from_user_id": {
                     "type": "string",
                     "example": "1"
                 },
+                "quote_id": {
+                    "type": "string",
+                    "example": "QTE42"
+                },
                 "to_user_id": {
                     "type": "string",
                     "example": "2"
@@ -308,9 +3697,21 @@ const docTemplate = `{
                 "credit_tx": {
                     "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransactionResponse"
                 },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
                 "debit_tx": {
                     "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.TransactionResponse"
                 },
+                "fee": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "fee_formatted": {
+                    "type": "string",
+                    "example": "$0.50"
+                },
                 "from_user_id": {
                     "type": "string",
                     "example": "1"
@@ -319,6 +3720,31 @@ const docTemplate = `{
                     "type": "string",
                     "example": "TRF1647881234567"
                 },
+                "quote_id": {
+                    "type": "string",
+                    "example": "QTE42"
+                },
+                "reversal_of": {
+                    "type": "string",
+                    "example": "TRF1647881234567"
+                },
+                "reversals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "TRF1647881239999"
+                    ]
+                },
+                "reversed_amount": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "standing_order_id": {
+                    "type": "string",
+                    "example": "STO42"
+                },
                 "state": {
                     "type": "string",
                     "example": "COMPLETED"
@@ -329,16 +3755,61 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse": {
+        "github_com_IskenT_money-transfer_internal_infra_http_model.UpdateUserRequest": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "type": "integer",
-                    "example": 10000
+                "name": {
+                    "type": "string",
+                    "example": "Lucy"
                 },
-                "balance_formatted": {
+                "status": {
                     "type": "string",
-                    "example": "$100.00"
+                    "example": "FROZEN"
+                },
+                "sweep_to": {
+                    "type": "string",
+                    "example": "2"
+                },
+                "tier": {
+                    "type": "string",
+                    "example": "PREMIUM"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transfer_*"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_3f9a1c0e7b2d4a6f8e0c1b3d5f7a9c2e"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/money-transfer"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.UserResponse": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.BalanceResponse"
+                    }
                 },
                 "id": {
                     "type": "string",
@@ -347,6 +3818,133 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "example": "Mark"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ACTIVE"
+                },
+                "tier": {
+                    "type": "string",
+                    "example": "STANDARD"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.WebhookAttemptResponse": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:57Z"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 182
+                },
+                "error": {
+                    "type": "string",
+                    "example": "webhook answered 503 Service Unavailable"
+                },
+                "response_body": {
+                    "type": "string",
+                    "example": "upstream unavailable"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 503
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempt_log": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_IskenT_money-transfer_internal_infra_http_model.WebhookAttemptResponse"
+                    }
+                },
+                "attempts": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:36:57Z"
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1234
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "transfer_completed"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "WHD42"
+                },
+                "last_error": {
+                    "type": "string",
+                    "example": "webhook answered 503 Service Unavailable"
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:36:56Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "PENDING"
+                },
+                "webhook_id": {
+                    "type": "string",
+                    "example": "WHK42"
+                }
+            }
+        },
+        "github_com_IskenT_money-transfer_internal_infra_http_model.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transfer_completed",
+                        "deposit_*"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "WHK42"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_3f9a1c0e7b2d4a6f8e0c1b3d5f7a9c2e"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-04-10T12:34:56Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/money-transfer"
                 }
             }
         }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+// CardService registers users' cards with the acquirer
+type CardService struct {
+	cardRepo        repository.CardRepository
+	userRepo        repository.UserRepository
+	acquirer        repository.CardAcquirer
+	acquirerTimeout time.Duration
+}
+
+// NewCardService
+func NewCardService(
+	cardRepo repository.CardRepository,
+	userRepo repository.UserRepository,
+	acquirer repository.CardAcquirer,
+	acquirerTimeout time.Duration,
+) *CardService {
+	return &CardService{
+		cardRepo:        cardRepo,
+		userRepo:        userRepo,
+		acquirer:        acquirer,
+		acquirerTimeout: acquirerTimeout,
+	}
+}
+
+// RegisterCard validates the card and exchanges its number for an acquirer
+// token. Only the token and the masked number are stored.
+func (s *CardService) RegisterCard(userID string, details model.CardDetails) (*model.Card, error) {
+	details.Normalize()
+
+	now := time.Now()
+	if err := details.Validate(now); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.CheckActive(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.acquirerTimeout)
+	defer cancel()
+
+	token, err := s.acquirer.Tokenize(ctx, &details)
+	if err != nil {
+		return nil, err
+	}
+
+	card := &model.Card{
+		UserID:      user.ID,
+		Token:       token.Token,
+		MaskedPAN:   model.MaskPAN(details.Number),
+		Brand:       details.Brand(),
+		HolderName:  details.HolderName,
+		ExpiryMonth: details.ExpiryMonth,
+		ExpiryYear:  details.ExpiryYear,
+		CreatedAt:   now,
+	}
+
+	if err := s.cardRepo.Create(card); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// ListCards
+func (s *CardService) ListCards(userID string) ([]*model.Card, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	return s.cardRepo.ListByUser(userID)
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// TestAppendExpectedVersion races two appends at the same expected version
+// of a balance stream; exactly one of them may win
+func TestAppendExpectedVersion(t *testing.T) {
+	env := newTestEnv(t)
+	_, pgEventStore := env.factory.CreateEventStore()
+
+	// The user's stream holds the balance_opened event at version 1
+	user := env.createUser(t, map[string]int{"GBP": 0})
+
+	start := make(chan struct{})
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			errs[i] = env.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+				_, err := pgEventStore.AppendTx(ctx, tx, 1, &event.BalanceChanged{
+					UserID:    user.ID,
+					Currency:  "GBP",
+					ChangedAt: time.Now(),
+				})
+				return err
+			})
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		switch err {
+		case nil:
+		case model.ErrVersionConflict:
+			failed++
+		default:
+			t.Fatalf("AppendTx error = %v, want nil or %v", err, model.ErrVersionConflict)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("%d of 2 appends failed, want 1", failed)
+	}
+}
+
+// TestTransferStaleVersion changes a transfer loaded before another change
+// was recorded, which must fail instead of appending on top of it
+func TestTransferStaleVersion(t *testing.T) {
+	env := newTestEnv(t)
+	_, pgTransferRepo := env.factory.CreateTransferRepository()
+
+	a := env.createUser(t, map[string]int{"GBP": 10000})
+	b := env.createUser(t, map[string]int{"GBP": 0})
+
+	created, err := env.transferService.CreateTransfer(service.TransferParams{
+		FromUserID: a.ID, ToUserID: b.ID, Amount: 1000, Currency: "GBP", Authorize: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+
+	first, err := pgTransferRepo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	stale, err := pgTransferRepo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	update := func(transfer *model.Transfer) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return env.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return pgTransferRepo.UpdateStateTx(ctx, tx, transfer)
+		})
+	}
+
+	if err := update(first); err != nil {
+		t.Fatalf("UpdateStateTx: %v", err)
+	}
+	if first.Version != stale.Version+1 {
+		t.Errorf("version after UpdateStateTx = %d, want %d", first.Version, stale.Version+1)
+	}
+
+	if err := update(stale); err != model.ErrVersionConflict {
+		t.Errorf("UpdateStateTx of a stale transfer error = %v, want %v", err, model.ErrVersionConflict)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+// FeeService
+type FeeService struct {
+	feeScheduleRepo repository.FeeScheduleRepository
+	ledgerRepo      repository.LedgerRepository
+}
+
+// NewFeeService
+func NewFeeService(
+	feeScheduleRepo repository.FeeScheduleRepository,
+	ledgerRepo repository.LedgerRepository,
+) *FeeService {
+	return &FeeService{
+		feeScheduleRepo: feeScheduleRepo,
+		ledgerRepo:      ledgerRepo,
+	}
+}
+
+// ListSchedules
+func (s *FeeService) ListSchedules() ([]*model.FeeSchedule, error) {
+	return s.feeScheduleRepo.List()
+}
+
+// Revenue sums the fees credited to the house revenue account within [from, to)
+func (s *FeeService) Revenue(from, to time.Time) ([]*model.AccountTotal, error) {
+	if !from.Before(to) {
+		return nil, model.ErrInvalidPeriod
+	}
+
+	return s.ledgerRepo.AccountTotals(model.AccountFeeRevenue, from, to)
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/repository/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxExternalAccountLength matches the fundings.external_account column
+const maxExternalAccountLength = 100
+
+// FundingService runs deposits and withdrawals through a FundingProvider
+type FundingService struct {
+	fundingRepo         repository.FundingRepository
+	provider            repository.FundingProvider
+	txManager           *database.TransactionManager
+	pgUserRepo          *postgresql.UserRepository
+	pgTransferRepo      *postgresql.TransferRepository
+	pgFundingRepo       *postgresql.FundingRepository
+	pgLedgerRepo        *postgresql.LedgerRepository
+	transferLimitRepo   repository.TransferLimitRepository
+	pgTransferLimitRepo *postgresql.TransferLimitRepository
+	providerTimeout     time.Duration
+}
+
+// NewFundingService
+func NewFundingService(
+	fundingRepo repository.FundingRepository,
+	provider repository.FundingProvider,
+	txManager *database.TransactionManager,
+	pgUserRepo *postgresql.UserRepository,
+	pgTransferRepo *postgresql.TransferRepository,
+	pgFundingRepo *postgresql.FundingRepository,
+	pgLedgerRepo *postgresql.LedgerRepository,
+	transferLimitRepo repository.TransferLimitRepository,
+	pgTransferLimitRepo *postgresql.TransferLimitRepository,
+	providerTimeout time.Duration,
+) *FundingService {
+	return &FundingService{
+		fundingRepo:         fundingRepo,
+		provider:            provider,
+		txManager:           txManager,
+		pgUserRepo:          pgUserRepo,
+		pgTransferRepo:      pgTransferRepo,
+		pgFundingRepo:       pgFundingRepo,
+		pgLedgerRepo:        pgLedgerRepo,
+		transferLimitRepo:   transferLimitRepo,
+		pgTransferLimitRepo: pgTransferLimitRepo,
+		providerTimeout:     providerTimeout,
+	}
+}
+
+// FundingParams
+type FundingParams struct {
+	UserID   string
+	Amount   int
+	Currency string
+	Method   model.PaymentMethodType
+	// ExternalAccount is the card or bank account the provider collects
+	// from or pays out to
+	ExternalAccount string
+}
+
+// Deposit collects funds from an external account and credits them to the
+// user once the provider confirms
+func (s *FundingService) Deposit(params FundingParams) (*model.Funding, error) {
+	return s.initiate(model.FundingTypeDeposit, params)
+}
+
+// Withdraw debits the user at once and pays the funds out to an external
+// account; they are returned to the user if the payout fails
+func (s *FundingService) Withdraw(params FundingParams) (*model.Funding, error) {
+	return s.initiate(model.FundingTypeWithdrawal, params)
+}
+
+// GetFunding
+func (s *FundingService) GetFunding(id string) (*model.Funding, error) {
+	return s.fundingRepo.GetByID(id)
+}
+
+// initiate records the operation as PENDING and submits it to the provider
+func (s *FundingService) initiate(fundingType model.FundingType, params FundingParams) (*model.Funding, error) {
+	if params.Amount <= 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	switch params.Method {
+	case model.PaymentMethodTypeCard, model.PaymentMethodTypeBank:
+	default:
+		return nil, model.ErrInvalidPaymentMethod
+	}
+
+	params.ExternalAccount = strings.TrimSpace(params.ExternalAccount)
+	if params.ExternalAccount == "" || len(params.ExternalAccount) > maxExternalAccountLength {
+		return nil, model.ErrInvalidExternalAccount
+	}
+
+	if params.Currency == "" {
+		params.Currency = model.DefaultCurrency
+	}
+
+	currency, err := model.LookupCurrency(params.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var funding *model.Funding
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		user, err := s.pgUserRepo.GetForUpdate(ctx, tx, params.UserID)
+		if err != nil {
+			return err
+		}
+
+		if err := user.CheckActive(); err != nil {
+			return err
+		}
+
+		balance, ok := user.BalanceIn(currency.Code)
+		if !ok {
+			return model.ErrCurrencyNotHeld
+		}
+
+		if fundingType == model.FundingTypeWithdrawal && balance.Available() < params.Amount {
+			return model.ErrInsufficientFunds
+		}
+
+		now := time.Now()
+
+		// Money leaving the platform counts towards the outgoing transfer limits
+		if fundingType == model.FundingTypeWithdrawal {
+			if _, err := checkLimits(ctx, tx, s.transferLimitRepo, s.pgTransferLimitRepo, user, currency.Code, params.Amount, false, now); err != nil {
+				return err
+			}
+		}
+
+		txIDGen, err := s.pgTransferRepo.GetTransactionIDGenerator()
+		if err != nil {
+			return err
+		}
+
+		transaction := &model.Transaction{
+			Stan:            model.Stan(txIDGen()),
+			Amount:          params.Amount,
+			Currency:        currency.Code,
+			State:           model.TransactionStatePending,
+			TransactionType: model.TransactionTypeCredit,
+			PaymentSource:   params.Method,
+			Note:            fmt.Sprintf("Deposit from %s", params.Method),
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+
+		if fundingType == model.FundingTypeWithdrawal {
+			transaction.TransactionType = model.TransactionTypeDebit
+			transaction.Note = fmt.Sprintf("Withdrawal to %s", params.Method)
+		}
+
+		funding = &model.Funding{
+			Type:            fundingType,
+			UserID:          user.ID,
+			Amount:          params.Amount,
+			Currency:        currency.Code,
+			Method:          params.Method,
+			ExternalAccount: params.ExternalAccount,
+			State:           model.TransactionStatePending,
+			Transaction:     transaction,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+
+		if err := s.pgFundingRepo.CreateTx(ctx, tx, funding); err != nil {
+			return err
+		}
+
+		// A withdrawal leaves the user's balance straight away and waits in suspense
+		if fundingType == model.FundingTypeWithdrawal {
+			journal := fundingJournal(funding, fmt.Sprintf("Withdrawal %s", funding.ID), now)
+			journal.Post(model.UserAccount(funding.UserID), funding.Currency, -funding.Amount)
+			journal.Post(model.AccountSuspense, funding.Currency, funding.Amount)
+			return s.pgLedgerRepo.PostTx(ctx, tx, journal, map[string]*model.User{funding.UserID: user})
+		}
+
+		return nil
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submit(funding)
+}
+
+// submit sends the operation to the provider and applies its answer. If the
+// provider cannot be reached the operation stays PENDING and is submitted
+// again by ProcessPending; providers recognise the repeated request by its ID.
+func (s *FundingService) submit(funding *model.Funding) (*model.Funding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.providerTimeout)
+	defer cancel()
+
+	var result *model.FundingResult
+	var err error
+	if funding.Type == model.FundingTypeDeposit {
+		result, err = s.provider.Collect(ctx, funding.Request())
+	} else {
+		result, err = s.provider.Payout(ctx, funding.Request())
+	}
+
+	if err != nil {
+		log.Printf("Error submitting funding %s to the provider: %v", funding.ID, err)
+		return funding, nil
+	}
+
+	return s.applyResult(funding.ID, result)
+}
+
+// applyResult settles a PENDING operation with the provider's answer.
+// Operations that were settled in the meantime are returned unchanged.
+func (s *FundingService) applyResult(id string, result *model.FundingResult) (*model.Funding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var funding *model.Funding
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		funding, err = s.pgFundingRepo.GetForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if funding.State != model.TransactionStatePending {
+			return nil
+		}
+
+		now := time.Now()
+		funding.ProviderRef = result.Reference
+		funding.UpdatedAt = now
+
+		var journal *model.Journal
+
+		switch result.State {
+		case model.TransactionStatePending:
+		case model.TransactionStateCompleted:
+			if funding.Type == model.FundingTypeDeposit {
+				journal = fundingJournal(funding, fmt.Sprintf("Deposit %s", funding.ID), now)
+				journal.Post(model.AccountSettlement, funding.Currency, -funding.Amount)
+				journal.Post(model.UserAccount(funding.UserID), funding.Currency, funding.Amount)
+			} else {
+				journal = fundingJournal(funding, fmt.Sprintf("Payout of withdrawal %s", funding.ID), now)
+				journal.Post(model.AccountSuspense, funding.Currency, -funding.Amount)
+				journal.Post(model.AccountSettlement, funding.Currency, funding.Amount)
+			}
+		case model.TransactionStateFailed:
+			funding.FailureReason = result.Reason
+			if funding.Type == model.FundingTypeWithdrawal {
+				journal = fundingJournal(funding, fmt.Sprintf("Return of withdrawal %s", funding.ID), now)
+				journal.Post(model.AccountSuspense, funding.Currency, -funding.Amount)
+				journal.Post(model.UserAccount(funding.UserID), funding.Currency, funding.Amount)
+			}
+		default:
+			return fmt.Errorf("funding provider returned unknown state %q for %s", result.State, funding.ID)
+		}
+
+		if result.State != model.TransactionStatePending {
+			funding.State = result.State
+			funding.CompletedAt = now
+			funding.Transaction.State = result.State
+			funding.Transaction.UpdatedAt = now
+		}
+
+		if journal != nil {
+			// Balance changes take the user's lock first
+			user, err := s.pgUserRepo.GetForUpdate(ctx, tx, funding.UserID)
+			if err != nil {
+				return err
+			}
+			if err := s.pgLedgerRepo.PostTx(ctx, tx, journal, map[string]*model.User{funding.UserID: user}); err != nil {
+				return err
+			}
+		}
+
+		// Like a declined card payment, a failed payout gives its limit usage back
+		if funding.Type == model.FundingTypeWithdrawal && funding.State == model.TransactionStateFailed {
+			if err := s.pgTransferLimitRepo.ReleaseUsageTx(ctx, tx, funding.UserID, funding.Currency, funding.Amount, funding.CreatedAt); err != nil {
+				return err
+			}
+		}
+
+		return s.pgFundingRepo.UpdateTx(ctx, tx, funding)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return funding, nil
+}
+
+// ProcessPending resubmits operations the provider never acknowledged and asks
+// the provider about the ones it left pending. Operations updated within the
+// provider timeout may still be in flight and are left alone. It returns the
+// number of operations settled.
+func (s *FundingService) ProcessPending(limit int) (int, error) {
+	ids, err := s.fundingRepo.ListPending(time.Now().Add(-s.providerTimeout), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, id := range ids {
+		funding, err := s.fundingRepo.GetByID(id)
+		if err != nil {
+			return settled, err
+		}
+
+		if funding.ProviderRef == "" {
+			funding, err = s.submit(funding)
+		} else {
+			funding, err = s.checkStatus(funding)
+		}
+		if err != nil {
+			return settled, fmt.Errorf("error processing funding %s: %w", id, err)
+		}
+
+		if funding.State != model.TransactionStatePending {
+			settled++
+		}
+	}
+
+	return settled, nil
+}
+
+// checkStatus
+func (s *FundingService) checkStatus(funding *model.Funding) (*model.Funding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.providerTimeout)
+	defer cancel()
+
+	result, err := s.provider.Status(ctx, funding.ProviderRef)
+	if err != nil {
+		log.Printf("Error getting the status of funding %s from the provider: %v", funding.ID, err)
+		return funding, nil
+	}
+
+	return s.applyResult(funding.ID, result)
+}
+
+// fundingJournal
+func fundingJournal(funding *model.Funding, description string, at time.Time) *model.Journal {
+	return &model.Journal{
+		FundingID:   funding.ID,
+		Description: description,
+		CreatedAt:   at,
+	}
+}
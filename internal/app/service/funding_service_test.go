@@ -0,0 +1,214 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/infra/funding"
+)
+
+// TestFundingFlow runs deposits and withdrawals end to end against the fake
+// provider, whose outcome is picked by the external account
+func TestFundingFlow(t *testing.T) {
+	env := newTestEnv(t)
+	fundingService := env.newFundingService(funding.NewFakeProvider(0), time.Second)
+
+	tests := []struct {
+		name        string
+		fundingType model.FundingType
+		account     string
+		state       model.TransactionState
+		balance     int
+	}{
+		{"deposit", model.FundingTypeDeposit, "DE89370400440532013000", model.TransactionStateCompleted, 15000},
+		{"declined deposit", model.FundingTypeDeposit, "decline-4000", model.TransactionStateFailed, 10000},
+		{"withdrawal", model.FundingTypeWithdrawal, "DE89370400440532013000", model.TransactionStateCompleted, 5000},
+		// The user gets the funds back
+		{"declined withdrawal", model.FundingTypeWithdrawal, "decline-4000", model.TransactionStateFailed, 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := env.createUser(t, map[string]int{"GBP": 10000})
+			params := service.FundingParams{
+				UserID:          user.ID,
+				Amount:          5000,
+				Currency:        "GBP",
+				Method:          model.PaymentMethodTypeBank,
+				ExternalAccount: tt.account,
+			}
+
+			submit := fundingService.Deposit
+			if tt.fundingType == model.FundingTypeWithdrawal {
+				submit = fundingService.Withdraw
+			}
+
+			f, err := submit(params)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.fundingType, err)
+			}
+			if f.State != tt.state || f.Transaction.State != tt.state {
+				t.Errorf("funding %s is %s with transaction %s, want %s", f.ID, f.State, f.Transaction.State, tt.state)
+			}
+			if f.ProviderRef == "" {
+				t.Errorf("funding %s has no provider reference", f.ID)
+			}
+			if got := env.balance(t, user.ID, "GBP"); got != tt.balance {
+				t.Errorf("balance = %d, want %d", got, tt.balance)
+			}
+		})
+	}
+}
+
+// TestFundingPendingSettlement leaves a deposit and a withdrawal pending at
+// the provider and settles them with ProcessPending
+func TestFundingPendingSettlement(t *testing.T) {
+	env := newTestEnv(t)
+	providerTimeout := 50 * time.Millisecond
+	fundingService := env.newFundingService(funding.NewFakeProvider(0), providerTimeout)
+
+	user := env.createUser(t, map[string]int{"GBP": 10000})
+
+	deposit, err := fundingService.Deposit(service.FundingParams{
+		UserID: user.ID, Amount: 3000, Currency: "GBP",
+		Method: model.PaymentMethodTypeCard, ExternalAccount: "card-pending",
+	})
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+
+	withdrawal, err := fundingService.Withdraw(service.FundingParams{
+		UserID: user.ID, Amount: 2000, Currency: "GBP",
+		Method: model.PaymentMethodTypeBank, ExternalAccount: "bank-pending",
+	})
+	if err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+
+	for _, f := range []*model.Funding{deposit, withdrawal} {
+		if f.State != model.TransactionStatePending {
+			t.Fatalf("funding %s is %s, want PENDING", f.ID, f.State)
+		}
+	}
+
+	// Only the withdrawal has left the balance so far
+	if got := env.balance(t, user.ID, "GBP"); got != 8000 {
+		t.Errorf("balance while pending = %d, want 8000", got)
+	}
+
+	// Operations updated within the provider timeout are left alone
+	time.Sleep(2 * providerTimeout)
+	if _, err := fundingService.ProcessPending(1000); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+
+	for _, f := range []*model.Funding{deposit, withdrawal} {
+		settled, err := fundingService.GetFunding(f.ID)
+		if err != nil {
+			t.Fatalf("GetFunding %s: %v", f.ID, err)
+		}
+		if settled.State != model.TransactionStateCompleted {
+			t.Errorf("funding %s is %s after ProcessPending, want COMPLETED", f.ID, settled.State)
+		}
+	}
+
+	if got := env.balance(t, user.ID, "GBP"); got != 11000 {
+		t.Errorf("balance after settlement = %d, want 11000", got)
+	}
+}
+
+func TestFundingRejected(t *testing.T) {
+	env := newTestEnv(t)
+	fundingService := env.newFundingService(funding.NewFakeProvider(0), time.Second)
+
+	user := env.createUser(t, map[string]int{"GBP": 1000})
+
+	tests := []struct {
+		name     string
+		params   service.FundingParams
+		withdraw bool
+		wantErr  error
+	}{
+		{"no amount", service.FundingParams{UserID: user.ID, Currency: "GBP", Method: model.PaymentMethodTypeBank, ExternalAccount: "x"}, false, model.ErrInvalidAmount},
+		{"transfer method", service.FundingParams{UserID: user.ID, Amount: 100, Currency: "GBP", Method: model.PaymentMethodTypeTransfer, ExternalAccount: "x"}, false, model.ErrInvalidPaymentMethod},
+		{"no external account", service.FundingParams{UserID: user.ID, Amount: 100, Currency: "GBP", Method: model.PaymentMethodTypeBank, ExternalAccount: " "}, false, model.ErrInvalidExternalAccount},
+		{"currency not held", service.FundingParams{UserID: user.ID, Amount: 100, Currency: "EUR", Method: model.PaymentMethodTypeBank, ExternalAccount: "x"}, false, model.ErrCurrencyNotHeld},
+		{"insufficient funds", service.FundingParams{UserID: user.ID, Amount: 1001, Currency: "GBP", Method: model.PaymentMethodTypeBank, ExternalAccount: "x"}, true, model.ErrInsufficientFunds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submit := fundingService.Deposit
+			if tt.withdraw {
+				submit = fundingService.Withdraw
+			}
+			if _, err := submit(tt.params); err != tt.wantErr {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := env.balance(t, user.ID, "GBP"); got != 1000 {
+		t.Errorf("balance = %d, want 1000", got)
+	}
+}
+
+// TestFundingWithdrawalLimits checks that withdrawals use up the outgoing
+// limits that transfers count against, and that a failed payout gives its
+// amount back
+func TestFundingWithdrawalLimits(t *testing.T) {
+	env := newTestEnv(t)
+	fundingService := env.newFundingService(funding.NewFakeProvider(0), time.Second)
+
+	user := env.createUser(t, map[string]int{"GBP": 10000})
+	other := env.createUser(t, map[string]int{"GBP": 0})
+
+	if _, err := env.db.Exec(`
+		INSERT INTO money_transfer.transfer_limits (limit_code, currency, user_id, max_daily)
+		VALUES ($1, 'GBP', $2, 5000)
+	`, "LIM-TEST-"+user.ID, user.ID); err != nil {
+		t.Fatalf("error creating limit: %v", err)
+	}
+	t.Cleanup(func() {
+		env.db.Exec(`DELETE FROM money_transfer.transfer_limits WHERE limit_code = $1`, "LIM-TEST-"+user.ID)
+	})
+
+	withdraw := func(amount int, account string) (*model.Funding, error) {
+		return fundingService.Withdraw(service.FundingParams{
+			UserID: user.ID, Amount: amount, Currency: "GBP",
+			Method: model.PaymentMethodTypeBank, ExternalAccount: account,
+		})
+	}
+
+	if _, err := withdraw(3000, "DE89370400440532013000"); err != nil {
+		t.Fatalf("Withdraw within the limit: %v", err)
+	}
+
+	// A declined payout does not use up the limit
+	if f, err := withdraw(2000, "decline-4000"); err != nil || f.State != model.TransactionStateFailed {
+		t.Fatalf("declined Withdraw = %v, want a failed withdrawal", err)
+	}
+
+	var limitErr *model.LimitExceededError
+	if _, err := withdraw(2001, "DE89370400440532013000"); !errors.As(err, &limitErr) {
+		t.Fatalf("Withdraw over the daily limit error = %v, want a limit error", err)
+	}
+
+	// Transfers share the usage with withdrawals
+	if _, err := env.transferService.CreateTransfer(service.TransferParams{
+		FromUserID: user.ID, ToUserID: other.ID, Amount: 2001, Currency: "GBP",
+	}); !errors.As(err, &limitErr) {
+		t.Fatalf("transfer over the daily limit error = %v, want a limit error", err)
+	}
+
+	if _, err := withdraw(2000, "DE89370400440532013000"); err != nil {
+		t.Fatalf("Withdraw up to the limit: %v", err)
+	}
+
+	if got := env.balance(t, user.ID, "GBP"); got != 5000 {
+		t.Errorf("balance = %d, want 5000", got)
+	}
+}
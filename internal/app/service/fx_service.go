@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+// FXService
+type FXService struct {
+	rateProvider repository.FXRateProvider
+	quoteRepo    repository.FXQuoteRepository
+	quoteTTL     time.Duration
+	spreadBps    int
+}
+
+// NewFXService
+func NewFXService(
+	rateProvider repository.FXRateProvider,
+	quoteRepo repository.FXQuoteRepository,
+	quoteTTL time.Duration,
+	spreadBps int,
+) *FXService {
+	return &FXService{
+		rateProvider: rateProvider,
+		quoteRepo:    quoteRepo,
+		quoteTTL:     quoteTTL,
+		spreadBps:    spreadBps,
+	}
+}
+
+// CreateQuote locks the current rate between two currencies for the quote TTL
+func (s *FXService) CreateQuote(fromCurrency, toCurrency string) (*model.FXQuote, error) {
+	from, err := model.LookupCurrency(fromCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := model.LookupCurrency(toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.Code == to.Code {
+		return nil, model.ErrInvalidCurrencyPair
+	}
+
+	rate, err := s.rateProvider.GetRate(from.Code, to.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quote := &model.FXQuote{
+		FromCurrency: from.Code,
+		ToCurrency:   to.Code,
+		MidRate:      rate.Rate,
+		Rate:         model.ApplySpread(rate.Rate, s.spreadBps),
+		SpreadBps:    s.spreadBps,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.quoteTTL),
+	}
+
+	if err := s.quoteRepo.Create(quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// GetQuote
+func (s *FXService) GetQuote(id string) (*model.FXQuote, error) {
+	return s.quoteRepo.GetByID(id)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+// LedgerService
+type LedgerService struct {
+	ledgerRepo repository.LedgerRepository
+}
+
+// NewLedgerService
+func NewLedgerService(ledgerRepo repository.LedgerRepository) *LedgerService {
+	return &LedgerService{
+		ledgerRepo: ledgerRepo,
+	}
+}
+
+// CheckConsistency verifies every journal balances and every cached
+// balance equals the sum of the postings to its account
+func (s *LedgerService) CheckConsistency() (*model.LedgerConsistencyReport, error) {
+	imbalances, err := s.ledgerRepo.JournalImbalances()
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches, err := s.ledgerRepo.BalanceMismatches()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LedgerConsistencyReport{
+		CheckedAt:         time.Now(),
+		BalanceMismatches: mismatches,
+		JournalImbalances: imbalances,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/infra/funding"
+)
+
+// TestBalanceMismatches corrupts cached balances and checks the consistency
+// report lists them with their ledger balances
+func TestBalanceMismatches(t *testing.T) {
+	env := newTestEnv(t)
+	ledgerRepo, _ := env.factory.CreateLedgerRepository()
+	ledgerService := service.NewLedgerService(ledgerRepo)
+	fundingService := env.newFundingService(funding.NewFakeProvider(0), time.Second)
+
+	// Other tests may leave mismatches of their own behind; only the user's
+	// balances are looked at
+	mismatchesOf := func(userID string) map[string]*model.BalanceMismatch {
+		t.Helper()
+
+		report, err := ledgerService.CheckConsistency()
+		if err != nil {
+			t.Fatalf("CheckConsistency: %v", err)
+		}
+
+		mismatches := make(map[string]*model.BalanceMismatch)
+		for _, m := range report.BalanceMismatches {
+			if m.UserID == userID {
+				mismatches[m.Currency] = m
+			}
+		}
+		return mismatches
+	}
+
+	corrupt := func(userID, currency string, delta int) {
+		t.Helper()
+
+		_, err := env.db.Exec(`
+			UPDATE money_transfer.balances SET amount = amount + $3
+			WHERE user_id = $1 AND currency = $2
+		`, userID, currency, delta)
+		if err != nil {
+			t.Fatalf("error corrupting balance: %v", err)
+		}
+		t.Cleanup(func() {
+			env.db.Exec(`
+				UPDATE money_transfer.balances SET amount = amount - $3
+				WHERE user_id = $1 AND currency = $2
+			`, userID, currency, delta)
+		})
+	}
+
+	// The GBP balance is posted to the ledger by a deposit; EUR has no postings
+	user := env.createUser(t, map[string]int{"GBP": 0, "EUR": 0})
+	_, err := fundingService.Deposit(service.FundingParams{
+		UserID:          user.ID,
+		Amount:          5000,
+		Currency:        "GBP",
+		Method:          model.PaymentMethodTypeBank,
+		ExternalAccount: "DE89370400440532013000",
+	})
+	if err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+
+	if got := mismatchesOf(user.ID); len(got) != 0 {
+		t.Fatalf("mismatches before corrupting = %d, want 0", len(got))
+	}
+
+	corrupt(user.ID, "GBP", 1)
+	corrupt(user.ID, "EUR", 250)
+
+	got := mismatchesOf(user.ID)
+	want := map[string]model.BalanceMismatch{
+		"GBP": {UserID: user.ID, Currency: "GBP", CachedBalance: 5001, LedgerBalance: 5000},
+		"EUR": {UserID: user.ID, Currency: "EUR", CachedBalance: 250, LedgerBalance: 0},
+	}
+	if len(got) != len(want) {
+		t.Errorf("%d mismatches reported, want %d", len(got), len(want))
+	}
+	for currency, w := range want {
+		if m, ok := got[currency]; !ok || *m != w {
+			t.Errorf("%s mismatch = %+v, want %+v", currency, m, w)
+		}
+	}
+}
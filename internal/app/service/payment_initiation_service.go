@@ -0,0 +1,243 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/iso20022"
+)
+
+// paymentRejectionReasons maps transfer rejections to ISO 20022 status reason codes
+var paymentRejectionReasons = []struct {
+	err    error
+	reason string
+}{
+	{model.ErrInsufficientFunds, iso20022.ReasonInsufficientFunds},
+	{model.ErrUserNotFound, iso20022.ReasonIncorrectAccountNumber},
+	{model.ErrAccountClosed, iso20022.ReasonClosedAccountNumber},
+	{model.ErrAccountFrozen, iso20022.ReasonBlockedAccount},
+	{model.ErrSameAccount, iso20022.ReasonTransactionForbidden},
+	{model.ErrInvalidAmount, iso20022.ReasonInvalidAmount},
+	{model.ErrUnsupportedCurrency, iso20022.ReasonCurrencyNotAllowed},
+	{model.ErrCurrencyNotHeld, iso20022.ReasonCurrencyNotAllowed},
+	{model.ErrIdempotencyKeyMismatch, iso20022.ReasonDuplication},
+	{model.ErrPaymentAlreadyExecuted, iso20022.ReasonDuplication},
+	{model.ErrLimitExceeded, iso20022.ReasonNotAllowedAmount},
+	{model.ErrTransferDenied, iso20022.ReasonTransactionForbidden},
+	{model.ErrRejectedInReview, iso20022.ReasonTransactionForbidden},
+	{model.ErrReviewExpired, iso20022.ReasonTransactionForbidden},
+}
+
+// PaymentInitiationService executes pain.001 credit transfer initiations
+type PaymentInitiationService struct {
+	transferService       *TransferService
+	userRepo              repository.UserRepository
+	paymentInitiationRepo repository.PaymentInitiationRepository
+	maxTransactions       int
+}
+
+// NewPaymentInitiationService
+func NewPaymentInitiationService(
+	transferService *TransferService,
+	userRepo repository.UserRepository,
+	paymentInitiationRepo repository.PaymentInitiationRepository,
+	maxTransactions int,
+) *PaymentInitiationService {
+	return &PaymentInitiationService{
+		transferService:       transferService,
+		userRepo:              userRepo,
+		paymentInitiationRepo: paymentInitiationRepo,
+		maxTransactions:       maxTransactions,
+	}
+}
+
+// Process validates the message and creates one transfer per credit transfer.
+// The debtor and creditor accounts are user IDs given as proprietary account
+// identifiers. A message or payment information whose transaction count or
+// control sum does not match is rejected as a whole. Each transfer is keyed
+// by its position in the message, and every executed payment information is
+// recorded by message ID and payment information ID, so processing the same
+// file again never pays twice: it replays the earlier results while their
+// idempotency keys last and rejects the rest as duplicates. With dryRun
+// nothing is executed and valid transactions are reported as ACTC.
+//
+// The transfers are made one after the other while the caller waits, so a
+// message with more transactions than the service takes is refused with
+// ErrTooManyTransactions before any of them runs.
+func (s *PaymentInitiationService) Process(doc *iso20022.Pain001, dryRun bool) (*iso20022.StatusReport, error) {
+	amounts := doc.Amounts()
+	if len(amounts) > s.maxTransactions {
+		return nil, fmt.Errorf("%w: %d, at most %d are accepted", model.ErrTooManyTransactions, len(amounts), s.maxTransactions)
+	}
+
+	report := iso20022.NewStatusReport(doc, time.Now())
+	hdr := doc.Initiation.GroupHeader
+
+	if reason, info := iso20022.CheckTotals(hdr.NumberOfTransactions, hdr.ControlSum, amounts); reason != "" {
+		report.Reject(reason, info)
+		return report, nil
+	}
+
+	for i := range doc.Initiation.PaymentInfos {
+		s.processPaymentInfo(hdr.MessageID, &doc.Initiation.PaymentInfos[i], report.PaymentInfos[i], dryRun)
+	}
+
+	report.Settle()
+	return report, nil
+}
+
+// processPaymentInfo
+func (s *PaymentInitiationService) processPaymentInfo(messageID string, p *iso20022.PaymentInfo, status *iso20022.PaymentInfoStatus, dryRun bool) {
+	if reason, info := iso20022.CheckTotals(p.NumberOfTransactions, p.ControlSum, p.Amounts()); reason != "" {
+		status.Reject(reason, info)
+		return
+	}
+
+	debtorID := p.DebtorAccount.Other
+	if debtorID == "" {
+		status.Reject(iso20022.ReasonIncorrectAccountNumber, "debtor account must be identified by user ID")
+		return
+	}
+
+	if err := s.checkAccount(debtorID); err != nil {
+		reason, info := paymentRejection(err)
+		status.Reject(reason, info)
+		return
+	}
+
+	replay, err := s.claimPaymentInfo(messageID, p.PaymentInfoID, dryRun)
+	if err != nil {
+		reason, info := paymentRejection(err)
+		status.Reject(reason, info)
+		return
+	}
+
+	for j := range p.Transactions {
+		t := &p.Transactions[j]
+		txStatus := status.Transactions[j]
+
+		currency, err := model.LookupCurrency(t.Amount.Currency)
+		if err != nil {
+			txStatus.Reject(iso20022.ReasonCurrencyNotAllowed, err.Error())
+			continue
+		}
+
+		amount, err := iso20022.MinorUnits(t.Amount.Value, currency.Exponent)
+		if err != nil {
+			txStatus.Reject(iso20022.ReasonInvalidAmount, fmt.Sprintf("invalid %s amount %q", currency.Code, t.Amount.Value))
+			continue
+		}
+
+		creditorID := t.CreditorAccount.Other
+		if creditorID == "" {
+			txStatus.Reject(iso20022.ReasonIncorrectAccountNumber, "creditor account must be identified by user ID")
+			continue
+		}
+
+		params := TransferParams{
+			FromUserID: debtorID,
+			ToUserID:   creditorID,
+			Amount:     amount,
+			Currency:   currency.Code,
+			Bulk:       true,
+		}
+
+		if dryRun {
+			if err := s.checkAccount(creditorID); err != nil {
+				reason, info := paymentRejection(err)
+				txStatus.Reject(reason, info)
+				continue
+			}
+			txStatus.Status = iso20022.StatusAcceptedTechnical
+			continue
+		}
+
+		key := fmt.Sprintf("pain001:%s:%s:%d", messageID, p.PaymentInfoID, j)
+		var transfer *model.Transfer
+		if replay {
+			transfer, err = s.transferService.ReplayTransferIdempotent(key, paymentRequestHash(params))
+			// Without a key the transaction was rejected the first time or its key has expired
+			if errors.Is(err, model.ErrIdempotencyKeyNotFound) {
+				err = errDuplicatePaymentInfo(messageID, p.PaymentInfoID)
+			}
+		} else {
+			transfer, _, err = s.transferService.CreateTransferIdempotent(key, paymentRequestHash(params), params)
+		}
+		if err != nil {
+			reason, info := paymentRejection(err)
+			txStatus.Reject(reason, info)
+			continue
+		}
+
+		txStatus.TransferID = transfer.ID
+		if err := reviewFailure(transfer); err != nil {
+			reason, info := paymentRejection(err)
+			txStatus.Reject(reason, info)
+			continue
+		}
+		if transfer.State == model.TransactionStateHeld {
+			txStatus.Status = iso20022.StatusPendingReview
+			continue
+		}
+		txStatus.Status = iso20022.StatusAccepted
+	}
+}
+
+// claimPaymentInfo records the payment information before its transactions
+// are executed and reports whether it was already executed, in which case
+// they are only replayed. A dry run claims nothing.
+func (s *PaymentInitiationService) claimPaymentInfo(messageID, paymentInfoID string, dryRun bool) (bool, error) {
+	if dryRun {
+		exists, err := s.paymentInitiationRepo.Exists(messageID, paymentInfoID)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return false, errDuplicatePaymentInfo(messageID, paymentInfoID)
+		}
+		return false, nil
+	}
+
+	claimed, err := s.paymentInitiationRepo.Claim(messageID, paymentInfoID)
+	if err != nil {
+		return false, err
+	}
+	return !claimed, nil
+}
+
+// errDuplicatePaymentInfo
+func errDuplicatePaymentInfo(messageID, paymentInfoID string) error {
+	return fmt.Errorf("%w: payment information %q of message %q was already executed",
+		model.ErrPaymentAlreadyExecuted, paymentInfoID, messageID)
+}
+
+// checkAccount
+func (s *PaymentInitiationService) checkAccount(userID string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	return user.CheckActive()
+}
+
+// paymentRejection maps an error to a status reason code, falling back to a
+// narrative reason for errors that are not a known rejection
+func paymentRejection(err error) (string, string) {
+	for _, r := range paymentRejectionReasons {
+		if errors.Is(err, r.err) {
+			return r.reason, err.Error()
+		}
+	}
+	return iso20022.ReasonNarrative, err.Error()
+}
+
+// paymentRequestHash
+func paymentRequestHash(params TransferParams) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%s", params.FromUserID, params.ToUserID, params.Amount, params.Currency)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/infra/iso20022"
+)
+
+// pain001 builds a message with one payment information per creditor, each
+// paying 10.00 GBP from the debtor
+func pain001(t *testing.T, messageID, debtorID string, creditorIDs ...string) *iso20022.Pain001 {
+	t.Helper()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"><CstmrCdtTrfInitn>
+<GrpHdr><MsgId>%s</MsgId><NbOfTxs>%d</NbOfTxs></GrpHdr>`, messageID, len(creditorIDs))
+	for i, creditorID := range creditorIDs {
+		fmt.Fprintf(&b, `<PmtInf><PmtInfId>P%d</PmtInfId><NbOfTxs>1</NbOfTxs><CtrlSum>10.00</CtrlSum>
+<DbtrAcct><Id><Othr><Id>%s</Id></Othr></Id></DbtrAcct>
+<CdtTrfTxInf><Amt><InstdAmt Ccy="GBP">10.00</InstdAmt></Amt><CdtrAcct><Id><Othr><Id>%s</Id></Othr></Id></CdtrAcct></CdtTrfTxInf>
+</PmtInf>`, i, debtorID, creditorID)
+	}
+	b.WriteString(`</CstmrCdtTrfInitn></Document>`)
+
+	doc, err := iso20022.ParsePain001(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("ParsePain001: %v", err)
+	}
+	return doc
+}
+
+// TestPaymentInitiationResubmitted submits a message again after the
+// idempotency keys of its transfers have expired
+func TestPaymentInitiationResubmitted(t *testing.T) {
+	env := newTestEnv(t)
+	paymentInitiationService := service.NewPaymentInitiationService(
+		env.transferService, env.userRepo, env.factory.CreatePaymentInitiationRepository(), 100,
+	)
+
+	debtor := env.createUser(t, map[string]int{"GBP": 10000})
+	creditor := env.createUser(t, map[string]int{"GBP": 0})
+	messageID := "MSG-" + debtor.ID
+
+	process := func(doc *iso20022.Pain001, dryRun bool) *iso20022.StatusReport {
+		t.Helper()
+
+		report, err := paymentInitiationService.Process(doc, dryRun)
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		return report
+	}
+
+	statuses := func(report *iso20022.StatusReport) []string {
+		var got []string
+		for _, p := range report.PaymentInfos {
+			for _, tx := range p.Transactions {
+				got = append(got, tx.Status+" "+tx.Reason)
+			}
+		}
+		return got
+	}
+
+	// The first payment information pays, the second pays the debtor itself
+	first := process(pain001(t, messageID, debtor.ID, creditor.ID, debtor.ID), false)
+	if got := strings.Join(statuses(first), ","); got != "ACCP ,RJCT AG01" {
+		t.Fatalf("first submission = %s", got)
+	}
+
+	// While the keys last the results are replayed
+	replay := process(pain001(t, messageID, debtor.ID, creditor.ID, debtor.ID), false)
+	if got := strings.Join(statuses(replay), ","); got != "ACCP ,RJCT AM05" {
+		t.Errorf("replay = %s", got)
+	}
+
+	if _, err := env.db.Exec(`DELETE FROM money_transfer.idempotency_keys WHERE key LIKE $1`, "pain001:"+messageID+":%"); err != nil {
+		t.Fatalf("error expiring idempotency keys: %v", err)
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		report := process(pain001(t, messageID, debtor.ID, creditor.ID, debtor.ID), dryRun)
+		if got := strings.Join(statuses(report), ","); got != "RJCT AM05,RJCT AM05" {
+			t.Errorf("resubmission with dry run %t = %s", dryRun, got)
+		}
+	}
+
+	if got := env.balance(t, creditor.ID, "GBP"); got != 1000 {
+		t.Errorf("creditor balance = %d, want 1000", got)
+	}
+}
+
+// TestPaymentInitiationTooManyTransactions refuses a message with more
+// transactions than the service takes, before executing any of them
+func TestPaymentInitiationTooManyTransactions(t *testing.T) {
+	paymentInitiationService := service.NewPaymentInitiationService(nil, nil, nil, 2)
+
+	if _, err := paymentInitiationService.Process(pain001(t, "MSG-LARGE", "1", "2", "3", "4"), false); !errors.Is(err, model.ErrTooManyTransactions) {
+		t.Errorf("Process of 3 transactions error = %v, want %v", err, model.ErrTooManyTransactions)
+	}
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+)
+
+// HoldExpiryProcessor periodically expires authorized transfers that were never
+// captured and held transfers that were never reviewed
+type HoldExpiryProcessor struct {
+	transferService *service.TransferService
+	interval        time.Duration
+	batchSize       int
+	running         atomic.Bool
+	done            chan struct{}
+}
+
+// NewHoldExpiryProcessor
+func NewHoldExpiryProcessor(transferService *service.TransferService, interval time.Duration, batchSize int) *HoldExpiryProcessor {
+	return &HoldExpiryProcessor{
+		transferService: transferService,
+		interval:        interval,
+		batchSize:       batchSize,
+		done:            make(chan struct{}),
+	}
+}
+
+// Start
+func (p *HoldExpiryProcessor) Start() {
+	if !p.running.CompareAndSwap(false, true) {
+		return
+	}
+
+	go p.expireHolds()
+}
+
+// Stop
+func (p *HoldExpiryProcessor) Stop() {
+	if !p.running.CompareAndSwap(true, false) {
+		return
+	}
+
+	close(p.done)
+}
+
+// expireHolds
+func (p *HoldExpiryProcessor) expireHolds() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Reviews first: the holds of held transfers are theirs to release
+			reviews, err := p.transferService.ExpireReviews(p.batchSize)
+			if err != nil {
+				log.Printf("Error expiring risk reviews: %v", err)
+			}
+			if reviews > 0 {
+				log.Printf("Expired %d held transfers", reviews)
+			}
+
+			expired, err := p.transferService.ExpireHolds(p.batchSize)
+			if err != nil {
+				log.Printf("Error expiring holds: %v", err)
+			}
+			if expired > 0 {
+				log.Printf("Expired %d authorized transfers", expired)
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
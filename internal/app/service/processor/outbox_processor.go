@@ -2,14 +2,34 @@ package processor
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
 	"fmt"
 	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	domainEvent "github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
 	"github.com/jmoiron/sqlx"
 )
 
+// Outbox event statuses
+const (
+	OutboxStatusPending   = "PENDING"
+	OutboxStatusProcessed = "PROCESSED"
+	OutboxStatusDead      = "DEAD"
+)
+
+// outboxEventTimeout bounds the processing of one event, publishing included
+const outboxEventTimeout = 30 * time.Second
+
+// minOutboxLease leaves room to publish at least one event of a claimed batch
+const minOutboxLease = 2 * outboxEventTimeout
+
 // OutboxEvent
 type OutboxEvent struct {
 	ID            int64      `db:"id"`
@@ -19,127 +39,311 @@ type OutboxEvent struct {
 	Payload       []byte     `db:"payload"`
 	CreatedAt     time.Time  `db:"created_at"`
 	ProcessedAt   *time.Time `db:"processed_at"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
 }
 
-// OutboxProcessor
+// OutboxProcessor delivers outbox events at least once. Each worker claims a
+// batch of due events with FOR UPDATE SKIP LOCKED and leases them by moving
+// next_attempt_at past the lease, so several replicas can poll the same table.
+// Events are published after the claim is committed, and each result is
+// recorded in its own transaction; events of a worker that dies are claimed
+// again once their lease runs out. An event is processed once it has been
+// handed to every publisher routed for it. An event that fails is retried
+// with exponential backoff, and after retry.MaxAttempts failures it is left
+// DEAD for an operator to look at.
 type OutboxProcessor struct {
-	db      *sqlx.DB
-	running bool
-	done    chan struct{}
+	txManager *database.TransactionManager
+	publisher repository.Publisher
+	interval  time.Duration
+	batchSize int
+	workers   int
+	lease     time.Duration
+	retry     database.RetryPolicy
+	running   atomic.Bool
+	done      chan struct{}
+	wg        sync.WaitGroup
 }
 
 // NewOutboxProcessor
-func NewOutboxProcessor(db *sqlx.DB) *OutboxProcessor {
+func NewOutboxProcessor(txManager *database.TransactionManager, publisher repository.Publisher, interval time.Duration, batchSize, workers int, lease time.Duration, retry database.RetryPolicy) *OutboxProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	if lease < minOutboxLease {
+		lease = minOutboxLease
+	}
+
 	return &OutboxProcessor{
-		db:      db,
-		running: false,
-		done:    make(chan struct{}),
+		txManager: txManager,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		workers:   workers,
+		lease:     lease,
+		retry:     retry,
+		done:      make(chan struct{}),
 	}
 }
 
 // Start
 func (p *OutboxProcessor) Start() {
-	if p.running {
+	if !p.running.CompareAndSwap(false, true) {
 		return
 	}
 
-	p.running = true
-	go p.processEvents()
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.processEvents()
+	}
 }
 
-// Stop
+// Stop waits for the batches being processed to be recorded
 func (p *OutboxProcessor) Stop() {
-	if !p.running {
+	if !p.running.CompareAndSwap(true, false) {
 		return
 	}
 
-	p.running = false
 	close(p.done)
+	p.wg.Wait()
 }
 
 // processEvents
 func (p *OutboxProcessor) processEvents() {
-	ticker := time.NewTicker(5 * time.Second)
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			p.processUnprocessedEvents()
+			// A full batch means more events are probably due
+			for p.processBatch() == p.batchSize {
+				select {
+				case <-p.done:
+					return
+				default:
+				}
+			}
 		case <-p.done:
 			return
 		}
 	}
 }
 
-// processUnprocessedEvents
-func (p *OutboxProcessor) processUnprocessedEvents() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	var events []OutboxEvent
-	err := p.db.SelectContext(ctx, &events, `
-		SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at, processed_at
-		FROM money_transfer.outbox_events
-		WHERE processed_at IS NULL
-		ORDER BY created_at
-		LIMIT 100
-	`)
+// processBatch claims due events, publishes them and records the outcome of
+// each. It returns the number of events claimed.
+func (p *OutboxProcessor) processBatch() int {
+	ctx := context.Background()
 
+	leaseUntil := time.Now().Add(p.lease)
+	events, err := p.claim(ctx)
 	if err != nil {
-		log.Printf("Error getting unprocessed events: %v", err)
-		return
+		log.Printf("Error claiming outbox events: %v", err)
+		return 0
 	}
 
-	if len(events) == 0 {
-		return
+	for i, event := range events {
+		// An event published after its lease ran out may be claimed and
+		// published by another worker as well; hand the rest back instead
+		if time.Now().Add(outboxEventTimeout).After(leaseUntil) {
+			p.release(ctx, events[i:])
+			break
+		}
+
+		if err := p.deliver(ctx, event); err != nil {
+			if err := p.markFailed(ctx, event, err); err != nil {
+				log.Printf("Error recording outbox event %d: %v", event.ID, err)
+			}
+			continue
+		}
+
+		if err := p.markProcessed(ctx, event); err != nil {
+			// The event is published again once its lease runs out
+			log.Printf("Error recording outbox event %d: %v", event.ID, err)
+		}
 	}
 
-	for _, event := range events {
-		err := p.processEvent(ctx, event)
+	return len(events)
+}
+
+// claim locks due events and leases them to this worker
+func (p *OutboxProcessor) claim(ctx context.Context) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+
+	// READ COMMITTED: under REPEATABLE READ, locking an event another worker
+	// has just committed would fail to serialize instead of skipping it
+	err := p.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		events = nil
+		err := tx.SelectContext(ctx, &events, `
+			UPDATE money_transfer.outbox_events
+			SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT id
+				FROM money_transfer.outbox_events
+				WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, processed_at, status, attempts
+		`, p.batchSize, p.lease.Milliseconds())
 
 		if err != nil {
-			log.Printf("Error processing event %d: %v", event.ID, err)
-			continue
+			return fmt.Errorf("error claiming events: %w", err)
 		}
+		return nil
+	}, database.WithIsolation(sql.LevelReadCommitted))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+// release makes leased events due again without counting an attempt
+func (p *OutboxProcessor) release(ctx context.Context, events []OutboxEvent) {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
 
-		_, err = p.db.ExecContext(ctx, `
+	err := p.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `
 			UPDATE money_transfer.outbox_events
-			SET processed_at = NOW()
-			WHERE id = $1
+			SET next_attempt_at = NOW()
+			WHERE id = ANY($1) AND status = 'PENDING'
+		`, ids)
+		return err
+	}, database.WithIsolation(sql.LevelReadCommitted))
+
+	if err != nil {
+		log.Printf("Error releasing %d outbox events: %v", len(ids), err)
+	}
+}
+
+// markProcessed
+func (p *OutboxProcessor) markProcessed(ctx context.Context, event OutboxEvent) error {
+	return p.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE money_transfer.outbox_events
+			SET status = 'PROCESSED', processed_at = NOW(), attempts = attempts + 1, last_error = NULL
+			WHERE id = $1 AND status = 'PENDING'
 		`, event.ID)
 
 		if err != nil {
-			log.Printf("Error marking event %d as processed: %v", event.ID, err)
+			return fmt.Errorf("error marking event %d as processed: %w", event.ID, err)
 		}
+		return nil
+	}, database.WithIsolation(sql.LevelReadCommitted))
+}
+
+// deliver processes an event and hands it to its publishers
+func (p *OutboxProcessor) deliver(ctx context.Context, event OutboxEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, outboxEventTimeout)
+	defer cancel()
+
+	if err := p.processEvent(ctx, event); err != nil {
+		return err
 	}
+
+	return p.publisher.Publish(ctx, &model.Event{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		CreatedAt:     event.CreatedAt,
+	})
 }
 
-// processEvent
-func (p *OutboxProcessor) processEvent(ctx context.Context, event OutboxEvent) error {
-	var payload map[string]interface{}
-	if err := json.Unmarshal(event.Payload, &payload); err != nil {
-		return fmt.Errorf("error unmarshaling payload: %w", err)
+// markFailed schedules the next attempt of an event, or dead-letters it
+func (p *OutboxProcessor) markFailed(ctx context.Context, event OutboxEvent, cause error) error {
+	return p.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return p.markFailedTx(ctx, tx, event, cause)
+	}, database.WithIsolation(sql.LevelReadCommitted))
+}
+
+// markFailedTx
+func (p *OutboxProcessor) markFailedTx(ctx context.Context, tx *sqlx.Tx, event OutboxEvent, cause error) error {
+	attempts := event.Attempts + 1
+
+	if attempts >= p.retry.MaxAttempts {
+		log.Printf("Outbox event %d (%s) failed %d times, giving up: %v", event.ID, event.EventType, attempts, cause)
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE money_transfer.outbox_events
+			SET status = 'DEAD', attempts = $2, last_error = $3
+			WHERE id = $1 AND status = 'PENDING'
+		`, event.ID, attempts, cause.Error())
+
+		if err != nil {
+			return fmt.Errorf("error dead-lettering event %d: %w", event.ID, err)
+		}
+		return nil
 	}
 
-	// Process
-	switch event.EventType {
-	case "transfer_completed":
-		return p.processTransferCompletedEvent(ctx, event, payload)
-	default:
-		return fmt.Errorf("unknown event type: %s", event.EventType)
+	delay := outboxBackoff(p.retry, attempts)
+	log.Printf("Error processing outbox event %d (%s), attempt %d, retrying in %s: %v", event.ID, event.EventType, attempts, delay, cause)
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.outbox_events
+		SET attempts = $2, last_error = $3, next_attempt_at = NOW() + $4 * INTERVAL '1 millisecond'
+		WHERE id = $1 AND status = 'PENDING'
+	`, event.ID, attempts, cause.Error(), delay.Milliseconds())
+
+	if err != nil {
+		return fmt.Errorf("error rescheduling event %d: %w", event.ID, err)
+	}
+	return nil
+}
+
+// outboxBackoff returns BaseDelay*2^(attempts-1), capped at MaxDelay
+func outboxBackoff(retry database.RetryPolicy, attempts int) time.Duration {
+	delay := retry.BaseDelay << (attempts - 1)
+	if delay <= 0 || delay > retry.MaxDelay {
+		delay = retry.MaxDelay
 	}
+	return delay
 }
 
-// processTransferCompletedEvent
-func (p *OutboxProcessor) processTransferCompletedEvent(ctx context.Context, event OutboxEvent, payload map[string]interface{}) error {
+// processEvent decodes the payload into its typed event. A payload that does
+// not decode fails like any other processing error.
+func (p *OutboxProcessor) processEvent(ctx context.Context, event OutboxEvent) error {
+	decoded, err := domainEvent.Decode(event.EventType, event.Payload)
+	if err != nil {
+		return err
+	}
 
-	// For now, just log the event
-	log.Printf("Transfer completed: %s from user %v to user %v for amount %v",
-		payload["transfer_id"],
-		payload["from_user_id"],
-		payload["to_user_id"],
-		payload["amount"])
+	switch e := decoded.(type) {
+	case *domainEvent.TransferCompleted:
+		log.Printf("Transfer completed: %s from user %s to user %s for amount %d %s",
+			e.TransferID, e.FromUserID, e.ToUserID, e.Amount, e.Currency)
+	case *domainEvent.TransferReversed:
+		log.Printf("Transfer reversed: %s reverses %s, from user %s to user %s for amount %d %s",
+			e.TransferID, e.ReversalOf, e.FromUserID, e.ToUserID, e.Amount, e.Currency)
+	case *domainEvent.TransferReversalApplied:
+		log.Printf("Transfer reversal applied: %s, %d reversed so far, now %s",
+			e.TransferID, e.ReversedAmount, e.State)
+	case *domainEvent.TransferFailed:
+		log.Printf("Transfer failed: %s from user %s to user %s for amount %d %s, response code %q",
+			e.TransferID, e.FromUserID, e.ToUserID, e.Amount, e.Currency, e.ResponseCode)
+	case *domainEvent.BalanceChanged:
+		log.Printf("Balance changed: user %s %+d %s, now %d (%s)",
+			e.UserID, e.Delta, e.Currency, e.Balance, e.JournalID)
+	case *domainEvent.ScheduledTransferFailed:
+		log.Printf("Scheduled transfer failed: %s from user %s to user %s for amount %d %s: %s",
+			e.ScheduleID, e.FromUserID, e.ToUserID, e.Amount, e.Currency, e.FailureReason)
+	case *domainEvent.StandingOrderRunFailed:
+		log.Printf("Standing order run failed: %s on %s from user %s to user %s for amount %d %s: %s",
+			e.StandingOrderID, e.RunDate, e.FromUserID, e.ToUserID, e.Amount, e.Currency, e.FailureReason)
+	default:
+		log.Printf("Event %s: %s %s", event.EventType, decoded.AggregateType(), decoded.AggregateID())
+	}
 
 	return nil
 }
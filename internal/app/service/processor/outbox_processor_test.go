@@ -0,0 +1,179 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/jmoiron/sqlx"
+)
+
+// testDSNEnv names the database the integration tests run against, with
+// every migration applied
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+func TestOutboxBackoff(t *testing.T) {
+	retry := database.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		name     string
+		retry    database.RetryPolicy
+		attempts int
+		want     time.Duration
+	}{
+		{"first failure", retry, 1, time.Second},
+		{"second failure", retry, 2, 2 * time.Second},
+		{"doubled", retry, 6, 32 * time.Second},
+		{"capped", retry, 7, time.Minute},
+		{"long after the cap", retry, 30, time.Minute},
+		{"shift overflows to negative", retry, 35, time.Minute},
+		{"shift overflows to zero", retry, 65, time.Minute},
+		{"no base delay", database.RetryPolicy{MaxDelay: time.Minute}, 3, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outboxBackoff(tt.retry, tt.attempts); got != tt.want {
+				t.Errorf("outboxBackoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+// outboxRow is the delivery state markFailed leaves on an event
+type outboxRow struct {
+	Status    string  `db:"status"`
+	Attempts  int     `db:"attempts"`
+	LastError *string `db:"last_error"`
+	// Milliseconds until the next attempt
+	DueIn float64 `db:"due_in"`
+}
+
+// newTestProcessor connects to TEST_DATABASE_DSN, skipping the test when it
+// is not set. Nothing is polled; the tests call the processor directly.
+func newTestProcessor(t *testing.T, retry database.RetryPolicy) (*OutboxProcessor, *sqlx.DB) {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to %s: %v", testDSNEnv, err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	txManager := database.NewTransactionManager(db, database.RetryPolicy{MaxAttempts: 1})
+	return NewOutboxProcessor(txManager, nil, time.Second, 10, 1, 0, retry), db
+}
+
+// insertOutboxEvent adds a pending event that is not due for an hour, so a
+// running processor leaves it alone
+func insertOutboxEvent(t *testing.T, db *sqlx.DB) OutboxEvent {
+	t.Helper()
+
+	var event OutboxEvent
+	err := db.Get(&event, `
+		INSERT INTO money_transfer.outbox_events (aggregate_type, aggregate_id, event_type, payload, next_attempt_at)
+		VALUES ('test', $1, 'test_event', '{}', NOW() + INTERVAL '1 hour')
+		RETURNING id, aggregate_type, aggregate_id, event_type, payload, created_at, processed_at, status, attempts
+	`, t.Name())
+	if err != nil {
+		t.Fatalf("error inserting outbox event: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM money_transfer.outbox_events WHERE id = $1`, event.ID) })
+
+	return event
+}
+
+// outboxState loads the delivery state of an event
+func outboxState(t *testing.T, db *sqlx.DB, id int64) outboxRow {
+	t.Helper()
+
+	var row outboxRow
+	err := db.Get(&row, `
+		SELECT status, attempts, last_error, EXTRACT(EPOCH FROM next_attempt_at - NOW()) * 1000 AS due_in
+		FROM money_transfer.outbox_events
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		t.Fatalf("error loading outbox event %d: %v", id, err)
+	}
+	return row
+}
+
+// TestMarkFailed fails an event until it is dead-lettered, checking each
+// attempt is scheduled after its backoff
+func TestMarkFailed(t *testing.T) {
+	retry := database.RetryPolicy{MaxAttempts: 4, BaseDelay: time.Minute, MaxDelay: 3 * time.Minute}
+	p, db := newTestProcessor(t, retry)
+	ctx := context.Background()
+
+	event := insertOutboxEvent(t, db)
+	cause := errors.New("broker unavailable")
+
+	// Attempts 1 to 3 are retried after 1, 2 and 3 (capped) minutes
+	for attempts, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		event.Attempts = attempts
+		if err := p.markFailed(ctx, event, cause); err != nil {
+			t.Fatalf("markFailed: %v", err)
+		}
+
+		row := outboxState(t, db, event.ID)
+		if row.Status != OutboxStatusPending || row.Attempts != attempts+1 {
+			t.Fatalf("after attempt %d status = %s with %d attempts, want %s with %d", attempts+1, row.Status, row.Attempts, OutboxStatusPending, attempts+1)
+		}
+		if row.LastError == nil || *row.LastError != cause.Error() {
+			t.Errorf("after attempt %d last_error = %v, want %q", attempts+1, row.LastError, cause)
+		}
+		// Allow for the time between the update and the query
+		if dueIn := time.Duration(row.DueIn) * time.Millisecond; dueIn > want || dueIn < want-5*time.Second {
+			t.Errorf("after attempt %d next attempt in %s, want %s", attempts+1, dueIn, want)
+		}
+	}
+
+	// The last attempt dead-letters the event
+	event.Attempts = retry.MaxAttempts - 1
+	if err := p.markFailed(ctx, event, errors.New("broker still unavailable")); err != nil {
+		t.Fatalf("markFailed: %v", err)
+	}
+
+	row := outboxState(t, db, event.ID)
+	if row.Status != OutboxStatusDead || row.Attempts != retry.MaxAttempts {
+		t.Fatalf("after the last attempt status = %s with %d attempts, want %s with %d", row.Status, row.Attempts, OutboxStatusDead, retry.MaxAttempts)
+	}
+	if row.LastError == nil || *row.LastError != "broker still unavailable" {
+		t.Errorf("after the last attempt last_error = %v, want %q", row.LastError, "broker still unavailable")
+	}
+
+	// A dead event is left alone by a late result
+	if err := p.markFailed(ctx, event, cause); err != nil {
+		t.Fatalf("markFailed: %v", err)
+	}
+	if err := p.markProcessed(ctx, event); err != nil {
+		t.Fatalf("markProcessed: %v", err)
+	}
+	if row := outboxState(t, db, event.ID); row.Status != OutboxStatusDead || row.Attempts != retry.MaxAttempts {
+		t.Errorf("dead event changed to %s with %d attempts", row.Status, row.Attempts)
+	}
+}
+
+// TestMarkFailedSingleAttempt dead-letters on the first failure when only one
+// attempt is allowed
+func TestMarkFailedSingleAttempt(t *testing.T) {
+	p, db := newTestProcessor(t, database.RetryPolicy{MaxAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour})
+
+	event := insertOutboxEvent(t, db)
+	if err := p.markFailed(context.Background(), event, errors.New("bad payload")); err != nil {
+		t.Fatalf("markFailed: %v", err)
+	}
+
+	if row := outboxState(t, db, event.ID); row.Status != OutboxStatusDead || row.Attempts != 1 {
+		t.Errorf("status = %s with %d attempts, want %s with 1", row.Status, row.Attempts, OutboxStatusDead)
+	}
+}
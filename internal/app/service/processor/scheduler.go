@@ -0,0 +1,118 @@
+package processor
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+)
+
+// Scheduler periodically executes scheduled transfers and standing orders that
+// have come due, picks up transfer batches whose processing was interrupted,
+// settles deposits and withdrawals the funding provider left pending, and
+// sends card payments the acquirer did not answer again
+type Scheduler struct {
+	scheduledTransferService *service.ScheduledTransferService
+	standingOrderService     *service.StandingOrderService
+	transferBatchService     *service.TransferBatchService
+	fundingService           *service.FundingService
+	transferService          *service.TransferService
+	interval                 time.Duration
+	batchSize                int
+	running                  atomic.Bool
+	done                     chan struct{}
+}
+
+// NewScheduler
+func NewScheduler(
+	scheduledTransferService *service.ScheduledTransferService,
+	standingOrderService *service.StandingOrderService,
+	transferBatchService *service.TransferBatchService,
+	fundingService *service.FundingService,
+	transferService *service.TransferService,
+	interval time.Duration,
+	batchSize int,
+) *Scheduler {
+	return &Scheduler{
+		scheduledTransferService: scheduledTransferService,
+		standingOrderService:     standingOrderService,
+		transferBatchService:     transferBatchService,
+		fundingService:           fundingService,
+		transferService:          transferService,
+		interval:                 interval,
+		batchSize:                batchSize,
+		done:                     make(chan struct{}),
+	}
+}
+
+// Start
+func (s *Scheduler) Start() {
+	if !s.running.CompareAndSwap(false, true) {
+		return
+	}
+
+	go s.executeDue()
+}
+
+// Stop
+func (s *Scheduler) Stop() {
+	if !s.running.CompareAndSwap(true, false) {
+		return
+	}
+
+	close(s.done)
+}
+
+// executeDue
+func (s *Scheduler) executeDue() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			executed, err := s.scheduledTransferService.ExecuteDue(s.batchSize)
+			if err != nil {
+				log.Printf("Error executing scheduled transfers: %v", err)
+			}
+			if executed > 0 {
+				log.Printf("Executed %d scheduled transfers", executed)
+			}
+
+			ran, err := s.standingOrderService.RunDue(s.batchSize)
+			if err != nil {
+				log.Printf("Error running standing orders: %v", err)
+			}
+			if ran > 0 {
+				log.Printf("Ran %d standing orders", ran)
+			}
+
+			processed, err := s.transferBatchService.ProcessOpen(s.batchSize)
+			if err != nil {
+				log.Printf("Error processing transfer batches: %v", err)
+			}
+			if processed > 0 {
+				log.Printf("Processed %d transfer batches", processed)
+			}
+
+			settled, err := s.fundingService.ProcessPending(s.batchSize)
+			if err != nil {
+				log.Printf("Error settling pending deposits and withdrawals: %v", err)
+			}
+			if settled > 0 {
+				log.Printf("Settled %d deposits and withdrawals", settled)
+			}
+
+			settled, err = s.transferService.ProcessPendingCardTransfers(s.batchSize)
+			if err != nil {
+				log.Printf("Error settling pending card transfers: %v", err)
+			}
+			if settled > 0 {
+				log.Printf("Settled %d card transfers", settled)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+)
+
+// WebhookDispatcher periodically sends due webhook deliveries
+type WebhookDispatcher struct {
+	webhookService *service.WebhookService
+	interval       time.Duration
+	batchSize      int
+	running        atomic.Bool
+	done           chan struct{}
+}
+
+// NewWebhookDispatcher
+func NewWebhookDispatcher(webhookService *service.WebhookService, interval time.Duration, batchSize int) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookService: webhookService,
+		interval:       interval,
+		batchSize:      batchSize,
+		done:           make(chan struct{}),
+	}
+}
+
+// Start
+func (d *WebhookDispatcher) Start() {
+	if !d.running.CompareAndSwap(false, true) {
+		return
+	}
+
+	go d.dispatch()
+}
+
+// Stop
+func (d *WebhookDispatcher) Stop() {
+	if !d.running.CompareAndSwap(true, false) {
+		return
+	}
+
+	close(d.done)
+}
+
+// dispatch sends batches until none is full, then waits for the next tick
+func (d *WebhookDispatcher) dispatch() {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for {
+				sent, err := d.webhookService.DeliverDue(d.batchSize)
+				if err != nil {
+					log.Printf("Error dispatching webhooks: %v", err)
+					break
+				}
+				if sent < d.batchSize {
+					break
+				}
+
+				select {
+				case <-d.done:
+					return
+				default:
+				}
+			}
+		case <-d.done:
+			return
+		}
+	}
+}
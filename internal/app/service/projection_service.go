@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/repository/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+// projectionReadBatch
+const projectionReadBatch = 1000
+
+// ProjectionService rebuilds the transfers and balances read models from the event store
+type ProjectionService struct {
+	eventStore       repository.EventStore
+	projectionRepo   repository.ProjectionRepository
+	txManager        *database.TransactionManager
+	pgEventStore     *postgresql.EventStoreRepository
+	pgProjectionRepo *postgresql.ProjectionRepository
+}
+
+// NewProjectionService
+func NewProjectionService(
+	eventStore repository.EventStore,
+	projectionRepo repository.ProjectionRepository,
+	txManager *database.TransactionManager,
+	pgEventStore *postgresql.EventStoreRepository,
+	pgProjectionRepo *postgresql.ProjectionRepository,
+) *ProjectionService {
+	return &ProjectionService{
+		eventStore:       eventStore,
+		projectionRepo:   projectionRepo,
+		txManager:        txManager,
+		pgEventStore:     pgEventStore,
+		pgProjectionRepo: pgProjectionRepo,
+	}
+}
+
+// Rebuild replays the whole event store and compares the result with the
+// live tables, then writes it to the projection tables. The pages are read
+// and compared in one read-only REPEATABLE READ transaction, so the events
+// and the live tables come from the same snapshot and transfers committed
+// meanwhile are not reported as mismatches. At most limit mismatches are
+// reported; 0 reports all of them.
+func (s *ProjectionService) Rebuild(limit int) (*model.ProjectionReport, error) {
+	var (
+		p          *projection
+		transfers  []*model.ProjectedTransfer
+		balances   []*model.ProjectedBalance
+		mismatches []*model.ProjectionMismatch
+	)
+
+	err := s.txManager.WithTransaction(context.Background(), func(ctx context.Context, tx *sqlx.Tx) error {
+		p = newProjection()
+
+		var after int64
+		for {
+			events, err := s.pgEventStore.ReadAllTx(ctx, tx, after, projectionReadBatch)
+			if err != nil {
+				return err
+			}
+
+			for _, stored := range events {
+				if err := p.apply(stored); err != nil {
+					return err
+				}
+				after = stored.Position
+			}
+
+			if len(events) < projectionReadBatch {
+				break
+			}
+		}
+
+		transfers, balances = p.rows()
+
+		var err error
+		mismatches, err = s.pgProjectionRepo.MismatchesTx(ctx, tx, transfers, balances, limit)
+		return err
+	}, database.WithReadOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projectionRepo.Replace(transfers, balances); err != nil {
+		return nil, err
+	}
+
+	return &model.ProjectionReport{
+		RebuiltAt:  time.Now(),
+		Events:     p.events,
+		Transfers:  len(transfers),
+		Balances:   len(balances),
+		Mismatches: mismatches,
+	}, nil
+}
+
+// LoadTransfer rebuilds one transfer from its own stream
+func (s *ProjectionService) LoadTransfer(id string) (*model.ProjectedTransfer, error) {
+	events, err := s.eventStore.Load(event.Transfer{}.AggregateType(), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, model.ErrTransferNotFound
+	}
+
+	p := newProjection()
+	for _, stored := range events {
+		if err := p.apply(stored); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.transfers[id], nil
+}
+
+// balanceKey
+type balanceKey struct {
+	userID   string
+	currency string
+}
+
+// projection folds events into transfers and balances. Every transfer event
+// carries the whole transfer, so the latest one wins; balances are the
+// opening balance plus the deltas of every change since.
+type projection struct {
+	events    int
+	versions  map[string]int
+	transfers map[string]*model.ProjectedTransfer
+	balances  map[balanceKey]*model.ProjectedBalance
+}
+
+// newProjection
+func newProjection() *projection {
+	return &projection{
+		versions:  make(map[string]int),
+		transfers: make(map[string]*model.ProjectedTransfer),
+		balances:  make(map[balanceKey]*model.ProjectedBalance),
+	}
+}
+
+// apply checks that the event follows the previous one of its stream and folds it in
+func (p *projection) apply(stored *model.StoredEvent) error {
+	stream := stored.AggregateType + "/" + stored.AggregateID
+	if stored.Version != p.versions[stream]+1 {
+		return fmt.Errorf("event %d: stream %s jumps from version %d to %d",
+			stored.Position, stream, p.versions[stream], stored.Version)
+	}
+	p.versions[stream] = stored.Version
+	p.events++
+
+	decoded, err := event.Decode(stored.EventType, stored.Payload)
+	if err != nil {
+		return fmt.Errorf("event %d: %w", stored.Position, err)
+	}
+
+	switch e := decoded.(type) {
+	case interface{ Snapshot() *event.Transfer }:
+		p.applyTransfer(e.Snapshot(), stored.Version)
+		if r, ok := e.(*event.TransferReversed); ok {
+			p.transfers[r.TransferID].Transfer.ReversalOf = r.ReversalOf
+		}
+	case *event.BalanceOpened:
+		p.balances[balanceKey{e.UserID, e.Currency}] = &model.ProjectedBalance{
+			UserID:   e.UserID,
+			Currency: e.Currency,
+			Amount:   e.Balance,
+			Version:  stored.Version,
+		}
+	case *event.BalanceChanged:
+		key := balanceKey{e.UserID, e.Currency}
+		b, ok := p.balances[key]
+		if !ok {
+			return fmt.Errorf("event %d: balance %s %s changed before it was opened",
+				stored.Position, e.UserID, e.Currency)
+		}
+		b.Amount += e.Delta
+		b.Version = stored.Version
+	}
+
+	return nil
+}
+
+// applyTransfer replaces the transfer with the event's snapshot. A transfer
+// keeps the transfer it reverses, which only its first event names.
+func (p *projection) applyTransfer(t *event.Transfer, version int) {
+	var reversalOf string
+	if prev, ok := p.transfers[t.TransferID]; ok {
+		reversalOf = prev.Transfer.ReversalOf
+	}
+
+	transfer := &model.Transfer{
+		ID:              t.TransferID,
+		FromUserID:      t.FromUserID,
+		ToUserID:        t.ToUserID,
+		Amount:          t.Amount,
+		Currency:        t.Currency,
+		Fee:             t.Fee,
+		QuoteID:         t.QuoteID,
+		StandingOrderID: t.StandingOrderID,
+		State:           model.TransactionState(t.State),
+		ReversalOf:      reversalOf,
+		ReversedAmount:  t.ReversedAmount,
+		CreatedAt:       t.CreatedAt,
+	}
+	if t.CompletedAt != nil {
+		transfer.CompletedAt = *t.CompletedAt
+	}
+
+	p.transfers[t.TransferID] = &model.ProjectedTransfer{Transfer: transfer, Version: version}
+}
+
+// rows returns the projected transfers and balances in key order
+func (p *projection) rows() ([]*model.ProjectedTransfer, []*model.ProjectedBalance) {
+	transfers := make([]*model.ProjectedTransfer, 0, len(p.transfers))
+	for _, t := range p.transfers {
+		transfers = append(transfers, t)
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].Transfer.ID < transfers[j].Transfer.ID
+	})
+
+	balances := make([]*model.ProjectedBalance, 0, len(p.balances))
+	for _, b := range p.balances {
+		balances = append(balances, b)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].UserID != balances[j].UserID {
+			return balances[i].UserID < balances[j].UserID
+		}
+		return balances[i].Currency < balances[j].Currency
+	})
+
+	return transfers, balances
+}
@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+)
+
+// TestProjectionRebuildDuringTransfers rebuilds the projections while
+// transfers commit. Events and live tables are read from one snapshot, so the
+// transfers never show up as mismatches.
+func TestProjectionRebuildDuringTransfers(t *testing.T) {
+	env := newTestEnv(t)
+
+	eventStore, pgEventStore := env.factory.CreateEventStore()
+	projectionRepo, pgProjectionRepo := env.factory.CreateProjectionRepository()
+	projectionService := service.NewProjectionService(
+		eventStore, projectionRepo, env.txManager, pgEventStore, pgProjectionRepo,
+	)
+
+	a := env.createUser(t, map[string]int{"GBP": 100000})
+	b := env.createUser(t, map[string]int{"GBP": 100000})
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if _, err := env.transferService.CreateTransfer(service.TransferParams{
+				FromUserID: a.ID, ToUserID: b.ID, Amount: 1, Currency: "GBP",
+			}); err != nil {
+				t.Errorf("transfer failed: %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		report, err := projectionService.Rebuild(10)
+		if err != nil {
+			t.Fatalf("Rebuild: %v", err)
+		}
+		for _, m := range report.Mismatches {
+			t.Errorf("rebuild %d: %s %s live %s projected %s", i, m.Table, m.Key, m.Live, m.Projected)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/repository/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+// transferRejections are the errors that mean a transfer can never succeed as
+// requested. Any other error is treated as transient and the run is retried.
+var transferRejections = []error{
+	model.ErrInsufficientFunds,
+	model.ErrSameAccount,
+	model.ErrUserNotFound,
+	model.ErrInvalidAmount,
+	model.ErrUnsupportedCurrency,
+	model.ErrCurrencyNotHeld,
+	model.ErrIdempotencyKeyMismatch,
+	model.ErrAccountFrozen,
+	model.ErrAccountClosed,
+	model.ErrLimitExceeded,
+	model.ErrTransferDenied,
+}
+
+// ScheduledTransferService
+type ScheduledTransferService struct {
+	transferService         *TransferService
+	userRepo                repository.UserRepository
+	scheduledTransferRepo   repository.ScheduledTransferRepository
+	txManager               *database.TransactionManager
+	pgScheduledTransferRepo *postgresql.ScheduledTransferRepository
+	claimTimeout            time.Duration
+}
+
+// NewScheduledTransferService
+func NewScheduledTransferService(
+	transferService *TransferService,
+	userRepo repository.UserRepository,
+	scheduledTransferRepo repository.ScheduledTransferRepository,
+	txManager *database.TransactionManager,
+	pgScheduledTransferRepo *postgresql.ScheduledTransferRepository,
+	claimTimeout time.Duration,
+) *ScheduledTransferService {
+	return &ScheduledTransferService{
+		transferService:         transferService,
+		userRepo:                userRepo,
+		scheduledTransferRepo:   scheduledTransferRepo,
+		txManager:               txManager,
+		pgScheduledTransferRepo: pgScheduledTransferRepo,
+		claimTimeout:            claimTimeout,
+	}
+}
+
+// ScheduleTransfer validates the transfer now and stores it to run at executeAt.
+// Balances are only checked when it runs.
+func (s *ScheduledTransferService) ScheduleTransfer(params TransferParams, executeAt time.Time) (*model.ScheduledTransfer, error) {
+	if params.Amount <= 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	if params.FromUserID == params.ToUserID {
+		return nil, model.ErrSameAccount
+	}
+
+	if params.QuoteID != "" {
+		return nil, model.ErrQuoteNotSchedulable
+	}
+
+	now := time.Now()
+	if !executeAt.After(now) {
+		return nil, model.ErrInvalidExecuteAt
+	}
+
+	if params.Currency == "" {
+		params.Currency = model.DefaultCurrency
+	}
+
+	currency, err := model.LookupCurrency(params.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range []string{params.FromUserID, params.ToUserID} {
+		user, err := s.userRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := user.CheckActive(); err != nil {
+			return nil, err
+		}
+
+		if _, ok := user.BalanceIn(currency.Code); !ok {
+			return nil, model.ErrCurrencyNotHeld
+		}
+	}
+
+	schedule := &model.ScheduledTransfer{
+		FromUserID: params.FromUserID,
+		ToUserID:   params.ToUserID,
+		Amount:     params.Amount,
+		Currency:   currency.Code,
+		ExecuteAt:  executeAt,
+		Status:     model.ScheduledTransferStatusScheduled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.scheduledTransferRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// GetScheduledTransfer
+func (s *ScheduledTransferService) GetScheduledTransfer(id string) (*model.ScheduledTransfer, error) {
+	return s.scheduledTransferRepo.GetByID(id)
+}
+
+// ListScheduledTransfers
+func (s *ScheduledTransferService) ListScheduledTransfers() ([]*model.ScheduledTransfer, error) {
+	return s.scheduledTransferRepo.List()
+}
+
+// CancelScheduledTransfer
+func (s *ScheduledTransferService) CancelScheduledTransfer(id string) (*model.ScheduledTransfer, error) {
+	if err := s.scheduledTransferRepo.Cancel(id); err != nil {
+		return nil, err
+	}
+
+	return s.scheduledTransferRepo.GetByID(id)
+}
+
+// ExecuteDue runs the scheduled transfers that are due and returns how many finished
+func (s *ScheduledTransferService) ExecuteDue(limit int) (int, error) {
+	now := time.Now()
+	staleBefore := now.Add(-s.claimTimeout)
+
+	schedules, err := s.scheduledTransferRepo.ListDue(now, staleBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for _, schedule := range schedules {
+		claimed, err := s.scheduledTransferRepo.Claim(schedule.ID, now, staleBefore)
+		if err != nil {
+			return executed, err
+		}
+		if !claimed {
+			// Cancelled, or picked up by another worker
+			continue
+		}
+
+		if err := s.execute(schedule); err != nil {
+			// Left in PROCESSING; the run is retried once the claim goes stale
+			log.Printf("Error executing scheduled transfer %s: %v", schedule.ID, err)
+			continue
+		}
+		executed++
+	}
+
+	return executed, nil
+}
+
+// execute
+func (s *ScheduledTransferService) execute(schedule *model.ScheduledTransfer) error {
+	params := TransferParams{
+		FromUserID: schedule.FromUserID,
+		ToUserID:   schedule.ToUserID,
+		Amount:     schedule.Amount,
+		Currency:   schedule.Currency,
+	}
+
+	transfer, _, err := s.transferService.CreateTransferIdempotent(schedule.IdempotencyKey(), schedule.ID, params)
+	if err != nil && !isTransferRejection(err) {
+		return err
+	}
+	if err == nil {
+		// A replayed transfer may have been reviewed already
+		err = reviewFailure(transfer)
+	}
+
+	schedule.ExecutedAt = time.Now()
+
+	if err != nil {
+		schedule.Status = model.ScheduledTransferStatusFailed
+		schedule.FailureReason = err.Error()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return s.pgScheduledTransferRepo.FailTx(ctx, tx, schedule)
+		})
+	}
+
+	schedule.Status = model.ScheduledTransferStatusCompleted
+	if transfer.State == model.TransactionStateHeld {
+		schedule.Status = model.ScheduledTransferStatusPendingReview
+	}
+	schedule.TransferID = transfer.ID
+	return s.scheduledTransferRepo.Complete(schedule)
+}
+
+// ReviewClosedTx completes or fails the schedule whose held transfer was reviewed
+func (s *ScheduledTransferService) ReviewClosedTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
+	schedule, err := s.pgScheduledTransferRepo.GetByTransferIDForUpdate(ctx, tx, transfer.ID)
+	if err == model.ErrScheduleNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if schedule.Status != model.ScheduledTransferStatusPendingReview {
+		return nil
+	}
+
+	if failure := reviewFailure(transfer); failure != nil {
+		schedule.Status = model.ScheduledTransferStatusFailed
+		schedule.FailureReason = failure.Error()
+		return s.pgScheduledTransferRepo.FailTx(ctx, tx, schedule)
+	}
+
+	if transfer.State != model.TransactionStateCompleted {
+		return nil
+	}
+
+	schedule.Status = model.ScheduledTransferStatusCompleted
+	return s.pgScheduledTransferRepo.CompleteReviewedTx(ctx, tx, schedule)
+}
+
+// isTransferRejection
+func isTransferRejection(err error) bool {
+	for _, rejection := range transferRejections {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,5 +2,19 @@ package service
 
 // Services
 type Services struct {
-	TransferService *TransferService
+	TransferService  *TransferService
+	UserService      *UserService
+	FXService        *FXService
+	LedgerService    *LedgerService
+	FeeService       *FeeService
+	StatementService *StatementService
+
+	ScheduledTransferService *ScheduledTransferService
+	StandingOrderService     *StandingOrderService
+	TransferBatchService     *TransferBatchService
+	PaymentInitiationService *PaymentInitiationService
+	FundingService           *FundingService
+	CardService              *CardService
+	WebhookService           *WebhookService
+	ProjectionService        *ProjectionService
 }
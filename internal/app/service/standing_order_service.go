@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/repository/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+// StandingOrderService
+type StandingOrderService struct {
+	transferService     *TransferService
+	userRepo            repository.UserRepository
+	standingOrderRepo   repository.StandingOrderRepository
+	txManager           *database.TransactionManager
+	pgStandingOrderRepo *postgresql.StandingOrderRepository
+}
+
+// NewStandingOrderService
+func NewStandingOrderService(
+	transferService *TransferService,
+	userRepo repository.UserRepository,
+	standingOrderRepo repository.StandingOrderRepository,
+	txManager *database.TransactionManager,
+	pgStandingOrderRepo *postgresql.StandingOrderRepository,
+) *StandingOrderService {
+	return &StandingOrderService{
+		transferService:     transferService,
+		userRepo:            userRepo,
+		standingOrderRepo:   standingOrderRepo,
+		txManager:           txManager,
+		pgStandingOrderRepo: pgStandingOrderRepo,
+	}
+}
+
+// StandingOrderParams
+type StandingOrderParams struct {
+	FromUserID     string
+	ToUserID       string
+	Amount         int
+	Currency       string
+	Frequency      model.Frequency
+	DayOfMonth     int
+	StartDate      time.Time
+	EndDate        time.Time
+	MaxOccurrences int
+	CatchUp        model.CatchUpPolicy
+}
+
+// CreateStandingOrder
+func (s *StandingOrderService) CreateStandingOrder(params StandingOrderParams) (*model.StandingOrder, error) {
+	if params.Amount <= 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	if params.FromUserID == params.ToUserID {
+		return nil, model.ErrSameAccount
+	}
+
+	if params.Currency == "" {
+		params.Currency = model.DefaultCurrency
+	}
+
+	if params.CatchUp == "" {
+		params.CatchUp = model.CatchUpSkip
+	}
+
+	currency, err := model.LookupCurrency(params.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := model.Day(now)
+
+	if params.StartDate.IsZero() {
+		params.StartDate = today
+	}
+
+	order := &model.StandingOrder{
+		FromUserID:     params.FromUserID,
+		ToUserID:       params.ToUserID,
+		Amount:         params.Amount,
+		Currency:       currency.Code,
+		Frequency:      params.Frequency,
+		DayOfMonth:     params.DayOfMonth,
+		StartDate:      model.Day(params.StartDate),
+		MaxOccurrences: params.MaxOccurrences,
+		CatchUp:        params.CatchUp,
+		Status:         model.StandingOrderStatusActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if !params.EndDate.IsZero() {
+		order.EndDate = model.Day(params.EndDate)
+	}
+
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+
+	if order.StartDate.Before(today) {
+		return nil, model.ErrInvalidStartDate
+	}
+
+	order.NextRunAt = order.OccurrenceOnOrAfter(order.StartDate)
+	if order.Finished(order.NextRunAt) {
+		return nil, model.ErrInvalidPeriod
+	}
+
+	for _, id := range []string{order.FromUserID, order.ToUserID} {
+		user, err := s.userRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := user.CheckActive(); err != nil {
+			return nil, err
+		}
+
+		if _, ok := user.BalanceIn(order.Currency); !ok {
+			return nil, model.ErrCurrencyNotHeld
+		}
+	}
+
+	if err := s.standingOrderRepo.Create(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetStandingOrder
+func (s *StandingOrderService) GetStandingOrder(id string) (*model.StandingOrder, error) {
+	return s.standingOrderRepo.GetByID(id)
+}
+
+// ListStandingOrders
+func (s *StandingOrderService) ListStandingOrders() ([]*model.StandingOrder, error) {
+	return s.standingOrderRepo.List()
+}
+
+// PauseStandingOrder stops runs until the order is resumed
+func (s *StandingOrderService) PauseStandingOrder(id string) (*model.StandingOrder, error) {
+	order, err := s.standingOrderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != model.StandingOrderStatusActive {
+		return nil, model.ErrInvalidStatusChange
+	}
+
+	order.Status = model.StandingOrderStatusPaused
+	order.UpdatedAt = time.Now()
+
+	if err := s.standingOrderRepo.UpdateStatus(order, model.StandingOrderStatusActive); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ResumeStandingOrder restarts a paused order from its next date on or after today.
+// Dates that passed while it was paused are not run.
+func (s *StandingOrderService) ResumeStandingOrder(id string) (*model.StandingOrder, error) {
+	order, err := s.standingOrderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != model.StandingOrderStatusPaused {
+		return nil, model.ErrInvalidStatusChange
+	}
+
+	now := time.Now()
+	order.Status = model.StandingOrderStatusActive
+	order.NextRunAt = order.OccurrenceOnOrAfter(now)
+	order.UpdatedAt = now
+
+	if order.Finished(order.NextRunAt) {
+		order.Status = model.StandingOrderStatusCompleted
+		order.NextRunAt = time.Time{}
+	}
+
+	if err := s.standingOrderRepo.UpdateStatus(order, model.StandingOrderStatusPaused); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// RunDue runs the standing orders that are due and returns how many were advanced
+func (s *StandingOrderService) RunDue(limit int) (int, error) {
+	now := time.Now()
+
+	orders, err := s.standingOrderRepo.ListDue(now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	advanced := 0
+	for _, order := range orders {
+		ok, err := s.run(order, now)
+		if err != nil {
+			// The order stays due and is retried on the next tick
+			log.Printf("Error running standing order %s: %v", order.ID, err)
+			continue
+		}
+		if ok {
+			advanced++
+		}
+	}
+
+	return advanced, nil
+}
+
+// run makes the order's due transfer, or skips it under the SKIP policy when
+// it was missed, then moves the order on to its next date. The transfer is
+// keyed by order and date, so a run that is repeated never pays twice. A run
+// whose transfer is held for review parks the order in PENDING_REVIEW, and
+// ReviewClosedTx moves it on once the review closes.
+func (s *StandingOrderService) run(order *model.StandingOrder, now time.Time) (bool, error) {
+	due := order.NextRunAt
+	ran := !order.Missed(now) || order.CatchUp == model.CatchUpRunOnce
+
+	var runErr error
+	if ran {
+		key := fmt.Sprintf("standing:%s:%s", order.ID, due.Format(time.DateOnly))
+
+		transfer, _, err := s.transferService.CreateTransferIdempotent(key, order.ID, TransferParams{
+			FromUserID:      order.FromUserID,
+			ToUserID:        order.ToUserID,
+			Amount:          order.Amount,
+			Currency:        order.Currency,
+			StandingOrderID: order.ID,
+			Bulk:            true,
+		})
+		if err != nil && !isTransferRejection(err) {
+			return false, err
+		}
+		if err == nil {
+			// A replayed transfer may have been reviewed already
+			err = reviewFailure(transfer)
+		}
+		if err == nil && transfer.State == model.TransactionStateHeld {
+			return s.holdRun(order, due, transfer.ID)
+		}
+		runErr = err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	advanced := false
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		advanced, err = s.advanceTx(ctx, tx, order, due, now, ran, runErr)
+		return err
+	})
+
+	return advanced, err
+}
+
+// holdRun parks the order until the review of its run's transfer closes
+func (s *StandingOrderService) holdRun(order *model.StandingOrder, due time.Time, transferID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	order.Status = model.StandingOrderStatusPendingReview
+	order.HeldTransferID = transferID
+
+	held := false
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		held, err = s.pgStandingOrderRepo.HoldRunTx(ctx, tx, order, due)
+		return err
+	})
+
+	return held, err
+}
+
+// ReviewClosedTx moves on the order whose run was waiting for the review of
+// its transfer, counting the run as failed when the transfer did not go ahead
+func (s *StandingOrderService) ReviewClosedTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
+	order, err := s.pgStandingOrderRepo.GetByHeldTransferIDForUpdate(ctx, tx, transfer.ID)
+	if err == model.ErrStandingOrderNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	runErr := reviewFailure(transfer)
+	if runErr == nil && transfer.State != model.TransactionStateCompleted {
+		return nil
+	}
+
+	_, err = s.advanceTx(ctx, tx, order, order.NextRunAt, time.Now(), true, runErr)
+	return err
+}
+
+// advanceTx records the run that was due at due, or that it was skipped, and
+// moves the order on to its next date
+func (s *StandingOrderService) advanceTx(ctx context.Context, tx *sqlx.Tx, order *model.StandingOrder, due, now time.Time, ran bool, runErr error) (bool, error) {
+	if ran {
+		order.Occurrences++
+		order.LastRunAt = now
+		order.LastFailureReason = ""
+		if runErr != nil {
+			order.LastFailureReason = runErr.Error()
+		}
+	}
+
+	next := order.NextRunAfter(due, now)
+
+	order.Status = model.StandingOrderStatusActive
+	order.HeldTransferID = ""
+	order.NextRunAt = next
+	if order.Finished(next) {
+		order.Status = model.StandingOrderStatusCompleted
+		order.NextRunAt = time.Time{}
+	}
+
+	advanced, err := s.pgStandingOrderRepo.AdvanceTx(ctx, tx, order, due)
+	if err != nil || !advanced || runErr == nil {
+		return advanced, err
+	}
+
+	return advanced, s.pgStandingOrderRepo.CreateRunFailedEventTx(ctx, tx, order, due)
+}
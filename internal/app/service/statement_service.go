@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+// StatementService
+type StatementService struct {
+	userRepo      repository.UserRepository
+	statementRepo repository.StatementRepository
+}
+
+// NewStatementService
+func NewStatementService(
+	userRepo repository.UserRepository,
+	statementRepo repository.StatementRepository,
+) *StatementService {
+	return &StatementService{
+		userRepo:      userRepo,
+		statementRepo: statementRepo,
+	}
+}
+
+// Statement returns the user's postings in the currency within [from, to),
+// with the opening, running and closing balances
+func (s *StatementService) Statement(userID, currency string, from, to time.Time) (*model.Statement, error) {
+	if !from.Before(to) {
+		return nil, model.ErrInvalidPeriod
+	}
+
+	if currency == "" {
+		currency = model.DefaultCurrency
+	}
+
+	c, err := model.LookupCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := s.statementRepo.GetStatement(userID, c.Code, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	statement.UserName = user.Name
+
+	statement.Reconcile()
+	return statement, nil
+}
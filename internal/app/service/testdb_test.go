@@ -0,0 +1,179 @@
+package service_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	domainRepository "github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/acquirer"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	repository "github.com/IskenT/money-transfer/internal/infra/repository/factory"
+	"github.com/IskenT/money-transfer/internal/infra/risk"
+	"github.com/jmoiron/sqlx"
+)
+
+// testDSNEnv names the database the integration tests run against. It must
+// have every migration applied; the tests only add users of their own.
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+// testEnv holds the services of an integration test, wired like the application
+type testEnv struct {
+	db              *sqlx.DB
+	txManager       *database.TransactionManager
+	factory         *repository.Factory
+	userRepo        domainRepository.UserRepository
+	transferService *service.TransferService
+}
+
+// newTestEnv connects to TEST_DATABASE_DSN, skipping the test when it is not
+// set. Risk screening lets every transfer through.
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	riskEngine, err := risk.ParseEngine([]byte("rules: []"))
+	if err != nil {
+		t.Fatalf("error building risk engine: %v", err)
+	}
+	return newTestEnvWithRisk(t, riskEngine)
+}
+
+// newTestEnvWithRules screens transfers with the rules shipped in config
+func newTestEnvWithRules(t *testing.T) *testEnv {
+	t.Helper()
+
+	riskEngine, err := risk.LoadEngine("../../../config/risk_rules.yaml")
+	if err != nil {
+		t.Fatalf("error loading risk rules: %v", err)
+	}
+	return newTestEnvWithRisk(t, riskEngine)
+}
+
+// newTestEnvWithRisk is newTestEnv with the given risk engine
+func newTestEnvWithRisk(t *testing.T, riskEngine domainRepository.RiskEngine) *testEnv {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to %s: %v", testDSNEnv, err)
+	}
+	db.SetMaxOpenConns(25)
+	t.Cleanup(func() { db.Close() })
+
+	txManager := database.NewTransactionManager(db, database.RetryPolicy{
+		MaxAttempts: 20,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    50 * time.Millisecond,
+	})
+	factory := repository.NewFactory(txManager)
+
+	userRepo, pgUserRepo := factory.CreateUserRepository()
+	transferRepo, pgTransferRepo := factory.CreateTransferRepository()
+	idempotencyKeyRepo, pgIdempotencyKeyRepo := factory.CreateIdempotencyKeyRepository()
+	_, pgFXQuoteRepo := factory.CreateFXQuoteRepository()
+	_, pgLedgerRepo := factory.CreateLedgerRepository()
+	holdRepo, pgHoldRepo := factory.CreateHoldRepository()
+	_, pgCardRepo := factory.CreateCardRepository()
+	transferLimitRepo, pgTransferLimitRepo := factory.CreateTransferLimitRepository()
+	riskReviewRepo, pgRiskReviewRepo := factory.CreateRiskReviewRepository()
+
+	transferService := service.NewTransferService(service.TransferServiceDeps{
+		UserRepo:             userRepo,
+		TransferRepo:         transferRepo,
+		IdempotencyKeyRepo:   idempotencyKeyRepo,
+		FeeScheduleRepo:      factory.CreateFeeScheduleRepository(),
+		TxManager:            txManager,
+		PgUserRepo:           pgUserRepo,
+		PgTransferRepo:       pgTransferRepo,
+		PgIdempotencyKeyRepo: pgIdempotencyKeyRepo,
+		PgFXQuoteRepo:        pgFXQuoteRepo,
+		PgLedgerRepo:         pgLedgerRepo,
+		HoldRepo:             holdRepo,
+		PgHoldRepo:           pgHoldRepo,
+		IdempotencyKeyTTL:    24 * time.Hour,
+		HoldTTL:              7 * 24 * time.Hour,
+
+		PgCardRepo:      pgCardRepo,
+		Acquirer:        acquirer.NewSimulator("test-secret"),
+		AcquirerTimeout: 5 * time.Second,
+
+		TransferLimitRepo:   transferLimitRepo,
+		PgTransferLimitRepo: pgTransferLimitRepo,
+
+		RiskEngine:       riskEngine,
+		RiskReviewRepo:   riskReviewRepo,
+		PgRiskReviewRepo: pgRiskReviewRepo,
+		ReviewTTL:        24 * time.Hour,
+	})
+
+	return &testEnv{
+		db:              db,
+		txManager:       txManager,
+		factory:         factory,
+		userRepo:        userRepo,
+		transferService: transferService,
+	}
+}
+
+// newFundingService runs fundings through the provider
+func (e *testEnv) newFundingService(provider domainRepository.FundingProvider, providerTimeout time.Duration) *service.FundingService {
+	fundingRepo, pgFundingRepo := e.factory.CreateFundingRepository()
+	_, pgUserRepo := e.factory.CreateUserRepository()
+	_, pgTransferRepo := e.factory.CreateTransferRepository()
+	_, pgLedgerRepo := e.factory.CreateLedgerRepository()
+	transferLimitRepo, pgTransferLimitRepo := e.factory.CreateTransferLimitRepository()
+
+	return service.NewFundingService(
+		fundingRepo, provider, e.txManager,
+		pgUserRepo, pgTransferRepo, pgFundingRepo, pgLedgerRepo,
+		transferLimitRepo, pgTransferLimitRepo, providerTimeout,
+	)
+}
+
+// newBatchService processes batches of up to 100 items
+func (e *testEnv) newBatchService() *service.TransferBatchService {
+	batchRepo, pgBatchRepo := e.factory.CreateTransferBatchRepository()
+	return service.NewTransferBatchService(e.transferService, batchRepo, e.txManager, pgBatchRepo, 100, time.Minute)
+}
+
+// createUser opens an account holding the given balances
+func (e *testEnv) createUser(t *testing.T, balances map[string]int) *model.User {
+	t.Helper()
+
+	user := &model.User{
+		Name:   t.Name(),
+		Tier:   model.AccountTierStandard,
+		Status: model.UserStatusActive,
+	}
+	for currency, amount := range balances {
+		user.Balances = append(user.Balances, &model.Balance{Currency: currency, Amount: amount})
+	}
+
+	if err := e.userRepo.Create(user); err != nil {
+		t.Fatalf("error creating user: %v", err)
+	}
+	return user
+}
+
+// balance returns the user's balance in the currency
+func (e *testEnv) balance(t *testing.T, userID, currency string) int {
+	t.Helper()
+
+	user, err := e.userRepo.GetByID(userID)
+	if err != nil {
+		t.Fatalf("error loading user %s: %v", userID, err)
+	}
+
+	b, ok := user.BalanceIn(currency)
+	if !ok {
+		t.Fatalf("user %s holds no %s", userID, currency)
+	}
+	return b.Amount
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/repository/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+// TransferBatchService
+type TransferBatchService struct {
+	transferService *TransferService
+	batchRepo       repository.TransferBatchRepository
+	txManager       *database.TransactionManager
+	pgBatchRepo     *postgresql.TransferBatchRepository
+	maxItems        int
+	claimTimeout    time.Duration
+}
+
+// NewTransferBatchService
+func NewTransferBatchService(
+	transferService *TransferService,
+	batchRepo repository.TransferBatchRepository,
+	txManager *database.TransactionManager,
+	pgBatchRepo *postgresql.TransferBatchRepository,
+	maxItems int,
+	claimTimeout time.Duration,
+) *TransferBatchService {
+	return &TransferBatchService{
+		transferService: transferService,
+		batchRepo:       batchRepo,
+		txManager:       txManager,
+		pgBatchRepo:     pgBatchRepo,
+		maxItems:        maxItems,
+		claimTimeout:    claimTimeout,
+	}
+}
+
+// SubmitBatch stores the batch and starts processing it in the background.
+// Items are validated when they run, so that each gets its own result.
+func (s *TransferBatchService) SubmitBatch(mode model.BatchMode, items []TransferParams) (*model.TransferBatch, error) {
+	if mode != model.BatchModeAtomic && mode != model.BatchModeBestEffort {
+		return nil, model.ErrInvalidBatchMode
+	}
+
+	if len(items) == 0 || len(items) > s.maxItems {
+		return nil, model.ErrInvalidBatchSize
+	}
+
+	now := time.Now()
+	batch := &model.TransferBatch{
+		Mode:      mode,
+		Status:    model.BatchStatusPending,
+		Items:     make([]*model.TransferBatchItem, len(items)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	for i, params := range items {
+		if params.Currency == "" {
+			params.Currency = model.DefaultCurrency
+		}
+
+		batch.Items[i] = &model.TransferBatchItem{
+			Index:      i,
+			FromUserID: params.FromUserID,
+			ToUserID:   params.ToUserID,
+			Amount:     params.Amount,
+			Currency:   params.Currency,
+			Status:     model.BatchItemStatusPending,
+		}
+	}
+
+	if err := s.batchRepo.Create(batch); err != nil {
+		return nil, err
+	}
+
+	// A batch lost here, for example on shutdown, is picked up by the scheduler
+	go func(id string) {
+		if err := s.ProcessBatch(id); err != nil {
+			log.Printf("Error processing batch %s: %v", id, err)
+		}
+	}(batch.ID)
+
+	return batch, nil
+}
+
+// GetBatch
+func (s *TransferBatchService) GetBatch(id string) (*model.TransferBatch, error) {
+	return s.batchRepo.GetByID(id)
+}
+
+// ProcessOpen processes batches that are waiting or whose worker stopped.
+// It returns the number of batches processed.
+func (s *TransferBatchService) ProcessOpen(limit int) (int, error) {
+	ids, err := s.batchRepo.ListOpen(time.Now().Add(-s.claimTimeout), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, id := range ids {
+		if err := s.ProcessBatch(id); err != nil {
+			log.Printf("Error processing batch %s: %v", id, err)
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// ProcessBatch claims the batch and runs its items. A transient error leaves
+// the batch PROCESSING so that it is retried once the claim goes stale.
+func (s *TransferBatchService) ProcessBatch(id string) error {
+	now := time.Now()
+
+	claimed, err := s.batchRepo.Claim(id, now, now.Add(-s.claimTimeout))
+	if err != nil || !claimed {
+		return err
+	}
+
+	batch, err := s.batchRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if batch.Mode == model.BatchModeAtomic {
+		return s.processAtomic(batch)
+	}
+
+	return s.processBestEffort(batch)
+}
+
+// processAtomic runs every item in one transaction with all users locked up
+// front. A batch cannot be all or nothing while one of its transfers waits
+// for a review, so an item that risk screening holds fails the batch.
+func (s *TransferBatchService) processAtomic(batch *model.TransferBatch) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second+time.Duration(len(batch.Items))*100*time.Millisecond)
+	defer cancel()
+
+	var failed *model.TransferBatchItem
+	var failErr error
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		// Start from scratch when a deadlock or serialization failure is retried
+		failed, failErr = nil, nil
+		for _, item := range batch.Items {
+			item.Status = model.BatchItemStatusPending
+			item.TransferID = ""
+		}
+
+		locked, err := s.pgBatchRepo.GetForUpdate(ctx, tx, batch.ID)
+		if err != nil {
+			return err
+		}
+
+		if locked.Status != model.BatchStatusProcessing {
+			// Settled by another worker while this one waited for the lock
+			return nil
+		}
+
+		userIDs := make([]string, 0, 2*len(batch.Items))
+		for _, item := range batch.Items {
+			userIDs = append(userIDs, item.FromUserID, item.ToUserID)
+		}
+
+		if _, err := s.transferService.lockUsers(ctx, tx, userIDs...); err != nil {
+			return err
+		}
+
+		for _, item := range batch.Items {
+			transfer, err := s.transferService.createTransferTx(ctx, tx, batchItemParams(item), nil)
+			if err == nil && transfer.State == model.TransactionStateHeld {
+				err = model.ErrHeldInAtomicBatch
+			}
+			if err != nil {
+				failed, failErr = item, err
+				return err
+			}
+
+			item.Status = model.BatchItemStatusCompleted
+			item.TransferID = transfer.ID
+		}
+
+		for _, item := range batch.Items {
+			if err := s.pgBatchRepo.UpdateItemTx(ctx, tx, batch, item); err != nil {
+				return err
+			}
+		}
+
+		batch.Settle(time.Now())
+		return s.pgBatchRepo.UpdateStatusTx(ctx, tx, batch)
+	}, s.txManager.WithRetry())
+
+	if err == nil {
+		return nil
+	}
+
+	if failed == nil || !(isTransferRejection(failErr) || errors.Is(failErr, model.ErrHeldInAtomicBatch)) {
+		return err
+	}
+
+	// Nothing was committed; record which item failed the batch
+	for _, item := range batch.Items {
+		item.TransferID = ""
+		item.Status = model.BatchItemStatusRolledBack
+	}
+	failed.Status = model.BatchItemStatusFailed
+	failed.Error = failErr.Error()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, item := range batch.Items {
+			if err := s.pgBatchRepo.UpdateItemTx(ctx, tx, batch, item); err != nil {
+				return err
+			}
+		}
+
+		batch.Settle(time.Now())
+		return s.pgBatchRepo.UpdateStatusTx(ctx, tx, batch)
+	})
+}
+
+// processBestEffort runs each pending item as its own transfer. Items whose
+// transfer is held for review stay HELD until ReviewClosedTx settles them.
+func (s *TransferBatchService) processBestEffort(batch *model.TransferBatch) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second+time.Duration(len(batch.Items))*100*time.Millisecond)
+	defer cancel()
+
+	for _, item := range batch.Items {
+		if item.Status != model.BatchItemStatusPending {
+			continue
+		}
+
+		transfer, _, err := s.transferService.CreateTransferIdempotent(batch.IdempotencyKey(item), batch.ID, batchItemParams(item))
+		if err != nil && !isTransferRejection(err) {
+			return err
+		}
+		if err == nil {
+			// A replayed transfer may have been reviewed already
+			err = reviewFailure(transfer)
+		}
+
+		if err != nil {
+			item.Status = model.BatchItemStatusFailed
+			item.Error = err.Error()
+		} else {
+			item.Status = model.BatchItemStatusCompleted
+			if transfer.State == model.TransactionStateHeld {
+				item.Status = model.BatchItemStatusHeld
+			}
+			item.TransferID = transfer.ID
+		}
+
+		err = s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return s.pgBatchRepo.UpdateItemTx(ctx, tx, batch, item)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	batch.Settle(time.Now())
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.pgBatchRepo.UpdateStatusTx(ctx, tx, batch)
+	})
+}
+
+// ReviewClosedTx settles the batch item whose held transfer was reviewed, and
+// the batch once none of its items is held any more
+func (s *TransferBatchService) ReviewClosedTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
+	batch, err := s.pgBatchRepo.GetByTransferIDForUpdate(ctx, tx, transfer.ID)
+	if err == model.ErrBatchNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, item := range batch.Items {
+		if item.TransferID != transfer.ID || item.Status != model.BatchItemStatusHeld {
+			continue
+		}
+
+		if failure := reviewFailure(transfer); failure != nil {
+			item.Status = model.BatchItemStatusFailed
+			item.Error = failure.Error()
+		} else if transfer.State == model.TransactionStateCompleted {
+			item.Status = model.BatchItemStatusCompleted
+		} else {
+			return nil
+		}
+
+		if err := s.pgBatchRepo.UpdateItemTx(ctx, tx, batch, item); err != nil {
+			return err
+		}
+
+		batch.Settle(time.Now())
+		return s.pgBatchRepo.UpdateStatusTx(ctx, tx, batch)
+	}
+
+	return nil
+}
+
+// batchItemParams
+func batchItemParams(item *model.TransferBatchItem) TransferParams {
+	return TransferParams{
+		FromUserID: item.FromUserID,
+		ToUserID:   item.ToUserID,
+		Amount:     item.Amount,
+		Currency:   item.Currency,
+		Bulk:       true,
+	}
+}
@@ -0,0 +1,138 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// waitBatch waits for the batch started by SubmitBatch to settle
+func waitBatch(t *testing.T, batchService *service.TransferBatchService, id string) *model.TransferBatch {
+	t.Helper()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		batch, err := batchService.GetBatch(id)
+		if err != nil {
+			t.Fatalf("GetBatch: %v", err)
+		}
+		if batch.Status != model.BatchStatusPending && batch.Status != model.BatchStatusProcessing {
+			return batch
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch %s still %s", id, batch.Status)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// TestAtomicBatchVelocity runs a batch of more items from one payer than the
+// shipped velocity rule allows in an hour, which must not hold any of them
+func TestAtomicBatchVelocity(t *testing.T) {
+	env := newTestEnvWithRules(t)
+	batchService := env.newBatchService()
+
+	payer := env.createUser(t, map[string]int{"USD": 100000})
+	payees := []*model.User{
+		env.createUser(t, map[string]int{"USD": 0}),
+		env.createUser(t, map[string]int{"USD": 0}),
+	}
+
+	items := make([]service.TransferParams, 8)
+	for i := range items {
+		items[i] = service.TransferParams{
+			FromUserID: payer.ID,
+			ToUserID:   payees[i%len(payees)].ID,
+			Amount:     1234,
+			Currency:   "USD",
+		}
+	}
+
+	submitted, err := batchService.SubmitBatch(model.BatchModeAtomic, items)
+	if err != nil {
+		t.Fatalf("SubmitBatch: %v", err)
+	}
+
+	batch := waitBatch(t, batchService, submitted.ID)
+	if batch.Status != model.BatchStatusCompleted {
+		for _, item := range batch.Items {
+			if item.Error != "" {
+				t.Logf("item %d: %s", item.Index, item.Error)
+			}
+		}
+		t.Fatalf("batch status = %s, want %s", batch.Status, model.BatchStatusCompleted)
+	}
+
+	if got := env.balance(t, payees[0].ID, "USD") + env.balance(t, payees[1].ID, "USD"); got != 8*1234 {
+		t.Errorf("payees received %d, want %d", got, 8*1234)
+	}
+
+	// Transfers made one at a time are still screened, without the batch
+	// counting towards them
+	for i := 1; i <= 6; i++ {
+		transfer, err := env.transferService.CreateTransfer(service.TransferParams{
+			FromUserID: payer.ID,
+			ToUserID:   payees[0].ID,
+			Amount:     1234,
+			Currency:   "USD",
+		})
+		if err != nil {
+			t.Fatalf("CreateTransfer %d: %v", i, err)
+		}
+
+		want := model.TransactionStateCompleted
+		if i == 6 {
+			want = model.TransactionStateHeld
+		}
+		if transfer.State != want {
+			t.Errorf("transfer %d in the hour state = %s, want %s", i, transfer.State, want)
+		}
+	}
+}
+
+// TestBatchTransfersPerHourLimit runs a batch of more items from one payer
+// than the seeded USD limit of 10 transfers per hour
+func TestBatchTransfersPerHourLimit(t *testing.T) {
+	env := newTestEnv(t)
+	batchService := env.newBatchService()
+
+	payer := env.createUser(t, map[string]int{"USD": 100000})
+	payee := env.createUser(t, map[string]int{"USD": 0})
+
+	params := service.TransferParams{FromUserID: payer.ID, ToUserID: payee.ID, Amount: 1000, Currency: "USD"}
+	items := make([]service.TransferParams, 12)
+	for i := range items {
+		items[i] = params
+	}
+
+	for _, mode := range []model.BatchMode{model.BatchModeAtomic, model.BatchModeBestEffort} {
+		submitted, err := batchService.SubmitBatch(mode, items)
+		if err != nil {
+			t.Fatalf("SubmitBatch %s: %v", mode, err)
+		}
+
+		if batch := waitBatch(t, batchService, submitted.ID); batch.Status != model.BatchStatusCompleted {
+			t.Fatalf("%s batch status = %s, want %s", mode, batch.Status, model.BatchStatusCompleted)
+		}
+	}
+
+	// The batches used up none of the payer's transfers in the hour
+	for i := 1; i <= 10; i++ {
+		if _, err := env.transferService.CreateTransfer(params); err != nil {
+			t.Fatalf("CreateTransfer %d: %v", i, err)
+		}
+	}
+
+	var limitErr *model.LimitExceededError
+	_, err := env.transferService.CreateTransfer(params)
+	if !errors.As(err, &limitErr) || limitErr.Limit != model.LimitTypeHourlyTransfers {
+		t.Fatalf("CreateTransfer 11 error = %v, want %s exceeded", err, model.LimitTypeHourlyTransfers)
+	}
+
+	if got := env.balance(t, payee.ID, "USD"); got != 34*1000 {
+		t.Errorf("payee balance = %d, want %d", got, 34*1000)
+	}
+}
@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/IskenT/money-transfer/internal/domain/model"
@@ -12,60 +16,1070 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// maxIdempotencyKeyLength
+const maxIdempotencyKeyLength = 255
+
 // TransferService
 type TransferService struct {
-	userRepo       repository.UserRepository
-	transferRepo   repository.TransferRepository
-	txManager      *database.TransactionManager
-	pgUserRepo     *postgresql.UserRepository
-	pgTransferRepo *postgresql.TransferRepository
+	userRepo             repository.UserRepository
+	transferRepo         repository.TransferRepository
+	idempotencyKeyRepo   repository.IdempotencyKeyRepository
+	feeScheduleRepo      repository.FeeScheduleRepository
+	txManager            *database.TransactionManager
+	pgUserRepo           *postgresql.UserRepository
+	pgTransferRepo       *postgresql.TransferRepository
+	pgIdempotencyKeyRepo *postgresql.IdempotencyKeyRepository
+	pgFXQuoteRepo        *postgresql.FXQuoteRepository
+	pgLedgerRepo         *postgresql.LedgerRepository
+	holdRepo             repository.HoldRepository
+	pgHoldRepo           *postgresql.HoldRepository
+	pgCardRepo           *postgresql.CardRepository
+	transferLimitRepo    repository.TransferLimitRepository
+	pgTransferLimitRepo  *postgresql.TransferLimitRepository
+	acquirer             repository.CardAcquirer
+	riskEngine           repository.RiskEngine
+	riskReviewRepo       repository.RiskReviewRepository
+	pgRiskReviewRepo     *postgresql.RiskReviewRepository
+	idempotencyKeyTTL    time.Duration
+	holdTTL              time.Duration
+	terminal             model.CardTerminal
+	acquirerTimeout      time.Duration
+	reviewTTL            time.Duration
+	reviewListeners      []ReviewListener
+}
+
+// ReviewListener settles whatever made a transfer that risk screening held,
+// such as a batch item or a scheduled run, once the review closes. It is
+// called in the transaction that closes the review, with the transfer in its
+// new state.
+type ReviewListener interface {
+	ReviewClosedTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error
+}
+
+// TransferServiceDeps holds the collaborators and settings of a
+// TransferService
+type TransferServiceDeps struct {
+	UserRepo             repository.UserRepository
+	TransferRepo         repository.TransferRepository
+	IdempotencyKeyRepo   repository.IdempotencyKeyRepository
+	FeeScheduleRepo      repository.FeeScheduleRepository
+	TxManager            *database.TransactionManager
+	PgUserRepo           *postgresql.UserRepository
+	PgTransferRepo       *postgresql.TransferRepository
+	PgIdempotencyKeyRepo *postgresql.IdempotencyKeyRepository
+	PgFXQuoteRepo        *postgresql.FXQuoteRepository
+	PgLedgerRepo         *postgresql.LedgerRepository
+	HoldRepo             repository.HoldRepository
+	PgHoldRepo           *postgresql.HoldRepository
+	IdempotencyKeyTTL    time.Duration
+	HoldTTL              time.Duration
+
+	// Card payments
+	PgCardRepo      *postgresql.CardRepository
+	Acquirer        repository.CardAcquirer
+	Terminal        model.CardTerminal
+	AcquirerTimeout time.Duration
+
+	// Limits
+	TransferLimitRepo   repository.TransferLimitRepository
+	PgTransferLimitRepo *postgresql.TransferLimitRepository
+
+	// Risk screening
+	RiskEngine       repository.RiskEngine
+	RiskReviewRepo   repository.RiskReviewRepository
+	PgRiskReviewRepo *postgresql.RiskReviewRepository
+	ReviewTTL        time.Duration
+}
+
+// NewTransferService
+func NewTransferService(deps TransferServiceDeps) *TransferService {
+	return &TransferService{
+		userRepo:             deps.UserRepo,
+		transferRepo:         deps.TransferRepo,
+		idempotencyKeyRepo:   deps.IdempotencyKeyRepo,
+		feeScheduleRepo:      deps.FeeScheduleRepo,
+		txManager:            deps.TxManager,
+		pgUserRepo:           deps.PgUserRepo,
+		pgTransferRepo:       deps.PgTransferRepo,
+		pgIdempotencyKeyRepo: deps.PgIdempotencyKeyRepo,
+		pgFXQuoteRepo:        deps.PgFXQuoteRepo,
+		pgLedgerRepo:         deps.PgLedgerRepo,
+		holdRepo:             deps.HoldRepo,
+		pgHoldRepo:           deps.PgHoldRepo,
+		pgCardRepo:           deps.PgCardRepo,
+		transferLimitRepo:    deps.TransferLimitRepo,
+		pgTransferLimitRepo:  deps.PgTransferLimitRepo,
+		acquirer:             deps.Acquirer,
+		riskEngine:           deps.RiskEngine,
+		riskReviewRepo:       deps.RiskReviewRepo,
+		pgRiskReviewRepo:     deps.PgRiskReviewRepo,
+		idempotencyKeyTTL:    deps.IdempotencyKeyTTL,
+		holdTTL:              deps.HoldTTL,
+		terminal:             deps.Terminal,
+		acquirerTimeout:      deps.AcquirerTimeout,
+		reviewTTL:            deps.ReviewTTL,
+	}
+}
+
+// TransferParams
+type TransferParams struct {
+	FromUserID string
+	ToUserID   string
+	Amount     int
+	Currency   string
+	// QuoteID makes the transfer cross-currency: Amount is debited in the
+	// quote's source currency and credited at the quoted rate
+	QuoteID string
+	// Authorize only places a hold on the sender's funds; the transfer
+	// stays PENDING until it is captured or voided
+	Authorize bool
+	// StandingOrderID links the transfer to the standing order that made it
+	StandingOrderID string
+	// Sweep moves funds out of an account that is being closed: no fee is
+	// charged and the sender may be frozen
+	Sweep bool
+	// CardID pays the transfer and its fee with one of the sender's cards
+	// instead of their balance. The transfer stays PENDING until the
+	// acquirer has answered.
+	CardID string
+	// Bulk marks an item of a batch, a pain.001 file or a standing order,
+	// which the payer submitted in advance rather than one at a time. It is
+	// not counted in the transfers per hour.
+	Bulk bool
+}
+
+// CreateTransfer
+func (s *TransferService) CreateTransfer(params TransferParams) (*model.Transfer, error) {
+	return s.createTransfer(params, nil)
+}
+
+// CreateTransferIdempotent executes the transfer at most once per key.
+// The returned flag is true when the result is a replay of an earlier request.
+func (s *TransferService) CreateTransferIdempotent(key, requestHash string, params TransferParams) (*model.Transfer, bool, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, false, model.ErrInvalidIdempotencyKey
+	}
+
+	transfer, err := s.replayIdempotencyKey(key, requestHash)
+	if err == nil {
+		return transfer, true, nil
+	}
+	if err != model.ErrIdempotencyKeyNotFound {
+		return nil, false, err
+	}
+
+	idempotencyKey := &model.IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+	}
+
+	transfer, err = s.createTransfer(params, idempotencyKey)
+	if err == model.ErrIdempotencyKeyExists {
+		// A concurrent request with the same key won the race
+		transfer, err = s.replayIdempotencyKey(key, requestHash)
+		if err != nil {
+			return nil, false, err
+		}
+		return transfer, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return transfer, false, nil
+}
+
+// IdempotentResponse returns the response stored for the key, or
+// model.ErrIdempotencyKeyNotFound while there is none: the key is unknown,
+// expired, or its first request has not answered yet
+func (s *TransferService) IdempotentResponse(key, requestHash string) (*model.IdempotentResponse, error) {
+	idempotencyKey, err := s.idempotencyKeyRepo.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !idempotencyKey.Matches(requestHash) {
+		return nil, model.ErrIdempotencyKeyMismatch
+	}
+
+	if idempotencyKey.Response == nil {
+		return nil, model.ErrIdempotencyKeyNotFound
+	}
+
+	return idempotencyKey.Response, nil
+}
+
+// SaveIdempotentResponse stores the response given to the request that
+// executed the key's transfer, for IdempotentResponse to return on retries
+func (s *TransferService) SaveIdempotentResponse(key string, response *model.IdempotentResponse) error {
+	return s.idempotencyKeyRepo.SaveResponse(key, response)
+}
+
+// ReplayTransferIdempotent returns the transfer an unexpired key executed
+// without executing anything, or model.ErrIdempotencyKeyNotFound
+func (s *TransferService) ReplayTransferIdempotent(key, requestHash string) (*model.Transfer, error) {
+	return s.replayIdempotencyKey(key, requestHash)
+}
+
+// replayIdempotencyKey
+func (s *TransferService) replayIdempotencyKey(key, requestHash string) (*model.Transfer, error) {
+	idempotencyKey, err := s.idempotencyKeyRepo.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !idempotencyKey.Matches(requestHash) {
+		return nil, model.ErrIdempotencyKeyMismatch
+	}
+
+	return s.transferRepo.GetByID(idempotencyKey.TransferID)
+}
+
+// createTransfer
+func (s *TransferService) createTransfer(params TransferParams, idempotencyKey *model.IdempotencyKey) (*model.Transfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transfer *model.Transfer
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		transfer, err = s.createTransferTx(ctx, tx, params, idempotencyKey)
+		return err
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.CardFunded() && transfer.State == model.TransactionStatePending {
+		return s.authorizeCard(transfer)
+	}
+
+	return transfer, nil
+}
+
+// createTransferTx runs a transfer inside the caller's transaction
+func (s *TransferService) createTransferTx(ctx context.Context, tx *sqlx.Tx, params TransferParams, idempotencyKey *model.IdempotencyKey) (*model.Transfer, error) {
+	fromUserID, toUserID, amount := params.FromUserID, params.ToUserID, params.Amount
+
+	if amount <= 0 {
+		return nil, model.ErrInvalidAmount
+	}
+
+	if fromUserID == toUserID {
+		return nil, model.ErrSameAccount
+	}
+
+	if params.CardID != "" && (params.Authorize || params.Sweep) {
+		return nil, model.ErrCardNotAllowed
+	}
+
+	if params.Currency == "" && params.QuoteID == "" {
+		params.Currency = model.DefaultCurrency
+	}
+
+	if params.Currency != "" {
+		if _, err := model.LookupCurrency(params.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	transferIDGen, err := s.pgTransferRepo.GetTransferIDGenerator()
+	if err != nil {
+		return nil, err
+	}
+	transferID := transferIDGen()
+
+	// Claim the key before taking any user locks
+	if idempotencyKey != nil {
+		now := time.Now()
+		idempotencyKey.TransferID = transferID
+		idempotencyKey.CreatedAt = now
+		idempotencyKey.ExpiresAt = now.Add(s.idempotencyKeyTTL)
+
+		if err := s.pgIdempotencyKeyRepo.CreateTx(ctx, tx, idempotencyKey); err != nil {
+			return nil, err
+		}
+	}
+
+	var quote *model.FXQuote
+	if params.QuoteID != "" {
+		quote, err = s.lockQuote(ctx, tx, params.QuoteID, params.Currency)
+		if err != nil {
+			return nil, err
+		}
+		params.Currency = quote.FromCurrency
+	}
+
+	debitCurrency, err := model.LookupCurrency(params.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	creditCurrency, creditAmount := debitCurrency, amount
+	if quote != nil {
+		creditCurrency, err = model.LookupCurrency(quote.ToCurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		creditAmount, err = model.ConvertAmount(amount, quote.Rate, debitCurrency, creditCurrency)
+		if err != nil {
+			return nil, err
+		}
+		if creditAmount <= 0 {
+			return nil, model.ErrInvalidAmount
+		}
+	}
+
+	users, err := s.lockUsers(ctx, tx, fromUserID, toUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromUser, ok := users[fromUserID]
+	if !ok {
+		return nil, model.ErrUserNotFound
+	}
+
+	toUser, ok := users[toUserID]
+	if !ok {
+		return nil, model.ErrUserNotFound
+	}
+
+	if err := fromUser.CheckActive(); err != nil && !(params.Sweep && err == model.ErrAccountFrozen) {
+		return nil, err
+	}
+
+	if err := toUser.CheckActive(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// A card pays in place of the sender's balance
+	var card *model.Card
+	paymentSource := model.PaymentMethodTypeTransfer
+	if params.CardID != "" {
+		card, err = s.pgCardRepo.GetTx(ctx, tx, params.CardID)
+		if err != nil {
+			return nil, err
+		}
+		if card.UserID != fromUser.ID {
+			return nil, model.ErrCardNotFound
+		}
+		if card.Expired(now) {
+			return nil, model.ErrCardExpired
+		}
+		paymentSource = model.PaymentMethodTypeCard
+	}
+
+	fromBalance, ok := fromUser.BalanceIn(debitCurrency.Code)
+	if !ok && card == nil {
+		return nil, model.ErrCurrencyNotHeld
+	}
+
+	if _, ok := toUser.BalanceIn(creditCurrency.Code); !ok {
+		return nil, model.ErrCurrencyNotHeld
+	}
+
+	fee := 0
+	if !params.Sweep {
+		fee, err = s.transferFee(fromUser, debitCurrency.Code, amount, paymentSource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if card == nil && fromBalance.Available() < amount+fee {
+		return nil, model.ErrInsufficientFunds
+	}
+
+	// Sweeps of a closing account are neither limited nor screened
+	var assessment *model.RiskAssessment
+	if !params.Sweep {
+		usage, err := checkLimits(ctx, tx, s.transferLimitRepo, s.pgTransferLimitRepo, fromUser, debitCurrency.Code, amount, params.Bulk, now)
+		if err != nil {
+			return nil, err
+		}
+
+		assessment, err = s.assessRisk(ctx, tx, &model.RiskCheck{
+			TransferID:    transferID,
+			FromUserID:    fromUserID,
+			ToUserID:      toUserID,
+			Amount:        amount,
+			Currency:      debitCurrency.Code,
+			PaymentSource: paymentSource,
+			At:            now,
+			Bulk:          params.Bulk,
+		}, usage)
+		if err != nil {
+			return nil, err
+		}
+	}
+	held := assessment != nil && assessment.Decision == model.RiskDecisionReview
+
+	var stan model.Stan
+	if card != nil {
+		stan, err = s.pgCardRepo.NextStan(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		txIDGen, err := s.pgTransferRepo.GetTransactionIDGenerator()
+		if err != nil {
+			return nil, err
+		}
+		stan = model.Stan(txIDGen())
+	}
+
+	debitTx := &model.Transaction{
+		Stan:            stan,
+		Amount:          amount,
+		Currency:        debitCurrency.Code,
+		Fee:             fee,
+		State:           model.TransactionStatePending,
+		TransactionType: model.TransactionTypeDebit,
+		PaymentSource:   paymentSource,
+		Note:            fmt.Sprintf("Transfer to %s", toUser.Name),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if card != nil {
+		debitTx.AppID = s.terminal.AppID
+		debitTx.ProfileID = s.terminal.ProfileID
+		debitTx.InstanceID = s.terminal.ID
+		debitTx.CardID = card.Ref
+		debitTx.Card = card.MaskedPAN
+		debitTx.CardName = card.HolderName
+		debitTx.CardAcct = card.Token
+		debitTx.BankC = card.BIN()
+		debitTx.Expiry = card.Expiry()
+		debitTx.Note = fmt.Sprintf("Card payment to %s", toUser.Name)
+	}
+
+	creditTx := &model.Transaction{
+		Stan:            stan,
+		Amount:          creditAmount,
+		Currency:        creditCurrency.Code,
+		State:           model.TransactionStatePending,
+		TransactionType: model.TransactionTypeCredit,
+		PaymentSource:   model.PaymentMethodTypeTransfer,
+		Note:            fmt.Sprintf("Transfer from %s", fromUser.Name),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	transfer := &model.Transfer{
+		ID:              transferID,
+		FromUserID:      fromUserID,
+		ToUserID:        toUserID,
+		Amount:          amount,
+		Currency:        debitCurrency.Code,
+		Fee:             fee,
+		State:           model.TransactionStatePending,
+		StandingOrderID: params.StandingOrderID,
+		DebitTx:         debitTx,
+		CreditTx:        creditTx,
+		CreatedAt:       now,
+	}
+
+	if held {
+		transfer.State = model.TransactionStateHeld
+		debitTx.State = model.TransactionStateHeld
+		creditTx.State = model.TransactionStateHeld
+	}
+
+	if quote != nil {
+		transfer.QuoteID = quote.ID
+		for _, t := range []*model.Transaction{debitTx, creditTx} {
+			t.FXRate = model.FormatRate(quote.Rate)
+			t.FXSpreadBps = quote.SpreadBps
+		}
+
+		quote.UsedAt = now
+		if err := s.pgFXQuoteRepo.MarkUsedTx(ctx, tx, quote); err != nil {
+			return nil, err
+		}
+	}
+
+	// The acquirer is asked once the transfer is committed, or approved
+	if card != nil {
+		if err := s.pgTransferRepo.CreateTx(ctx, tx, transfer); err != nil {
+			return nil, err
+		}
+		if held {
+			return transfer, s.createReviewTx(ctx, tx, transfer, assessment, false)
+		}
+		return transfer, nil
+	}
+
+	// A held transfer reserves its funds until it is reviewed
+	if params.Authorize || held {
+		if err := s.pgTransferRepo.CreateTx(ctx, tx, transfer); err != nil {
+			return nil, err
+		}
+
+		expiresAt := now.Add(s.holdTTL)
+		if held {
+			expiresAt = now.Add(s.reviewTTL)
+		}
+
+		err := s.pgHoldRepo.CreateTx(ctx, tx, &model.Hold{
+			TransferID: transfer.ID,
+			UserID:     fromUserID,
+			Currency:   debitCurrency.Code,
+			Amount:     amount + fee,
+			Status:     model.HoldStatusActive,
+			CreatedAt:  now,
+			ExpiresAt:  expiresAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if held {
+			return transfer, s.createReviewTx(ctx, tx, transfer, assessment, params.Authorize)
+		}
+		return transfer, nil
+	}
+
+	transfer.State = model.TransactionStateCompleted
+	transfer.DebitTx.State = model.TransactionStateCompleted
+	transfer.CreditTx.State = model.TransactionStateCompleted
+	transfer.CompletedAt = time.Now()
+	transfer.DebitTx.UpdatedAt = transfer.CompletedAt
+	transfer.CreditTx.UpdatedAt = transfer.CompletedAt
+
+	if err := s.pgTransferRepo.CreateTx(ctx, tx, transfer); err != nil {
+		return nil, err
+	}
+
+	if err := s.pgLedgerRepo.PostTx(ctx, tx, transferJournal(transfer), users); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// CaptureTransfer completes an authorized transfer: the hold is released
+// and the amount and fee are posted to the ledger
+func (s *TransferService) CaptureTransfer(id string) (*model.Transfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transfer *model.Transfer
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var hold *model.Hold
+		var err error
+
+		transfer, hold, err = s.lockAuthorization(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if hold.Expired(now) {
+			return model.ErrAuthorizationExpired
+		}
+
+		return s.captureTx(ctx, tx, transfer, hold, now)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// captureTx releases a locked hold and posts its transfer to the ledger
+func (s *TransferService) captureTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer, hold *model.Hold, now time.Time) error {
+	users, err := s.lockUsers(ctx, tx, transfer.FromUserID, transfer.ToUserID)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := user.CheckActive(); err != nil {
+			return err
+		}
+	}
+
+	hold.Status = model.HoldStatusCaptured
+	hold.ReleasedAt = now
+	if err := s.pgHoldRepo.ReleaseTx(ctx, tx, hold); err != nil {
+		return err
+	}
+
+	transfer.State = model.TransactionStateCompleted
+	transfer.DebitTx.State = model.TransactionStateCompleted
+	transfer.CreditTx.State = model.TransactionStateCompleted
+	transfer.CompletedAt = now
+	transfer.DebitTx.UpdatedAt = now
+	transfer.CreditTx.UpdatedAt = now
+
+	if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+		return err
+	}
+
+	return s.pgLedgerRepo.PostTx(ctx, tx, transferJournal(transfer), users)
+}
+
+// VoidTransfer cancels an authorized transfer and releases its hold
+func (s *TransferService) VoidTransfer(id string) (*model.Transfer, error) {
+	return s.releaseAuthorization(id, model.TransactionStateVoided, model.HoldStatusVoided)
+}
+
+// ExpireHolds voids authorizations that were not captured in time.
+// It returns the number of transfers expired.
+func (s *TransferService) ExpireHolds(limit int) (int, error) {
+	holds, err := s.holdRepo.ListExpired(time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, hold := range holds {
+		_, err := s.releaseAuthorization(hold.TransferID, model.TransactionStateExpired, model.HoldStatusExpired)
+		if err == model.ErrTransferNotPending {
+			// Captured or voided since it was listed
+			continue
+		}
+		if err != nil {
+			return expired, fmt.Errorf("error expiring transfer %s: %w", hold.TransferID, err)
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// releaseAuthorization
+func (s *TransferService) releaseAuthorization(id string, state model.TransactionState, holdStatus model.HoldStatus) (*model.Transfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transfer *model.Transfer
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var hold *model.Hold
+		var err error
+
+		transfer, hold, err = s.lockAuthorization(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		hold.Status = holdStatus
+		hold.ReleasedAt = now
+		if err := s.pgHoldRepo.ReleaseTx(ctx, tx, hold); err != nil {
+			return err
+		}
+
+		transfer.State = state
+		transfer.DebitTx.State = state
+		transfer.CreditTx.State = state
+		transfer.DebitTx.UpdatedAt = now
+		transfer.CreditTx.UpdatedAt = now
+
+		if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+			return err
+		}
+
+		return s.pgTransferLimitRepo.ReleaseUsageTx(ctx, tx, transfer.FromUserID, transfer.Currency, transfer.Amount, transfer.CreatedAt)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// lockAuthorization locks a PENDING transfer and its active hold
+func (s *TransferService) lockAuthorization(ctx context.Context, tx *sqlx.Tx, id string) (*model.Transfer, *model.Hold, error) {
+	transfer, err := s.pgTransferRepo.GetForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if transfer.State != model.TransactionStatePending || transfer.DebitTx == nil || transfer.CreditTx == nil || transfer.CardFunded() {
+		return nil, nil, model.ErrTransferNotPending
+	}
+
+	hold, err := s.pgHoldRepo.GetByTransferIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !hold.Active() {
+		return nil, nil, model.ErrTransferNotPending
+	}
+
+	return transfer, hold, nil
+}
+
+// ReviewDecision
+type ReviewDecision struct {
+	Reviewer string
+	Note     string
+}
+
+// ApproveTransfer lets a transfer held by risk screening go ahead as it would
+// have without the review: a balance transfer is posted, an authorization
+// awaits capture and a card payment is sent to the acquirer
+func (s *TransferService) ApproveTransfer(id string, decision ReviewDecision) (*model.Transfer, error) {
+	if strings.TrimSpace(decision.Reviewer) == "" {
+		return nil, model.ErrInvalidReviewer
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transfer *model.Transfer
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var review *model.RiskReview
+		var err error
+
+		transfer, review, err = s.lockReview(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		review.Status = model.RiskReviewStatusApproved
+		review.Reviewer = strings.TrimSpace(decision.Reviewer)
+		review.Note = decision.Note
+		review.ReviewedAt = now
+		if err := s.pgRiskReviewRepo.UpdateTx(ctx, tx, review); err != nil {
+			return err
+		}
+
+		if transfer.CardFunded() {
+			transfer.State = model.TransactionStatePending
+			transfer.DebitTx.State = model.TransactionStatePending
+			transfer.CreditTx.State = model.TransactionStatePending
+			transfer.DebitTx.UpdatedAt = now
+			transfer.CreditTx.UpdatedAt = now
+			if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+				return err
+			}
+			return s.reviewClosedTx(ctx, tx, transfer)
+		}
+
+		hold, err := s.pgHoldRepo.GetByTransferIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !hold.Active() {
+			return model.ErrTransferNotHeld
+		}
+		if hold.Expired(now) {
+			return model.ErrReviewExpired
+		}
+
+		if !review.Authorize {
+			if err := s.captureTx(ctx, tx, transfer, hold, now); err != nil {
+				return err
+			}
+			return s.reviewClosedTx(ctx, tx, transfer)
+		}
+
+		// The authorization window starts when the review ends
+		hold.ExpiresAt = now.Add(s.holdTTL)
+		if err := s.pgHoldRepo.ExtendTx(ctx, tx, hold); err != nil {
+			return err
+		}
+
+		transfer.State = model.TransactionStatePending
+		transfer.DebitTx.State = model.TransactionStatePending
+		transfer.CreditTx.State = model.TransactionStatePending
+		transfer.DebitTx.UpdatedAt = now
+		transfer.CreditTx.UpdatedAt = now
+		if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+			return err
+		}
+		return s.reviewClosedTx(ctx, tx, transfer)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.CardFunded() {
+		return s.authorizeCard(transfer)
+	}
+
+	return transfer, nil
+}
+
+// RejectTransfer cancels a transfer held by risk screening
+func (s *TransferService) RejectTransfer(id string, decision ReviewDecision) (*model.Transfer, error) {
+	if strings.TrimSpace(decision.Reviewer) == "" {
+		return nil, model.ErrInvalidReviewer
+	}
+
+	return s.closeReview(id, model.RiskReviewStatusRejected, decision)
+}
+
+// ExpireReviews rejects held transfers nobody reviewed in time.
+// It returns the number of transfers expired.
+func (s *TransferService) ExpireReviews(limit int) (int, error) {
+	reviews, err := s.riskReviewRepo.ListExpired(time.Now().Add(-s.reviewTTL), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, review := range reviews {
+		_, err := s.closeReview(review.TransferID, model.RiskReviewStatusExpired, ReviewDecision{})
+		if err == model.ErrTransferNotHeld {
+			// Reviewed since it was listed
+			continue
+		}
+		if err != nil {
+			return expired, fmt.Errorf("error expiring review of transfer %s: %w", review.TransferID, err)
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// closeReview ends the review of a held transfer that will not go ahead,
+// releasing its funds
+func (s *TransferService) closeReview(id string, status model.RiskReviewStatus, decision ReviewDecision) (*model.Transfer, error) {
+	state := model.TransactionStateRejected
+	holdStatus := model.HoldStatusVoided
+	if status == model.RiskReviewStatusExpired {
+		state = model.TransactionStateExpired
+		holdStatus = model.HoldStatusExpired
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transfer *model.Transfer
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var review *model.RiskReview
+		var err error
+
+		transfer, review, err = s.lockReview(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !transfer.CardFunded() {
+			hold, err := s.pgHoldRepo.GetByTransferIDForUpdate(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+
+			hold.Status = holdStatus
+			hold.ReleasedAt = now
+			if err := s.pgHoldRepo.ReleaseTx(ctx, tx, hold); err != nil {
+				return err
+			}
+		}
+
+		transfer.State = state
+		transfer.DebitTx.State = state
+		transfer.CreditTx.State = state
+		transfer.DebitTx.UpdatedAt = now
+		transfer.CreditTx.UpdatedAt = now
+
+		if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+			return err
+		}
+
+		if err := s.pgTransferLimitRepo.ReleaseUsageTx(ctx, tx, transfer.FromUserID, transfer.Currency, transfer.Amount, transfer.CreatedAt); err != nil {
+			return err
+		}
+
+		review.Status = status
+		review.Reviewer = strings.TrimSpace(decision.Reviewer)
+		review.Note = decision.Note
+		review.ReviewedAt = now
+		if err := s.pgRiskReviewRepo.UpdateTx(ctx, tx, review); err != nil {
+			return err
+		}
+
+		return s.reviewClosedTx(ctx, tx, transfer)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
 }
 
-// NewTransferService
-func NewTransferService(
-	userRepo repository.UserRepository,
-	transferRepo repository.TransferRepository,
-	txManager *database.TransactionManager,
-	pgUserRepo *postgresql.UserRepository,
-	pgTransferRepo *postgresql.TransferRepository,
-) *TransferService {
-	return &TransferService{
-		userRepo:       userRepo,
-		transferRepo:   transferRepo,
-		txManager:      txManager,
-		pgUserRepo:     pgUserRepo,
-		pgTransferRepo: pgTransferRepo,
+// AddReviewListener registers a listener for closed reviews. Listeners are
+// added while the application is wired, before any review can close.
+func (s *TransferService) AddReviewListener(listener ReviewListener) {
+	s.reviewListeners = append(s.reviewListeners, listener)
+}
+
+// reviewClosedTx
+func (s *TransferService) reviewClosedTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
+	for _, listener := range s.reviewListeners {
+		if err := listener.ReviewClosedTx(ctx, tx, transfer); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-// CreateTransfer
-func (s *TransferService) CreateTransfer(fromUserID, toUserID string, amount int) (*model.Transfer, error) {
-	if amount <= 0 {
-		return nil, model.ErrInvalidAmount
+// reviewFailure is why a held transfer that will not go ahead failed, or nil
+// when the review let it go ahead
+func reviewFailure(transfer *model.Transfer) error {
+	switch transfer.State {
+	case model.TransactionStateRejected:
+		return model.ErrRejectedInReview
+	case model.TransactionStateExpired:
+		return model.ErrReviewExpired
 	}
+	return nil
+}
 
-	if fromUserID == toUserID {
-		return nil, model.ErrSameAccount
+// lockReview locks a HELD transfer and loads its pending review
+func (s *TransferService) lockReview(ctx context.Context, tx *sqlx.Tx, id string) (*model.Transfer, *model.RiskReview, error) {
+	transfer, err := s.pgTransferRepo.GetForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if transfer.State != model.TransactionStateHeld || transfer.DebitTx == nil || transfer.CreditTx == nil {
+		return nil, nil, model.ErrTransferNotHeld
+	}
+
+	review, err := s.pgRiskReviewRepo.GetByTransferIDTx(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if review.Status != model.RiskReviewStatusPending {
+		return nil, nil, model.ErrTransferNotHeld
+	}
+
+	return transfer, review, nil
+}
+
+const (
+	defaultRiskReviewPageSize = 50
+	maxRiskReviewPageSize     = 200
+)
+
+// ListRiskReviews returns reviews oldest first; an empty status lists all of them
+func (s *TransferService) ListRiskReviews(status model.RiskReviewStatus, limit int) ([]*model.RiskReview, error) {
+	if status != "" && !status.Valid() {
+		return nil, model.ErrInvalidReviewStatus
+	}
+
+	if limit <= 0 {
+		limit = defaultRiskReviewPageSize
+	}
+	if limit > maxRiskReviewPageSize {
+		limit = maxRiskReviewPageSize
+	}
+
+	return s.riskReviewRepo.List(status, limit)
+}
+
+// GetRiskReview
+func (s *TransferService) GetRiskReview(transferID string) (*model.RiskReview, error) {
+	return s.riskReviewRepo.GetByTransferID(transferID)
+}
+
+// ReversalParams
+type ReversalParams struct {
+	// Amount is taken back from the recipient in the credited currency;
+	// zero reverses whatever is left of the transfer
+	Amount int
+	// Force lets the reversal take the recipient's balance below zero
+	Force  bool
+	Reason string
+}
+
+// ReverseTransfer moves funds back from the recipient to the sender as a new
+// transfer linked to the original. Fees are not refunded.
+func (s *TransferService) ReverseTransfer(id string, params ReversalParams) (*model.Transfer, error) {
+	if params.Amount < 0 {
+		return nil, model.ErrInvalidAmount
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var transfer *model.Transfer
+	var reversal *model.Transfer
 
 	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
-		// SELECT FOR UPDATE
-		fromUser, err := s.pgUserRepo.GetForUpdate(ctx, tx, fromUserID)
+		original, err := s.pgTransferRepo.GetForUpdate(ctx, tx, id)
 		if err != nil {
 			return err
 		}
 
-		toUser, err := s.pgUserRepo.GetForUpdate(ctx, tx, toUserID)
+		if !original.Reversible() || original.DebitTx == nil || original.CreditTx == nil {
+			return model.ErrTransferNotReversible
+		}
+
+		remaining := original.RemainingAmount()
+		amount := params.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return model.ErrReversalExceedsAmount
+		}
+
+		// Refund the sender pro rata in the debited currency. Working from the
+		// cumulative reversed amount means the partial refunds add up to
+		// exactly the original debit once the transfer is fully reversed.
+		debited, credited := original.DebitTx.Amount, original.CreditTx.Amount
+		refund := debited*(original.ReversedAmount+amount)/credited - debited*original.ReversedAmount/credited
+		if refund <= 0 {
+			return model.ErrInvalidAmount
+		}
+
+		users, err := s.lockUsers(ctx, tx, original.ToUserID, original.FromUserID)
 		if err != nil {
 			return err
 		}
 
-		if fromUser.Balance < amount {
+		recipient, ok := users[original.ToUserID]
+		if !ok {
+			return model.ErrUserNotFound
+		}
+
+		sender, ok := users[original.FromUserID]
+		if !ok {
+			return model.ErrUserNotFound
+		}
+
+		recipientBalance, ok := recipient.BalanceIn(original.CreditTx.Currency)
+		if !ok {
+			return model.ErrCurrencyNotHeld
+		}
+
+		if _, ok := sender.BalanceIn(original.DebitTx.Currency); !ok {
+			return model.ErrCurrencyNotHeld
+		}
+
+		// Reversals are allowed on frozen accounts, but a closed account must stay empty
+		if recipient.Status == model.UserStatusClosed || sender.Status == model.UserStatusClosed {
+			return model.ErrAccountClosed
+		}
+
+		if recipientBalance.Available() < amount && !params.Force {
 			return model.ErrInsufficientFunds
 		}
 
@@ -80,62 +1094,417 @@ func (s *TransferService) CreateTransfer(fromUserID, toUserID string, amount int
 		}
 
 		now := time.Now()
-		txID := txIDGen()
-		stan := model.Stan(txID)
+		stan := model.Stan(txIDGen())
+
+		note := fmt.Sprintf("Reversal of %s", original.ID)
+		if params.Reason != "" {
+			note = fmt.Sprintf("%s: %s", note, params.Reason)
+		}
 
 		debitTx := &model.Transaction{
 			Stan:            stan,
 			Amount:          amount,
-			State:           model.TransactionStatePending,
+			Currency:        original.CreditTx.Currency,
+			State:           model.TransactionStateCompleted,
 			TransactionType: model.TransactionTypeDebit,
 			PaymentSource:   model.PaymentMethodTypeTransfer,
-			Note:            fmt.Sprintf("Transfer to %s", toUser.Name),
+			Note:            note,
 			CreatedAt:       now,
 			UpdatedAt:       now,
 		}
 
 		creditTx := &model.Transaction{
 			Stan:            stan,
-			Amount:          amount,
-			State:           model.TransactionStatePending,
+			Amount:          refund,
+			Currency:        original.DebitTx.Currency,
+			State:           model.TransactionStateCompleted,
 			TransactionType: model.TransactionTypeCredit,
 			PaymentSource:   model.PaymentMethodTypeTransfer,
-			Note:            fmt.Sprintf("Transfer from %s", fromUser.Name),
+			Note:            note,
 			CreatedAt:       now,
 			UpdatedAt:       now,
 		}
 
-		transfer = &model.Transfer{
-			ID:         transferIDGen(),
-			FromUserID: fromUserID,
-			ToUserID:   toUserID,
-			Amount:     amount,
-			State:      model.TransactionStatePending,
-			DebitTx:    debitTx,
-			CreditTx:   creditTx,
-			CreatedAt:  now,
+		// Unwind at the rate the original was converted at
+		if original.DebitTx.FXRate != "" {
+			rate, err := model.ParseRate(original.DebitTx.FXRate)
+			if err != nil {
+				return err
+			}
+			for _, t := range []*model.Transaction{debitTx, creditTx} {
+				t.FXRate = model.FormatRate(model.InvertRate(rate))
+				t.FXSpreadBps = original.DebitTx.FXSpreadBps
+			}
+		}
+
+		reversal = &model.Transfer{
+			ID:          transferIDGen(),
+			FromUserID:  original.ToUserID,
+			ToUserID:    original.FromUserID,
+			Amount:      amount,
+			Currency:    original.CreditTx.Currency,
+			State:       model.TransactionStateCompleted,
+			ReversalOf:  original.ID,
+			DebitTx:     debitTx,
+			CreditTx:    creditTx,
+			CreatedAt:   now,
+			CompletedAt: now,
+		}
+
+		if err := s.pgTransferRepo.CreateTx(ctx, tx, reversal); err != nil {
+			return err
+		}
+
+		if err := s.pgLedgerRepo.PostTx(ctx, tx, transferJournal(reversal), users); err != nil {
+			return err
+		}
+
+		original.ReversedAmount += amount
+		original.State = model.TransactionStatePartiallyReversed
+		if original.RemainingAmount() == 0 {
+			original.State = model.TransactionStateReversed
+		}
+
+		return s.pgTransferRepo.UpdateReversalTx(ctx, tx, original)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// lockUsers takes the row locks of the given users in ascending ID order, so
+// that transactions locking overlapping sets of users cannot deadlock. Every
+// transaction that changes balances locks its users through here, after any
+// transfer, hold or quote rows it needs. Unknown users, including IDs that are
+// not numbers, are left out of the map.
+func (s *TransferService) lockUsers(ctx context.Context, tx *sqlx.Tx, ids ...string) (map[string]*model.User, error) {
+	type userID struct {
+		id  string
+		num int64
+	}
+
+	unique := make(map[string]bool, len(ids))
+	sorted := make([]userID, 0, len(ids))
+	for _, id := range ids {
+		if unique[id] {
+			continue
+		}
+		unique[id] = true
+
+		num, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		sorted = append(sorted, userID{id: id, num: num})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].num < sorted[j].num })
+
+	users := make(map[string]*model.User, len(sorted))
+	for _, u := range sorted {
+		user, err := s.pgUserRepo.GetForUpdate(ctx, tx, u.id)
+		if err == model.ErrUserNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		users[u.id] = user
+	}
+
+	return users, nil
+}
+
+// transferJournal builds the ledger postings for a completed transfer.
+// The sender, or the card paying for them, pays the fee on top of the
+// amount, into the fee revenue account.
+// A cross-currency transfer passes through the FX position account so
+// that each currency balances on its own.
+func transferJournal(t *model.Transfer) *model.Journal {
+	journal := &model.Journal{
+		TransferID:  t.ID,
+		Description: fmt.Sprintf("Transfer %s", t.ID),
+		CreatedAt:   t.CompletedAt,
+	}
+
+	if t.ReversalOf != "" {
+		journal.Description = fmt.Sprintf("Reversal %s of %s", t.ID, t.ReversalOf)
+	}
+
+	from := model.UserAccount(t.FromUserID)
+	to := model.UserAccount(t.ToUserID)
+
+	// The acquirer owes a card payment, amount and fee, to the settlement account
+	if t.CardFunded() {
+		from = model.AccountSettlement
+	}
+
+	journal.Post(from, t.DebitTx.Currency, -(t.DebitTx.Amount + t.Fee))
+	if t.Fee > 0 {
+		journal.Post(model.AccountFeeRevenue, t.DebitTx.Currency, t.Fee)
+	}
+
+	if t.DebitTx.Currency == t.CreditTx.Currency {
+		journal.Post(to, t.CreditTx.Currency, t.CreditTx.Amount)
+		return journal
+	}
+
+	journal.Post(model.AccountFXPosition, t.DebitTx.Currency, t.DebitTx.Amount)
+	journal.Post(model.AccountFXPosition, t.CreditTx.Currency, -t.CreditTx.Amount)
+	journal.Post(to, t.CreditTx.Currency, t.CreditTx.Amount)
+	return journal
+}
+
+// transferFee applies the fee schedule matching the sender's tier and the payment method.
+// Currencies without a schedule are free.
+func (s *TransferService) transferFee(sender *model.User, currency string, amount int, method model.PaymentMethodType) (int, error) {
+	schedules, err := s.feeScheduleRepo.ListByCurrency(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	schedule, ok := model.SelectFeeSchedule(schedules, sender.Tier, method)
+	if !ok {
+		return 0, nil
+	}
+
+	return schedule.Compute(amount), nil
+}
+
+// checkLimits counts an outgoing payment, a transfer or a withdrawal, in the
+// sender's usage and checks it against the limit for their tier in the
+// currency. Bulk items count towards the amounts only: the transfers per hour
+// limit paces what the sender does by hand, not a batch or a standing order.
+// The usage rows are written while the sender is locked, so a concurrent
+// payment by the same sender fails to serialize and is retried against the new
+// totals.
+func checkLimits(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	transferLimitRepo repository.TransferLimitRepository,
+	pgTransferLimitRepo *postgresql.TransferLimitRepository,
+	sender *model.User,
+	currency string,
+	amount int,
+	bulk bool,
+	now time.Time,
+) ([]*model.LimitUsage, error) {
+	limits, err := transferLimitRepo.ListByCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, ok := model.SelectTransferLimit(limits, sender.ID, sender.Tier)
+	if ok {
+		if err := limit.CheckSingle(amount); err != nil {
+			return nil, err
+		}
+	}
+
+	// Usage is kept without a limit too, so that a limit added later sees it
+	count := 1
+	if bulk {
+		count = 0
+	}
+
+	usage, err := pgTransferLimitRepo.AddUsageTx(ctx, tx, sender.ID, currency, amount, count, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		for _, u := range usage {
+			if bulk && u.Period == model.LimitPeriodHour {
+				continue
+			}
+			if err := limit.CheckUsage(u); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// assessRisk asks the risk engine about a transfer that passed every other
+// check. A denied transfer is logged with the engine's reasons, which are not
+// shown to the sender.
+func (s *TransferService) assessRisk(ctx context.Context, tx *sqlx.Tx, check *model.RiskCheck, usage []*model.LimitUsage) (*model.RiskAssessment, error) {
+	paid, err := s.pgTransferRepo.HasPaidTx(ctx, tx, check.FromUserID, check.ToUserID)
+	if err != nil {
+		return nil, err
+	}
+	check.NewPayee = !paid
+
+	for _, u := range usage {
+		switch u.Period {
+		case model.LimitPeriodHour:
+			check.TransfersThisHour = u.Count
+		case model.LimitPeriodDay:
+			check.AmountToday = u.Amount
 		}
+	}
+
+	assessment, err := s.riskEngine.Assess(ctx, check)
+	if err != nil {
+		return nil, fmt.Errorf("error assessing transfer risk: %w", err)
+	}
+
+	if assessment.Decision == model.RiskDecisionDeny {
+		log.Printf("Transfer %s from %s to %s denied by risk screening: %s",
+			check.TransferID, check.FromUserID, check.ToUserID, strings.Join(assessment.Reasons, "; "))
+		return nil, model.ErrTransferDenied
+	}
+
+	return assessment, nil
+}
+
+// createReviewTx records why a transfer is held; authorize makes it an
+// authorization once approved
+func (s *TransferService) createReviewTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer, assessment *model.RiskAssessment, authorize bool) error {
+	return s.pgRiskReviewRepo.CreateTx(ctx, tx, &model.RiskReview{
+		TransferID: transfer.ID,
+		Status:     model.RiskReviewStatusPending,
+		Reasons:    assessment.Reasons,
+		Authorize:  authorize,
+		CreatedAt:  transfer.CreatedAt,
+	})
+}
+
+// GetLimits returns the limit that applies to the user in the currency, if any,
+// and what they have used of it
+func (s *TransferService) GetLimits(userID, currency string) (*model.TransferLimit, []*model.LimitUsage, error) {
+	c, err := model.LookupCurrency(currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	currency = c.Code
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limits, err := s.transferLimitRepo.ListByCurrency(currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit, _ := model.SelectTransferLimit(limits, user.ID, user.Tier)
+
+	usage, err := s.transferLimitRepo.GetUsage(user.ID, currency, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return limit, usage, nil
+}
+
+// lockQuote locks the quote for the rest of the transaction and checks it can still be used
+func (s *TransferService) lockQuote(ctx context.Context, tx *sqlx.Tx, quoteID, currency string) (*model.FXQuote, error) {
+	quote, err := s.pgFXQuoteRepo.GetForUpdate(ctx, tx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.Used() {
+		return nil, model.ErrQuoteAlreadyUsed
+	}
+
+	if quote.Expired(time.Now()) {
+		return nil, model.ErrQuoteExpired
+	}
+
+	if currency != "" && !strings.EqualFold(currency, quote.FromCurrency) {
+		return nil, model.ErrQuoteCurrencyMismatch
+	}
+
+	return quote, nil
+}
+
+// authorizeCard asks the acquirer to charge the card of a PENDING card-funded
+// transfer and applies the answer. If the acquirer cannot be reached the
+// transfer stays PENDING and is sent again by ProcessPendingCardTransfers
+// with the same STAN, by which the acquirer recognises it.
+func (s *TransferService) authorizeCard(transfer *model.Transfer) (*model.Transfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.acquirerTimeout)
+	defer cancel()
+
+	debitTx := transfer.DebitTx
+	result, err := s.acquirer.Authorize(ctx, &model.CardAuthorization{
+		TerminalID:   debitTx.InstanceID,
+		ProfileID:    debitTx.ProfileID,
+		Stan:         debitTx.Stan,
+		BusinessDate: model.BusinessDate(debitTx.CreatedAt),
+		Token:        debitTx.CardAcct,
+		Amount:       debitTx.Amount + transfer.Fee,
+		Currency:     debitTx.Currency,
+	})
+	if err != nil {
+		log.Printf("Error authorizing card %s for transfer %s: %v", debitTx.Card, transfer.ID, err)
+		return transfer, nil
+	}
+
+	return s.applyCardResult(transfer.ID, result)
+}
+
+// applyCardResult completes a PENDING card-funded transfer the acquirer
+// approved, or fails it. Transfers settled in the meantime are returned unchanged.
+func (s *TransferService) applyCardResult(id string, result *model.CardAuthorizationResult) (*model.Transfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-		fromUser.Balance -= amount
-		toUser.Balance += amount
+	var transfer *model.Transfer
 
-		if err := s.pgUserRepo.UpdateTx(ctx, tx, fromUser); err != nil {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		transfer, err = s.pgTransferRepo.GetForUpdate(ctx, tx, id)
+		if err != nil {
 			return err
 		}
 
-		if err := s.pgUserRepo.UpdateTx(ctx, tx, toUser); err != nil {
+		if transfer.State != model.TransactionStatePending || !transfer.CardFunded() || transfer.CreditTx == nil {
+			return nil
+		}
+
+		now := time.Now()
+		transfer.DebitTx.AuthCode = result.AuthCode
+		transfer.DebitTx.ResponseCode = result.ResponseCode
+		transfer.DebitTx.UpdatedAt = now
+		transfer.CreditTx.UpdatedAt = now
+
+		if !result.Approved {
+			log.Printf("Card %s declined for transfer %s: %s %s", transfer.DebitTx.Card, transfer.ID, result.ResponseCode, result.Reason)
+
+			transfer.State = model.TransactionStateFailed
+			transfer.DebitTx.State = model.TransactionStateFailed
+			transfer.CreditTx.State = model.TransactionStateFailed
+			if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+				return err
+			}
+
+			return s.pgTransferLimitRepo.ReleaseUsageTx(ctx, tx, transfer.FromUserID, transfer.Currency, transfer.Amount, transfer.CreatedAt)
+		}
+
+		users, err := s.lockUsers(ctx, tx, transfer.FromUserID, transfer.ToUserID)
+		if err != nil {
 			return err
 		}
 
 		transfer.State = model.TransactionStateCompleted
 		transfer.DebitTx.State = model.TransactionStateCompleted
 		transfer.CreditTx.State = model.TransactionStateCompleted
-		transfer.CompletedAt = time.Now()
-		transfer.DebitTx.UpdatedAt = transfer.CompletedAt
-		transfer.CreditTx.UpdatedAt = transfer.CompletedAt
+		transfer.CompletedAt = now
 
-		return s.pgTransferRepo.CreateTx(ctx, tx, transfer)
-	})
+		if err := s.pgTransferRepo.UpdateStateTx(ctx, tx, transfer); err != nil {
+			return err
+		}
+
+		return s.pgLedgerRepo.PostTx(ctx, tx, transferJournal(transfer), users)
+	}, s.txManager.WithRetry())
 
 	if err != nil {
 		return nil, err
@@ -144,22 +1513,79 @@ func (s *TransferService) CreateTransfer(fromUserID, toUserID string, amount int
 	return transfer, nil
 }
 
+// ProcessPendingCardTransfers sends card-funded transfers the acquirer never
+// answered again. Transfers created within the acquirer timeout may still be
+// in flight and are left alone. It returns the number of transfers settled.
+func (s *TransferService) ProcessPendingCardTransfers(limit int) (int, error) {
+	ids, err := s.transferRepo.ListPendingCard(time.Now().Add(-s.acquirerTimeout), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, id := range ids {
+		transfer, err := s.transferRepo.GetByID(id)
+		if err != nil {
+			return settled, err
+		}
+
+		transfer, err = s.authorizeCard(transfer)
+		if err != nil {
+			return settled, fmt.Errorf("error processing card transfer %s: %w", id, err)
+		}
+
+		if transfer.State != model.TransactionStatePending {
+			settled++
+		}
+	}
+
+	return settled, nil
+}
+
 // GetTransfer
 func (s *TransferService) GetTransfer(id string) (*model.Transfer, error) {
 	return s.transferRepo.GetByID(id)
 }
 
-// ListTransfers
-func (s *TransferService) ListTransfers() ([]*model.Transfer, error) {
-	return s.transferRepo.List()
-}
+const (
+	defaultTransferPageSize = 50
+	maxTransferPageSize     = 200
+)
 
-// ListUsers
-func (s *TransferService) ListUsers() ([]*model.User, error) {
-	return s.userRepo.List()
-}
+// ListTransfers returns one page of transfers matching the filter
+func (s *TransferService) ListTransfers(filter repository.ListFilter) (*repository.TransferPage, error) {
+	switch filter.Direction {
+	case "":
+	case repository.DirectionIncoming, repository.DirectionOutgoing:
+		if filter.UserID == "" {
+			return nil, model.ErrInvalidFilter
+		}
+	default:
+		return nil, model.ErrInvalidFilter
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = repository.SortByCreatedAt
+	case repository.SortByCreatedAt, repository.SortByAmount:
+	default:
+		return nil, model.ErrInvalidFilter
+	}
+
+	if filter.MinAmount < 0 || filter.MaxAmount < 0 || (filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount) {
+		return nil, model.ErrInvalidFilter
+	}
+
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return nil, model.ErrInvalidFilter
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTransferPageSize
+	}
+	if filter.Limit > maxTransferPageSize {
+		filter.Limit = maxTransferPageSize
+	}
 
-// UserByID
-func (s *TransferService) UserByID(id string) (*model.User, error) {
-	return s.userRepo.GetByID(id)
+	return s.transferRepo.List(filter)
 }
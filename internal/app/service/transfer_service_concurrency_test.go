@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+)
+
+// TestOpposingTransfers runs A→B and B→A transfers at the same time. GBP has
+// no fee schedule or limit, so every transfer must complete and the two
+// balances must still add up to what they started with.
+func TestOpposingTransfers(t *testing.T) {
+	env := newTestEnv(t)
+
+	const (
+		workers   = 8
+		transfers = 25
+		opening   = 100000
+	)
+
+	a := env.createUser(t, map[string]int{"GBP": opening})
+	b := env.createUser(t, map[string]int{"GBP": opening})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*transfers)
+	for w := 0; w < workers; w++ {
+		from, to := a.ID, b.ID
+		if w%2 == 1 {
+			from, to = b.ID, a.ID
+		}
+
+		wg.Add(1)
+		go func(from, to string, amount int) {
+			defer wg.Done()
+			for i := 0; i < transfers; i++ {
+				_, err := env.transferService.CreateTransfer(service.TransferParams{
+					FromUserID: from,
+					ToUserID:   to,
+					Amount:     amount,
+					Currency:   "GBP",
+				})
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(from, to, 100+w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if database.IsRetryable(err) {
+			t.Errorf("deadlock or serialization failure reached the caller: %v", err)
+		} else {
+			t.Errorf("transfer failed: %v", err)
+		}
+	}
+
+	total := env.balance(t, a.ID, "GBP") + env.balance(t, b.ID, "GBP")
+	if total != 2*opening {
+		t.Errorf("total balance = %d, want %d", total, 2*opening)
+	}
+}
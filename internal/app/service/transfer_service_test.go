@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/http/handler"
+)
+
+// TestIdempotentResponse stores the first response given for a key and
+// checks that retries get it back unchanged, whatever the transfer's state
+func TestIdempotentResponse(t *testing.T) {
+	env := newTestEnv(t)
+
+	a := env.createUser(t, map[string]int{"GBP": 10000})
+	b := env.createUser(t, map[string]int{"GBP": 0})
+	key := "test:" + t.Name() + ":" + a.ID
+	params := service.TransferParams{FromUserID: a.ID, ToUserID: b.ID, Amount: 1000, Currency: "GBP"}
+
+	if _, err := env.transferService.IdempotentResponse(key, "hash"); err != model.ErrIdempotencyKeyNotFound {
+		t.Fatalf("IdempotentResponse of an unused key error = %v, want %v", err, model.ErrIdempotencyKeyNotFound)
+	}
+
+	transfer, replayed, err := env.transferService.CreateTransferIdempotent(key, "hash", params)
+	if err != nil || replayed {
+		t.Fatalf("CreateTransferIdempotent = %v, replayed %t", err, replayed)
+	}
+
+	// Until the response is stored the key has none to give
+	if _, err := env.transferService.IdempotentResponse(key, "hash"); err != model.ErrIdempotencyKeyNotFound {
+		t.Fatalf("IdempotentResponse before saving error = %v, want %v", err, model.ErrIdempotencyKeyNotFound)
+	}
+
+	first := &model.IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":"` + transfer.ID + `","state":"COMPLETED"}` + "\n")}
+	if err := env.transferService.SaveIdempotentResponse(key, first); err != nil {
+		t.Fatalf("SaveIdempotentResponse: %v", err)
+	}
+
+	// Only the first response is kept
+	if err := env.transferService.SaveIdempotentResponse(key, &model.IdempotentResponse{StatusCode: 202, Body: []byte("{}")}); err != nil {
+		t.Fatalf("SaveIdempotentResponse again: %v", err)
+	}
+
+	got, err := env.transferService.IdempotentResponse(key, "hash")
+	if err != nil {
+		t.Fatalf("IdempotentResponse: %v", err)
+	}
+	if got.StatusCode != first.StatusCode || !bytes.Equal(got.Body, first.Body) {
+		t.Errorf("IdempotentResponse = %d %s, want %d %s", got.StatusCode, got.Body, first.StatusCode, first.Body)
+	}
+
+	if _, err := env.transferService.IdempotentResponse(key, "other hash"); err != model.ErrIdempotencyKeyMismatch {
+		t.Errorf("IdempotentResponse with another request error = %v, want %v", err, model.ErrIdempotencyKeyMismatch)
+	}
+
+	if got := env.balance(t, a.ID, "GBP"); got != 9000 {
+		t.Errorf("sender balance = %d, want 9000", got)
+	}
+}
+
+// TestListTransfersPages pages through a user's transfers with ties on the
+// sort key and a transfer made between pages; every transfer there was when
+// paging started must come up exactly once, in order
+func TestListTransfersPages(t *testing.T) {
+	env := newTestEnv(t)
+
+	tests := []struct {
+		name       string
+		sortBy     repository.TransferSortField
+		descending bool
+	}{
+		{"newest first", repository.SortByCreatedAt, true},
+		{"smallest first", repository.SortByAmount, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payer := env.createUser(t, map[string]int{"GBP": 100000})
+			payee := env.createUser(t, map[string]int{"GBP": 0})
+
+			transfer := func(amount int) *model.Transfer {
+				t.Helper()
+
+				transfer, err := env.transferService.CreateTransfer(service.TransferParams{
+					FromUserID: payer.ID, ToUserID: payee.ID, Amount: amount, Currency: "GBP",
+				})
+				if err != nil {
+					t.Fatalf("CreateTransfer: %v", err)
+				}
+				return transfer
+			}
+
+			var want []*model.Transfer
+			for _, amount := range []int{300, 100, 200, 100, 300, 100, 200} {
+				want = append(want, transfer(amount))
+			}
+			if tt.sortBy == repository.SortByAmount {
+				// Equal amounts follow in the order they were made
+				sort.SliceStable(want, func(i, j int) bool { return want[i].Amount < want[j].Amount })
+			}
+			if tt.descending {
+				slices.Reverse(want)
+			}
+
+			filter := repository.ListFilter{
+				UserID:     payer.ID,
+				Direction:  repository.DirectionOutgoing,
+				SortBy:     tt.sortBy,
+				Descending: tt.descending,
+				Limit:      3,
+			}
+
+			var got []*model.Transfer
+			for pages := 1; ; pages++ {
+				page, err := env.transferService.ListTransfers(filter)
+				if err != nil {
+					t.Fatalf("ListTransfers page %d: %v", pages, err)
+				}
+				got = append(got, page.Transfers...)
+
+				if pages == 1 {
+					// The newest and smallest transfer sorts before the
+					// cursor, so it must not show up on later pages
+					transfer(50)
+				}
+
+				if page.NextCursor == "" {
+					break
+				}
+				filter.Cursor = page.NextCursor
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("%d transfers listed, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if got[i].ID != want[i].ID {
+					t.Errorf("transfer %d = %s for %d, want %s for %d", i, got[i].ID, got[i].Amount, want[i].ID, want[i].Amount)
+				}
+			}
+		})
+	}
+}
+
+// TestListTransfersInvalidCursor sends cursors that were never handed out,
+// or were handed out for another sort order, which are the client's mistake
+func TestListTransfersInvalidCursor(t *testing.T) {
+	env := newTestEnv(t)
+	controller := handler.NewTransferController(env.transferService, nil)
+
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		query  string
+		cursor string
+	}{
+		{"not base64", "", "not a cursor!"},
+		{"not JSON", "", encode("k=1&id=1")},
+		{"no key", "", encode(`{"id":42}`)},
+		{"key not a time", "", encode(`{"k":"yesterday","id":42}`)},
+		{"key not an amount", "&sort=amount", encode(`{"k":"2024-04-10T12:34:56Z","id":42}`)},
+		{"amount out of range", "&sort=amount", encode(`{"k":"99999999999999999999","id":42}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/transfers?cursor="+url.QueryEscape(tt.cursor)+tt.query, nil)
+			w := httptest.NewRecorder()
+			controller.ListTransfersHandler(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+		})
+	}
+}
+
+// TestTransferToInvalidUserID locks a user whose ID is not a number, which
+// cannot exist
+func TestTransferToInvalidUserID(t *testing.T) {
+	env := newTestEnv(t)
+
+	payer := env.createUser(t, map[string]int{"GBP": 10000})
+
+	for _, to := range []string{"abc", "1e3", "99999999999999999999"} {
+		_, err := env.transferService.CreateTransfer(service.TransferParams{
+			FromUserID: payer.ID, ToUserID: to, Amount: 1000, Currency: "GBP",
+		})
+		if err != model.ErrUserNotFound {
+			t.Errorf("CreateTransfer to %q error = %v, want %v", to, err, model.ErrUserNotFound)
+		}
+	}
+
+	if got := env.balance(t, payer.ID, "GBP"); got != 10000 {
+		t.Errorf("payer balance = %d, want 10000", got)
+	}
+}
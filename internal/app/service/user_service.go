@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/repository/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxUserNameLength matches the users.name column
+const maxUserNameLength = 100
+
+// UserService
+type UserService struct {
+	userRepo        repository.UserRepository
+	transferService *TransferService
+	txManager       *database.TransactionManager
+	pgUserRepo      *postgresql.UserRepository
+	pgFundingRepo   *postgresql.FundingRepository
+}
+
+// NewUserService
+func NewUserService(
+	userRepo repository.UserRepository,
+	transferService *TransferService,
+	txManager *database.TransactionManager,
+	pgUserRepo *postgresql.UserRepository,
+	pgFundingRepo *postgresql.FundingRepository,
+) *UserService {
+	return &UserService{
+		userRepo:        userRepo,
+		transferService: transferService,
+		txManager:       txManager,
+		pgUserRepo:      pgUserRepo,
+		pgFundingRepo:   pgFundingRepo,
+	}
+}
+
+// CreateUserParams
+type CreateUserParams struct {
+	Name string
+	// Tier defaults to STANDARD
+	Tier model.AccountTier
+	// Currencies the account holds, each opened with a zero balance;
+	// defaults to USD
+	Currencies []string
+}
+
+// UpdateUserParams; nil fields are left unchanged
+type UpdateUserParams struct {
+	Name   *string
+	Tier   *model.AccountTier
+	Status *model.UserStatus
+	// SweepTo closes an account that still holds funds by transferring each
+	// balance to this user first. Only valid together with Status CLOSED.
+	SweepTo string
+}
+
+// CreateUser opens an ACTIVE account
+func (s *UserService) CreateUser(params CreateUserParams) (*model.User, error) {
+	name, err := validUserName(params.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tier := params.Tier
+	if tier == "" {
+		tier = model.AccountTierStandard
+	}
+	if !tier.Valid() {
+		return nil, model.ErrInvalidAccountTier
+	}
+
+	currencies := params.Currencies
+	if len(currencies) == 0 {
+		currencies = []string{model.DefaultCurrency}
+	}
+
+	user := &model.User{
+		Name:   name,
+		Tier:   tier,
+		Status: model.UserStatusActive,
+	}
+
+	held := make(map[string]bool, len(currencies))
+	for _, code := range currencies {
+		currency, err := model.LookupCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		if held[currency.Code] {
+			continue
+		}
+		held[currency.Code] = true
+		user.Balances = append(user.Balances, &model.Balance{Currency: currency.Code})
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser changes the user's details or status. Closing an account
+// requires it to be empty, or SweepTo to move its funds out in the same
+// transaction. A closed account cannot be changed any more.
+func (s *UserService) UpdateUser(id string, params UpdateUserParams) (*model.User, error) {
+	closing := params.Status != nil && *params.Status == model.UserStatusClosed
+	if params.SweepTo != "" && !closing {
+		return nil, model.ErrInvalidSweep
+	}
+
+	if params.Name != nil {
+		name, err := validUserName(*params.Name)
+		if err != nil {
+			return nil, err
+		}
+		params.Name = &name
+	}
+
+	if params.Tier != nil && !params.Tier.Valid() {
+		return nil, model.ErrInvalidAccountTier
+	}
+
+	if params.Status != nil && !params.Status.Valid() {
+		return nil, model.ErrInvalidUserStatus
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		ids := []string{id}
+		if params.SweepTo != "" {
+			ids = append(ids, params.SweepTo)
+		}
+
+		users, err := s.transferService.lockUsers(ctx, tx, ids...)
+		if err != nil {
+			return err
+		}
+
+		user, ok := users[id]
+		if !ok {
+			return model.ErrUserNotFound
+		}
+
+		if user.Status == model.UserStatusClosed {
+			return model.ErrAccountClosed
+		}
+
+		if params.Name != nil {
+			user.Name = *params.Name
+		}
+
+		if params.Tier != nil {
+			user.Tier = *params.Tier
+		}
+
+		if params.Status != nil && *params.Status != user.Status {
+			if !user.Status.CanChangeTo(*params.Status) {
+				return model.ErrInvalidUserStatus
+			}
+
+			if closing {
+				pending, err := s.pgFundingRepo.HasPendingTx(ctx, tx, user.ID)
+				if err != nil {
+					return err
+				}
+				if !pending {
+					pending, err = s.transferService.pgTransferRepo.HasPendingCardTx(ctx, tx, user.ID)
+					if err != nil {
+						return err
+					}
+				}
+				if pending {
+					return model.ErrAccountNotEmpty
+				}
+			}
+
+			if closing && params.SweepTo != "" {
+				if err := s.sweepTx(ctx, tx, user, params.SweepTo); err != nil {
+					return err
+				}
+			} else if closing && !user.Empty() {
+				return model.ErrAccountNotEmpty
+			}
+
+			user.Status = *params.Status
+		}
+
+		return s.pgUserRepo.UpdateTx(ctx, tx, user)
+	}, s.txManager.WithRetry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(id)
+}
+
+// sweepTx transfers every balance of the user to the target account,
+// without fees. Funds held by pending authorizations cannot be swept.
+func (s *UserService) sweepTx(ctx context.Context, tx *sqlx.Tx, user *model.User, targetID string) error {
+	for _, b := range user.Balances {
+		if b.Held != 0 || b.Amount < 0 {
+			return model.ErrAccountNotEmpty
+		}
+	}
+
+	for _, b := range user.Balances {
+		if b.Amount == 0 {
+			continue
+		}
+
+		_, err := s.transferService.createTransferTx(ctx, tx, TransferParams{
+			FromUserID: user.ID,
+			ToUserID:   targetID,
+			Amount:     b.Amount,
+			Currency:   b.Currency,
+			Sweep:      true,
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListUsers
+func (s *UserService) ListUsers() ([]*model.User, error) {
+	return s.userRepo.List()
+}
+
+// UserByID
+func (s *UserService) UserByID(id string) (*model.User, error) {
+	return s.userRepo.GetByID(id)
+}
+
+// validUserName
+func validUserName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxUserNameLength {
+		return "", model.ErrInvalidUserName
+	}
+	return name, nil
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/webhook"
+)
+
+const (
+	defaultDeliveryPageSize = 50
+	maxDeliveryPageSize     = 200
+
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 100
+)
+
+// WebhookService manages webhook subscriptions and delivers outbox events to
+// them. It is a Publisher: the outbox processor hands it every event, and it
+// queues a delivery for each matching subscription.
+type WebhookService struct {
+	webhookRepo          repository.WebhookRepository
+	sender               repository.WebhookSender
+	timeout              time.Duration
+	allowPrivateNetworks bool
+	retry                database.RetryPolicy
+}
+
+// NewWebhookService
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	sender repository.WebhookSender,
+	timeout time.Duration,
+	allowPrivateNetworks bool,
+	retry database.RetryPolicy,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo:          webhookRepo,
+		sender:               sender,
+		timeout:              timeout,
+		allowPrivateNetworks: allowPrivateNetworks,
+		retry:                retry,
+	}
+}
+
+// CreateWebhookParams
+type CreateWebhookParams struct {
+	URL        string
+	EventTypes []string
+	// Secret signs the deliveries; one is generated when it is empty
+	Secret string
+}
+
+// UpdateWebhookParams changes the fields that are set
+type UpdateWebhookParams struct {
+	URL        *string
+	EventTypes []string
+	Secret     *string
+	Active     *bool
+}
+
+// CreateWebhook
+func (s *WebhookService) CreateWebhook(params CreateWebhookParams) (*model.WebhookSubscription, error) {
+	webhookURL, err := s.validWebhookURL(params.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := validEventTypes(params.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := params.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if err := validWebhookSecret(secret); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription := &model.WebhookSubscription{
+		URL:        webhookURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.webhookRepo.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// GetWebhook
+func (s *WebhookService) GetWebhook(id string) (*model.WebhookSubscription, error) {
+	return s.webhookRepo.GetSubscription(id)
+}
+
+// ListWebhooks
+func (s *WebhookService) ListWebhooks() ([]*model.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptions()
+}
+
+// UpdateWebhook
+func (s *WebhookService) UpdateWebhook(id string, params UpdateWebhookParams) (*model.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.URL != nil {
+		if subscription.URL, err = s.validWebhookURL(*params.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.EventTypes != nil {
+		if subscription.EventTypes, err = validEventTypes(params.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Secret != nil {
+		if err := validWebhookSecret(*params.Secret); err != nil {
+			return nil, err
+		}
+		subscription.Secret = *params.Secret
+	}
+
+	if params.Active != nil {
+		subscription.Active = *params.Active
+	}
+
+	subscription.UpdatedAt = time.Now()
+	if err := s.webhookRepo.UpdateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// DeleteWebhook
+func (s *WebhookService) DeleteWebhook(id string) error {
+	return s.webhookRepo.DeleteSubscription(id)
+}
+
+// ListDeliveries returns a subscription's deliveries newest first
+func (s *WebhookService) ListDeliveries(subscriptionID string, status model.WebhookDeliveryStatus, limit int) ([]*model.WebhookDelivery, error) {
+	if status != "" && !status.Valid() {
+		return nil, model.ErrInvalidDeliveryStatus
+	}
+
+	if _, err := s.webhookRepo.GetSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveryPageSize
+	}
+	if limit > maxDeliveryPageSize {
+		limit = maxDeliveryPageSize
+	}
+
+	return s.webhookRepo.ListDeliveries(subscriptionID, status, limit)
+}
+
+// Redeliver sends a delivery again on the next dispatch, whatever happened
+// to it before, with a fresh set of attempts
+func (s *WebhookService) Redeliver(subscriptionID, deliveryID string) (*model.WebhookDelivery, error) {
+	if err := s.webhookRepo.Redeliver(subscriptionID, deliveryID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.GetDelivery(subscriptionID, deliveryID)
+}
+
+// Publish queues the event for every active subscription it matches
+func (s *WebhookService) Publish(ctx context.Context, event *model.Event) error {
+	subscriptions, err := s.webhookRepo.ListSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, subscription := range subscriptions {
+		if subscription.Active && subscription.Matches(event.EventType) {
+			ids = append(ids, subscription.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return s.webhookRepo.CreateDeliveries(event, ids)
+}
+
+// Close
+func (s *WebhookService) Close() error {
+	return nil
+}
+
+// DeliverDue makes one attempt at up to limit due deliveries, in parallel.
+// A delivery is leased while it is being sent, so a crashed worker's
+// deliveries are picked up again once the lease runs out.
+func (s *WebhookService) DeliverDue(limit int) (int, error) {
+	now := time.Now()
+	deliveries, err := s.webhookRepo.ClaimDue(now, now.Add(2*s.timeout+time.Minute), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *model.WebhookDelivery) {
+			defer wg.Done()
+			if err := s.deliver(delivery); err != nil {
+				log.Printf("Error delivering webhook %s: %v", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// deliver sends a claimed delivery and records the attempt. A failed
+// delivery is retried with backoff until it has used up its attempts.
+func (s *WebhookService) deliver(delivery *model.WebhookDelivery) error {
+	subscription, err := s.webhookRepo.GetSubscription(delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	event, err := s.webhookRepo.GetEvent(delivery.EventID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	attempt := s.sender.Send(ctx, subscription, delivery, event)
+
+	delivery.Attempts++
+	delivery.LastError = attempt.Error
+
+	switch {
+	case attempt.Success:
+		delivery.Status = model.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = attempt.AttemptedAt
+	case delivery.Attempts >= s.retry.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		log.Printf("Webhook delivery %s to %s failed %d times, giving up: %s",
+			delivery.ID, subscription.ID, delivery.Attempts, attempt.Error)
+	default:
+		delivery.NextAttemptAt = time.Now().Add(webhookBackoff(s.retry, delivery.Attempts))
+	}
+
+	return s.webhookRepo.RecordAttempt(delivery, attempt)
+}
+
+// webhookBackoff returns BaseDelay*2^(attempts-1), capped at MaxDelay
+func webhookBackoff(retry database.RetryPolicy, attempts int) time.Duration {
+	delay := retry.BaseDelay << (attempts - 1)
+	if delay <= 0 || delay > retry.MaxDelay {
+		delay = retry.MaxDelay
+	}
+	return delay
+}
+
+// validWebhookURL rejects URLs whose host is an address, or a localhost name,
+// that the sender would refuse to dial. Other names are only checked when
+// they are dialled, since what they resolve to can change.
+func (s *WebhookService) validWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", model.ErrInvalidWebhookURL
+	}
+
+	if s.allowPrivateNetworks {
+		return raw, nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", model.ErrWebhookURLNotPublic
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !webhook.PublicAddress(addr) {
+		return "", model.ErrWebhookURLNotPublic
+	}
+
+	return raw, nil
+}
+
+// validEventTypes accepts known event types, "*" and prefixes ending in "*"
+// that match at least one of them
+func validEventTypes(eventTypes []string) ([]string, error) {
+	var valid []string
+	for _, pattern := range eventTypes {
+		pattern = strings.TrimSpace(pattern)
+		if strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			return nil, model.ErrInvalidEventTypes
+		}
+
+		known := false
+		for _, eventType := range event.Types() {
+			if model.MatchEventType(pattern, eventType) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, model.ErrInvalidEventTypes
+		}
+
+		if !slices.Contains(valid, pattern) {
+			valid = append(valid, pattern)
+		}
+	}
+
+	if len(valid) == 0 {
+		return nil, model.ErrInvalidEventTypes
+	}
+	return valid, nil
+}
+
+// validWebhookSecret
+func validWebhookSecret(secret string) error {
+	if len(secret) < minWebhookSecretLength || len(secret) > maxWebhookSecretLength {
+		return model.ErrInvalidWebhookSecret
+	}
+	return nil
+}
+
+// newWebhookSecret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
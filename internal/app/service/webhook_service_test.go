@@ -0,0 +1,41 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/infra/database"
+)
+
+// TestCreateWebhookRejectsURL checks URLs that are refused before anything is stored
+func TestCreateWebhookRejectsURL(t *testing.T) {
+	webhookService := service.NewWebhookService(nil, nil, time.Second, false, database.RetryPolicy{})
+
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"ftp://example.com/hook", model.ErrInvalidWebhookURL},
+		{"https:///hook", model.ErrInvalidWebhookURL},
+		{"http://localhost:8080/hook", model.ErrWebhookURLNotPublic},
+		{"http://api.LOCALHOST./hook", model.ErrWebhookURLNotPublic},
+		{"http://127.0.0.1/hook", model.ErrWebhookURLNotPublic},
+		{"http://169.254.169.254/latest/meta-data/", model.ErrWebhookURLNotPublic},
+		{"http://10.0.0.5/hook", model.ErrWebhookURLNotPublic},
+		{"http://[::1]:8080/hook", model.ErrWebhookURLNotPublic},
+		{"http://[::ffff:192.168.0.1]/hook", model.ErrWebhookURLNotPublic},
+	}
+
+	for _, tt := range tests {
+		_, err := webhookService.CreateWebhook(service.CreateWebhookParams{
+			URL:        tt.url,
+			EventTypes: []string{"*"},
+		})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("CreateWebhook(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
@@ -7,26 +7,42 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/app/service/processor"
 	"github.com/IskenT/money-transfer/internal/config"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	domainRepository "github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/IskenT/money-transfer/internal/infra/acquirer"
 	"github.com/IskenT/money-transfer/internal/infra/database"
+	"github.com/IskenT/money-transfer/internal/infra/funding"
 	"github.com/IskenT/money-transfer/internal/infra/http/middleware"
 	"github.com/IskenT/money-transfer/internal/infra/http/router"
+	"github.com/IskenT/money-transfer/internal/infra/publisher"
 	repository "github.com/IskenT/money-transfer/internal/infra/repository/factory"
+	"github.com/IskenT/money-transfer/internal/infra/risk"
+	"github.com/IskenT/money-transfer/internal/infra/webhook"
 	"github.com/jmoiron/sqlx"
 )
 
 // Application represents the main application
 type Application struct {
-	server    *http.Server
-	services  *service.Services
-	router    *router.Router
-	isRunning bool
-	db        *sqlx.DB
-	txManager *database.TransactionManager
+	config              *config.Config
+	server              *http.Server
+	services            *service.Services
+	router              *router.Router
+	publisher           domainRepository.Publisher
+	outboxProcessor     *processor.OutboxProcessor
+	holdExpiryProcessor *processor.HoldExpiryProcessor
+	webhookDispatcher   *processor.WebhookDispatcher
+	webhookSender       *webhook.Sender
+	scheduler           *processor.Scheduler
+	isRunning           bool
+	db                  *sqlx.DB
+	txManager           *database.TransactionManager
 }
 
 // NewApplication
@@ -39,21 +55,169 @@ func NewApplication() *Application {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	txManager := database.NewTransactionManager(db)
+	txManager := database.NewTransactionManager(db, database.RetryPolicy{
+		MaxAttempts: cfg.Transaction.MaxAttempts,
+		BaseDelay:   cfg.Transaction.RetryBaseDelay,
+		MaxDelay:    cfg.Transaction.RetryMaxDelay,
+	})
 
 	repoFactory := repository.NewFactory(txManager)
 
 	userRepo, pgUserRepo := repoFactory.CreateUserRepository()
 	transferRepo, pgTransferRepo := repoFactory.CreateTransferRepository()
+	idempotencyKeyRepo, pgIdempotencyKeyRepo := repoFactory.CreateIdempotencyKeyRepository()
+	fxQuoteRepo, pgFXQuoteRepo := repoFactory.CreateFXQuoteRepository()
+	fxRateProvider := repoFactory.CreateFXRateProvider()
+	ledgerRepo, pgLedgerRepo := repoFactory.CreateLedgerRepository()
+	feeScheduleRepo := repoFactory.CreateFeeScheduleRepository()
+	statementRepo := repoFactory.CreateStatementRepository()
+	holdRepo, pgHoldRepo := repoFactory.CreateHoldRepository()
+	scheduledTransferRepo, pgScheduledTransferRepo := repoFactory.CreateScheduledTransferRepository()
+	standingOrderRepo, pgStandingOrderRepo := repoFactory.CreateStandingOrderRepository()
+	transferBatchRepo, pgTransferBatchRepo := repoFactory.CreateTransferBatchRepository()
+	fundingRepo, pgFundingRepo := repoFactory.CreateFundingRepository()
+	cardRepo, pgCardRepo := repoFactory.CreateCardRepository()
+	transferLimitRepo, pgTransferLimitRepo := repoFactory.CreateTransferLimitRepository()
+	riskReviewRepo, pgRiskReviewRepo := repoFactory.CreateRiskReviewRepository()
+	webhookRepo, _ := repoFactory.CreateWebhookRepository()
+	eventStore, pgEventStore := repoFactory.CreateEventStore()
+	projectionRepo, pgProjectionRepo := repoFactory.CreateProjectionRepository()
+	paymentInitiationRepo := repoFactory.CreatePaymentInitiationRepository()
 
-	transferService := service.NewTransferService(
-		userRepo, transferRepo, txManager, pgUserRepo, pgTransferRepo,
+	cardAcquirer := newCardAcquirer(cfg.Card)
+	riskEngine := newRiskEngine(cfg.Risk)
+	terminal := model.CardTerminal{
+		ID:        cfg.Card.TerminalID,
+		ProfileID: uint32(cfg.Card.MerchantProfileID),
+		AppID:     cfg.Card.AppID,
+	}
+
+	transferService := service.NewTransferService(service.TransferServiceDeps{
+		UserRepo:             userRepo,
+		TransferRepo:         transferRepo,
+		IdempotencyKeyRepo:   idempotencyKeyRepo,
+		FeeScheduleRepo:      feeScheduleRepo,
+		TxManager:            txManager,
+		PgUserRepo:           pgUserRepo,
+		PgTransferRepo:       pgTransferRepo,
+		PgIdempotencyKeyRepo: pgIdempotencyKeyRepo,
+		PgFXQuoteRepo:        pgFXQuoteRepo,
+		PgLedgerRepo:         pgLedgerRepo,
+		HoldRepo:             holdRepo,
+		PgHoldRepo:           pgHoldRepo,
+		IdempotencyKeyTTL:    cfg.Idempotency.KeyTTL,
+		HoldTTL:              cfg.Holds.TTL,
+
+		PgCardRepo:      pgCardRepo,
+		Acquirer:        cardAcquirer,
+		Terminal:        terminal,
+		AcquirerTimeout: cfg.Card.AcquirerTimeout,
+
+		TransferLimitRepo:   transferLimitRepo,
+		PgTransferLimitRepo: pgTransferLimitRepo,
+
+		RiskEngine:       riskEngine,
+		RiskReviewRepo:   riskReviewRepo,
+		PgRiskReviewRepo: pgRiskReviewRepo,
+		ReviewTTL:        cfg.Risk.ReviewTTL,
+	})
+
+	cardService := service.NewCardService(cardRepo, userRepo, cardAcquirer, cfg.Card.AcquirerTimeout)
+
+	userService := service.NewUserService(userRepo, transferService, txManager, pgUserRepo, pgFundingRepo)
+
+	fxService := service.NewFXService(
+		fxRateProvider, fxQuoteRepo, cfg.FX.QuoteTTL, cfg.FX.SpreadBps,
+	)
+
+	scheduledTransferService := service.NewScheduledTransferService(
+		transferService, userRepo, scheduledTransferRepo, txManager,
+		pgScheduledTransferRepo, cfg.Scheduler.ClaimTimeout,
+	)
+
+	standingOrderService := service.NewStandingOrderService(
+		transferService, userRepo, standingOrderRepo, txManager, pgStandingOrderRepo,
+	)
+
+	transferBatchService := service.NewTransferBatchService(
+		transferService, transferBatchRepo, txManager, pgTransferBatchRepo,
+		cfg.Batches.MaxItems, cfg.Scheduler.ClaimTimeout,
+	)
+
+	// Batches and schedules whose transfer was held settle when its review closes
+	transferService.AddReviewListener(transferBatchService)
+	transferService.AddReviewListener(scheduledTransferService)
+	transferService.AddReviewListener(standingOrderService)
+
+	paymentInitiationService := service.NewPaymentInitiationService(
+		transferService, userRepo, paymentInitiationRepo, cfg.PaymentInitiations.MaxTransactions,
+	)
+
+	fundingService := service.NewFundingService(
+		fundingRepo, newFundingProvider(cfg.Funding), txManager,
+		pgUserRepo, pgTransferRepo, pgFundingRepo, pgLedgerRepo,
+		transferLimitRepo, pgTransferLimitRepo, cfg.Funding.ProviderTimeout,
+	)
+
+	ledgerService := service.NewLedgerService(ledgerRepo)
+	feeService := service.NewFeeService(feeScheduleRepo, ledgerRepo)
+	statementService := service.NewStatementService(userRepo, statementRepo)
+	projectionService := service.NewProjectionService(
+		eventStore, projectionRepo, txManager, pgEventStore, pgProjectionRepo,
+	)
+
+	webhookSender := webhook.NewSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateNetworks)
+	webhookService := service.NewWebhookService(
+		webhookRepo, webhookSender, cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateNetworks,
+		database.RetryPolicy{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			BaseDelay:   cfg.Webhooks.RetryBaseDelay,
+			MaxDelay:    cfg.Webhooks.RetryMaxDelay,
+		},
 	)
 
 	services := &service.Services{
-		TransferService: transferService,
+		TransferService:  transferService,
+		UserService:      userService,
+		FXService:        fxService,
+		LedgerService:    ledgerService,
+		FeeService:       feeService,
+		StatementService: statementService,
+
+		ScheduledTransferService: scheduledTransferService,
+		StandingOrderService:     standingOrderService,
+		TransferBatchService:     transferBatchService,
+		PaymentInitiationService: paymentInitiationService,
+		FundingService:           fundingService,
+		CardService:              cardService,
+		WebhookService:           webhookService,
+		ProjectionService:        projectionService,
 	}
 
+	// Subscriptions get every event, whatever the routes say
+	eventPublisher := publisher.Chain{webhookService, newPublisher(cfg.Outbox)}
+	outboxProcessor := processor.NewOutboxProcessor(
+		txManager, eventPublisher, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize, cfg.Outbox.Workers, cfg.Outbox.Lease,
+		database.RetryPolicy{
+			MaxAttempts: cfg.Outbox.MaxAttempts,
+			BaseDelay:   cfg.Outbox.RetryBaseDelay,
+			MaxDelay:    cfg.Outbox.RetryMaxDelay,
+		},
+	)
+
+	holdExpiryProcessor := processor.NewHoldExpiryProcessor(
+		transferService, cfg.Holds.ExpiryInterval, cfg.Holds.ExpiryBatch,
+	)
+
+	webhookDispatcher := processor.NewWebhookDispatcher(
+		webhookService, cfg.Webhooks.DispatchInterval, cfg.Webhooks.BatchSize,
+	)
+
+	scheduler := processor.NewScheduler(
+		scheduledTransferService, standingOrderService, transferBatchService, fundingService, transferService,
+		cfg.Scheduler.Interval, cfg.Scheduler.BatchSize,
+	)
+
 	r := router.NewRouter(services)
 
 	server := &http.Server{
@@ -64,12 +228,19 @@ func NewApplication() *Application {
 	}
 
 	return &Application{
-		server:    server,
-		services:  services,
-		router:    r,
-		isRunning: false,
-		db:        db,
-		txManager: txManager,
+		config:              cfg,
+		server:              server,
+		services:            services,
+		router:              r,
+		publisher:           eventPublisher,
+		outboxProcessor:     outboxProcessor,
+		holdExpiryProcessor: holdExpiryProcessor,
+		webhookDispatcher:   webhookDispatcher,
+		webhookSender:       webhookSender,
+		scheduler:           scheduler,
+		isRunning:           false,
+		db:                  db,
+		txManager:           txManager,
 	}
 }
 
@@ -93,6 +264,8 @@ func (a *Application) Start() error {
 			log.Printf("HTTP server shutdown error: %v", err)
 		}
 
+		a.stopProcessors()
+
 		if err := a.db.Close(); err != nil {
 			log.Printf("Database connection close error: %v", err)
 		}
@@ -101,6 +274,11 @@ func (a *Application) Start() error {
 		close(idleConnsClosed)
 	}()
 
+	a.outboxProcessor.Start()
+	a.holdExpiryProcessor.Start()
+	a.webhookDispatcher.Start()
+	a.scheduler.Start()
+
 	a.isRunning = true
 	log.Printf("Server started on http://localhost%s", a.server.Addr)
 	log.Printf("Swagger UI available at http://localhost%s/swagger/index.html", a.server.Addr)
@@ -128,6 +306,8 @@ func (a *Application) Stop() error {
 		return err
 	}
 
+	a.stopProcessors()
+
 	if err := a.db.Close(); err != nil {
 		return err
 	}
@@ -135,7 +315,111 @@ func (a *Application) Stop() error {
 	return nil
 }
 
+// stopProcessors stops the background processors; the outbox processor
+// finishes the batches it has claimed first
+func (a *Application) stopProcessors() {
+	a.scheduler.Stop()
+	a.holdExpiryProcessor.Stop()
+	a.webhookDispatcher.Stop()
+	a.outboxProcessor.Stop()
+
+	if err := a.publisher.Close(); err != nil {
+		log.Printf("Error closing event publishers: %v", err)
+	}
+	a.webhookSender.Close()
+}
+
+// newFundingProvider
+func newFundingProvider(cfg config.FundingConfig) domainRepository.FundingProvider {
+	switch cfg.Provider {
+	case "fake":
+		return funding.NewFakeProvider(cfg.FakeSettleDelay)
+	}
+
+	log.Fatalf("Unknown funding provider %q", cfg.Provider)
+	return nil
+}
+
+// newCardAcquirer
+func newCardAcquirer(cfg config.CardConfig) domainRepository.CardAcquirer {
+	switch cfg.Acquirer {
+	case "simulator":
+		return acquirer.NewSimulator(cfg.SimulatorSecret)
+	}
+
+	log.Fatalf("Unknown card acquirer %q", cfg.Acquirer)
+	return nil
+}
+
+// newRiskEngine
+func newRiskEngine(cfg config.RiskConfig) domainRepository.RiskEngine {
+	switch cfg.Engine {
+	case "rules":
+		engine, err := risk.LoadEngine(cfg.RulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load risk rules: %v", err)
+		}
+		return engine
+	}
+
+	log.Fatalf("Unknown risk engine %q", cfg.Engine)
+	return nil
+}
+
+// newPublisher builds the publishers named in the outbox routes
+func newPublisher(cfg config.OutboxConfig) domainRepository.Publisher {
+	routes, err := publisher.ParseRoutes(cfg.Routes)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_ROUTES: %v", err)
+	}
+
+	publishers := make(map[string]domainRepository.Publisher)
+	for _, name := range routes.Names() {
+		var p domainRepository.Publisher
+		var err error
+
+		switch name {
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				log.Fatalf("OUTBOX_WEBHOOK_URL is required by the webhook publisher")
+			}
+			p = publisher.NewWebhookPublisher(cfg.WebhookURL, cfg.PublishTimeout)
+		case "kafka":
+			p, err = publisher.NewKafkaPublisher(
+				strings.Split(cfg.KafkaBrokers, ","), cfg.KafkaTopic, "money-transfer", cfg.PublishTimeout,
+			)
+		case "nats":
+			p, err = publisher.NewNATSPublisher(cfg.NATSURL, cfg.NATSSubjectPrefix, cfg.PublishTimeout)
+		case "file":
+			p, err = publisher.NewFilePublisher(cfg.FilePath)
+		default:
+			log.Fatalf("Unknown event publisher %q", name)
+		}
+
+		if err != nil {
+			log.Fatalf("Failed to create %s publisher: %v", name, err)
+		}
+		publishers[name] = p
+	}
+
+	router, err := publisher.NewRouter(routes, publishers)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_ROUTES: %v", err)
+	}
+	return router
+}
+
 // DB
 func (a *Application) DB() *sqlx.DB {
 	return a.db
 }
+
+// Config
+func (a *Application) Config() *config.Config {
+	return a.config
+}
+
+// Services
+func (a *Application) Services() *service.Services {
+	return a.services
+}
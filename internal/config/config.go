@@ -8,8 +8,20 @@ import (
 
 // Config
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server             ServerConfig
+	Database           DatabaseConfig
+	Idempotency        IdempotencyConfig
+	FX                 FXConfig
+	Holds              HoldsConfig
+	Scheduler          SchedulerConfig
+	Batches            BatchesConfig
+	PaymentInitiations PaymentInitiationsConfig
+	Transaction        TransactionConfig
+	Funding            FundingConfig
+	Card               CardConfig
+	Risk               RiskConfig
+	Outbox             OutboxConfig
+	Webhooks           WebhooksConfig
 }
 
 // ServerConfig
@@ -31,6 +43,116 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// IdempotencyConfig
+type IdempotencyConfig struct {
+	KeyTTL time.Duration
+}
+
+// FXConfig
+type FXConfig struct {
+	QuoteTTL  time.Duration
+	SpreadBps int
+}
+
+// HoldsConfig
+type HoldsConfig struct {
+	TTL            time.Duration
+	ExpiryInterval time.Duration
+	ExpiryBatch    int
+}
+
+// SchedulerConfig
+type SchedulerConfig struct {
+	Interval     time.Duration
+	BatchSize    int
+	ClaimTimeout time.Duration
+}
+
+// TransactionConfig
+type TransactionConfig struct {
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// FundingConfig
+type FundingConfig struct {
+	// Provider selects the FundingProvider; only "fake" is built in
+	Provider        string
+	ProviderTimeout time.Duration
+	FakeSettleDelay time.Duration
+}
+
+// CardConfig
+type CardConfig struct {
+	// Acquirer selects the CardAcquirer; only "simulator" is built in
+	Acquirer        string
+	AcquirerTimeout time.Duration
+	// TerminalID, MerchantProfileID and AppID identify this system to the acquirer
+	TerminalID        string
+	MerchantProfileID int
+	AppID             int
+	SimulatorSecret   string
+}
+
+// RiskConfig
+type RiskConfig struct {
+	// Engine selects the RiskEngine; only "rules" is built in
+	Engine    string
+	RulesFile string
+	// ReviewTTL is how long a held transfer waits for review before it expires
+	ReviewTTL time.Duration
+}
+
+// OutboxConfig
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Workers      int
+	// Lease is how long a worker owns the events it claimed
+	Lease time.Duration
+	// MaxAttempts is how often an event is tried before it is dead-lettered
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// Routes maps event types to publishers, see publisher.ParseRoutes
+	Routes            string
+	PublishTimeout    time.Duration
+	WebhookURL        string
+	KafkaBrokers      string
+	KafkaTopic        string
+	NATSURL           string
+	NATSSubjectPrefix string
+	FilePath          string
+}
+
+// WebhooksConfig
+type WebhooksConfig struct {
+	DispatchInterval time.Duration
+	BatchSize        int
+	// Timeout is how long a receiver has to answer one delivery
+	Timeout time.Duration
+	// MaxAttempts is how often a delivery is tried before it is FAILED
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// AllowPrivateNetworks lets subscriptions point at loopback, link-local
+	// and private addresses, for local development
+	AllowPrivateNetworks bool
+}
+
+// BatchesConfig
+type BatchesConfig struct {
+	MaxItems int
+}
+
+// PaymentInitiationsConfig
+type PaymentInitiationsConfig struct {
+	// MaxTransactions is the most transactions a pain.001 message may have;
+	// they are executed while the client waits for the status report
+	MaxTransactions int
+}
+
 // NewConfig
 func NewConfig() *Config {
 	return &Config{
@@ -49,6 +171,79 @@ func NewConfig() *Config {
 			Name:     getEnv("POSTGRES_DB", "money_transfer"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
+		Idempotency: IdempotencyConfig{
+			KeyTTL: getEnvAsDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		},
+		FX: FXConfig{
+			QuoteTTL:  getEnvAsDuration("FX_QUOTE_TTL", 30*time.Second),
+			SpreadBps: getEnvAsInt("FX_SPREAD_BPS", 50),
+		},
+		Holds: HoldsConfig{
+			TTL:            getEnvAsDuration("HOLD_TTL", 7*24*time.Hour),
+			ExpiryInterval: getEnvAsDuration("HOLD_EXPIRY_INTERVAL", time.Minute),
+			ExpiryBatch:    getEnvAsInt("HOLD_EXPIRY_BATCH", 100),
+		},
+		Scheduler: SchedulerConfig{
+			Interval:     getEnvAsDuration("SCHEDULER_INTERVAL", 10*time.Second),
+			BatchSize:    getEnvAsInt("SCHEDULER_BATCH_SIZE", 100),
+			ClaimTimeout: getEnvAsDuration("SCHEDULER_CLAIM_TIMEOUT", time.Minute),
+		},
+		Batches: BatchesConfig{
+			MaxItems: getEnvAsInt("BATCH_MAX_ITEMS", 1000),
+		},
+		PaymentInitiations: PaymentInitiationsConfig{
+			MaxTransactions: getEnvAsInt("PAIN001_MAX_TRANSACTIONS", 200),
+		},
+		Transaction: TransactionConfig{
+			MaxAttempts:    getEnvAsInt("TX_MAX_ATTEMPTS", 5),
+			RetryBaseDelay: getEnvAsDuration("TX_RETRY_BASE_DELAY", 10*time.Millisecond),
+			RetryMaxDelay:  getEnvAsDuration("TX_RETRY_MAX_DELAY", 500*time.Millisecond),
+		},
+		Funding: FundingConfig{
+			Provider:        getEnv("FUNDING_PROVIDER", "fake"),
+			ProviderTimeout: getEnvAsDuration("FUNDING_PROVIDER_TIMEOUT", 10*time.Second),
+			FakeSettleDelay: getEnvAsDuration("FAKE_FUNDING_SETTLE_DELAY", 30*time.Second),
+		},
+		Card: CardConfig{
+			Acquirer:          getEnv("CARD_ACQUIRER", "simulator"),
+			AcquirerTimeout:   getEnvAsDuration("CARD_ACQUIRER_TIMEOUT", 10*time.Second),
+			TerminalID:        getEnv("CARD_TERMINAL_ID", "MT000001"),
+			MerchantProfileID: getEnvAsInt("CARD_MERCHANT_PROFILE_ID", 1),
+			AppID:             getEnvAsInt("CARD_APP_ID", 1),
+			SimulatorSecret:   getEnv("CARD_SIMULATOR_SECRET", "simulator-secret"),
+		},
+		Risk: RiskConfig{
+			Engine:    getEnv("RISK_ENGINE", "rules"),
+			RulesFile: getEnv("RISK_RULES_FILE", "config/risk_rules.yaml"),
+			ReviewTTL: getEnvAsDuration("RISK_REVIEW_TTL", 72*time.Hour),
+		},
+		Outbox: OutboxConfig{
+			PollInterval:   getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:      getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			Workers:        getEnvAsInt("OUTBOX_WORKERS", 1),
+			Lease:          getEnvAsDuration("OUTBOX_LEASE", 5*time.Minute),
+			MaxAttempts:    getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+			RetryBaseDelay: getEnvAsDuration("OUTBOX_RETRY_BASE_DELAY", time.Second),
+			RetryMaxDelay:  getEnvAsDuration("OUTBOX_RETRY_MAX_DELAY", time.Hour),
+
+			Routes:            getEnv("OUTBOX_ROUTES", ""),
+			PublishTimeout:    getEnvAsDuration("OUTBOX_PUBLISH_TIMEOUT", 10*time.Second),
+			WebhookURL:        getEnv("OUTBOX_WEBHOOK_URL", ""),
+			KafkaBrokers:      getEnv("OUTBOX_KAFKA_BROKERS", "localhost:9092"),
+			KafkaTopic:        getEnv("OUTBOX_KAFKA_TOPIC", "money-transfer.events"),
+			NATSURL:           getEnv("OUTBOX_NATS_URL", "nats://localhost:4222"),
+			NATSSubjectPrefix: getEnv("OUTBOX_NATS_SUBJECT_PREFIX", "money-transfer.events"),
+			FilePath:          getEnv("OUTBOX_FILE_PATH", "outbox_events.jsonl"),
+		},
+		Webhooks: WebhooksConfig{
+			DispatchInterval:     getEnvAsDuration("WEBHOOK_DISPATCH_INTERVAL", time.Second),
+			BatchSize:            getEnvAsInt("WEBHOOK_BATCH_SIZE", 50),
+			Timeout:              getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts:          getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 12),
+			RetryBaseDelay:       getEnvAsDuration("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second),
+			RetryMaxDelay:        getEnvAsDuration("WEBHOOK_RETRY_MAX_DELAY", 6*time.Hour),
+			AllowPrivateNetworks: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		},
 	}
 }
 
@@ -72,6 +267,18 @@ func getEnvAsBool(key string, fallback bool) bool {
 	return fallback
 }
 
+// getEnvAsInt
+func getEnvAsInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fallback
+		}
+		return i
+	}
+	return fallback
+}
+
 // getEnvAsDuration
 func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
 	if value, exists := os.LookupEnv(key); exists {
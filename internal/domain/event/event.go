@@ -0,0 +1,383 @@
+package event
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// Event is a typed domain event written to the outbox. Its JSON encoding is
+// the outbox payload and is described by the event's JSON Schema.
+type Event interface {
+	EventType() string
+	AggregateType() string
+	AggregateID() string
+	header() *Header
+}
+
+// Header is embedded in every event
+type Header struct {
+	SchemaVersion int `json:"schema_version" description:"Version of the event's schema; set by the registry"`
+}
+
+// header
+func (h *Header) header() *Header {
+	return h
+}
+
+// Transfer is the state of a transfer carried by the transfer events
+type Transfer struct {
+	TransferID      string     `json:"transfer_id" description:"Transfer ID"`
+	FromUserID      string     `json:"from_user_id" description:"Sender"`
+	ToUserID        string     `json:"to_user_id" description:"Recipient"`
+	Amount          int        `json:"amount" description:"Amount in minor units of the currency"`
+	Currency        string     `json:"currency" description:"ISO-4217 currency code"`
+	Fee             int        `json:"fee" description:"Fee charged to the sender in minor units"`
+	QuoteID         string     `json:"quote_id,omitempty" description:"FX quote of a cross-currency transfer"`
+	StandingOrderID string     `json:"standing_order_id,omitempty" description:"Standing order that made the transfer"`
+	PaymentSource   string     `json:"payment_source" description:"TRANSFER when paid from the sender's balance, or CARD"`
+	State           string     `json:"state" description:"Transfer state after the change"`
+	CreatedAt       time.Time  `json:"created_at" description:"When the transfer was created"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty" description:"When the transfer completed"`
+	ReversedAmount  int        `json:"reversed_amount,omitempty" description:"Amount reversed so far, in minor units of the credited currency"`
+}
+
+// AggregateType
+func (t Transfer) AggregateType() string {
+	return "transfer"
+}
+
+// AggregateID
+func (t Transfer) AggregateID() string {
+	return t.TransferID
+}
+
+// Snapshot returns the transfer state, so that all transfer events can be read alike
+func (t *Transfer) Snapshot() *Transfer {
+	return t
+}
+
+// TransferInitiated: a card-funded transfer was sent to the acquirer
+type TransferInitiated struct {
+	Header
+	Transfer
+}
+
+// TransferAuthorized: funds were held until the transfer is captured
+type TransferAuthorized struct {
+	Header
+	Transfer
+}
+
+// TransferHeld: risk screening held the transfer for review
+type TransferHeld struct {
+	Header
+	Transfer
+}
+
+// TransferCompleted
+type TransferCompleted struct {
+	Header
+	Transfer
+}
+
+// TransferFailed
+type TransferFailed struct {
+	Header
+	Transfer
+	ResponseCode string `json:"response_code,omitempty" description:"Acquirer response code of a declined card payment"`
+}
+
+// TransferVoided: an authorization was voided and its hold released
+type TransferVoided struct {
+	Header
+	Transfer
+}
+
+// TransferExpired: an authorization or a held transfer was never captured or reviewed
+type TransferExpired struct {
+	Header
+	Transfer
+}
+
+// TransferRejected: a reviewer rejected a held transfer
+type TransferRejected struct {
+	Header
+	Transfer
+}
+
+// TransferReversed: the transfer is a reversal, moving money back from the
+// recipient of the original transfer to its sender
+type TransferReversed struct {
+	Header
+	Transfer
+	ReversalOf string `json:"reversal_of" description:"Transfer being reversed"`
+}
+
+// TransferReversalApplied: part or all of the transfer was reversed by a
+// reversal transfer
+type TransferReversalApplied struct {
+	Header
+	Transfer
+}
+
+func (*TransferInitiated) EventType() string  { return "transfer_initiated" }
+func (*TransferAuthorized) EventType() string { return "transfer_authorized" }
+func (*TransferHeld) EventType() string       { return "transfer_held" }
+func (*TransferCompleted) EventType() string  { return "transfer_completed" }
+func (*TransferFailed) EventType() string     { return "transfer_failed" }
+func (*TransferVoided) EventType() string     { return "transfer_voided" }
+func (*TransferExpired) EventType() string    { return "transfer_expired" }
+func (*TransferRejected) EventType() string   { return "transfer_rejected" }
+func (*TransferReversed) EventType() string   { return "transfer_reversed" }
+
+func (*TransferReversalApplied) EventType() string { return "transfer_reversal_applied" }
+
+// NewTransferEvent returns the event for the transfer's latest state change
+func NewTransferEvent(transfer *model.Transfer) Event {
+	t := Transfer{
+		TransferID:      transfer.ID,
+		FromUserID:      transfer.FromUserID,
+		ToUserID:        transfer.ToUserID,
+		Amount:          transfer.Amount,
+		Currency:        transfer.Currency,
+		Fee:             transfer.Fee,
+		QuoteID:         transfer.QuoteID,
+		StandingOrderID: transfer.StandingOrderID,
+		PaymentSource:   string(model.PaymentMethodTypeTransfer),
+		State:           string(transfer.State),
+		CreatedAt:       transfer.CreatedAt,
+		CompletedAt:     optionalTime(transfer.CompletedAt),
+		ReversedAmount:  transfer.ReversedAmount,
+	}
+	if transfer.DebitTx != nil && transfer.DebitTx.PaymentSource != "" {
+		t.PaymentSource = string(transfer.DebitTx.PaymentSource)
+	}
+
+	switch transfer.State {
+	case model.TransactionStatePending:
+		if transfer.CardFunded() {
+			return &TransferInitiated{Transfer: t}
+		}
+		return &TransferAuthorized{Transfer: t}
+	case model.TransactionStateFailed:
+		e := &TransferFailed{Transfer: t}
+		if transfer.DebitTx != nil {
+			e.ResponseCode = transfer.DebitTx.ResponseCode
+		}
+		return e
+	case model.TransactionStateVoided:
+		return &TransferVoided{Transfer: t}
+	case model.TransactionStateExpired:
+		return &TransferExpired{Transfer: t}
+	case model.TransactionStateHeld:
+		return &TransferHeld{Transfer: t}
+	case model.TransactionStateRejected:
+		return &TransferRejected{Transfer: t}
+	case model.TransactionStatePartiallyReversed, model.TransactionStateReversed:
+		return &TransferReversalApplied{Transfer: t}
+	}
+
+	if transfer.ReversalOf != "" {
+		return &TransferReversed{Transfer: t, ReversalOf: transfer.ReversalOf}
+	}
+
+	return &TransferCompleted{Transfer: t}
+}
+
+// BalanceChanged: a ledger posting changed a user's balance in one currency
+type BalanceChanged struct {
+	Header
+	UserID      string    `json:"user_id" description:"Account holder"`
+	Currency    string    `json:"currency" description:"ISO-4217 currency code"`
+	Delta       int       `json:"delta" description:"Change in minor units; negative for debits"`
+	Balance     int       `json:"balance" description:"Balance after the change"`
+	JournalID   string    `json:"journal_id" description:"Ledger journal of the posting"`
+	TransferID  string    `json:"transfer_id,omitempty" description:"Transfer that changed the balance"`
+	FundingID   string    `json:"funding_id,omitempty" description:"Deposit or withdrawal that changed the balance"`
+	Description string    `json:"description" description:"Journal description"`
+	ChangedAt   time.Time `json:"changed_at" description:"When the posting was made"`
+}
+
+func (*BalanceChanged) EventType() string     { return "balance_changed" }
+func (*BalanceChanged) AggregateType() string { return "user" }
+func (e *BalanceChanged) AggregateID() string { return e.UserID }
+
+// BalanceOpened: a user's balance in one currency was opened
+type BalanceOpened struct {
+	Header
+	UserID   string    `json:"user_id" description:"Account holder"`
+	Currency string    `json:"currency" description:"ISO-4217 currency code"`
+	Balance  int       `json:"balance" description:"Opening balance in minor units"`
+	OpenedAt time.Time `json:"opened_at" description:"When the balance was opened"`
+}
+
+func (*BalanceOpened) EventType() string     { return "balance_opened" }
+func (*BalanceOpened) AggregateType() string { return "user" }
+func (e *BalanceOpened) AggregateID() string { return e.UserID }
+
+// Funding is the state of a deposit or withdrawal carried by the funding events
+type Funding struct {
+	FundingID     string     `json:"funding_id" description:"Deposit or withdrawal ID"`
+	Type          string     `json:"type" description:"DEPOSIT or WITHDRAWAL"`
+	UserID        string     `json:"user_id" description:"Account holder"`
+	Amount        int        `json:"amount" description:"Amount in minor units of the currency"`
+	Currency      string     `json:"currency" description:"ISO-4217 currency code"`
+	PaymentMethod string     `json:"payment_method" description:"CARD or BANK"`
+	State         string     `json:"state" description:"State after the change"`
+	FailureReason string     `json:"failure_reason,omitempty" description:"Why the operation failed"`
+	CreatedAt     time.Time  `json:"created_at" description:"When the operation was created"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty" description:"When the operation completed or failed"`
+}
+
+// AggregateType
+func (f Funding) AggregateType() string {
+	return "funding"
+}
+
+// AggregateID
+func (f Funding) AggregateID() string {
+	return f.FundingID
+}
+
+// DepositInitiated
+type DepositInitiated struct {
+	Header
+	Funding
+}
+
+// DepositCompleted
+type DepositCompleted struct {
+	Header
+	Funding
+}
+
+// DepositFailed
+type DepositFailed struct {
+	Header
+	Funding
+}
+
+// WithdrawalInitiated
+type WithdrawalInitiated struct {
+	Header
+	Funding
+}
+
+// WithdrawalCompleted
+type WithdrawalCompleted struct {
+	Header
+	Funding
+}
+
+// WithdrawalFailed
+type WithdrawalFailed struct {
+	Header
+	Funding
+}
+
+func (*DepositInitiated) EventType() string    { return "deposit_initiated" }
+func (*DepositCompleted) EventType() string    { return "deposit_completed" }
+func (*DepositFailed) EventType() string       { return "deposit_failed" }
+func (*WithdrawalInitiated) EventType() string { return "withdrawal_initiated" }
+func (*WithdrawalCompleted) EventType() string { return "withdrawal_completed" }
+func (*WithdrawalFailed) EventType() string    { return "withdrawal_failed" }
+
+// NewFundingEvent returns the event for the operation's latest state change
+func NewFundingEvent(funding *model.Funding) Event {
+	f := Funding{
+		FundingID:     funding.ID,
+		Type:          string(funding.Type),
+		UserID:        funding.UserID,
+		Amount:        funding.Amount,
+		Currency:      funding.Currency,
+		PaymentMethod: string(funding.Method),
+		State:         string(funding.State),
+		FailureReason: funding.FailureReason,
+		CreatedAt:     funding.CreatedAt,
+		CompletedAt:   optionalTime(funding.CompletedAt),
+	}
+
+	deposit := funding.Type == model.FundingTypeDeposit
+	switch {
+	case funding.State == model.TransactionStateCompleted && deposit:
+		return &DepositCompleted{Funding: f}
+	case funding.State == model.TransactionStateCompleted:
+		return &WithdrawalCompleted{Funding: f}
+	case funding.State == model.TransactionStateFailed && deposit:
+		return &DepositFailed{Funding: f}
+	case funding.State == model.TransactionStateFailed:
+		return &WithdrawalFailed{Funding: f}
+	case deposit:
+		return &DepositInitiated{Funding: f}
+	}
+	return &WithdrawalInitiated{Funding: f}
+}
+
+// ScheduledTransferFailed: the transfer of a scheduled transfer was rejected when it ran
+type ScheduledTransferFailed struct {
+	Header
+	ScheduleID    string    `json:"schedule_id" description:"Scheduled transfer ID"`
+	FromUserID    string    `json:"from_user_id" description:"Sender"`
+	ToUserID      string    `json:"to_user_id" description:"Recipient"`
+	Amount        int       `json:"amount" description:"Amount in minor units of the currency"`
+	Currency      string    `json:"currency" description:"ISO-4217 currency code"`
+	ExecuteAt     time.Time `json:"execute_at" description:"When the transfer was due"`
+	FailureReason string    `json:"failure_reason" description:"Why the transfer was rejected"`
+	FailedAt      time.Time `json:"failed_at" description:"When it ran"`
+}
+
+func (*ScheduledTransferFailed) EventType() string     { return "scheduled_transfer_failed" }
+func (*ScheduledTransferFailed) AggregateType() string { return "scheduled_transfer" }
+func (e *ScheduledTransferFailed) AggregateID() string { return e.ScheduleID }
+
+// NewScheduledTransferFailed
+func NewScheduledTransferFailed(schedule *model.ScheduledTransfer) *ScheduledTransferFailed {
+	return &ScheduledTransferFailed{
+		ScheduleID:    schedule.ID,
+		FromUserID:    schedule.FromUserID,
+		ToUserID:      schedule.ToUserID,
+		Amount:        schedule.Amount,
+		Currency:      schedule.Currency,
+		ExecuteAt:     schedule.ExecuteAt,
+		FailureReason: schedule.FailureReason,
+		FailedAt:      schedule.ExecutedAt,
+	}
+}
+
+// StandingOrderRunFailed: the transfer of one run of a standing order was rejected
+type StandingOrderRunFailed struct {
+	Header
+	StandingOrderID string `json:"standing_order_id" description:"Standing order ID"`
+	FromUserID      string `json:"from_user_id" description:"Sender"`
+	ToUserID        string `json:"to_user_id" description:"Recipient"`
+	Amount          int    `json:"amount" description:"Amount in minor units of the currency"`
+	Currency        string `json:"currency" description:"ISO-4217 currency code"`
+	RunDate         string `json:"run_date" format:"date" description:"Date of the run, YYYY-MM-DD"`
+	FailureReason   string `json:"failure_reason" description:"Why the transfer was rejected"`
+}
+
+func (*StandingOrderRunFailed) EventType() string     { return "standing_order_run_failed" }
+func (*StandingOrderRunFailed) AggregateType() string { return "standing_order" }
+func (e *StandingOrderRunFailed) AggregateID() string { return e.StandingOrderID }
+
+// NewStandingOrderRunFailed
+func NewStandingOrderRunFailed(order *model.StandingOrder, due time.Time) *StandingOrderRunFailed {
+	return &StandingOrderRunFailed{
+		StandingOrderID: order.ID,
+		FromUserID:      order.FromUserID,
+		ToUserID:        order.ToUserID,
+		Amount:          order.Amount,
+		Currency:        order.Currency,
+		RunDate:         due.Format(time.DateOnly),
+		FailureReason:   order.LastFailureReason,
+	}
+}
+
+// optionalTime
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
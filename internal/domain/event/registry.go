@@ -0,0 +1,159 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var (
+	ErrUnknownEventType   = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported event schema version")
+)
+
+// registration
+type registration struct {
+	version  int
+	newEvent func() Event
+}
+
+// Registry is the one place event types are encoded and decoded, so the
+// payload of every event type follows its schema.
+//
+// Within a schema version, fields may only be added, and only as optional
+// fields; consumers must ignore fields they do not know. Renaming or removing
+// a field, or changing its type or meaning, needs a new version.
+type Registry struct {
+	events map[string]registration
+}
+
+// NewRegistry
+func NewRegistry() *Registry {
+	return &Registry{
+		events: make(map[string]registration),
+	}
+}
+
+// Register adds an event type at its current schema version
+func (r *Registry) Register(version int, newEvent func() Event) {
+	eventType := newEvent().EventType()
+	if _, ok := r.events[eventType]; ok {
+		panic(fmt.Sprintf("event type %s registered twice", eventType))
+	}
+	r.events[eventType] = registration{version: version, newEvent: newEvent}
+}
+
+// Types returns every registered event type in alphabetical order
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.events))
+	for eventType := range r.events {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Version returns the current schema version of an event type
+func (r *Registry) Version(eventType string) (int, error) {
+	reg, ok := r.events[eventType]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+	return reg.version, nil
+}
+
+// Encode stamps the event with its schema version and returns its payload
+func (r *Registry) Encode(e Event) ([]byte, error) {
+	reg, ok := r.events[e.EventType()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, e.EventType())
+	}
+	if reflect.TypeOf(e) != reflect.TypeOf(reg.newEvent()) {
+		return nil, fmt.Errorf("event type %s is registered as %T, not %T", e.EventType(), reg.newEvent(), e)
+	}
+
+	e.header().SchemaVersion = reg.version
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s event: %w", e.EventType(), err)
+	}
+	return payload, nil
+}
+
+// Decode reads a payload into its typed event. Payloads written before events
+// were versioned carry no schema_version and are read as version 1.
+func (r *Registry) Decode(eventType string, payload []byte) (Event, error) {
+	reg, ok := r.events[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	e := reg.newEvent()
+	if err := json.Unmarshal(payload, e); err != nil {
+		return nil, fmt.Errorf("error decoding %s event: %w", eventType, err)
+	}
+
+	h := e.header()
+	if h.SchemaVersion == 0 {
+		h.SchemaVersion = 1
+	}
+	if h.SchemaVersion > reg.version {
+		return nil, fmt.Errorf("%w: %s v%d, this build reads up to v%d", ErrUnsupportedVersion, eventType, h.SchemaVersion, reg.version)
+	}
+
+	return e, nil
+}
+
+// defaultRegistry holds every event the application writes
+var defaultRegistry = newDefaultRegistry()
+
+// newDefaultRegistry
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	r.Register(1, func() Event { return &TransferInitiated{} })
+	r.Register(1, func() Event { return &TransferAuthorized{} })
+	r.Register(1, func() Event { return &TransferHeld{} })
+	r.Register(1, func() Event { return &TransferCompleted{} })
+	r.Register(1, func() Event { return &TransferFailed{} })
+	r.Register(1, func() Event { return &TransferVoided{} })
+	r.Register(1, func() Event { return &TransferExpired{} })
+	r.Register(1, func() Event { return &TransferRejected{} })
+	r.Register(1, func() Event { return &TransferReversed{} })
+	r.Register(1, func() Event { return &TransferReversalApplied{} })
+	r.Register(1, func() Event { return &BalanceOpened{} })
+	r.Register(1, func() Event { return &BalanceChanged{} })
+	r.Register(1, func() Event { return &DepositInitiated{} })
+	r.Register(1, func() Event { return &DepositCompleted{} })
+	r.Register(1, func() Event { return &DepositFailed{} })
+	r.Register(1, func() Event { return &WithdrawalInitiated{} })
+	r.Register(1, func() Event { return &WithdrawalCompleted{} })
+	r.Register(1, func() Event { return &WithdrawalFailed{} })
+	r.Register(1, func() Event { return &ScheduledTransferFailed{} })
+	r.Register(1, func() Event { return &StandingOrderRunFailed{} })
+
+	return r
+}
+
+// Default returns the registry of every event the application writes
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Encode uses the default registry
+func Encode(e Event) ([]byte, error) {
+	return defaultRegistry.Encode(e)
+}
+
+// Decode uses the default registry
+func Decode(eventType string, payload []byte) (Event, error) {
+	return defaultRegistry.Decode(eventType, payload)
+}
+
+// Types lists the event types of the default registry
+func Types() []string {
+	return defaultRegistry.Types()
+}
@@ -0,0 +1,124 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schemaDialect
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns the JSON Schema of an event type's payload at its current
+// version. Unknown fields are allowed, as later releases may add fields.
+func (r *Registry) Schema(eventType string) ([]byte, error) {
+	reg, ok := r.events[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	schema := objectSchema(reflect.TypeOf(reg.newEvent()).Elem())
+	schema["$schema"] = schemaDialect
+	schema["$id"] = fmt.Sprintf("urn:money-transfer:events:%s:v%d", eventType, reg.version)
+	schema["title"] = eventType
+	schema["additionalProperties"] = true
+	schema["properties"].(map[string]interface{})["schema_version"] = map[string]interface{}{
+		"type":        "integer",
+		"const":       reg.version,
+		"description": "Version of the event's schema",
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s schema: %w", eventType, err)
+	}
+	return append(data, '\n'), nil
+}
+
+// objectSchema describes a struct; embedded structs are flattened the way
+// encoding/json flattens them
+func objectSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+				collect(field.Type)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+
+			name, options, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = field.Name
+			}
+
+			property := typeSchema(field.Type)
+			if description := field.Tag.Get("description"); description != "" {
+				property["description"] = description
+			}
+			if format := field.Tag.Get("format"); format != "" {
+				property["format"] = format
+			}
+			properties[name] = property
+
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	collect(t)
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// typeSchema
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t)
+	}
+
+	return map[string]interface{}{}
+}
+
+// Schema uses the default registry
+func Schema(eventType string) ([]byte, error) {
+	return defaultRegistry.Schema(eventType)
+}
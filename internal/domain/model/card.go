@@ -0,0 +1,224 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CardBrand
+type CardBrand string
+
+const (
+	CardBrandVisa       CardBrand = "VISA"
+	CardBrandMastercard CardBrand = "MASTERCARD"
+	CardBrandAmex       CardBrand = "AMEX"
+	CardBrandOther      CardBrand = "OTHER"
+
+	// MaxStan is the largest system trace audit number; STANs are six digits
+	MaxStan = 999999
+
+	// CardResponseApproved is the ISO 8583 response code of an approved authorization
+	CardResponseApproved = "00"
+
+	maxCardholderNameLength = 100
+)
+
+// CardDetails is a card as entered by the cardholder. The full number only
+// passes through on its way to the acquirer; it is never stored and prints
+// masked.
+type CardDetails struct {
+	Number      string
+	HolderName  string
+	ExpiryMonth int
+	ExpiryYear  int
+}
+
+// String masks the card number so that card details can be logged
+func (c CardDetails) String() string {
+	return fmt.Sprintf("%s %s %02d/%02d", MaskPAN(c.Number), c.HolderName, c.ExpiryMonth, c.ExpiryYear%100)
+}
+
+// GoString
+func (c CardDetails) GoString() string {
+	return c.String()
+}
+
+// Normalize strips spaces and dashes from the number, trims the name and
+// turns a two-digit expiry year into a four-digit one
+func (c *CardDetails) Normalize() {
+	c.Number = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, c.Number)
+	c.HolderName = strings.TrimSpace(c.HolderName)
+	if c.ExpiryYear >= 0 && c.ExpiryYear < 100 {
+		c.ExpiryYear += 2000
+	}
+}
+
+// Validate checks the number against the Luhn checksum and that the card has
+// not expired; a card is valid until the end of its expiry month
+func (c *CardDetails) Validate(now time.Time) error {
+	if !ValidPAN(c.Number) {
+		return ErrInvalidCardNumber
+	}
+
+	if c.HolderName == "" || utf8.RuneCountInString(c.HolderName) > maxCardholderNameLength {
+		return ErrInvalidCardholderName
+	}
+
+	if c.ExpiryMonth < 1 || c.ExpiryMonth > 12 || c.ExpiryYear < 2000 || c.ExpiryYear > 9999 {
+		return ErrInvalidCardExpiry
+	}
+
+	if cardExpired(c.ExpiryMonth, c.ExpiryYear, now) {
+		return ErrCardExpired
+	}
+
+	return nil
+}
+
+// Brand is derived from the leading digits of the number
+func (c *CardDetails) Brand() CardBrand {
+	n := c.Number
+	switch {
+	case strings.HasPrefix(n, "4"):
+		return CardBrandVisa
+	case strings.HasPrefix(n, "34"), strings.HasPrefix(n, "37"):
+		return CardBrandAmex
+	case len(n) >= 2 && n[:2] >= "51" && n[:2] <= "55":
+		return CardBrandMastercard
+	case len(n) >= 4 && n[:4] >= "2221" && n[:4] <= "2720":
+		return CardBrandMastercard
+	}
+	return CardBrandOther
+}
+
+// ValidPAN reports whether the number has 12 to 19 digits and passes the Luhn check
+func ValidPAN(pan string) bool {
+	if len(pan) < 12 || len(pan) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(pan) - 1; i >= 0; i-- {
+		if pan[i] < '0' || pan[i] > '9' {
+			return false
+		}
+		d := int(pan[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// MaskPAN keeps the first six and last four digits of a card number, the
+// most that may be displayed, and masks the rest. Short input is masked entirely.
+func MaskPAN(pan string) string {
+	if len(pan) < 12 {
+		return strings.Repeat("*", len(pan))
+	}
+	return pan[:6] + strings.Repeat("*", len(pan)-10) + pan[len(pan)-4:]
+}
+
+// cardExpired
+func cardExpired(month, year int, now time.Time) bool {
+	firstOfNextMonth := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.UTC().Before(firstOfNextMonth)
+}
+
+// Card is a card registered to a user. The acquirer's token stands for the
+// card number, which is kept only in masked form.
+type Card struct {
+	ID string
+	// Ref is the numeric ID that transactions record as CardID
+	Ref         uint32
+	UserID      string
+	Token       string
+	MaskedPAN   string
+	Brand       CardBrand
+	HolderName  string
+	ExpiryMonth int
+	ExpiryYear  int
+	CreatedAt   time.Time
+}
+
+// Expired
+func (c *Card) Expired(now time.Time) bool {
+	return cardExpired(c.ExpiryMonth, c.ExpiryYear, now)
+}
+
+// Expiry is the MM/YY form printed on the card
+func (c *Card) Expiry() string {
+	return fmt.Sprintf("%02d/%02d", c.ExpiryMonth, c.ExpiryYear%100)
+}
+
+// BIN is the issuer identification number, the first six digits
+func (c *Card) BIN() string {
+	if len(c.MaskedPAN) < 6 {
+		return ""
+	}
+	return c.MaskedPAN[:6]
+}
+
+// CardTerminal identifies this system to the acquirer
+type CardTerminal struct {
+	ID        string
+	ProfileID uint32
+	AppID     int
+}
+
+// BusinessDate is the UTC day a STAN is unique within
+func BusinessDate(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// FormatStan zero-pads a trace number to six digits
+func FormatStan(n int) Stan {
+	return Stan(fmt.Sprintf("%06d", n))
+}
+
+// StanFromSequence turns the n-th value of a sequence starting at 1 into a
+// STAN, starting again at 000001 after 999999
+func StanFromSequence(n int64) Stan {
+	return FormatStan(int((n-1)%MaxStan) + 1)
+}
+
+// CardToken is the acquirer's answer to a tokenization request
+type CardToken struct {
+	Token string
+}
+
+// CardAuthorization asks the acquirer to charge a tokenized card. The
+// terminal, STAN and business date identify the request, so the acquirer
+// answers a request that is sent again with the original result.
+type CardAuthorization struct {
+	TerminalID   string
+	ProfileID    uint32
+	Stan         Stan
+	BusinessDate time.Time
+	Token        string
+	Amount       int
+	Currency     string
+}
+
+// CardAuthorizationResult; AuthCode is set when the payment was approved
+type CardAuthorizationResult struct {
+	Approved     bool
+	AuthCode     string
+	ResponseCode string
+	Reason       string
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidPAN(t *testing.T) {
+	tests := []struct {
+		name string
+		pan  string
+		want bool
+	}{
+		{"visa", "4111111111111111", true},
+		{"mastercard", "5555555555554444", true},
+		{"amex", "378282246310005", true},
+		{"shortest", "000000000000", true},
+		{"longest", "1234567890123456785", true},
+		{"bad check digit", "4111111111111112", false},
+		{"too short", "79927398713", false},
+		{"too long", "12345678901234567850", false},
+		{"spaces", "4111 1111 1111 1111", false},
+		{"letters", "411111111111111a", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidPAN(tt.pan); got != tt.want {
+				t.Errorf("ValidPAN(%q) = %t, want %t", tt.pan, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPAN(t *testing.T) {
+	tests := []struct {
+		name string
+		pan  string
+		want string
+	}{
+		{"sixteen digits", "4111111111111111", "411111******1111"},
+		{"fifteen digits", "378282246310005", "378282*****0005"},
+		{"twelve digits", "123456789012", "123456**9012"},
+		{"nineteen digits", "1234567890123456785", "123456*********6785"},
+		{"too short", "12345678901", "***********"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskPAN(tt.pan); got != tt.want {
+				t.Errorf("MaskPAN(%q) = %q, want %q", tt.pan, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCardExpired(t *testing.T) {
+	card := &Card{ExpiryMonth: 2, ExpiryYear: 2024}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"month before", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC), false},
+		{"last day of the month", time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC), false},
+		{"first day after", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), true},
+		{"year after", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), true},
+		// Still the last day of February in UTC
+		{"behind UTC", time.Date(2024, 2, 29, 18, 0, 0, 0, time.FixedZone("UTC-5", -5*3600)), false},
+		// Already March in UTC
+		{"ahead of UTC", time.Date(2024, 3, 1, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)), false},
+		{"ahead of UTC past midnight UTC", time.Date(2024, 3, 1, 3, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := card.Expired(tt.now); got != tt.want {
+				t.Errorf("Expired(%s) = %t, want %t", tt.now, got, tt.want)
+			}
+		})
+	}
+
+	// December rolls over into January of the next year
+	december := &Card{ExpiryMonth: 12, ExpiryYear: 2024}
+	if december.Expired(time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Error("card expiring 12/24 expired on 31 December 2024")
+	}
+	if !december.Expired(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("card expiring 12/24 not expired on 1 January 2025")
+	}
+}
+
+func TestStanFromSequence(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want Stan
+	}{
+		{1, "000001"},
+		{42, "000042"},
+		{MaxStan, "999999"},
+		{MaxStan + 1, "000001"},
+		{MaxStan + 2, "000002"},
+		{2 * MaxStan, "999999"},
+		{2*MaxStan + 1, "000001"},
+	}
+
+	for _, tt := range tests {
+		if got := StanFromSequence(tt.n); got != tt.want {
+			t.Errorf("StanFromSequence(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency is used when a request does not name a currency
+const DefaultCurrency = "USD"
+
+// Currency describes an ISO-4217 currency
+type Currency struct {
+	Code     string
+	Exponent int
+	Symbol   string
+}
+
+// currencies lists the supported currencies with their minor-unit exponent
+var currencies = map[string]Currency{
+	"USD": {Code: "USD", Exponent: 2, Symbol: "$"},
+	"EUR": {Code: "EUR", Exponent: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", Exponent: 2, Symbol: "£"},
+	"CHF": {Code: "CHF", Exponent: 2},
+	"KZT": {Code: "KZT", Exponent: 2, Symbol: "₸"},
+	"JPY": {Code: "JPY", Exponent: 0, Symbol: "¥"},
+	"KRW": {Code: "KRW", Exponent: 0, Symbol: "₩"},
+	"KWD": {Code: "KWD", Exponent: 3},
+	"BHD": {Code: "BHD", Exponent: 3},
+}
+
+// LookupCurrency
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, ErrUnsupportedCurrency
+	}
+	return c, nil
+}
+
+// Format renders an amount given in minor units, e.g. 1500 JPY as "¥1500"
+// and 1500 KWD as "KWD 1.500"
+func (c Currency) Format(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	major := c.Decimal(amount)
+	if c.Symbol != "" {
+		return sign + c.Symbol + major
+	}
+	return sign + c.Code + " " + major
+}
+
+// Decimal renders an amount given in minor units as a plain decimal in major
+// units, e.g. -1025 USD as "-10.25"
+func (c Currency) Decimal(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if c.Exponent == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+
+	unit := 1
+	for i := 0; i < c.Exponent; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, c.Exponent, amount%unit)
+}
@@ -3,9 +3,80 @@ package model
 import "errors"
 
 var (
-	ErrInsufficientFunds = errors.New("insufficient funds")
-	ErrSameAccount       = errors.New("cannot transfer to same account")
-	ErrUserNotFound      = errors.New("user not found")
-	ErrInvalidAmount     = errors.New("invalid amount")
-	ErrTransferNotFound  = errors.New("transfer not found")
+	ErrInsufficientFunds      = errors.New("insufficient funds")
+	ErrSameAccount            = errors.New("cannot transfer to same account")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrInvalidAmount          = errors.New("invalid amount")
+	ErrTransferNotFound       = errors.New("transfer not found")
+	ErrInvalidIdempotencyKey  = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrIdempotencyKeyExists   = errors.New("idempotency key already exists")
+	ErrPaymentAlreadyExecuted = errors.New("payment was already executed")
+	ErrUnsupportedCurrency    = errors.New("unsupported currency")
+	ErrCurrencyNotHeld        = errors.New("account does not hold this currency")
+	ErrInvalidCurrencyPair    = errors.New("invalid currency pair")
+	ErrInvalidRate            = errors.New("invalid exchange rate")
+	ErrFXRateNotFound         = errors.New("exchange rate not found")
+	ErrQuoteNotFound          = errors.New("quote not found")
+	ErrQuoteExpired           = errors.New("quote has expired")
+	ErrQuoteAlreadyUsed       = errors.New("quote has already been used")
+	ErrQuoteCurrencyMismatch  = errors.New("transfer currency does not match quote")
+	ErrUnbalancedJournal      = errors.New("journal postings do not balance")
+	ErrInvalidPeriod          = errors.New("invalid period")
+	ErrTransferNotReversible  = errors.New("transfer cannot be reversed")
+	ErrReversalExceedsAmount  = errors.New("reversal exceeds the remaining transfer amount")
+	ErrTransferNotPending     = errors.New("transfer is not awaiting capture")
+	ErrHoldNotFound           = errors.New("hold not found")
+	ErrAuthorizationExpired   = errors.New("authorization has expired")
+	ErrScheduleNotFound       = errors.New("scheduled transfer not found")
+	ErrScheduleNotCancellable = errors.New("scheduled transfer can no longer be cancelled")
+	ErrInvalidExecuteAt       = errors.New("execute_at must be in the future")
+	ErrQuoteNotSchedulable    = errors.New("FX quotes cannot be used with scheduled transfers")
+	ErrStandingOrderNotFound  = errors.New("standing order not found")
+	ErrStandingOrderUnbounded = errors.New("standing order needs an end date or a maximum number of occurrences")
+	ErrInvalidFrequency       = errors.New("invalid frequency")
+	ErrInvalidDayOfMonth      = errors.New("invalid day of month")
+	ErrInvalidCatchUpPolicy   = errors.New("invalid catch-up policy")
+	ErrInvalidStatusChange    = errors.New("standing order cannot change to this status")
+	ErrInvalidStartDate       = errors.New("start_date cannot be in the past")
+	ErrBatchNotFound          = errors.New("transfer batch not found")
+	ErrInvalidBatchMode       = errors.New("invalid batch mode")
+	ErrInvalidBatchSize       = errors.New("invalid number of batch items")
+	ErrTooManyTransactions    = errors.New("too many transactions in the message")
+	ErrInvalidCursor          = errors.New("invalid cursor")
+	ErrInvalidFilter          = errors.New("invalid filter")
+	ErrAccountFrozen          = errors.New("account is frozen")
+	ErrAccountClosed          = errors.New("account is closed")
+	ErrInvalidUserName        = errors.New("invalid user name")
+	ErrInvalidAccountTier     = errors.New("invalid account tier")
+	ErrInvalidUserStatus      = errors.New("account cannot change to this status")
+	ErrAccountNotEmpty        = errors.New("account must have a zero balance and no pending operations to be closed")
+	ErrInvalidSweep           = errors.New("funds can only be swept when closing the account")
+	ErrFundingNotFound        = errors.New("funding operation not found")
+	ErrInvalidPaymentMethod   = errors.New("invalid payment method")
+	ErrInvalidExternalAccount = errors.New("invalid external account")
+	ErrCardNotFound           = errors.New("card not found")
+	ErrInvalidCardNumber      = errors.New("invalid card number")
+	ErrInvalidCardholderName  = errors.New("invalid cardholder name")
+	ErrInvalidCardExpiry      = errors.New("invalid card expiry date")
+	ErrCardExpired            = errors.New("card has expired")
+	ErrCardNotAllowed         = errors.New("cards cannot fund authorizations or scheduled transfers")
+	ErrStanExhausted          = errors.New("no trace numbers left for the terminal today")
+	ErrLimitExceeded          = errors.New("transfer limit exceeded")
+	ErrTransferDenied         = errors.New("transfer was declined by risk screening")
+	ErrTransferNotHeld        = errors.New("transfer is not held for review")
+	ErrRejectedInReview       = errors.New("transfer was rejected in risk review")
+	ErrHeldInAtomicBatch      = errors.New("transfer was held for risk review; an atomic batch cannot wait for a review")
+	ErrReviewExpired          = errors.New("review period has expired")
+	ErrInvalidReviewer        = errors.New("reviewer is required")
+	ErrInvalidReviewStatus    = errors.New("invalid review status")
+	ErrWebhookNotFound        = errors.New("webhook subscription not found")
+	ErrInvalidWebhookURL      = errors.New("webhook URL must be an absolute http or https URL")
+	ErrWebhookURLNotPublic    = errors.New("webhook URL must not point to a loopback, link-local or private address")
+	ErrInvalidEventTypes      = errors.New("invalid event types")
+	ErrInvalidWebhookSecret   = errors.New("webhook secret must be 16 to 100 characters")
+	ErrDeliveryNotFound       = errors.New("webhook delivery not found")
+	ErrInvalidDeliveryStatus  = errors.New("invalid delivery status")
+	ErrVersionConflict        = errors.New("aggregate was changed by a concurrent append")
 )
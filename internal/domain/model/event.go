@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is an outbox event on its way to the publishers
+type Event struct {
+	ID            int64
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       json.RawMessage
+	CreatedAt     time.Time
+}
+
+// StoredEvent is an event in the event store. Position orders all events;
+// Version is the event's place in its aggregate's stream.
+type StoredEvent struct {
+	Position      int64
+	AggregateType string
+	AggregateID   string
+	Version       int
+	EventType     string
+	Payload       json.RawMessage
+	RecordedAt    time.Time
+}
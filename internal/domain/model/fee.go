@@ -0,0 +1,124 @@
+package model
+
+// AccountTier
+type AccountTier string
+
+// Valid
+func (t AccountTier) Valid() bool {
+	return t == AccountTierStandard || t == AccountTierPremium
+}
+
+// FeeType
+type FeeType string
+
+const (
+	AccountTierStandard AccountTier = "STANDARD"
+	AccountTierPremium  AccountTier = "PREMIUM"
+
+	FeeTypeFlat       FeeType = "FLAT"
+	FeeTypePercentage FeeType = "PERCENTAGE"
+	FeeTypeTiered     FeeType = "TIERED"
+
+	AccountFeeRevenue = "system:fee_revenue"
+)
+
+// FeeTier applies to amounts up to and including UpTo; the last tier has UpTo 0
+type FeeTier struct {
+	UpTo          int
+	FlatAmount    int
+	PercentageBps int
+}
+
+// FeeSchedule
+type FeeSchedule struct {
+	ID            string
+	Name          string
+	Currency      string
+	Type          FeeType
+	FlatAmount    int
+	PercentageBps int
+	Tiers         []FeeTier
+	MinFee        int
+	MaxFee        int
+	AccountTier   AccountTier
+	PaymentMethod PaymentMethodType
+}
+
+// Compute returns the fee for an amount in minor units. Percentages are
+// rounded half up to a whole minor unit, then the fee is clamped to
+// MinFee and, when set, MaxFee.
+func (s *FeeSchedule) Compute(amount int) int {
+	var fee int
+
+	switch s.Type {
+	case FeeTypeFlat:
+		fee = s.FlatAmount
+	case FeeTypePercentage:
+		fee = percentageOf(amount, s.PercentageBps)
+	case FeeTypeTiered:
+		for _, t := range s.Tiers {
+			if t.UpTo == 0 || amount <= t.UpTo {
+				fee = t.FlatAmount + percentageOf(amount, t.PercentageBps)
+				break
+			}
+		}
+	}
+
+	if fee < s.MinFee {
+		fee = s.MinFee
+	}
+	if s.MaxFee > 0 && fee > s.MaxFee {
+		fee = s.MaxFee
+	}
+
+	return fee
+}
+
+// matchScore ranks how specifically the schedule targets a tier and payment
+// method; -1 means it does not apply
+func (s *FeeSchedule) matchScore(tier AccountTier, method PaymentMethodType) int {
+	score := 0
+
+	if s.PaymentMethod != "" {
+		if s.PaymentMethod != method {
+			return -1
+		}
+		score += 2
+	}
+
+	if s.AccountTier != "" {
+		if s.AccountTier != tier {
+			return -1
+		}
+		score++
+	}
+
+	return score
+}
+
+// SelectFeeSchedule picks the most specific schedule for the tier and payment
+// method: tier and method, then method only, then tier only, then the default
+func SelectFeeSchedule(schedules []*FeeSchedule, tier AccountTier, method PaymentMethodType) (*FeeSchedule, bool) {
+	var best *FeeSchedule
+	bestScore := -1
+
+	for _, s := range schedules {
+		if score := s.matchScore(tier, method); score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+
+	return best, best != nil
+}
+
+// percentageOf
+func percentageOf(amount, bps int) int {
+	return (amount*bps + 5000) / 10000
+}
+
+// AccountTotal sums the postings to an account in one currency
+type AccountTotal struct {
+	Currency string
+	Amount   int
+	Entries  int
+}
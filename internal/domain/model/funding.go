@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// FundingType
+type FundingType string
+
+const (
+	FundingTypeDeposit    FundingType = "DEPOSIT"
+	FundingTypeWithdrawal FundingType = "WITHDRAWAL"
+
+	// AccountSettlement mirrors the funds held at external card and bank
+	// providers: deposits are drawn from it and withdrawals are paid into it
+	AccountSettlement = "system:settlement"
+	// AccountSuspense holds withdrawn funds until the provider confirms the payout
+	AccountSuspense = "system:suspense"
+)
+
+// Funding moves money between a user's balance and an external card or bank
+// account: a deposit (cash-in) or a withdrawal (cash-out)
+type Funding struct {
+	ID              string
+	Type            FundingType
+	UserID          string
+	Amount          int
+	Currency        string
+	Method          PaymentMethodType
+	ExternalAccount string
+	// ProviderRef identifies the operation at the funding provider once it was accepted
+	ProviderRef   string
+	State         TransactionState
+	FailureReason string
+	Transaction   *Transaction
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   time.Time
+}
+
+// Request
+func (f *Funding) Request() *FundingRequest {
+	return &FundingRequest{
+		ID:              f.ID,
+		Method:          f.Method,
+		ExternalAccount: f.ExternalAccount,
+		Amount:          f.Amount,
+		Currency:        f.Currency,
+	}
+}
+
+// FundingRequest asks a funding provider to collect or pay out funds.
+// Providers use the ID to recognise a request that is sent again.
+type FundingRequest struct {
+	ID              string
+	Method          PaymentMethodType
+	ExternalAccount string
+	Amount          int
+	Currency        string
+}
+
+// FundingResult is a provider's answer; State stays PENDING until the
+// provider has settled the operation
+type FundingResult struct {
+	Reference string
+	State     TransactionState
+	Reason    string
+}
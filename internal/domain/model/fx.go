@@ -0,0 +1,110 @@
+package model
+
+import (
+	"math/big"
+	"time"
+)
+
+// RateScale is the number of decimal places kept for exchange rates
+const RateScale = 8
+
+// FXRate is a mid-market rate: one unit of Base buys Rate units of Quote
+type FXRate struct {
+	Base      string
+	Quote     string
+	Rate      *big.Rat
+	UpdatedAt time.Time
+}
+
+// FXQuote locks a customer rate between two currencies until ExpiresAt
+type FXQuote struct {
+	ID           string
+	FromCurrency string
+	ToCurrency   string
+	MidRate      *big.Rat
+	Rate         *big.Rat
+	SpreadBps    int
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	UsedAt       time.Time
+}
+
+// Expired
+func (q *FXQuote) Expired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+// Used
+func (q *FXQuote) Used() bool {
+	return !q.UsedAt.IsZero()
+}
+
+// Rounding rules for currency conversion:
+//
+//  1. The customer rate is the mid rate reduced by the spread, truncated
+//     to RateScale decimal places.
+//  2. A converted amount is truncated toward zero to a whole minor unit
+//     of the target currency, so the recipient is never credited more
+//     than the quoted rate allows.
+
+// ApplySpread returns the customer rate for a mid rate and a spread in basis points
+func ApplySpread(mid *big.Rat, spreadBps int) *big.Rat {
+	r := new(big.Rat).Mul(mid, big.NewRat(int64(10000-spreadBps), 10000))
+	return TruncateRate(r)
+}
+
+// InvertRate returns 1/rate truncated to RateScale decimal places
+func InvertRate(rate *big.Rat) *big.Rat {
+	return TruncateRate(new(big.Rat).Inv(rate))
+}
+
+// TruncateRate drops every decimal place past RateScale
+func TruncateRate(rate *big.Rat) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(RateScale), nil)
+	n := new(big.Int).Mul(rate.Num(), scale)
+	n.Quo(n, rate.Denom())
+	return new(big.Rat).SetFrac(n, scale)
+}
+
+// FormatRate
+func FormatRate(rate *big.Rat) string {
+	return rate.FloatString(RateScale)
+}
+
+// ParseRate
+func ParseRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return r, nil
+}
+
+// ConvertAmount converts an amount in minor units of from into minor units of
+// to. A result that does not fit an int is an ErrInvalidAmount.
+func ConvertAmount(amount int, rate *big.Rat, from, to Currency) (int, error) {
+	n := new(big.Int).Mul(big.NewInt(int64(amount)), rate.Num())
+	d := new(big.Int).Set(rate.Denom())
+
+	shift := to.Exponent - from.Exponent
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil)
+	if shift > 0 {
+		n.Mul(n, pow)
+	} else {
+		d.Mul(d, pow)
+	}
+
+	n.Quo(n, d)
+	if !n.IsInt64() || int64(int(n.Int64())) != n.Int64() {
+		return 0, ErrInvalidAmount
+	}
+	return int(n.Int64()), nil
+}
+
+// abs
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// rat parses a decimal or fraction, failing the test on bad input
+func rat(t *testing.T, s string) *big.Rat {
+	t.Helper()
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		t.Fatalf("bad rational %q", s)
+	}
+	return r
+}
+
+func TestApplySpread(t *testing.T) {
+	tests := []struct {
+		name      string
+		mid       string
+		spreadBps int
+		want      string
+	}{
+		{"no spread", "1.1", 0, "1.1"},
+		{"spread", "1.1", 50, "1.0945"},
+		{"truncated after spread", "150.12345678", 25, "149.74814813"},
+		{"mid past scale", "0.123456789", 0, "0.12345678"},
+		{"half-way digit is truncated", "1.000000015", 0, "1.00000001"},
+		{"full spread", "1.5", 10000, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ApplySpread(rat(t, tt.mid), tt.spreadBps)
+			if got.Cmp(rat(t, tt.want)) != 0 {
+				t.Errorf("ApplySpread(%s, %d) = %s, want %s", tt.mid, tt.spreadBps, FormatRate(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateRate(t *testing.T) {
+	tests := []struct {
+		name string
+		rate string
+		want string
+	}{
+		{"whole", "1", "1"},
+		{"at scale", "1.23456789", "1.23456789"},
+		{"half-way digit", "0.123456785", "0.12345678"},
+		{"just below one", "0.999999999", "0.99999999"},
+		{"repeating fraction", "2/3", "0.66666666"},
+		{"below scale", "0.000000009", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateRate(rat(t, tt.rate))
+			if got.Cmp(rat(t, tt.want)) != 0 {
+				t.Errorf("TruncateRate(%s) = %s, want %s", tt.rate, FormatRate(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestInvertRate(t *testing.T) {
+	tests := []struct {
+		name string
+		rate string
+		want string
+	}{
+		{"exact", "2", "0.5"},
+		{"repeating", "3", "0.33333333"},
+		{"repeating pair", "1.1", "0.9090909"},
+		{"small rate", "0.00000007", "14285714.28571428"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InvertRate(rat(t, tt.rate))
+			if got.Cmp(rat(t, tt.want)) != 0 {
+				t.Errorf("InvertRate(%s) = %s, want %s", tt.rate, FormatRate(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	usd, jpy, kwd, eur := currencies["USD"], currencies["JPY"], currencies["KWD"], currencies["EUR"]
+
+	tests := []struct {
+		name    string
+		amount  int
+		rate    string
+		from    Currency
+		to      Currency
+		want    int
+		wantErr error
+	}{
+		{"same exponent", 10000, "0.9", usd, eur, 9000, nil},
+		{"to fewer decimals", 1000, "150.5", usd, jpy, 1505, nil},
+		{"to more decimals", 1505, "0.00664451", jpy, usd, 999, nil},
+		{"two to three decimals", 10000, "0.30705", usd, kwd, 30705, nil},
+		{"three to no decimals, half-way", 1000, "489.5", kwd, jpy, 489, nil},
+		{"half-way minor unit", 5, "0.5", usd, eur, 2, nil},
+		{"below one minor unit", 1, "150.5", usd, jpy, 1, nil},
+		{"rounds down to zero", 1, "0.004", jpy, usd, 0, nil},
+		{"largest amount", math.MaxInt64, "1", usd, eur, math.MaxInt64, nil},
+		{"rate overflows", math.MaxInt64, "2", usd, eur, 0, ErrInvalidAmount},
+		{"exponent overflows", 10_000_000_000_000_000, "1", jpy, kwd, 0, ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertAmount(tt.amount, rat(t, tt.rate), tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConvertAmount(%d, %s, %s, %s) error = %v, want %v", tt.amount, tt.rate, tt.from.Code, tt.to.Code, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertAmount(%d, %s, %s, %s) = %d, want %d", tt.amount, tt.rate, tt.from.Code, tt.to.Code, got, tt.want)
+			}
+		})
+	}
+}
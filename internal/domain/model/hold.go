@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// HoldStatus
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "ACTIVE"
+	HoldStatusCaptured HoldStatus = "CAPTURED"
+	HoldStatusVoided   HoldStatus = "VOIDED"
+	HoldStatusExpired  HoldStatus = "EXPIRED"
+)
+
+// Hold reserves funds of an authorized transfer on the sender's balance
+type Hold struct {
+	ID         int64
+	TransferID string
+	UserID     string
+	Currency   string
+	Amount     int
+	Status     HoldStatus
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	ReleasedAt time.Time
+}
+
+// Active
+func (h *Hold) Active() bool {
+	return h.Status == HoldStatusActive
+}
+
+// Expired
+func (h *Hold) Expired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// IdempotencyKey
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	TransferID  string
+	// Response is nil until the first response has been stored
+	Response  *IdempotentResponse
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IdempotentResponse is the response first given to a request with an
+// idempotency key, returned unchanged when the request is retried
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Matches reports whether the key was first used with the same request
+func (k *IdempotencyKey) Matches(requestHash string) bool {
+	return k.RequestHash == requestHash
+}
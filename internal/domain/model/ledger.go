@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// System accounts exist only in the ledger and have no cached balance
+const (
+	AccountOpeningBalance = "system:opening_balance"
+	AccountFXPosition     = "system:fx"
+)
+
+// userAccountPrefix
+const userAccountPrefix = "user:"
+
+// UserAccount returns the ledger account holding a user's balances
+func UserAccount(userID string) string {
+	return userAccountPrefix + userID
+}
+
+// UserIDFromAccount returns the user behind a ledger account, if any
+func UserIDFromAccount(account string) (string, bool) {
+	if !strings.HasPrefix(account, userAccountPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(account, userAccountPrefix), true
+}
+
+// Posting moves Amount minor units of Currency into Account;
+// a negative amount moves money out of it
+type Posting struct {
+	Account  string
+	Currency string
+	Amount   int
+}
+
+// Journal is a group of postings that must sum to zero per currency
+type Journal struct {
+	ID          string
+	TransferID  string
+	FundingID   string
+	Description string
+	Postings    []*Posting
+	CreatedAt   time.Time
+}
+
+// Post appends a posting to the journal
+func (j *Journal) Post(account, currency string, amount int) {
+	j.Postings = append(j.Postings, &Posting{
+		Account:  account,
+		Currency: currency,
+		Amount:   amount,
+	})
+}
+
+// Validate checks the journal is balanced
+func (j *Journal) Validate() error {
+	if len(j.Postings) < 2 {
+		return ErrUnbalancedJournal
+	}
+
+	sums := make(map[string]int)
+	for _, p := range j.Postings {
+		if p.Amount == 0 {
+			return ErrUnbalancedJournal
+		}
+		sums[p.Currency] += p.Amount
+	}
+
+	for currency, sum := range sums {
+		if sum != 0 {
+			return fmt.Errorf("%w: %s postings sum to %d", ErrUnbalancedJournal, currency, sum)
+		}
+	}
+
+	return nil
+}
+
+// BalanceMismatch is a cached balance that differs from the sum of its postings
+type BalanceMismatch struct {
+	UserID        string
+	Currency      string
+	CachedBalance int
+	LedgerBalance int
+}
+
+// JournalImbalance is a journal whose postings do not sum to zero in a currency
+type JournalImbalance struct {
+	JournalID string
+	Currency  string
+	Sum       int
+}
+
+// LedgerConsistencyReport
+type LedgerConsistencyReport struct {
+	CheckedAt         time.Time
+	BalanceMismatches []*BalanceMismatch
+	JournalImbalances []*JournalImbalance
+}
+
+// Consistent
+func (r *LedgerConsistencyReport) Consistent() bool {
+	return len(r.BalanceMismatches) == 0 && len(r.JournalImbalances) == 0
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimitType names the limit a transfer exceeded
+type LimitType string
+
+// LimitPeriod is a UTC calendar window that transfer usage is counted in
+type LimitPeriod string
+
+const (
+	LimitTypeSingleTransfer  LimitType = "MAX_SINGLE_TRANSFER"
+	LimitTypeDailyAmount     LimitType = "MAX_DAILY_AMOUNT"
+	LimitTypeMonthlyAmount   LimitType = "MAX_MONTHLY_AMOUNT"
+	LimitTypeHourlyTransfers LimitType = "MAX_TRANSFERS_PER_HOUR"
+
+	LimitPeriodHour  LimitPeriod = "HOUR"
+	LimitPeriodDay   LimitPeriod = "DAY"
+	LimitPeriodMonth LimitPeriod = "MONTH"
+)
+
+// LimitPeriods in the order their usage rows are locked
+var LimitPeriods = []LimitPeriod{LimitPeriodHour, LimitPeriodDay, LimitPeriodMonth}
+
+// Start is the beginning of the window containing t
+func (p LimitPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case LimitPeriodHour:
+		return t.Truncate(time.Hour)
+	case LimitPeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return BusinessDate(t)
+}
+
+// End is the start of the next window, when usage resets
+func (p LimitPeriod) End(t time.Time) time.Time {
+	start := p.Start(t)
+	switch p {
+	case LimitPeriodHour:
+		return start.Add(time.Hour)
+	case LimitPeriodMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// TransferLimit caps a sender's outgoing transfers in one currency. A limit
+// with a UserID applies to that user only, one with an AccountTier to the
+// tier; neither makes it the default. Zero means no limit.
+type TransferLimit struct {
+	ID          string
+	Currency    string
+	AccountTier AccountTier
+	UserID      string
+	MaxSingle   int
+	MaxDaily    int
+	MaxMonthly  int
+	MaxPerHour  int
+}
+
+// LimitUsage is what a sender has transferred in one currency within a window
+type LimitUsage struct {
+	Period      LimitPeriod
+	PeriodStart time.Time
+	Amount      int
+	Count       int
+}
+
+// ResetsAt
+func (u *LimitUsage) ResetsAt() time.Time {
+	return u.Period.End(u.PeriodStart)
+}
+
+// matchScore ranks how specifically the limit targets the user; -1 means it does not apply
+func (l *TransferLimit) matchScore(userID string, tier AccountTier) int {
+	if l.UserID != "" {
+		if l.UserID != userID {
+			return -1
+		}
+		return 2
+	}
+
+	if l.AccountTier != "" {
+		if l.AccountTier != tier {
+			return -1
+		}
+		return 1
+	}
+
+	return 0
+}
+
+// SelectTransferLimit picks the user's own limit, then their tier's, then the default
+func SelectTransferLimit(limits []*TransferLimit, userID string, tier AccountTier) (*TransferLimit, bool) {
+	var best *TransferLimit
+	bestScore := -1
+
+	for _, l := range limits {
+		if score := l.matchScore(userID, tier); score > bestScore {
+			best, bestScore = l, score
+		}
+	}
+
+	return best, best != nil
+}
+
+// CheckSingle checks an amount against the per-transfer maximum
+func (l *TransferLimit) CheckSingle(amount int) error {
+	if l.MaxSingle > 0 && amount > l.MaxSingle {
+		return &LimitExceededError{
+			Limit:    LimitTypeSingleTransfer,
+			Currency: l.Currency,
+			Max:      l.MaxSingle,
+		}
+	}
+	return nil
+}
+
+// CheckUsage checks usage that already includes the new transfer against the
+// maximum for its window
+func (l *TransferLimit) CheckUsage(usage *LimitUsage) error {
+	var limit LimitType
+	var max, used int
+
+	switch usage.Period {
+	case LimitPeriodHour:
+		limit, max, used = LimitTypeHourlyTransfers, l.MaxPerHour, usage.Count
+	case LimitPeriodDay:
+		limit, max, used = LimitTypeDailyAmount, l.MaxDaily, usage.Amount
+	case LimitPeriodMonth:
+		limit, max, used = LimitTypeMonthlyAmount, l.MaxMonthly, usage.Amount
+	}
+
+	if max > 0 && used > max {
+		return &LimitExceededError{
+			Limit:    limit,
+			Currency: l.Currency,
+			Max:      max,
+			ResetsAt: usage.ResetsAt(),
+		}
+	}
+	return nil
+}
+
+// LimitExceededError says which limit a transfer exceeded and when it resets.
+// It matches ErrLimitExceeded with errors.Is.
+type LimitExceededError struct {
+	Limit    LimitType
+	Currency string
+	// Max is in minor units, or a number of transfers for MAX_TRANSFERS_PER_HOUR
+	Max int
+	// ResetsAt is zero for the single transfer limit, which never resets
+	ResetsAt time.Time
+}
+
+// Error
+func (e *LimitExceededError) Error() string {
+	if e.ResetsAt.IsZero() {
+		return fmt.Sprintf("%s: %s is %d in %s", ErrLimitExceeded, e.Limit, e.Max, e.Currency)
+	}
+	return fmt.Sprintf("%s: %s is %d in %s, resets at %s", ErrLimitExceeded, e.Limit, e.Max, e.Currency, e.ResetsAt.Format(time.RFC3339))
+}
+
+// Unwrap
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
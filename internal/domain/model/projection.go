@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// ProjectedTransfer is a transfer rebuilt from the event store, at the
+// version of the last event applied to it
+type ProjectedTransfer struct {
+	Transfer *Transfer
+	Version  int
+}
+
+// ProjectedBalance is a balance rebuilt from the event store
+type ProjectedBalance struct {
+	UserID   string
+	Currency string
+	Amount   int
+	Version  int
+}
+
+// ProjectionMismatch is a row that differs between a live table and its
+// projection. Live or Projected is empty when the row is missing on that side.
+type ProjectionMismatch struct {
+	Table     string
+	Key       string
+	Live      string
+	Projected string
+}
+
+// ProjectionReport
+type ProjectionReport struct {
+	RebuiltAt  time.Time
+	Events     int
+	Transfers  int
+	Balances   int
+	Mismatches []*ProjectionMismatch
+}
+
+// Consistent
+func (r *ProjectionReport) Consistent() bool {
+	return len(r.Mismatches) == 0
+}
@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// RiskDecision
+type RiskDecision string
+
+// RiskReviewStatus
+type RiskReviewStatus string
+
+const (
+	RiskDecisionAllow  RiskDecision = "ALLOW"
+	RiskDecisionReview RiskDecision = "REVIEW"
+	RiskDecisionDeny   RiskDecision = "DENY"
+
+	RiskReviewStatusPending  RiskReviewStatus = "PENDING"
+	RiskReviewStatusApproved RiskReviewStatus = "APPROVED"
+	RiskReviewStatusRejected RiskReviewStatus = "REJECTED"
+	RiskReviewStatusExpired  RiskReviewStatus = "EXPIRED"
+)
+
+// Valid
+func (d RiskDecision) Valid() bool {
+	return d == RiskDecisionAllow || d == RiskDecisionReview || d == RiskDecisionDeny
+}
+
+// Severity orders decisions so that the strictest one wins
+func (d RiskDecision) Severity() int {
+	switch d {
+	case RiskDecisionReview:
+		return 1
+	case RiskDecisionDeny:
+		return 2
+	}
+	return 0
+}
+
+// Valid
+func (s RiskReviewStatus) Valid() bool {
+	switch s {
+	case RiskReviewStatusPending, RiskReviewStatusApproved, RiskReviewStatusRejected, RiskReviewStatusExpired:
+		return true
+	}
+	return false
+}
+
+// RiskCheck describes a transfer about to be committed, with what is known
+// about the sender's history
+type RiskCheck struct {
+	TransferID    string
+	FromUserID    string
+	ToUserID      string
+	Amount        int
+	Currency      string
+	PaymentSource PaymentMethodType
+	At            time.Time
+	// NewPayee is set when the sender has never completed a transfer to the recipient
+	NewPayee bool
+	// TransfersThisHour and AmountToday are the sender's usage in the
+	// transfer's currency, including the transfer itself. Bulk items add to
+	// AmountToday only.
+	TransfersThisHour int
+	AmountToday       int
+	// Bulk is set for items of a batch, a pain.001 file or a standing order,
+	// whose number in the hour says nothing about the sender
+	Bulk bool
+}
+
+// RiskAssessment
+type RiskAssessment struct {
+	Decision RiskDecision
+	Reasons  []string
+}
+
+// RiskReview is a transfer held for review and what the reviewer decided
+type RiskReview struct {
+	ID         string
+	TransferID string
+	Status     RiskReviewStatus
+	Reasons    []string
+	// Authorize makes an approved transfer an authorization awaiting
+	// capture instead of completing it
+	Authorize  bool
+	Reviewer   string
+	Note       string
+	CreatedAt  time.Time
+	ReviewedAt time.Time
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// ScheduledTransferStatus
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusScheduled  ScheduledTransferStatus = "SCHEDULED"
+	ScheduledTransferStatusProcessing ScheduledTransferStatus = "PROCESSING"
+	ScheduledTransferStatusCompleted  ScheduledTransferStatus = "COMPLETED"
+	// ScheduledTransferStatusPendingReview waits for the risk review of its held transfer
+	ScheduledTransferStatusPendingReview ScheduledTransferStatus = "PENDING_REVIEW"
+	ScheduledTransferStatusFailed        ScheduledTransferStatus = "FAILED"
+	ScheduledTransferStatusCancelled     ScheduledTransferStatus = "CANCELLED"
+)
+
+// ScheduledTransfer is a transfer submitted ahead of the time it executes
+type ScheduledTransfer struct {
+	ID            string
+	FromUserID    string
+	ToUserID      string
+	Amount        int
+	Currency      string
+	ExecuteAt     time.Time
+	Status        ScheduledTransferStatus
+	TransferID    string
+	FailureReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	ExecutedAt    time.Time
+}
+
+// Cancellable
+func (s *ScheduledTransfer) Cancellable() bool {
+	return s.Status == ScheduledTransferStatusScheduled
+}
+
+// IdempotencyKey identifies the schedule's transfer so that an execution
+// interrupted after the transfer committed is never run twice
+func (s *ScheduledTransfer) IdempotencyKey() string {
+	return "scheduled:" + s.ID
+}
@@ -0,0 +1,171 @@
+package model
+
+import "time"
+
+// Frequency
+type Frequency string
+
+// CatchUpPolicy decides what happens to runs missed while the scheduler was not running
+type CatchUpPolicy string
+
+// StandingOrderStatus
+type StandingOrderStatus string
+
+const (
+	FrequencyDaily           Frequency = "DAILY"
+	FrequencyWeekly          Frequency = "WEEKLY"
+	FrequencyMonthly         Frequency = "MONTHLY"
+	FrequencyLastBusinessDay Frequency = "LAST_BUSINESS_DAY"
+
+	// CatchUpSkip drops missed runs and waits for the next scheduled date
+	CatchUpSkip CatchUpPolicy = "SKIP"
+	// CatchUpRunOnce makes a single run for all missed dates
+	CatchUpRunOnce CatchUpPolicy = "RUN_ONCE"
+
+	StandingOrderStatusActive    StandingOrderStatus = "ACTIVE"
+	StandingOrderStatusPaused    StandingOrderStatus = "PAUSED"
+	StandingOrderStatusCompleted StandingOrderStatus = "COMPLETED"
+	// StandingOrderStatusPendingReview waits for the risk review of the run's
+	// held transfer before the order moves on to its next date
+	StandingOrderStatusPendingReview StandingOrderStatus = "PENDING_REVIEW"
+)
+
+// StandingOrder repeats a transfer on a calendar schedule. Dates are UTC days;
+// a run is due from the start of its day.
+type StandingOrder struct {
+	ID                string
+	FromUserID        string
+	ToUserID          string
+	Amount            int
+	Currency          string
+	Frequency         Frequency
+	DayOfMonth        int
+	StartDate         time.Time
+	EndDate           time.Time
+	MaxOccurrences    int
+	Occurrences       int
+	CatchUp           CatchUpPolicy
+	Status            StandingOrderStatus
+	NextRunAt         time.Time
+	LastRunAt         time.Time
+	LastFailureReason string
+	// HeldTransferID is the transfer of the run waiting for risk review
+	HeldTransferID string
+	TransferIDs    []string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Validate
+func (o *StandingOrder) Validate() error {
+	switch o.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyLastBusinessDay:
+		if o.DayOfMonth != 0 {
+			return ErrInvalidDayOfMonth
+		}
+	case FrequencyMonthly:
+		if o.DayOfMonth < 1 || o.DayOfMonth > 31 {
+			return ErrInvalidDayOfMonth
+		}
+	default:
+		return ErrInvalidFrequency
+	}
+
+	switch o.CatchUp {
+	case CatchUpSkip, CatchUpRunOnce:
+	default:
+		return ErrInvalidCatchUpPolicy
+	}
+
+	if o.EndDate.IsZero() && o.MaxOccurrences <= 0 {
+		return ErrStandingOrderUnbounded
+	}
+
+	if o.MaxOccurrences < 0 || (!o.EndDate.IsZero() && o.EndDate.Before(o.StartDate)) {
+		return ErrInvalidPeriod
+	}
+
+	return nil
+}
+
+// OccurrenceOnOrAfter returns the first scheduled date on or after the given day
+func (o *StandingOrder) OccurrenceOnOrAfter(t time.Time) time.Time {
+	day := Day(t)
+	if day.Before(o.StartDate) {
+		day = o.StartDate
+	}
+
+	switch o.Frequency {
+	case FrequencyWeekly:
+		days := int(day.Sub(o.StartDate).Hours() / 24)
+		return o.StartDate.AddDate(0, 0, (days+6)/7*7)
+	case FrequencyMonthly:
+		candidate := dayOfMonth(day.Year(), day.Month(), o.DayOfMonth)
+		if candidate.Before(day) {
+			candidate = dayOfMonth(day.Year(), day.Month()+1, o.DayOfMonth)
+		}
+		return candidate
+	case FrequencyLastBusinessDay:
+		candidate := lastBusinessDay(day.Year(), day.Month())
+		if candidate.Before(day) {
+			candidate = lastBusinessDay(day.Year(), day.Month()+1)
+		}
+		return candidate
+	default:
+		return day
+	}
+}
+
+// OccurrenceAfter returns the first scheduled date strictly after the given day
+func (o *StandingOrder) OccurrenceAfter(t time.Time) time.Time {
+	return o.OccurrenceOnOrAfter(Day(t).AddDate(0, 0, 1))
+}
+
+// NextRunAfter returns the date of the run after the one due at due. Dates
+// missed before now are skipped: a run made after downtime, or dropped under
+// the SKIP policy, stands for all of them.
+func (o *StandingOrder) NextRunAfter(due, now time.Time) time.Time {
+	today := Day(now)
+	next := o.OccurrenceAfter(due)
+	if next.Before(today) {
+		next = o.OccurrenceOnOrAfter(today)
+	}
+	return next
+}
+
+// Finished reports whether a run on the given date would be past the order's end
+func (o *StandingOrder) Finished(next time.Time) bool {
+	if o.MaxOccurrences > 0 && o.Occurrences >= o.MaxOccurrences {
+		return true
+	}
+	return !o.EndDate.IsZero() && next.After(o.EndDate)
+}
+
+// Missed reports whether the due run is from an earlier day than now
+func (o *StandingOrder) Missed(now time.Time) bool {
+	return o.NextRunAt.Before(Day(now))
+}
+
+// Day truncates a time to the start of its UTC day
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dayOfMonth returns day n of the month, or the month's last day when it is shorter
+func dayOfMonth(year int, month time.Month, n int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	if n > last.Day() {
+		return last
+	}
+	return time.Date(year, month, n, 0, 0, 0, 0, time.UTC)
+}
+
+// lastBusinessDay returns the last Monday to Friday of the month
+func lastBusinessDay(year int, month time.Month) time.Time {
+	day := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// date parses a YYYY-MM-DD day, failing the test on bad input
+func date(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		t.Fatalf("bad date %q", s)
+	}
+	return d
+}
+
+func TestOccurrenceOnOrAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		frequency  Frequency
+		dayOfMonth int
+		start      string
+		on         time.Time
+		want       string
+	}{
+		{"daily", FrequencyDaily, 0, "2024-01-01", time.Date(2024, 1, 5, 15, 30, 0, 0, time.UTC), "2024-01-05"},
+		{"daily before start", FrequencyDaily, 0, "2024-01-10", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), "2024-01-10"},
+		// 23:30 in New York on the 5th is already the 6th in UTC
+		{"daily behind UTC", FrequencyDaily, 0, "2024-01-01", time.Date(2024, 1, 5, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600)), "2024-01-06"},
+
+		{"weekly on the day", FrequencyWeekly, 0, "2024-01-03", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), "2024-01-10"},
+		{"weekly day after", FrequencyWeekly, 0, "2024-01-03", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC), "2024-01-17"},
+		{"weekly before start", FrequencyWeekly, 0, "2024-01-03", time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), "2024-01-03"},
+
+		{"31st in January", FrequencyMonthly, 31, "2024-01-01", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "2024-01-31"},
+		{"31st in a leap February", FrequencyMonthly, 31, "2024-01-01", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "2024-02-29"},
+		{"31st in February", FrequencyMonthly, 31, "2023-01-01", time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), "2023-02-28"},
+		{"30th in February", FrequencyMonthly, 30, "2023-01-01", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC), "2023-02-28"},
+		{"31st in April", FrequencyMonthly, 31, "2024-01-01", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "2024-04-30"},
+		{"31st in April on the 30th", FrequencyMonthly, 31, "2024-01-01", time.Date(2024, 4, 30, 12, 0, 0, 0, time.UTC), "2024-04-30"},
+		{"31st after April", FrequencyMonthly, 31, "2024-01-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2024-05-31"},
+		{"15th passed", FrequencyMonthly, 15, "2024-01-01", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), "2024-02-15"},
+		{"31st across the new year", FrequencyMonthly, 31, "2023-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "2024-01-31"},
+
+		{"last business day on a Thursday", FrequencyLastBusinessDay, 0, "2024-01-01", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "2024-02-29"},
+		{"last business day before a Saturday", FrequencyLastBusinessDay, 0, "2024-01-01", time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), "2024-08-30"},
+		{"last business day before a Sunday", FrequencyLastBusinessDay, 0, "2024-01-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "2024-03-29"},
+		{"last business day in a 30-day month", FrequencyLastBusinessDay, 0, "2024-01-01", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), "2024-06-28"},
+		{"weekend after the last business day", FrequencyLastBusinessDay, 0, "2024-01-01", time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC), "2024-09-30"},
+		{"last business day of the year", FrequencyLastBusinessDay, 0, "2023-01-01", time.Date(2023, 12, 30, 0, 0, 0, 0, time.UTC), "2024-01-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &StandingOrder{Frequency: tt.frequency, DayOfMonth: tt.dayOfMonth, StartDate: date(t, tt.start)}
+			if got := o.OccurrenceOnOrAfter(tt.on); !got.Equal(date(t, tt.want)) {
+				t.Errorf("OccurrenceOnOrAfter(%s) = %s, want %s", tt.on, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+// TestMonthEndSchedule follows a schedule on the 31st through short months;
+// a short month does not move the later dates
+func TestMonthEndSchedule(t *testing.T) {
+	o := &StandingOrder{Frequency: FrequencyMonthly, DayOfMonth: 31, StartDate: date(t, "2024-01-01")}
+
+	want := []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31", "2024-06-30", "2024-07-31"}
+
+	due := o.OccurrenceOnOrAfter(o.StartDate)
+	for i, w := range want {
+		if !due.Equal(date(t, w)) {
+			t.Fatalf("run %d = %s, want %s", i+1, due.Format(time.DateOnly), w)
+		}
+		due = o.OccurrenceAfter(due)
+	}
+}
+
+func TestNextRunAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		frequency  Frequency
+		dayOfMonth int
+		start      string
+		due        string
+		now        time.Time
+		want       string
+	}{
+		{"daily on time", FrequencyDaily, 0, "2024-01-01", "2024-01-01", time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC), "2024-01-02"},
+		{"daily after downtime", FrequencyDaily, 0, "2024-01-01", "2024-01-01", time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), "2024-01-05"},
+		{"weekly after downtime", FrequencyWeekly, 0, "2024-01-03", "2024-01-03", time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC), "2024-01-31"},
+		{"weekly after downtime on the day", FrequencyWeekly, 0, "2024-01-03", "2024-01-03", time.Date(2024, 1, 24, 9, 0, 0, 0, time.UTC), "2024-01-24"},
+		{"monthly after downtime", FrequencyMonthly, 31, "2024-01-01", "2024-01-31", time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC), "2024-04-30"},
+		{"monthly a day late", FrequencyMonthly, 31, "2024-01-01", "2024-01-31", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), "2024-02-29"},
+		{"last business day after downtime", FrequencyLastBusinessDay, 0, "2024-01-01", "2024-01-31", time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC), "2024-03-29"},
+		{"last business day missed over the weekend", FrequencyLastBusinessDay, 0, "2024-01-01", "2024-08-30", time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC), "2024-09-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &StandingOrder{Frequency: tt.frequency, DayOfMonth: tt.dayOfMonth, StartDate: date(t, tt.start)}
+			if got := o.NextRunAfter(date(t, tt.due), tt.now); !got.Equal(date(t, tt.want)) {
+				t.Errorf("NextRunAfter(%s, %s) = %s, want %s", tt.due, tt.now, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestMissed(t *testing.T) {
+	o := &StandingOrder{NextRunAt: date(t, "2024-01-01")}
+
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, 1, 1, 23, 59, 59, 0, time.UTC), false},
+		{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		// Still the 1st in UTC
+		{time.Date(2024, 1, 1, 20, 0, 0, 0, time.FixedZone("UTC-3", -3*3600)), false},
+	}
+
+	for _, tt := range tests {
+		if got := o.Missed(tt.now); got != tt.want {
+			t.Errorf("Missed(%s) = %t, want %t", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestFinished(t *testing.T) {
+	tests := []struct {
+		name  string
+		order StandingOrder
+		next  string
+		want  bool
+	}{
+		{"occurrences left", StandingOrder{MaxOccurrences: 3, Occurrences: 2}, "2024-01-10", false},
+		{"occurrences used up", StandingOrder{MaxOccurrences: 3, Occurrences: 3}, "2024-01-10", true},
+		{"on the end date", StandingOrder{EndDate: date(t, "2024-01-10")}, "2024-01-10", false},
+		{"after the end date", StandingOrder{EndDate: date(t, "2024-01-10")}, "2024-01-11", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.order.Finished(date(t, tt.next)); got != tt.want {
+				t.Errorf("Finished(%s) = %t, want %t", tt.next, got, tt.want)
+			}
+		})
+	}
+}
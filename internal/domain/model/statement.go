@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// Statement lists the postings to one balance of a user within [From, To)
+type Statement struct {
+	UserID         string
+	UserName       string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int
+	ClosingBalance int
+	TotalDebits    int
+	TotalCredits   int
+	Lines          []*StatementLine
+}
+
+// StatementLine
+type StatementLine struct {
+	// TransferID is the transfer, deposit or withdrawal behind the line; the
+	// return of a failed withdrawal is its ID with an -R suffix
+	TransferID     string
+	ReversalOf     string
+	Stan           Stan
+	Type           TransactionType
+	CounterpartyID string
+	Note           string
+	Amount         int
+	Fee            int
+	Balance        int
+	PostedAt       time.Time
+}
+
+// Net is the change to the balance; a debit also takes the fee
+func (l *StatementLine) Net() int {
+	if l.Type == TransactionTypeDebit {
+		return -(l.Amount + l.Fee)
+	}
+	return l.Amount
+}
+
+// Reconcile works back from the closing balance to the opening balance and
+// fills in the totals and the running balance after each line
+func (s *Statement) Reconcile() {
+	s.TotalDebits, s.TotalCredits = 0, 0
+
+	net := 0
+	for _, line := range s.Lines {
+		net += line.Net()
+		if line.Type == TransactionTypeDebit {
+			s.TotalDebits += line.Amount + line.Fee
+		} else {
+			s.TotalCredits += line.Amount
+		}
+	}
+
+	s.OpeningBalance = s.ClosingBalance - net
+
+	balance := s.OpeningBalance
+	for _, line := range s.Lines {
+		balance += line.Net()
+		line.Balance = balance
+	}
+}
@@ -21,11 +21,23 @@ const (
 	TransactionStatePending   TransactionState = "PENDING"
 	TransactionStateCompleted TransactionState = "COMPLETED"
 	TransactionStateFailed    TransactionState = "FAILED"
+	TransactionStateVoided    TransactionState = "VOIDED"
+	TransactionStateExpired   TransactionState = "EXPIRED"
+	// TransactionStateHeld is a transfer parked for risk review; REJECTED is one the reviewer turned down
+	TransactionStateHeld     TransactionState = "HELD"
+	TransactionStateRejected TransactionState = "REJECTED"
+
+	TransactionStateReversed          TransactionState = "REVERSED"
+	TransactionStatePartiallyReversed TransactionState = "PARTIALLY_REVERSED"
 
 	PaymentMethodTypeTransfer PaymentMethodType = "TRANSFER"
+	PaymentMethodTypeCard     PaymentMethodType = "CARD"
+	PaymentMethodTypeBank     PaymentMethodType = "BANK"
 )
 
-// Transaction
+// Transaction; the card fields are set on the debit side of a card-funded
+// transfer. Card holds the masked number, CardAcct the acquirer's token and
+// BankC the issuer's BIN. InstanceID is the terminal the STAN is unique to.
 type Transaction struct {
 	Stan            Stan
 	AppID           int
@@ -37,8 +49,13 @@ type Transaction struct {
 	CardAcct        string
 	BankC           string
 	Expiry          string
+	AuthCode        string
+	ResponseCode    string
 	Amount          int
+	Currency        string
 	Fee             int
+	FXRate          string
+	FXSpreadBps     int
 	State           TransactionState
 	TransactionType TransactionType
 	PaymentSource   PaymentMethodType
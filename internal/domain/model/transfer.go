@@ -4,13 +4,46 @@ import "time"
 
 // Transfer
 type Transfer struct {
-	ID          string
-	FromUserID  string
-	ToUserID    string
-	Amount      int
-	State       TransactionState
-	DebitTx     *Transaction
-	CreditTx    *Transaction
-	CreatedAt   time.Time
-	CompletedAt time.Time
+	ID              string
+	FromUserID      string
+	ToUserID        string
+	Amount          int
+	Currency        string
+	Fee             int
+	QuoteID         string
+	State           TransactionState
+	ReversalOf      string
+	ReversedAmount  int
+	Reversals       []string
+	StandingOrderID string
+	DebitTx         *Transaction
+	CreditTx        *Transaction
+	CreatedAt       time.Time
+	CompletedAt     time.Time
+	// Version is the transfer's event stream version when it was loaded or
+	// last changed; a change is only recorded on top of that version
+	Version int
+}
+
+// CardFunded reports whether the transfer is paid by card rather than from
+// the sender's balance
+func (t *Transfer) CardFunded() bool {
+	return t.DebitTx != nil && t.DebitTx.PaymentSource == PaymentMethodTypeCard
+}
+
+// Reversible; a card-funded transfer is not, as its funds would have to go
+// back to the card
+func (t *Transfer) Reversible() bool {
+	if t.ReversalOf != "" || t.CardFunded() {
+		return false
+	}
+	return t.State == TransactionStateCompleted || t.State == TransactionStatePartiallyReversed
+}
+
+// RemainingAmount is the part of the credited amount not yet reversed
+func (t *Transfer) RemainingAmount() int {
+	if t.CreditTx == nil {
+		return 0
+	}
+	return t.CreditTx.Amount - t.ReversedAmount
 }
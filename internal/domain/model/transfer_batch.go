@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// BatchMode
+type BatchMode string
+
+// BatchStatus
+type BatchStatus string
+
+// BatchItemStatus
+type BatchItemStatus string
+
+const (
+	// BatchModeAtomic runs every item in one transaction: all of them complete or none do
+	BatchModeAtomic BatchMode = "ATOMIC"
+	// BatchModeBestEffort runs each item on its own
+	BatchModeBestEffort BatchMode = "BEST_EFFORT"
+
+	BatchStatusPending            BatchStatus = "PENDING"
+	BatchStatusProcessing         BatchStatus = "PROCESSING"
+	BatchStatusCompleted          BatchStatus = "COMPLETED"
+	BatchStatusPartiallyCompleted BatchStatus = "PARTIALLY_COMPLETED"
+	BatchStatusFailed             BatchStatus = "FAILED"
+	// BatchStatusPendingReview waits for the risk review of its held items
+	BatchStatusPendingReview BatchStatus = "PENDING_REVIEW"
+
+	BatchItemStatusPending   BatchItemStatus = "PENDING"
+	BatchItemStatusCompleted BatchItemStatus = "COMPLETED"
+	BatchItemStatusFailed    BatchItemStatus = "FAILED"
+	// BatchItemStatusHeld marks a best-effort item whose transfer is held for risk review
+	BatchItemStatusHeld BatchItemStatus = "HELD"
+	// BatchItemStatusRolledBack marks the items of an atomic batch that failed on another item
+	BatchItemStatusRolledBack BatchItemStatus = "ROLLED_BACK"
+)
+
+// TransferBatch
+type TransferBatch struct {
+	ID          string
+	Mode        BatchMode
+	Status      BatchStatus
+	Items       []*TransferBatchItem
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt time.Time
+}
+
+// TransferBatchItem
+type TransferBatchItem struct {
+	Index      int
+	FromUserID string
+	ToUserID   string
+	Amount     int
+	Currency   string
+	Status     BatchItemStatus
+	TransferID string
+	Error      string
+}
+
+// Finished
+func (b *TransferBatch) Finished() bool {
+	switch b.Status {
+	case BatchStatusCompleted, BatchStatusPartiallyCompleted, BatchStatusFailed:
+		return true
+	}
+	return false
+}
+
+// Count returns the number of items in the given status
+func (b *TransferBatch) Count(status BatchItemStatus) int {
+	n := 0
+	for _, item := range b.Items {
+		if item.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Settle derives the batch status from its items once none is pending. A
+// batch with held items waits in PENDING_REVIEW until their reviews close.
+func (b *TransferBatch) Settle(now time.Time) {
+	if b.Count(BatchItemStatusPending) > 0 {
+		return
+	}
+
+	b.UpdatedAt = now
+	if b.Count(BatchItemStatusHeld) > 0 {
+		b.Status = BatchStatusPendingReview
+		return
+	}
+
+	completed := b.Count(BatchItemStatusCompleted)
+	switch {
+	case completed == len(b.Items):
+		b.Status = BatchStatusCompleted
+	case completed == 0:
+		b.Status = BatchStatusFailed
+	default:
+		b.Status = BatchStatusPartiallyCompleted
+	}
+
+	b.CompletedAt = now
+}
+
+// IdempotencyKey identifies the transfer of a best-effort item, so that
+// reprocessing an interrupted batch does not run it twice
+func (b *TransferBatch) IdempotencyKey(item *TransferBatchItem) string {
+	return fmt.Sprintf("batch:%s:%d", b.ID, item.Index)
+}
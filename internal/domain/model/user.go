@@ -1,8 +1,83 @@
 package model
 
+// Balance
+type Balance struct {
+	Currency string
+	// Amount is the ledger balance
+	Amount int
+	// Held is the sum of active holds of authorized transfers
+	Held int
+}
+
+// Available is what can still be spent: the ledger balance less active holds
+func (b *Balance) Available() int {
+	return b.Amount - b.Held
+}
+
+// UserStatus
+type UserStatus string
+
+const (
+	UserStatusActive UserStatus = "ACTIVE"
+	// UserStatusFrozen blocks transfers from and to the account until it is unfrozen
+	UserStatusFrozen UserStatus = "FROZEN"
+	// UserStatusClosed is final; a closed account holds no funds
+	UserStatusClosed UserStatus = "CLOSED"
+)
+
+// Valid
+func (s UserStatus) Valid() bool {
+	switch s {
+	case UserStatusActive, UserStatusFrozen, UserStatusClosed:
+		return true
+	}
+	return false
+}
+
+// CanChangeTo reports whether an account in this status can move to next
+func (s UserStatus) CanChangeTo(next UserStatus) bool {
+	return next.Valid() && s != UserStatusClosed
+}
+
 // User
 type User struct {
-	ID      string
-	Name    string
-	Balance int
+	ID       string
+	Name     string
+	Tier     AccountTier
+	Status   UserStatus
+	Balances []*Balance
+	// Version is the event stream version of the user's balances when the
+	// user was locked; ledger postings are recorded on top of that version
+	Version int
+}
+
+// CheckActive returns ErrAccountFrozen or ErrAccountClosed for an account that cannot transact
+func (u *User) CheckActive() error {
+	switch u.Status {
+	case UserStatusFrozen:
+		return ErrAccountFrozen
+	case UserStatusClosed:
+		return ErrAccountClosed
+	}
+	return nil
+}
+
+// Empty reports whether the user holds no funds in any currency and has no active holds
+func (u *User) Empty() bool {
+	for _, b := range u.Balances {
+		if b.Amount != 0 || b.Held != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BalanceIn returns the user's balance in the given currency, if the user holds it
+func (u *User) BalanceIn(currency string) (*Balance, bool) {
+	for _, b := range u.Balances {
+		if b.Currency == currency {
+			return b, true
+		}
+	}
+	return nil, false
 }
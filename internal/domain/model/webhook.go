@@ -0,0 +1,81 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// WebhookDeliveryStatus
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// Valid
+func (s WebhookDeliveryStatus) Valid() bool {
+	switch s {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookSubscription sends the events it subscribes to to a URL, signed with
+// its secret. An event type pattern is an event type, a prefix ending in "*"
+// such as "transfer_*", or "*" for every event.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	Secret     string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Matches
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	for _, pattern := range s.EventTypes {
+		if MatchEventType(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchEventType
+func MatchEventType(pattern, eventType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventType, prefix)
+	}
+	return pattern == eventType
+}
+
+// WebhookDelivery is one event on its way to one subscription
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        int64
+	EventType      string
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+	AttemptLog     []*WebhookAttempt
+}
+
+// WebhookAttempt is one POST of a delivery and how the receiver answered
+type WebhookAttempt struct {
+	AttemptedAt time.Time
+	Success     bool
+	// StatusCode is zero when no answer was received
+	StatusCode   int
+	Error        string
+	ResponseBody string
+	Duration     time.Duration
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// CardAcquirer tokenizes cards and authorizes card payments
+type CardAcquirer interface {
+	// Tokenize registers the card with the acquirer and returns the token that
+	// stands for its number from then on
+	Tokenize(ctx context.Context, card *model.CardDetails) (*model.CardToken, error)
+	// Authorize charges the card behind a token. A request sent again with the
+	// same terminal, STAN and business date gets the original answer.
+	Authorize(ctx context.Context, req *model.CardAuthorization) (*model.CardAuthorizationResult, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// CardRepository
+type CardRepository interface {
+	// Create saves the card and sets its ID; a card the user already
+	// registered is returned as it was saved
+	Create(card *model.Card) error
+	GetByID(id string) (*model.Card, error)
+	ListByUser(userID string) ([]*model.Card, error)
+}
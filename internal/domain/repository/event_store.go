@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// EventStore reads the append-only event streams. Events are appended in the
+// transaction of the state change they record.
+type EventStore interface {
+	// Load returns the stream of one aggregate in version order
+	Load(aggregateType, aggregateID string) ([]*model.StoredEvent, error)
+	// ReadAll returns up to limit events after the given position, in position order
+	ReadAll(after int64, limit int) ([]*model.StoredEvent, error)
+}
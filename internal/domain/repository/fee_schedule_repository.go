@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// FeeScheduleRepository
+type FeeScheduleRepository interface {
+	List() ([]*model.FeeSchedule, error)
+	ListByCurrency(currency string) ([]*model.FeeSchedule, error)
+}
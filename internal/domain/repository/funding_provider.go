@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// FundingProvider moves funds between the system and external cards and bank accounts
+type FundingProvider interface {
+	// Collect pulls funds from the external account for a deposit
+	Collect(ctx context.Context, req *model.FundingRequest) (*model.FundingResult, error)
+	// Payout pushes funds to the external account for a withdrawal
+	Payout(ctx context.Context, req *model.FundingRequest) (*model.FundingResult, error)
+	// Status reports the outcome of an operation the provider left pending
+	Status(ctx context.Context, reference string) (*model.FundingResult, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// FundingRepository
+type FundingRepository interface {
+	GetByID(id string) (*model.Funding, error)
+	// ListPending returns the IDs of pending operations last updated before the given time
+	ListPending(updatedBefore time.Time, limit int) ([]string, error)
+}
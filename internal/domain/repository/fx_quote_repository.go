@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// FXQuoteRepository
+type FXQuoteRepository interface {
+	Create(quote *model.FXQuote) error
+	GetByID(id string) (*model.FXQuote, error)
+}
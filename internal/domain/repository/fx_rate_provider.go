@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// FXRateProvider supplies mid-market exchange rates
+type FXRateProvider interface {
+	GetRate(base, quote string) (*model.FXRate, error)
+}
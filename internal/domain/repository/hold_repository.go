@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// HoldRepository
+type HoldRepository interface {
+	GetByTransferID(transferID string) (*model.Hold, error)
+	ListExpired(now time.Time, limit int) ([]*model.Hold, error)
+}
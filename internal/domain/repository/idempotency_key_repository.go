@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// IdempotencyKeyRepository
+type IdempotencyKeyRepository interface {
+	GetByKey(key string) (*model.IdempotencyKey, error)
+	// SaveResponse stores the first response given to the key's request
+	SaveResponse(key string, response *model.IdempotentResponse) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// LedgerRepository
+type LedgerRepository interface {
+	BalanceMismatches() ([]*model.BalanceMismatch, error)
+	JournalImbalances() ([]*model.JournalImbalance, error)
+	AccountTotals(account string, from, to time.Time) ([]*model.AccountTotal, error)
+}
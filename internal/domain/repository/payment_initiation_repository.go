@@ -0,0 +1,10 @@
+package repository
+
+// PaymentInitiationRepository records the pain.001 payment informations that
+// were executed, identified by message ID and payment information ID
+type PaymentInitiationRepository interface {
+	// Claim records the payment information and reports whether it was new
+	Claim(messageID, paymentInfoID string) (bool, error)
+	// Exists reports whether the payment information was already claimed
+	Exists(messageID, paymentInfoID string) (bool, error)
+}
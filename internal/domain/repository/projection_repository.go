@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/IskenT/money-transfer/internal/domain/model"
+
+// ProjectionRepository stores the read models rebuilt from the event store
+type ProjectionRepository interface {
+	// Replace swaps the contents of the projection tables for the given rows
+	Replace(transfers []*model.ProjectedTransfer, balances []*model.ProjectedBalance) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// Publisher delivers outbox events to a downstream system. An event may be
+// published more than once, so consumers must tolerate duplicates.
+type Publisher interface {
+	Publish(ctx context.Context, event *model.Event) error
+	Close() error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// RiskEngine screens transfers before they are committed
+type RiskEngine interface {
+	// Assess decides whether the transfer may go ahead, must be held for
+	// review or is denied, and why
+	Assess(ctx context.Context, check *model.RiskCheck) (*model.RiskAssessment, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// RiskReviewRepository
+type RiskReviewRepository interface {
+	GetByTransferID(transferID string) (*model.RiskReview, error)
+	// List returns reviews oldest first; an empty status lists all of them
+	List(status model.RiskReviewStatus, limit int) ([]*model.RiskReview, error)
+	// ListExpired returns pending reviews created before the given time
+	ListExpired(createdBefore time.Time, limit int) ([]*model.RiskReview, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// ScheduledTransferRepository
+type ScheduledTransferRepository interface {
+	Create(schedule *model.ScheduledTransfer) error
+	GetByID(id string) (*model.ScheduledTransfer, error)
+	List() ([]*model.ScheduledTransfer, error)
+	ListDue(now, staleBefore time.Time, limit int) ([]*model.ScheduledTransfer, error)
+	Claim(id string, now, staleBefore time.Time) (bool, error)
+	Cancel(id string) error
+	Complete(schedule *model.ScheduledTransfer) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// StandingOrderRepository
+type StandingOrderRepository interface {
+	Create(order *model.StandingOrder) error
+	GetByID(id string) (*model.StandingOrder, error)
+	List() ([]*model.StandingOrder, error)
+	ListDue(now time.Time, limit int) ([]*model.StandingOrder, error)
+	UpdateStatus(order *model.StandingOrder, from model.StandingOrderStatus) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// StatementRepository
+type StatementRepository interface {
+	GetStatement(userID, currency string, from, to time.Time) (*model.Statement, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// TransferBatchRepository
+type TransferBatchRepository interface {
+	Create(batch *model.TransferBatch) error
+	GetByID(id string) (*model.TransferBatch, error)
+	ListOpen(staleBefore time.Time, limit int) ([]string, error)
+	Claim(id string, now, staleBefore time.Time) (bool, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// TransferLimitRepository
+type TransferLimitRepository interface {
+	ListByCurrency(currency string) ([]*model.TransferLimit, error)
+	// GetUsage returns the sender's usage in the windows containing at,
+	// hour first; windows without transfers have zero usage
+	GetUsage(userID, currency string, at time.Time) ([]*model.LimitUsage, error)
+}
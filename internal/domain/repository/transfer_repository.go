@@ -1,10 +1,56 @@
 package repository
 
-import "github.com/IskenT/money-transfer/internal/domain/model"
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// TransferDirection
+type TransferDirection string
+
+// TransferSortField
+type TransferSortField string
+
+const (
+	// DirectionIncoming selects the transfers ListFilter.UserID received
+	DirectionIncoming TransferDirection = "incoming"
+	// DirectionOutgoing selects the transfers ListFilter.UserID sent
+	DirectionOutgoing TransferDirection = "outgoing"
+
+	SortByCreatedAt TransferSortField = "created_at"
+	SortByAmount    TransferSortField = "amount"
+)
+
+// ListFilter selects one page of transfers. Zero values do not filter.
+type ListFilter struct {
+	UserID    string
+	Direction TransferDirection
+	States    []model.TransactionState
+	MinAmount int
+	MaxAmount int
+	// CreatedFrom is inclusive, CreatedTo is exclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	SortBy      TransferSortField
+	Descending  bool
+	Limit       int
+	// Cursor is the NextCursor of the previous page, with the same filter
+	Cursor string
+}
+
+// TransferPage
+type TransferPage struct {
+	Transfers  []*model.Transfer
+	NextCursor string
+}
 
 // TransferRepository
 type TransferRepository interface {
 	Create(transfer *model.Transfer) error
 	GetByID(id string) (*model.Transfer, error)
-	List() ([]*model.Transfer, error)
+	List(filter ListFilter) (*TransferPage, error)
+	// ListPendingCard returns the IDs of card-funded transfers still waiting
+	// for the acquirer that were created before the given time
+	ListPendingCard(createdBefore time.Time, limit int) ([]string, error)
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// WebhookRepository
+type WebhookRepository interface {
+	CreateSubscription(subscription *model.WebhookSubscription) error
+	GetSubscription(id string) (*model.WebhookSubscription, error)
+	ListSubscriptions() ([]*model.WebhookSubscription, error)
+	UpdateSubscription(subscription *model.WebhookSubscription) error
+	// DeleteSubscription also deletes its deliveries and their attempts
+	DeleteSubscription(id string) error
+
+	// CreateDeliveries queues the event for the subscriptions. An event
+	// already queued for a subscription is skipped.
+	CreateDeliveries(event *model.Event, subscriptionIDs []string) error
+	// ClaimDue leases due deliveries of active subscriptions until leaseUntil,
+	// when they are due again unless an attempt has been recorded
+	ClaimDue(now, leaseUntil time.Time, limit int) ([]*model.WebhookDelivery, error)
+	// RecordAttempt logs the attempt and saves the delivery's new state
+	RecordAttempt(delivery *model.WebhookDelivery, attempt *model.WebhookAttempt) error
+	GetDelivery(subscriptionID, id string) (*model.WebhookDelivery, error)
+	// ListDeliveries returns deliveries newest first, with their attempts;
+	// an empty status lists all of them
+	ListDeliveries(subscriptionID string, status model.WebhookDeliveryStatus, limit int) ([]*model.WebhookDelivery, error)
+	// Redeliver makes a delivery PENDING and due at the given time, with a
+	// fresh set of attempts
+	Redeliver(subscriptionID, id string, now time.Time) error
+
+	GetEvent(id int64) (*model.Event, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// WebhookSender posts an event to a subscription's URL
+type WebhookSender interface {
+	// Send makes one attempt at the delivery. A failed attempt is reported in
+	// the result, not as an error.
+	Send(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery, event *model.Event) *model.WebhookAttempt
+}
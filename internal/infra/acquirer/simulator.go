@@ -0,0 +1,98 @@
+package acquirer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+const simulatorTokenPrefix = "sim_"
+
+// simulatorDeclines maps the last four digits of test cards to the decline
+// the simulator answers with
+var simulatorDeclines = map[string]struct {
+	code   string
+	reason string
+}{
+	"0002": {"05", "do not honor"},
+	"9995": {"51", "insufficient funds"},
+	"0069": {"54", "expired card"},
+	"0119": {"91", "issuer unavailable"},
+}
+
+// Simulator is an in-process acquirer, so that card payments run offline.
+// Cards are approved unless their number ends in one of the test suffixes
+// in simulatorDeclines. Tokens and answers are derived from the secret, so
+// the simulator keeps no state and answers the same request the same way
+// every time.
+type Simulator struct {
+	secret []byte
+}
+
+// NewSimulator
+func NewSimulator(secret string) *Simulator {
+	return &Simulator{
+		secret: []byte(secret),
+	}
+}
+
+// Tokenize returns the same token for the same card. The token carries the
+// last four digits, which is all the simulator needs to decide an authorization.
+func (s *Simulator) Tokenize(ctx context.Context, card *model.CardDetails) (*model.CardToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !model.ValidPAN(card.Number) {
+		return nil, model.ErrInvalidCardNumber
+	}
+
+	last4 := card.Number[len(card.Number)-4:]
+	mac := s.mac(card.Number, fmt.Sprintf("%02d%04d", card.ExpiryMonth, card.ExpiryYear))
+
+	return &model.CardToken{
+		Token: simulatorTokenPrefix + last4 + "_" + mac[:24],
+	}, nil
+}
+
+// Authorize
+func (s *Simulator) Authorize(ctx context.Context, req *model.CardAuthorization) (*model.CardAuthorizationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strings.TrimPrefix(req.Token, simulatorTokenPrefix), "_")
+	if !strings.HasPrefix(req.Token, simulatorTokenPrefix) || len(parts) != 2 || len(parts[0]) != 4 {
+		return &model.CardAuthorizationResult{
+			ResponseCode: "14",
+			Reason:       "invalid card",
+		}, nil
+	}
+
+	if decline, ok := simulatorDeclines[parts[0]]; ok {
+		return &model.CardAuthorizationResult{
+			ResponseCode: decline.code,
+			Reason:       decline.reason,
+		}, nil
+	}
+
+	mac := s.mac(req.TerminalID, req.BusinessDate.Format("20060102"), string(req.Stan))
+
+	return &model.CardAuthorizationResult{
+		Approved:     true,
+		AuthCode:     strings.ToUpper(mac[:6]),
+		ResponseCode: model.CardResponseApproved,
+	}, nil
+}
+
+// mac
+func (s *Simulator) mac(parts ...string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -3,28 +3,125 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// RetryPolicy controls how often a transaction that lost a serialization
+// conflict or was picked as a deadlock victim is run again
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// backoff returns a random delay of up to BaseDelay*2^attempt, capped at MaxDelay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// TxOptions
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	Retry     *RetryPolicy
+}
+
+// TxOption
+type TxOption func(*TxOptions)
+
+// WithIsolation overrides the default REPEATABLE READ isolation level
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+// WithReadOnly starts the transaction READ ONLY, so that it can only read
+func WithReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+// WithRetryPolicy runs the transaction again on SQLSTATE 40001 and 40P01.
+// The function must be safe to run more than once.
+func WithRetryPolicy(policy RetryPolicy) TxOption {
+	return func(o *TxOptions) {
+		o.Retry = &policy
+	}
+}
+
 // TransactionManager
 type TransactionManager struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	retry RetryPolicy
 }
 
 // NewTransactionManager
-func NewTransactionManager(db *sqlx.DB) *TransactionManager {
+func NewTransactionManager(db *sqlx.DB, retry RetryPolicy) *TransactionManager {
 	return &TransactionManager{
-		db: db,
+		db:    db,
+		retry: retry,
 	}
 }
 
+// WithRetry runs the transaction again on SQLSTATE 40001 and 40P01 using the
+// manager's retry policy. The function must be safe to run more than once.
+func (m *TransactionManager) WithRetry() TxOption {
+	return WithRetryPolicy(m.retry)
+}
+
 // WithTransaction
-func (m *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
-	tx, err := m.db.BeginTxx(ctx, &sql.TxOptions{
+func (m *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error, opts ...TxOption) error {
+	options := TxOptions{
 		Isolation: sql.LevelRepeatableRead,
-		ReadOnly:  false,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.runTransaction(ctx, options, fn)
+		if err == nil {
+			return nil
+		}
+
+		if options.Retry == nil || attempt >= options.Retry.MaxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(options.Retry.backoff(attempt - 1)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// runTransaction
+func (m *TransactionManager) runTransaction(ctx context.Context, options TxOptions, fn func(ctx context.Context, tx *sqlx.Tx) error) (err error) {
+	tx, err := m.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: options.Isolation,
+		ReadOnly:  options.ReadOnly,
 	})
 	if err != nil {
 		return fmt.Errorf("error beginning transaction: %w", err)
@@ -48,6 +145,21 @@ func (m *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx co
 	return err
 }
 
+// IsRetryable reports whether the error is a serialization failure, a
+// deadlock or a lost event store append, after which the whole transaction
+// can safely be run again
+func IsRetryable(err error) bool {
+	if errors.Is(err, model.ErrVersionConflict) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+}
+
 // DB
 func (m *TransactionManager) DB() *sqlx.DB {
 	return m.db
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		max     time.Duration
+	}{
+		{"first attempt", RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}, 0, 10 * time.Millisecond},
+		{"doubled", RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}, 3, 80 * time.Millisecond},
+		{"capped", RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}, 10, 50 * time.Millisecond},
+		{"shift overflows to negative", RetryPolicy{BaseDelay: time.Second, MaxDelay: 500 * time.Millisecond}, 34, 500 * time.Millisecond},
+		{"shift overflows to zero", RetryPolicy{BaseDelay: time.Second, MaxDelay: 500 * time.Millisecond}, 64, 500 * time.Millisecond},
+		{"no base delay", RetryPolicy{MaxDelay: 500 * time.Millisecond}, 2, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				delay := tt.policy.backoff(tt.attempt)
+				if delay <= 0 || delay > tt.max {
+					t.Fatalf("backoff(%d) = %s, want in (0, %s]", tt.attempt, delay, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffWithoutDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	for attempt := 0; attempt < 70; attempt++ {
+		if delay := policy.backoff(attempt); delay != 0 {
+			t.Fatalf("backoff(%d) = %s, want 0", attempt, delay)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("connection refused"), false},
+		{"serialization failure", &pgconn.PgError{Code: pgSerializationFailure}, true},
+		{"deadlock", &pgconn.PgError{Code: pgDeadlockDetected}, true},
+		{"wrapped deadlock", fmt.Errorf("error locking user: %w", &pgconn.PgError{Code: pgDeadlockDetected}), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"version conflict", model.ErrVersionConflict, true},
+		{"wrapped version conflict", fmt.Errorf("error appending event: %w", model.ErrVersionConflict), true},
+		{"domain error", model.ErrInsufficientFunds, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
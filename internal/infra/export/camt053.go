@@ -0,0 +1,249 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// camt053Namespace
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+type camtDocument struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Namespace string        `xml:"xmlns,attr"`
+	Statement camtBkToCstmr `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstmr struct {
+	GrpHdr camtGrpHdr `xml:"GrpHdr"`
+	Stmt   camtStmt   `xml:"Stmt"`
+}
+
+type camtGrpHdr struct {
+	MsgID   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camtStmt struct {
+	ID        string        `xml:"Id"`
+	CreDtTm   string        `xml:"CreDtTm"`
+	FrToDt    camtFrToDt    `xml:"FrToDt"`
+	Acct      camtAcct      `xml:"Acct"`
+	Bal       []camtBal     `xml:"Bal"`
+	TxsSummry camtTxsSummry `xml:"TxsSummry"`
+	Ntry      []camtNtry    `xml:"Ntry"`
+}
+
+type camtFrToDt struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAcct struct {
+	ID  string `xml:"Id>Othr>Id"`
+	Ccy string `xml:"Ccy"`
+	Nm  string `xml:"Nm,omitempty"`
+}
+
+type camtAmt struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtBal struct {
+	Tp        string  `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmt `xml:"Amt"`
+	CdtDbtInd string  `xml:"CdtDbtInd"`
+	DtTm      string  `xml:"Dt>DtTm"`
+}
+
+type camtTxsSummry struct {
+	TtlNtries    camtTtlNtries `xml:"TtlNtries"`
+	TtlCdtNtries camtNbAndSum  `xml:"TtlCdtNtries"`
+	TtlDbtNtries camtNbAndSum  `xml:"TtlDbtNtries"`
+}
+
+type camtTtlNtries struct {
+	NbOfNtries int    `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+	TtlNetNtry struct {
+		Amt       string `xml:"Amt"`
+		CdtDbtInd string `xml:"CdtDbtInd"`
+	} `xml:"TtlNetNtry"`
+}
+
+type camtNbAndSum struct {
+	NbOfNtries int    `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtNtry struct {
+	NtryRef     string     `xml:"NtryRef"`
+	Amt         camtAmt    `xml:"Amt"`
+	CdtDbtInd   string     `xml:"CdtDbtInd"`
+	RvslInd     bool       `xml:"RvslInd,omitempty"`
+	Sts         string     `xml:"Sts>Cd"`
+	BookgDtTm   string     `xml:"BookgDt>DtTm"`
+	ValDtTm     string     `xml:"ValDt>DtTm"`
+	AcctSvcrRef string     `xml:"AcctSvcrRef"`
+	BkTxCd      string     `xml:"BkTxCd>Prtry>Cd"`
+	TxDtls      camtTxDtls `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTxDtls struct {
+	EndToEndID string         `xml:"Refs>EndToEndId"`
+	Amt        camtAmt        `xml:"Amt"`
+	CdtDbtInd  string         `xml:"CdtDbtInd"`
+	Chrgs      *camtChrgs     `xml:"Chrgs,omitempty"`
+	RltdPties  *camtRltdPties `xml:"RltdPties,omitempty"`
+	Ustrd      string         `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+type camtChrgs struct {
+	TtlChrgsAndTaxAmt camtAmt `xml:"TtlChrgsAndTaxAmt"`
+}
+
+type camtRltdPties struct {
+	DbtrAcct *camtAcctRef `xml:"DbtrAcct,omitempty"`
+	CdtrAcct *camtAcctRef `xml:"CdtrAcct,omitempty"`
+}
+
+type camtAcctRef struct {
+	ID string `xml:"Id>Othr>Id"`
+}
+
+// camtTime formats a timestamp as an ISO 8601 date-time in UTC
+func camtTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// camtIndicator splits a signed amount into its absolute value and CRDT or DBIT
+func camtIndicator(amount int) (int, string) {
+	if amount < 0 {
+		return -amount, "DBIT"
+	}
+	return amount, "CRDT"
+}
+
+// renderCAMT053 writes an ISO 20022 camt.053.001.08 bank-to-customer statement
+// with the opening and closing booked balances and one booked entry per line
+func renderCAMT053(w io.Writer, s *model.Statement, c model.Currency, now time.Time) error {
+	id := fmt.Sprintf("STMT-%s-%s-%s", s.UserID, s.Currency, s.From.UTC().Format("20060102150405"))
+	amt := func(amount int) camtAmt {
+		return camtAmt{Ccy: s.Currency, Value: c.Decimal(amount)}
+	}
+
+	stmt := camtStmt{
+		ID:      id,
+		CreDtTm: camtTime(now),
+		FrToDt: camtFrToDt{
+			FrDtTm: camtTime(s.From),
+			ToDtTm: camtTime(s.To),
+		},
+		Acct: camtAcct{
+			ID:  s.UserID,
+			Ccy: s.Currency,
+			Nm:  s.UserName,
+		},
+		Ntry: make([]camtNtry, len(s.Lines)),
+	}
+
+	for _, b := range []struct {
+		code   string
+		amount int
+		at     time.Time
+	}{
+		{"OPBD", s.OpeningBalance, s.From},
+		{"CLBD", s.ClosingBalance, s.To},
+	} {
+		amount, indicator := camtIndicator(b.amount)
+		stmt.Bal = append(stmt.Bal, camtBal{
+			Tp:        b.code,
+			Amt:       amt(amount),
+			CdtDbtInd: indicator,
+			DtTm:      camtTime(b.at),
+		})
+	}
+
+	summary := &stmt.TxsSummry
+	summary.TtlNtries.NbOfNtries = len(s.Lines)
+	summary.TtlNtries.Sum = c.Decimal(s.TotalCredits + s.TotalDebits)
+	net, indicator := camtIndicator(s.TotalCredits - s.TotalDebits)
+	summary.TtlNtries.TtlNetNtry.Amt = c.Decimal(net)
+	summary.TtlNtries.TtlNetNtry.CdtDbtInd = indicator
+	summary.TtlCdtNtries.Sum = c.Decimal(s.TotalCredits)
+	summary.TtlDbtNtries.Sum = c.Decimal(s.TotalDebits)
+
+	for i, l := range s.Lines {
+		amount, indicator := camtIndicator(l.Net())
+
+		txDtls := camtTxDtls{
+			EndToEndID: l.TransferID,
+			Amt:        amt(l.Amount),
+			CdtDbtInd:  indicator,
+			Ustrd:      l.Note,
+		}
+
+		// Deposits and withdrawals have no counterparty in the system
+		if l.CounterpartyID != "" {
+			txDtls.RltdPties = &camtRltdPties{}
+		}
+
+		if l.Type == model.TransactionTypeDebit {
+			summary.TtlDbtNtries.NbOfNtries++
+			if txDtls.RltdPties != nil {
+				txDtls.RltdPties.CdtrAcct = &camtAcctRef{ID: l.CounterpartyID}
+			}
+		} else {
+			summary.TtlCdtNtries.NbOfNtries++
+			if txDtls.RltdPties != nil {
+				txDtls.RltdPties.DbtrAcct = &camtAcctRef{ID: l.CounterpartyID}
+			}
+		}
+
+		if l.Fee > 0 {
+			txDtls.Chrgs = &camtChrgs{TtlChrgsAndTaxAmt: amt(l.Fee)}
+		}
+
+		stmt.Ntry[i] = camtNtry{
+			NtryRef:     l.TransferID,
+			Amt:         amt(amount),
+			CdtDbtInd:   indicator,
+			RvslInd:     l.ReversalOf != "",
+			Sts:         "BOOK",
+			BookgDtTm:   camtTime(l.PostedAt),
+			ValDtTm:     camtTime(l.PostedAt),
+			AcctSvcrRef: string(l.Stan),
+			BkTxCd:      "TRANSFER",
+			TxDtls:      txDtls,
+		}
+	}
+
+	doc := camtDocument{
+		Namespace: camt053Namespace,
+		Statement: camtBkToCstmr{
+			GrpHdr: camtGrpHdr{
+				MsgID:   id,
+				CreDtTm: camtTime(now),
+			},
+			Stmt: stmt,
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
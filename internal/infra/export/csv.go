@@ -0,0 +1,47 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// csvHeader
+var csvHeader = []string{
+	"posted_at", "transfer_id", "reversal_of", "stan", "type", "counterparty_id",
+	"description", "currency", "amount", "fee", "net", "balance",
+}
+
+// renderCSV writes one row per line with amounts in major units
+func renderCSV(w io.Writer, s *model.Statement, c model.Currency) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, l := range s.Lines {
+		err := cw.Write([]string{
+			l.PostedAt.UTC().Format(time.RFC3339),
+			l.TransferID,
+			l.ReversalOf,
+			string(l.Stan),
+			string(l.Type),
+			l.CounterpartyID,
+			l.Note,
+			s.Currency,
+			c.Decimal(l.Amount),
+			c.Decimal(l.Fee),
+			c.Decimal(l.Net()),
+			c.Decimal(l.Balance),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// Format
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatOFX     Format = "ofx"
+	FormatCAMT053 Format = "camt053"
+)
+
+// ErrUnsupportedFormat
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// mediaTypes maps the media types accepted in an Accept header to a format
+var mediaTypes = map[string]Format{
+	"text/csv":                              FormatCSV,
+	"application/x-ofx":                     FormatOFX,
+	"application/ofx":                       FormatOFX,
+	"application/vnd.iso20022.camt.053+xml": FormatCAMT053,
+	"application/xml":                       FormatCAMT053,
+	"text/xml":                              FormatCAMT053,
+}
+
+// Negotiate picks the format from the format query parameter, falling back to
+// the first supported media type of the Accept header, then to CSV
+func Negotiate(format, accept string) (Format, error) {
+	if format != "" {
+		switch f := Format(strings.ToLower(format)); f {
+		case FormatCSV, FormatOFX, FormatCAMT053:
+			return f, nil
+		}
+		return "", ErrUnsupportedFormat
+	}
+
+	if accept == "" {
+		return FormatCSV, nil
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		if f, ok := mediaTypes[mediaType]; ok {
+			return f, nil
+		}
+
+		if mediaType == "*/*" || mediaType == "text/*" {
+			return FormatCSV, nil
+		}
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// ContentType
+func (f Format) ContentType() string {
+	switch f {
+	case FormatOFX:
+		return "application/x-ofx"
+	case FormatCAMT053:
+		return "application/xml; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension
+func (f Format) Extension() string {
+	if f == FormatCAMT053 {
+		return "xml"
+	}
+	return string(f)
+}
+
+// Filename names the export after the account, currency and period
+func Filename(s *model.Statement, f Format) string {
+	return fmt.Sprintf("statement-%s-%s-%s-%s.%s",
+		s.UserID, s.Currency, s.From.UTC().Format("20060102"), s.To.UTC().Format("20060102"), f.Extension())
+}
+
+// Render writes the statement in the given format; now is when it is generated
+func Render(w io.Writer, f Format, s *model.Statement, now time.Time) error {
+	c, err := model.LookupCurrency(s.Currency)
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case FormatCSV:
+		return renderCSV(w, s, c)
+	case FormatOFX:
+		return renderOFX(w, s, c, now)
+	case FormatCAMT053:
+		return renderCAMT053(w, s, c, now)
+	}
+
+	return ErrUnsupportedFormat
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// generatedAt stands in for the time an export is made
+var generatedAt = time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+// testStatement has a debit with a fee, a credit, a reversal, a deposit and a
+// counterparty-less line whose note is longer than an OFX NAME
+func testStatement() *model.Statement {
+	s := &model.Statement{
+		UserID:         "42",
+		UserName:       "Jürgen Müller",
+		Currency:       "EUR",
+		From:           time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		ClosingBalance: 523456,
+		Lines: []*model.StatementLine{
+			{
+				TransferID:     "TRF1001",
+				Stan:           "000101",
+				Type:           model.TransactionTypeDebit,
+				CounterpartyID: "7",
+				Amount:         25000,
+				Fee:            25,
+				PostedAt:       time.Date(2026, 9, 3, 14, 5, 6, 0, time.UTC),
+			},
+			{
+				TransferID:     "TRF1002",
+				Stan:           "000102",
+				Type:           model.TransactionTypeCredit,
+				CounterpartyID: "9",
+				Amount:         1050,
+				PostedAt:       time.Date(2026, 9, 10, 8, 0, 0, 0, time.UTC),
+			},
+			{
+				TransferID:     "TRF1003",
+				ReversalOf:     "TRF1001",
+				Stan:           "000103",
+				Type:           model.TransactionTypeCredit,
+				CounterpartyID: "7",
+				Amount:         25000,
+				PostedAt:       time.Date(2026, 9, 11, 12, 30, 0, 0, time.UTC),
+			},
+			{
+				TransferID: "DEP2001",
+				Stan:       "000104",
+				Type:       model.TransactionTypeCredit,
+				Note:       "Überweisung für Miete, Nebenkosten und Garage",
+				Amount:     99999,
+				PostedAt:   time.Date(2026, 9, 30, 23, 59, 59, 0, time.UTC),
+			},
+		},
+	}
+	s.Reconcile()
+	return s
+}
+
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		format Format
+		golden string
+	}{
+		{FormatCSV, "statement.csv.golden"},
+		{FormatOFX, "statement.ofx.golden"},
+		{FormatCAMT053, "statement.camt053.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.format, testStatement(), generatedAt); err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatalf("error writing %s: %v", path, err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("error reading %s: %v", path, err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("%s export does not match %s:\n%s", tt.format, path, buf.String())
+			}
+		})
+	}
+}
+
+func TestRenderUnsupportedCurrency(t *testing.T) {
+	s := testStatement()
+	s.Currency = "XXX"
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatCSV, s, generatedAt); err != model.ErrUnsupportedCurrency {
+		t.Errorf("Render error = %v, want %v", err, model.ErrUnsupportedCurrency)
+	}
+}
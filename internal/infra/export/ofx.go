@@ -0,0 +1,147 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// ofxHeader is the processing instruction that marks an OFX 2.2 document
+const ofxHeader = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+// ofxBankID identifies this system as the account servicer
+const ofxBankID = "MONEYTRANSFER"
+
+// ofxNameLength is the maximum number of characters in NAME
+const ofxNameLength = 32
+
+type ofxDocument struct {
+	XMLName xml.Name     `xml:"OFX"`
+	SignOn  ofxSignOn    `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    ofxStmtTrnRs `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	DTServer string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxStmtTrnRs struct {
+	TrnUID string    `xml:"TRNUID"`
+	Status ofxStatus `xml:"STATUS"`
+	StmtRs ofxStmtRs `xml:"STMTRS"`
+}
+
+type ofxStmtRs struct {
+	CurDef    string      `xml:"CURDEF"`
+	BankAcct  ofxBankAcct `xml:"BANKACCTFROM"`
+	TranList  ofxTranList `xml:"BANKTRANLIST"`
+	LedgerBal ofxBalance  `xml:"LEDGERBAL"`
+}
+
+type ofxBankAcct struct {
+	BankID   string `xml:"BANKID"`
+	AcctID   string `xml:"ACCTID"`
+	AcctType string `xml:"ACCTTYPE"`
+}
+
+type ofxTranList struct {
+	DTStart      string       `xml:"DTSTART"`
+	DTEnd        string       `xml:"DTEND"`
+	Transactions []ofxStmtTrn `xml:"STMTTRN"`
+}
+
+type ofxStmtTrn struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	BalAmt string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+// ofxTime formats a timestamp as an OFX date-time in UTC
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:UTC]"
+}
+
+// renderOFX writes an OFX 2.2 bank statement response. TRNAMT is the net
+// change to the balance, so a debit includes its fee.
+func renderOFX(w io.Writer, s *model.Statement, c model.Currency, now time.Time) error {
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+
+	doc := ofxDocument{
+		SignOn: ofxSignOn{
+			Status:   ok,
+			DTServer: ofxTime(now),
+			Language: "ENG",
+		},
+		Bank: ofxStmtTrnRs{
+			TrnUID: "0",
+			Status: ok,
+			StmtRs: ofxStmtRs{
+				CurDef: s.Currency,
+				BankAcct: ofxBankAcct{
+					BankID:   ofxBankID,
+					AcctID:   s.UserID,
+					AcctType: "CHECKING",
+				},
+				TranList: ofxTranList{
+					DTStart:      ofxTime(s.From),
+					DTEnd:        ofxTime(s.To),
+					Transactions: make([]ofxStmtTrn, len(s.Lines)),
+				},
+				LedgerBal: ofxBalance{
+					BalAmt: c.Decimal(s.ClosingBalance),
+					DTAsOf: ofxTime(s.To),
+				},
+			},
+		},
+	}
+
+	for i, l := range s.Lines {
+		name := l.Note
+		if l.CounterpartyID != "" {
+			name = "User " + l.CounterpartyID
+		}
+		if utf8.RuneCountInString(name) > ofxNameLength {
+			name = string([]rune(name)[:ofxNameLength])
+		}
+
+		doc.Bank.StmtRs.TranList.Transactions[i] = ofxStmtTrn{
+			TrnType:  string(l.Type),
+			DTPosted: ofxTime(l.PostedAt),
+			TrnAmt:   c.Decimal(l.Net()),
+			FITID:    l.TransferID,
+			Name:     name,
+			Memo:     l.Note,
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header+ofxHeader); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
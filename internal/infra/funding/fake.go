@@ -0,0 +1,98 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+const fakeReferencePrefix = "FAKE-"
+
+// FakeProvider settles deposits and withdrawals in process, so that the whole
+// funding flow runs offline. The external account decides the outcome: one
+// containing "decline" fails at once, one containing "pending" settles after
+// the settle delay, and any other succeeds at once. The outcome is encoded in
+// the reference, so the provider keeps no state and answers the same request
+// the same way every time.
+type FakeProvider struct {
+	settleDelay time.Duration
+	now         func() time.Time
+}
+
+// NewFakeProvider
+func NewFakeProvider(settleDelay time.Duration) *FakeProvider {
+	return &FakeProvider{
+		settleDelay: settleDelay,
+		now:         time.Now,
+	}
+}
+
+// Collect
+func (p *FakeProvider) Collect(ctx context.Context, req *model.FundingRequest) (*model.FundingResult, error) {
+	return p.submit(ctx, req)
+}
+
+// Payout
+func (p *FakeProvider) Payout(ctx context.Context, req *model.FundingRequest) (*model.FundingResult, error) {
+	return p.submit(ctx, req)
+}
+
+// submit
+func (p *FakeProvider) submit(ctx context.Context, req *model.FundingRequest) (*model.FundingResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	account := strings.ToLower(req.ExternalAccount)
+
+	switch {
+	case strings.Contains(account, "decline"):
+		return &model.FundingResult{
+			Reference: fakeReferencePrefix + req.ID,
+			State:     model.TransactionStateFailed,
+			Reason:    "declined by the provider",
+		}, nil
+	case strings.Contains(account, "pending"):
+		settleAt := p.now().Add(p.settleDelay).Unix()
+		return &model.FundingResult{
+			Reference: fmt.Sprintf("%s%s-%d", fakeReferencePrefix, req.ID, settleAt),
+			State:     model.TransactionStatePending,
+		}, nil
+	}
+
+	return &model.FundingResult{
+		Reference: fakeReferencePrefix + req.ID,
+		State:     model.TransactionStateCompleted,
+	}, nil
+}
+
+// Status completes a pending operation once its settle time has passed
+func (p *FakeProvider) Status(ctx context.Context, reference string) (*model.FundingResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	i := strings.LastIndex(reference, "-")
+	if !strings.HasPrefix(reference, fakeReferencePrefix) || i < len(fakeReferencePrefix) {
+		return nil, fmt.Errorf("unknown funding reference %q", reference)
+	}
+
+	settleAt, err := strconv.ParseInt(reference[i+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unknown funding reference %q", reference)
+	}
+
+	result := &model.FundingResult{
+		Reference: reference,
+		State:     model.TransactionStateCompleted,
+	}
+	if p.now().Unix() < settleAt {
+		result.State = model.TransactionStatePending
+	}
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package funding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+func TestFakeProviderSubmit(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := NewFakeProvider(time.Minute)
+	p.now = func() time.Time { return now }
+
+	tests := []struct {
+		account   string
+		state     model.TransactionState
+		reference string
+	}{
+		{"DE89370400440532013000", model.TransactionStateCompleted, "FAKE-DEP1"},
+		{"card-decline-0002", model.TransactionStateFailed, "FAKE-DEP1"},
+		{"BANK-PENDING-1", model.TransactionStatePending, "FAKE-DEP1-1790856060"},
+	}
+
+	for _, tt := range tests {
+		req := &model.FundingRequest{ID: "DEP1", ExternalAccount: tt.account, Amount: 1000, Currency: "USD"}
+
+		for name, submit := range map[string]func(context.Context, *model.FundingRequest) (*model.FundingResult, error){
+			"Collect": p.Collect,
+			"Payout":  p.Payout,
+		} {
+			result, err := submit(context.Background(), req)
+			if err != nil {
+				t.Fatalf("%s(%s): %v", name, tt.account, err)
+			}
+			if result.State != tt.state || result.Reference != tt.reference {
+				t.Errorf("%s(%s) = %s %s, want %s %s", name, tt.account, result.State, result.Reference, tt.state, tt.reference)
+			}
+			if tt.state == model.TransactionStateFailed && result.Reason == "" {
+				t.Errorf("%s(%s) failed without a reason", name, tt.account)
+			}
+		}
+	}
+}
+
+func TestFakeProviderStatus(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := NewFakeProvider(time.Minute)
+	p.now = func() time.Time { return now }
+
+	result, err := p.Collect(context.Background(), &model.FundingRequest{ID: "DEP1", ExternalAccount: "pending"})
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	for _, tt := range []struct {
+		at    time.Time
+		state model.TransactionState
+	}{
+		{now, model.TransactionStatePending},
+		{now.Add(59 * time.Second), model.TransactionStatePending},
+		{now.Add(time.Minute), model.TransactionStateCompleted},
+		{now.Add(time.Hour), model.TransactionStateCompleted},
+	} {
+		p.now = func() time.Time { return tt.at }
+
+		status, err := p.Status(context.Background(), result.Reference)
+		if err != nil {
+			t.Fatalf("Status at %s: %v", tt.at, err)
+		}
+		if status.State != tt.state || status.Reference != result.Reference {
+			t.Errorf("Status at %s = %s %s, want %s", tt.at, status.State, status.Reference, tt.state)
+		}
+	}
+}
+
+func TestFakeProviderUnknownReference(t *testing.T) {
+	p := NewFakeProvider(time.Minute)
+
+	for _, reference := range []string{"", "FAKE-", "FAKE-DEP1", "FAKE-DEP1-soon", "OTHER-DEP1-1790856060"} {
+		if _, err := p.Status(context.Background(), reference); err == nil {
+			t.Errorf("Status(%q) succeeded", reference)
+		}
+	}
+}
+
+func TestFakeProviderCancelled(t *testing.T) {
+	p := NewFakeProvider(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := p.Collect(ctx, &model.FundingRequest{ID: "DEP1", ExternalAccount: "ok"}); err != context.Canceled {
+		t.Errorf("Collect error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := p.Status(ctx, "FAKE-DEP1-1790856060"); err != context.Canceled {
+		t.Errorf("Status error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// CardController handles HTTP requests for users' cards
+type CardController struct {
+	service *service.CardService
+}
+
+// NewCardController creates a new CardController
+func NewCardController(service *service.CardService) *CardController {
+	return &CardController{
+		service: service,
+	}
+}
+
+// RegisterCardHandler godoc
+// @Summary Register a card
+// @Description Validate a card (Luhn check and expiry) and register it with the acquirer. Only the acquirer's token and the masked number are stored; registering the same card again returns the existing card.
+// @Tags cards
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param card body httpModel.CardRequest true "Card details"
+// @Success 201 {object} httpModel.CardResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users/{id}/cards [post]
+func (c *CardController) RegisterCardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.CardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	card, err := c.service.RegisterCard(vars["id"], model.CardDetails{
+		Number:      req.Number,
+		HolderName:  req.HolderName,
+		ExpiryMonth: req.ExpiryMonth,
+		ExpiryYear:  req.ExpiryYear,
+	})
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidCardNumber, model.ErrInvalidCardholderName, model.ErrInvalidCardExpiry:
+			statusCode = http.StatusBadRequest
+		case model.ErrCardExpired, model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(httpModel.CardToResponse(card))
+}
+
+// ListCardsHandler godoc
+// @Summary List a user's cards
+// @Description Get the cards registered to a user, with masked numbers
+// @Tags cards
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} httpModel.CardResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users/{id}/cards [get]
+func (c *CardController) ListCardsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	cards, err := c.service.ListCards(vars["id"])
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrUserNotFound {
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]*httpModel.CardResponse, len(cards))
+	for i, card := range cards {
+		response[i] = httpModel.CardToResponse(card)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
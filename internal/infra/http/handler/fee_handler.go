@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+)
+
+// FeeController handles HTTP requests for fee schedules and fee revenue
+type FeeController struct {
+	service *service.FeeService
+}
+
+// NewFeeController creates a new FeeController
+func NewFeeController(service *service.FeeService) *FeeController {
+	return &FeeController{
+		service: service,
+	}
+}
+
+// ListFeeSchedulesHandler godoc
+// @Summary List fee schedules
+// @Description Get every configured fee schedule
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Success 200 {array} httpModel.FeeScheduleResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/fee-schedules [get]
+func (c *FeeController) ListFeeSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	schedules, err := c.service.ListSchedules()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]*httpModel.FeeScheduleResponse, 0, len(schedules))
+	for _, s := range schedules {
+		response = append(response, httpModel.FeeScheduleToResponse(s))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// FeeRevenueHandler godoc
+// @Summary Fee revenue report
+// @Description Sum the fees credited to the house revenue account per currency. The period defaults to the current month
+// @Tags fees
+// @Accept json
+// @Produce json
+// @Param from query string false "Start of the period, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the period, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} httpModel.FeeRevenueReportResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/reports/fee-revenue [get]
+func (c *FeeController) FeeRevenueHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	now := time.Now().UTC()
+	from, to, err := parsePeriod(r,
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		now,
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	totals, err := c.service.Revenue(from, to)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrInvalidPeriod {
+			statusCode = http.StatusBadRequest
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.FeeRevenueToResponse(from, to, totals))
+}
+
+// parsePeriod reads the from and to query parameters, falling back to the defaults
+func parsePeriod(r *http.Request, defaultFrom, defaultTo time.Time) (time.Time, time.Time, error) {
+	from, err := parseTimeParam(r.URL.Query().Get("from"), defaultFrom)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to, err := parseTimeParam(r.URL.Query().Get("to"), defaultTo)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return from, to, nil
+}
+
+// parseTimeParam accepts RFC3339 timestamps and plain dates
+func parseTimeParam(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, model.ErrInvalidPeriod
+}
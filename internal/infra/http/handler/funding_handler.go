@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// FundingController handles HTTP requests for deposits and withdrawals
+type FundingController struct {
+	service *service.FundingService
+}
+
+// NewFundingController creates a new FundingController
+func NewFundingController(service *service.FundingService) *FundingController {
+	return &FundingController{
+		service: service,
+	}
+}
+
+// CreateDepositHandler godoc
+// @Summary Deposit funds
+// @Description Collect funds from a card or bank account through the funding provider. The user is credited when the provider confirms; a deposit the provider has not settled yet is returned as PENDING with status 202.
+// @Tags fundings
+// @Accept json
+// @Produce json
+// @Param deposit body httpModel.FundingRequest true "Deposit details"
+// @Success 201 {object} httpModel.FundingResponse
+// @Success 202 {object} httpModel.FundingResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/deposits [post]
+func (c *FundingController) CreateDepositHandler(w http.ResponseWriter, r *http.Request) {
+	c.createFunding(w, r, c.service.Deposit)
+}
+
+// CreateWithdrawalHandler godoc
+// @Summary Withdraw funds
+// @Description Pay funds out to a card or bank account through the funding provider. The user is debited at once and the funds are returned if the payout fails; a withdrawal the provider has not settled yet is returned as PENDING with status 202. Withdrawals count towards the user's outgoing transfer limits.
+// @Tags fundings
+// @Accept json
+// @Produce json
+// @Param withdrawal body httpModel.FundingRequest true "Withdrawal details"
+// @Success 201 {object} httpModel.FundingResponse
+// @Success 202 {object} httpModel.FundingResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.LimitExceededResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/withdrawals [post]
+func (c *FundingController) CreateWithdrawalHandler(w http.ResponseWriter, r *http.Request) {
+	c.createFunding(w, r, c.service.Withdraw)
+}
+
+// createFunding
+func (c *FundingController) createFunding(w http.ResponseWriter, r *http.Request, initiate func(service.FundingParams) (*model.Funding, error)) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.FundingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	funding, err := initiate(service.FundingParams{
+		UserID:          req.UserID,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		Method:          model.PaymentMethodType(req.PaymentMethod),
+		ExternalAccount: req.ExternalAccount,
+	})
+	if err != nil {
+		var limitErr *model.LimitExceededError
+		if errors.As(err, &limitErr) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(httpModel.LimitExceededToResponse(limitErr))
+			return
+		}
+
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidAmount, model.ErrInvalidPaymentMethod, model.ErrInvalidExternalAccount:
+			statusCode = http.StatusBadRequest
+		case model.ErrUnsupportedCurrency, model.ErrCurrencyNotHeld, model.ErrInsufficientFunds:
+			statusCode = http.StatusBadRequest
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	statusCode := http.StatusCreated
+	if funding.State == model.TransactionStatePending {
+		statusCode = http.StatusAccepted
+	}
+
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(httpModel.FundingToResponse(funding))
+}
+
+// GetDepositByIDHandler godoc
+// @Summary Get a deposit
+// @Description Get a deposit and its current state by ID
+// @Tags fundings
+// @Produce json
+// @Param id path string true "Deposit ID"
+// @Success 200 {object} httpModel.FundingResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/deposits/{id} [get]
+func (c *FundingController) GetDepositByIDHandler(w http.ResponseWriter, r *http.Request) {
+	c.getFunding(w, r, model.FundingTypeDeposit)
+}
+
+// GetWithdrawalByIDHandler godoc
+// @Summary Get a withdrawal
+// @Description Get a withdrawal and its current state by ID
+// @Tags fundings
+// @Produce json
+// @Param id path string true "Withdrawal ID"
+// @Success 200 {object} httpModel.FundingResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/withdrawals/{id} [get]
+func (c *FundingController) GetWithdrawalByIDHandler(w http.ResponseWriter, r *http.Request) {
+	c.getFunding(w, r, model.FundingTypeWithdrawal)
+}
+
+// getFunding
+func (c *FundingController) getFunding(w http.ResponseWriter, r *http.Request, fundingType model.FundingType) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	funding, err := c.service.GetFunding(vars["id"])
+	if err == nil && funding.Type != fundingType {
+		err = model.ErrFundingNotFound
+	}
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrFundingNotFound {
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.FundingToResponse(funding))
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// FXController handles HTTP requests for exchange rate quotes
+type FXController struct {
+	service *service.FXService
+}
+
+// NewFXController creates a new FXController
+func NewFXController(service *service.FXService) *FXController {
+	return &FXController{
+		service: service,
+	}
+}
+
+// CreateQuoteHandler godoc
+// @Summary Create an FX quote
+// @Description Lock an exchange rate between two currencies for a short time. Pass the quote ID to a transfer to make it cross-currency
+// @Tags fx
+// @Accept json
+// @Produce json
+// @Param quote body httpModel.QuoteRequest true "Currency pair"
+// @Success 201 {object} httpModel.QuoteResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/fx/quotes [post]
+func (c *FXController) CreateQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.QuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	quote, err := c.service.CreateQuote(req.FromCurrency, req.ToCurrency)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrUnsupportedCurrency:
+			statusCode = http.StatusBadRequest
+		case model.ErrInvalidCurrencyPair:
+			statusCode = http.StatusBadRequest
+		case model.ErrFXRateNotFound:
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(httpModel.QuoteToResponse(quote))
+}
+
+// GetQuoteByIDHandler godoc
+// @Summary Get an FX quote
+// @Description Get quote details by ID
+// @Tags fx
+// @Accept json
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Success 200 {object} httpModel.QuoteResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/fx/quotes/{id} [get]
+func (c *FXController) GetQuoteByIDHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	quote, err := c.service.GetQuote(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrQuoteNotFound {
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.QuoteToResponse(quote))
+}
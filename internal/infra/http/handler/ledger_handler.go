@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+)
+
+// LedgerController handles HTTP requests for the ledger
+type LedgerController struct {
+	service *service.LedgerService
+}
+
+// NewLedgerController creates a new LedgerController
+func NewLedgerController(service *service.LedgerService) *LedgerController {
+	return &LedgerController{
+		service: service,
+	}
+}
+
+// CheckConsistencyHandler godoc
+// @Summary Check ledger consistency
+// @Description Verify every journal sums to zero per currency and every cached balance equals the sum of its postings
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Success 200 {object} httpModel.LedgerConsistencyResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/ledger/consistency [get]
+func (c *LedgerController) CheckConsistencyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	report, err := c.service.CheckConsistency()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.LedgerConsistencyToResponse(report))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// LimitController
+type LimitController struct {
+	service *service.TransferService
+}
+
+// NewLimitController
+func NewLimitController(service *service.TransferService) *LimitController {
+	return &LimitController{
+		service: service,
+	}
+}
+
+// GetLimitsHandler godoc
+// @Summary Get a user's transfer limits
+// @Description Get the limits on the user's outgoing transfers in a currency and what has been used of them in the current UTC hour, day and month. A maximum of zero means no limit.
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param currency query string false "Currency, USD by default"
+// @Success 200 {object} httpModel.TransferLimitResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users/{id}/limits [get]
+func (c *LimitController) GetLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+	if currency == "" {
+		currency = model.DefaultCurrency
+	}
+
+	limit, usage, err := c.service.GetLimits(vars["id"], currency)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrUnsupportedCurrency:
+			statusCode = http.StatusBadRequest
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.TransferLimitToResponse(vars["id"], currency, limit, usage))
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/IskenT/money-transfer/internal/infra/iso20022"
+)
+
+// maxPaymentInitiationSize limits the size of an uploaded pain.001 message
+const maxPaymentInitiationSize = 10 << 20
+
+// PaymentInitiationController handles HTTP requests for ISO 20022 payment initiations
+type PaymentInitiationController struct {
+	service *service.PaymentInitiationService
+}
+
+// NewPaymentInitiationController creates a new PaymentInitiationController
+func NewPaymentInitiationController(service *service.PaymentInitiationService) *PaymentInitiationController {
+	return &PaymentInitiationController{
+		service: service,
+	}
+}
+
+// CreatePaymentInitiationHandler godoc
+// @Summary Submit a pain.001 payment initiation
+// @Description Validate an ISO 20022 pain.001 customer credit transfer initiation and create a transfer for each credit transfer. Accounts are user IDs given as Othr/Id. The response is a pain.002 status report with a status and reason code per transaction. Submitting the same message again does not pay twice. A message with more than PAIN001_MAX_TRANSACTIONS transactions is refused with 413.
+// @Tags payment-initiations
+// @Accept xml
+// @Produce xml
+// @Param dry_run query bool false "Only validate; valid transactions are reported as ACTC"
+// @Success 200 {string} string "pain.002.001.10 status report"
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 413 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/payment-initiations [post]
+func (c *PaymentInitiationController) CreatePaymentInitiationHandler(w http.ResponseWriter, r *http.Request) {
+	writeError := func(statusCode int, err error) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+	}
+
+	doc, err := iso20022.ParsePain001(http.MaxBytesReader(w, r.Body, maxPaymentInitiationSize))
+	if err != nil {
+		writeError(http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := c.service.Process(doc, r.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, model.ErrTooManyTransactions) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		writeError(statusCode, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteXML(&buf); err != nil {
+		writeError(http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
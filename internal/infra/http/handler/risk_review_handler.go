@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// RiskReviewController
+type RiskReviewController struct {
+	service *service.TransferService
+}
+
+// NewRiskReviewController
+func NewRiskReviewController(service *service.TransferService) *RiskReviewController {
+	return &RiskReviewController{
+		service: service,
+	}
+}
+
+// ListRiskReviewsHandler godoc
+// @Summary List risk reviews
+// @Description Get transfers held by risk screening and how their reviews ended, oldest first
+// @Tags admin
+// @Produce json
+// @Param status query string false "PENDING, APPROVED, REJECTED or EXPIRED; all reviews when omitted"
+// @Param limit query int false "Maximum number of reviews, 50 by default and at most 200"
+// @Success 200 {array} httpModel.RiskReviewResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/admin/risk-reviews [get]
+func (c *RiskReviewController) ListRiskReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	status := model.RiskReviewStatus(strings.ToUpper(query.Get("status")))
+
+	reviews, err := c.service.ListRiskReviews(status, limit)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrInvalidReviewStatus {
+			statusCode = http.StatusBadRequest
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]*httpModel.RiskReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		response = append(response, httpModel.RiskReviewToResponse(review))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRiskReviewHandler godoc
+// @Summary Get a transfer's risk review
+// @Description Get why a transfer was held by risk screening and how the review ended
+// @Tags admin
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} httpModel.RiskReviewResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/admin/transfers/{id}/review [get]
+func (c *RiskReviewController) GetRiskReviewHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	review, err := c.service.GetRiskReview(vars["id"])
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrTransferNotHeld {
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.RiskReviewToResponse(review))
+}
+
+// ApproveTransferHandler godoc
+// @Summary Approve a held transfer
+// @Description Let a transfer held by risk screening go ahead. A balance transfer completes, an authorization becomes PENDING until captured and a card payment is sent to the acquirer.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Param review body httpModel.ReviewRequest true "Review decision"
+// @Success 200 {object} httpModel.TransferResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/admin/transfers/{id}/approve [post]
+func (c *RiskReviewController) ApproveTransferHandler(w http.ResponseWriter, r *http.Request) {
+	c.review(w, r, c.service.ApproveTransfer)
+}
+
+// RejectTransferHandler godoc
+// @Summary Reject a held transfer
+// @Description Cancel a transfer held by risk screening and release the sender's funds
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Param review body httpModel.ReviewRequest true "Review decision"
+// @Success 200 {object} httpModel.TransferResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/admin/transfers/{id}/reject [post]
+func (c *RiskReviewController) RejectTransferHandler(w http.ResponseWriter, r *http.Request) {
+	c.review(w, r, c.service.RejectTransfer)
+}
+
+// review
+func (c *RiskReviewController) review(w http.ResponseWriter, r *http.Request, decide func(string, service.ReviewDecision) (*model.Transfer, error)) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	transfer, err := decide(vars["id"], service.ReviewDecision{
+		Reviewer: req.Reviewer,
+		Note:     req.Note,
+	})
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidReviewer:
+			statusCode = http.StatusBadRequest
+		case model.ErrTransferNotFound, model.ErrHoldNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrTransferNotHeld, model.ErrReviewExpired:
+			statusCode = http.StatusConflict
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.TransferToResponse(transfer))
+}
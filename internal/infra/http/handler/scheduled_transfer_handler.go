@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// ScheduledTransferController handles HTTP requests for scheduled transfers
+type ScheduledTransferController struct {
+	service *service.ScheduledTransferService
+}
+
+// NewScheduledTransferController creates a new ScheduledTransferController
+func NewScheduledTransferController(service *service.ScheduledTransferService) *ScheduledTransferController {
+	return &ScheduledTransferController{
+		service: service,
+	}
+}
+
+// ListScheduledTransfersHandler godoc
+// @Summary List scheduled transfers
+// @Description Get a list of all scheduled transfers, latest execution time first
+// @Tags scheduled-transfers
+// @Accept json
+// @Produce json
+// @Success 200 {array} httpModel.ScheduledTransferResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/scheduled-transfers [get]
+func (c *ScheduledTransferController) ListScheduledTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	schedules, err := c.service.ListScheduledTransfers()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]*httpModel.ScheduledTransferResponse, 0, len(schedules))
+	for _, s := range schedules {
+		response = append(response, httpModel.ScheduledTransferToResponse(s))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetScheduledTransferByIDHandler godoc
+// @Summary Get a scheduled transfer
+// @Description Get scheduled transfer details by ID, including the executed transfer or the failure reason
+// @Tags scheduled-transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled transfer ID"
+// @Success 200 {object} httpModel.ScheduledTransferResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/scheduled-transfers/{id} [get]
+func (c *ScheduledTransferController) GetScheduledTransferByIDHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	schedule, err := c.service.GetScheduledTransfer(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrScheduleNotFound {
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.ScheduledTransferToResponse(schedule))
+}
+
+// CancelScheduledTransferHandler godoc
+// @Summary Cancel a scheduled transfer
+// @Description Cancel a scheduled transfer that has not started executing
+// @Tags scheduled-transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled transfer ID"
+// @Success 200 {object} httpModel.ScheduledTransferResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/scheduled-transfers/{id}/cancel [post]
+func (c *ScheduledTransferController) CancelScheduledTransferHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	schedule, err := c.service.CancelScheduledTransfer(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrScheduleNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrScheduleNotCancellable:
+			statusCode = http.StatusConflict
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.ScheduledTransferToResponse(schedule))
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// StandingOrderController handles HTTP requests for standing orders
+type StandingOrderController struct {
+	service *service.StandingOrderService
+}
+
+// NewStandingOrderController creates a new StandingOrderController
+func NewStandingOrderController(service *service.StandingOrderService) *StandingOrderController {
+	return &StandingOrderController{
+		service: service,
+	}
+}
+
+// CreateStandingOrderHandler godoc
+// @Summary Create a standing order
+// @Description Repeat a transfer daily, weekly, monthly on a given day, or on the last business day of each month
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param order body httpModel.StandingOrderRequest true "Standing order details"
+// @Success 201 {object} httpModel.StandingOrderResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/standing-orders [post]
+func (c *StandingOrderController) CreateStandingOrderHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.StandingOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	params := service.StandingOrderParams{
+		FromUserID:     req.FromUserID,
+		ToUserID:       req.ToUserID,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		Frequency:      model.Frequency(req.Frequency),
+		DayOfMonth:     req.DayOfMonth,
+		MaxOccurrences: req.MaxOccurrences,
+		CatchUp:        model.CatchUpPolicy(req.CatchUp),
+	}
+
+	for _, d := range []struct {
+		value string
+		dest  *time.Time
+	}{
+		{req.StartDate, &params.StartDate},
+		{req.EndDate, &params.EndDate},
+	} {
+		if d.value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.DateOnly, d.value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid date, expected YYYY-MM-DD"})
+			return
+		}
+		*d.dest = t
+	}
+
+	order, err := c.service.CreateStandingOrder(params)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrSameAccount, model.ErrInvalidAmount, model.ErrUnsupportedCurrency, model.ErrCurrencyNotHeld:
+			statusCode = http.StatusBadRequest
+		case model.ErrInvalidFrequency, model.ErrInvalidDayOfMonth, model.ErrInvalidCatchUpPolicy:
+			statusCode = http.StatusBadRequest
+		case model.ErrStandingOrderUnbounded, model.ErrInvalidPeriod, model.ErrInvalidStartDate:
+			statusCode = http.StatusBadRequest
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(httpModel.StandingOrderToResponse(order))
+}
+
+// ListStandingOrdersHandler godoc
+// @Summary List standing orders
+// @Description Get a list of all standing orders, newest first
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Success 200 {array} httpModel.StandingOrderResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/standing-orders [get]
+func (c *StandingOrderController) ListStandingOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	orders, err := c.service.ListStandingOrders()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]*httpModel.StandingOrderResponse, 0, len(orders))
+	for _, o := range orders {
+		response = append(response, httpModel.StandingOrderToResponse(o))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetStandingOrderByIDHandler godoc
+// @Summary Get a standing order
+// @Description Get standing order details by ID, including the transfers its runs made
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Standing order ID"
+// @Success 200 {object} httpModel.StandingOrderResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/standing-orders/{id} [get]
+func (c *StandingOrderController) GetStandingOrderByIDHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	order, err := c.service.GetStandingOrder(vars["id"])
+	writeStandingOrderResult(w, order, err)
+}
+
+// PauseStandingOrderHandler godoc
+// @Summary Pause a standing order
+// @Description Stop an active standing order from running until it is resumed
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Standing order ID"
+// @Success 200 {object} httpModel.StandingOrderResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/standing-orders/{id}/pause [post]
+func (c *StandingOrderController) PauseStandingOrderHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	order, err := c.service.PauseStandingOrder(vars["id"])
+	writeStandingOrderResult(w, order, err)
+}
+
+// ResumeStandingOrderHandler godoc
+// @Summary Resume a standing order
+// @Description Restart a paused standing order from its next date on or after today
+// @Tags standing-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Standing order ID"
+// @Success 200 {object} httpModel.StandingOrderResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/standing-orders/{id}/resume [post]
+func (c *StandingOrderController) ResumeStandingOrderHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	order, err := c.service.ResumeStandingOrder(vars["id"])
+	writeStandingOrderResult(w, order, err)
+}
+
+// writeStandingOrderResult
+func writeStandingOrderResult(w http.ResponseWriter, order *model.StandingOrder, err error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrStandingOrderNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrInvalidStatusChange:
+			statusCode = http.StatusConflict
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.StandingOrderToResponse(order))
+}
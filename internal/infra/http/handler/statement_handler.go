@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/infra/export"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// StatementController handles HTTP requests for account statements
+type StatementController struct {
+	service *service.StatementService
+}
+
+// NewStatementController creates a new StatementController
+func NewStatementController(service *service.StatementService) *StatementController {
+	return &StatementController{
+		service: service,
+	}
+}
+
+// GetStatementHandler godoc
+// @Summary Get an account statement
+// @Description Get the opening balance, every debit and credit with the running balance, and the closing balance of one of the user's currencies. The period defaults to the current month
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param currency query string false "ISO-4217 currency code, defaults to USD"
+// @Param from query string false "Start of the period, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the period, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} httpModel.StatementResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users/{id}/statement [get]
+func (c *StatementController) GetStatementHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	now := time.Now().UTC()
+	from, to, err := parsePeriod(r,
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		now,
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	statement, err := c.service.Statement(id, r.URL.Query().Get("currency"), from, to)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidPeriod, model.ErrUnsupportedCurrency, model.ErrCurrencyNotHeld:
+			statusCode = http.StatusBadRequest
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.StatementToResponse(statement))
+}
+
+// ExportStatementHandler godoc
+// @Summary Export an account statement
+// @Description Render the statement as CSV, OFX 2.2 or ISO 20022 camt.053 for import into accounting tools. The format query parameter takes precedence over the Accept header; the default is CSV
+// @Tags users
+// @Produce text/csv
+// @Produce application/x-ofx
+// @Produce application/xml
+// @Param id path string true "User ID"
+// @Param format query string false "csv, ofx or camt053"
+// @Param currency query string false "ISO-4217 currency code, defaults to USD"
+// @Param from query string false "Start of the period, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the period, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 406 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users/{id}/statement/export [get]
+func (c *StatementController) ExportStatementHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	writeError := func(statusCode int, err error) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+	}
+
+	format, err := export.Negotiate(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		writeError(http.StatusNotAcceptable, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	from, to, err := parsePeriod(r,
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		now,
+	)
+	if err != nil {
+		writeError(http.StatusBadRequest, err)
+		return
+	}
+
+	statement, err := c.service.Statement(id, r.URL.Query().Get("currency"), from, to)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidPeriod, model.ErrUnsupportedCurrency, model.ErrCurrencyNotHeld:
+			statusCode = http.StatusBadRequest
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		}
+
+		writeError(statusCode, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Render(&buf, format, statement, time.Now()); err != nil {
+		writeError(http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename(statement, format)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// TransferBatchController handles HTTP requests for transfer batches
+type TransferBatchController struct {
+	service *service.TransferBatchService
+}
+
+// NewTransferBatchController creates a new TransferBatchController
+func NewTransferBatchController(service *service.TransferBatchService) *TransferBatchController {
+	return &TransferBatchController{
+		service: service,
+	}
+}
+
+// CreateTransferBatchHandler godoc
+// @Summary Submit a transfer batch
+// @Description Submit many transfers in one call. In ATOMIC mode all items complete or none do; in BEST_EFFORT mode each item gets its own result. The batch runs in the background; poll it by ID for the per-item results.
+// @Tags transfer-batches
+// @Accept json
+// @Produce json
+// @Param batch body httpModel.TransferBatchRequest true "Batch details"
+// @Success 202 {object} httpModel.TransferBatchResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/transfer-batches [post]
+func (c *TransferBatchController) CreateTransferBatchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.TransferBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	items := make([]service.TransferParams, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = service.TransferParams{
+			FromUserID: item.FromUserID,
+			ToUserID:   item.ToUserID,
+			Amount:     item.Amount,
+			Currency:   item.Currency,
+		}
+	}
+
+	batch, err := c.service.SubmitBatch(model.BatchMode(req.Mode), items)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidBatchMode, model.ErrInvalidBatchSize:
+			statusCode = http.StatusBadRequest
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(httpModel.TransferBatchToResponse(batch))
+}
+
+// GetTransferBatchByIDHandler godoc
+// @Summary Get a transfer batch
+// @Description Get a transfer batch by ID with the status, transfer and error of each item
+// @Tags transfer-batches
+// @Accept json
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} httpModel.TransferBatchResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/transfer-batches/{id} [get]
+func (c *TransferBatchController) GetTransferBatchByIDHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	batch, err := c.service.GetBatch(id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if err == model.ErrBatchNotFound {
+			statusCode = http.StatusNotFound
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.TransferBatchToResponse(batch))
+}
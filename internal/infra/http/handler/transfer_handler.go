@@ -1,40 +1,90 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/IskenT/money-transfer/internal/app/service"
 	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
 	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
 	"github.com/gorilla/mux"
 )
 
+// IdempotencyKeyHeader
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses served from an earlier request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
 // TransferController handles HTTP requests for transfers
 type TransferController struct {
-	service *service.TransferService
+	service          *service.TransferService
+	scheduledService *service.ScheduledTransferService
 }
 
 // NewTransferController creates a new TransferController
-func NewTransferController(service *service.TransferService) *TransferController {
+func NewTransferController(service *service.TransferService, scheduledService *service.ScheduledTransferService) *TransferController {
 	return &TransferController{
-		service: service,
+		service:          service,
+		scheduledService: scheduledService,
 	}
 }
 
 // CreateTransferHandler godoc
 // @Summary Create a new money transfer
-// @Description Transfer money from one user to another
+// @Description Transfer money from one user to another. With execute_at the transfer is scheduled instead and 202 is returned. With card_id the sender's card pays instead of their balance; a card payment the acquirer has not answered yet is returned as PENDING with status 202. A transfer over one of the sender's limits is rejected with 422 and a LimitExceededResponse naming the limit and when it resets. A transfer flagged by risk screening is returned as HELD with status 202 until it is reviewed, or declined with 403.
 // @Tags transfers
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Key that makes retries of the same request safe"
 // @Param transfer body httpModel.TransferRequest true "Transfer details"
 // @Success 201 {object} httpModel.TransferResponse
+// @Success 202 {object} httpModel.ScheduledTransferResponse
 // @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
 // @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.LimitExceededResponse
 // @Failure 500 {object} httpModel.ErrorResponse
+// @Failure 503 {object} httpModel.ErrorResponse
 // @Router /api/transfers [post]
 func (c *TransferController) CreateTransferHandler(w http.ResponseWriter, r *http.Request) {
+	c.createTransfer(w, r, false)
+}
+
+// AuthorizeTransferHandler godoc
+// @Summary Authorize a transfer
+// @Description Place a hold on the sender's funds; the transfer stays PENDING until it is captured, voided or expires. A transfer flagged by risk screening is returned as HELD with status 202 and becomes PENDING once approved.
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key that makes retries of the same request safe"
+// @Param transfer body httpModel.TransferRequest true "Transfer details"
+// @Success 201 {object} httpModel.TransferResponse
+// @Success 202 {object} httpModel.TransferResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.LimitExceededResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/transfers/authorize [post]
+func (c *TransferController) AuthorizeTransferHandler(w http.ResponseWriter, r *http.Request) {
+	c.createTransfer(w, r, true)
+}
+
+// createTransfer
+func (c *TransferController) createTransfer(w http.ResponseWriter, r *http.Request, authorize bool) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req httpModel.TransferRequest
@@ -44,8 +94,56 @@ func (c *TransferController) CreateTransferHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	transfer, err := c.service.CreateTransfer(req.FromUserID, req.ToUserID, req.Amount)
+	if req.ExecuteAt != "" {
+		c.scheduleTransfer(w, r, req, authorize)
+		return
+	}
+
+	var (
+		transfer *model.Transfer
+		replayed bool
+		err      error
+	)
+
+	params := service.TransferParams{
+		FromUserID: req.FromUserID,
+		ToUserID:   req.ToUserID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		QuoteID:    req.QuoteID,
+		Authorize:  authorize,
+		CardID:     req.CardID,
+	}
+
+	key, idempotent := r.Header[http.CanonicalHeaderKey(IdempotencyKeyHeader)]
+	if idempotent {
+		hash := requestHash(req, authorize)
+
+		// A retry gets the stored response; until the first request has
+		// stored it, the transfer is replayed in its current state
+		var response *model.IdempotentResponse
+		response, err = c.service.IdempotentResponse(key[0], hash)
+		if err == nil {
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(response.StatusCode)
+			w.Write(response.Body)
+			return
+		}
+		if err == model.ErrIdempotencyKeyNotFound {
+			transfer, replayed, err = c.service.CreateTransferIdempotent(key[0], hash, params)
+		}
+	} else {
+		transfer, err = c.service.CreateTransfer(params)
+	}
+
 	if err != nil {
+		var limitErr *model.LimitExceededError
+		if errors.As(err, &limitErr) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(httpModel.LimitExceededToResponse(limitErr))
+			return
+		}
+
 		statusCode := http.StatusInternalServerError
 
 		switch err {
@@ -57,6 +155,34 @@ func (c *TransferController) CreateTransferHandler(w http.ResponseWriter, r *htt
 			statusCode = http.StatusNotFound
 		case model.ErrInvalidAmount:
 			statusCode = http.StatusBadRequest
+		case model.ErrInvalidIdempotencyKey:
+			statusCode = http.StatusBadRequest
+		case model.ErrIdempotencyKeyMismatch:
+			statusCode = http.StatusUnprocessableEntity
+		case model.ErrUnsupportedCurrency:
+			statusCode = http.StatusBadRequest
+		case model.ErrCurrencyNotHeld:
+			statusCode = http.StatusBadRequest
+		case model.ErrQuoteNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrQuoteExpired, model.ErrQuoteCurrencyMismatch:
+			statusCode = http.StatusBadRequest
+		case model.ErrQuoteAlreadyUsed:
+			statusCode = http.StatusConflict
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		case model.ErrCardNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrCardNotAllowed:
+			statusCode = http.StatusBadRequest
+		case model.ErrCardExpired:
+			statusCode = http.StatusUnprocessableEntity
+		case model.ErrStanExhausted:
+			statusCode = http.StatusServiceUnavailable
+		case model.ErrTransferDenied:
+			statusCode = http.StatusForbidden
 		}
 
 		w.WriteHeader(statusCode)
@@ -64,8 +190,87 @@ func (c *TransferController) CreateTransferHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(httpModel.TransferToResponse(transfer))
+	if replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+	}
+
+	statusCode := http.StatusCreated
+	if transfer.State == model.TransactionStateHeld || transfer.CardFunded() && transfer.State == model.TransactionStatePending {
+		statusCode = http.StatusAccepted
+	}
+
+	var body bytes.Buffer
+	json.NewEncoder(&body).Encode(httpModel.TransferToResponse(transfer))
+
+	if idempotent && !replayed {
+		response := &model.IdempotentResponse{StatusCode: statusCode, Body: body.Bytes()}
+		if err := c.service.SaveIdempotentResponse(key[0], response); err != nil {
+			log.Printf("Failed to store the response for idempotency key %q: %v", key[0], err)
+		}
+	}
+
+	w.WriteHeader(statusCode)
+	w.Write(body.Bytes())
+}
+
+// scheduleTransfer
+func (c *TransferController) scheduleTransfer(w http.ResponseWriter, r *http.Request, req httpModel.TransferRequest, authorize bool) {
+	if authorize {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "execute_at is not supported for authorizations"})
+		return
+	}
+
+	if req.CardID != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: model.ErrCardNotAllowed.Error()})
+		return
+	}
+
+	if _, ok := r.Header[http.CanonicalHeaderKey(IdempotencyKeyHeader)]; ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Idempotency-Key is not supported for scheduled transfers"})
+		return
+	}
+
+	executeAt, err := time.Parse(time.RFC3339, req.ExecuteAt)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid execute_at, expected RFC3339"})
+		return
+	}
+
+	schedule, err := c.scheduledService.ScheduleTransfer(service.TransferParams{
+		FromUserID: req.FromUserID,
+		ToUserID:   req.ToUserID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		QuoteID:    req.QuoteID,
+	}, executeAt)
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrSameAccount, model.ErrInvalidAmount, model.ErrInvalidExecuteAt:
+			statusCode = http.StatusBadRequest
+		case model.ErrUnsupportedCurrency, model.ErrCurrencyNotHeld, model.ErrQuoteNotSchedulable:
+			statusCode = http.StatusBadRequest
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(httpModel.ScheduledTransferToResponse(schedule))
 }
 
 // GetTransferByIDHandler godoc
@@ -103,30 +308,252 @@ func (c *TransferController) GetTransferByIDHandler(w http.ResponseWriter, r *ht
 }
 
 // ListTransfersHandler godoc
-// @Summary List all transfers
-// @Description Get a list of all transfers
+// @Summary List transfers
+// @Description Get one page of transfers, newest first by default. Pass next_cursor back as cursor, with the same filters, to get the next page.
 // @Tags transfers
 // @Accept json
 // @Produce json
-// @Success 200 {array} httpModel.TransferResponse
+// @Param user_id query string false "Only transfers sent or received by this user"
+// @Param direction query string false "incoming or outgoing, relative to user_id"
+// @Param state query string false "Comma-separated transfer states"
+// @Param min_amount query int false "Minimum amount in minor units"
+// @Param max_amount query int false "Maximum amount in minor units"
+// @Param from query string false "Created at or after, RFC3339 or YYYY-MM-DD"
+// @Param to query string false "Created before, RFC3339 or YYYY-MM-DD"
+// @Param sort query string false "created_at (default) or amount"
+// @Param order query string false "desc (default) or asc"
+// @Param limit query int false "Page size, default 50, at most 200"
+// @Param cursor query string false "Cursor from the previous page"
+// @Success 200 {object} httpModel.TransferListResponse
+// @Failure 400 {object} httpModel.ErrorResponse
 // @Failure 500 {object} httpModel.ErrorResponse
 // @Router /api/transfers [get]
 func (c *TransferController) ListTransfersHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	transfers, err := c.service.ListTransfers()
+	filter, err := parseTransferFilter(r)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	page, err := c.service.ListTransfers(filter)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidFilter, model.ErrInvalidCursor:
+			statusCode = http.StatusBadRequest
+		}
+
+		w.WriteHeader(statusCode)
 		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	// Convert to response objects
-	response := make([]*httpModel.TransferResponse, 0, len(transfers))
-	for _, t := range transfers {
-		response = append(response, httpModel.TransferToResponse(t))
+	response := &httpModel.TransferListResponse{
+		Transfers:  make([]*httpModel.TransferResponse, 0, len(page.Transfers)),
+		NextCursor: page.NextCursor,
+	}
+	for _, t := range page.Transfers {
+		response.Transfers = append(response.Transfers, httpModel.TransferToResponse(t))
 	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// parseTransferFilter reads the list filters from the query string
+func parseTransferFilter(r *http.Request) (repository.ListFilter, error) {
+	query := r.URL.Query()
+
+	filter := repository.ListFilter{
+		UserID:     query.Get("user_id"),
+		Direction:  repository.TransferDirection(query.Get("direction")),
+		SortBy:     repository.TransferSortField(query.Get("sort")),
+		Descending: true,
+		Cursor:     query.Get("cursor"),
+	}
+
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return filter, model.ErrInvalidFilter
+	}
+
+	if states := query.Get("state"); states != "" {
+		for _, state := range strings.Split(states, ",") {
+			filter.States = append(filter.States, model.TransactionState(strings.ToUpper(strings.TrimSpace(state))))
+		}
+	}
+
+	for _, p := range []struct {
+		name string
+		dest *int
+	}{
+		{"min_amount", &filter.MinAmount},
+		{"max_amount", &filter.MaxAmount},
+		{"limit", &filter.Limit},
+	} {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, model.ErrInvalidFilter
+		}
+		*p.dest = n
+	}
+
+	from, to, err := parsePeriod(r, time.Time{}, time.Time{})
+	if err != nil {
+		return filter, err
+	}
+	filter.CreatedFrom, filter.CreatedTo = from, to
+
+	return filter, nil
+}
+
+// CaptureTransferHandler godoc
+// @Summary Capture an authorized transfer
+// @Description Complete a PENDING transfer, moving the held funds to the recipient
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} httpModel.TransferResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/transfers/{id}/capture [post]
+func (c *TransferController) CaptureTransferHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	transfer, err := c.service.CaptureTransfer(vars["id"])
+	writeAuthorizationResult(w, transfer, err)
+}
+
+// VoidTransferHandler godoc
+// @Summary Void an authorized transfer
+// @Description Cancel a PENDING transfer and release the hold on the sender's funds
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} httpModel.TransferResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/transfers/{id}/void [post]
+func (c *TransferController) VoidTransferHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	transfer, err := c.service.VoidTransfer(vars["id"])
+	writeAuthorizationResult(w, transfer, err)
+}
+
+// writeAuthorizationResult
+func writeAuthorizationResult(w http.ResponseWriter, transfer *model.Transfer, err error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrTransferNotFound, model.ErrHoldNotFound, model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrTransferNotPending, model.ErrAuthorizationExpired:
+			statusCode = http.StatusConflict
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.TransferToResponse(transfer))
+}
+
+// ReverseTransferHandler godoc
+// @Summary Reverse a completed transfer
+// @Description Move funds back from the recipient to the sender, fully or partially, as a linked transfer
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Param reversal body httpModel.ReversalRequest false "Reversal details"
+// @Success 201 {object} httpModel.TransferResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 422 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/transfers/{id}/reverse [post]
+func (c *TransferController) ReverseTransferHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var req httpModel.ReversalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	reversal, err := c.service.ReverseTransfer(id, service.ReversalParams{
+		Amount: req.Amount,
+		Force:  req.Force,
+		Reason: req.Reason,
+	})
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrTransferNotFound, model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrInsufficientFunds:
+			statusCode = http.StatusBadRequest
+		case model.ErrInvalidAmount, model.ErrReversalExceedsAmount:
+			statusCode = http.StatusBadRequest
+		case model.ErrCurrencyNotHeld:
+			statusCode = http.StatusBadRequest
+		case model.ErrTransferNotReversible:
+			statusCode = http.StatusConflict
+		case model.ErrAccountClosed:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(httpModel.TransferToResponse(reversal))
+}
+
+// requestHash fingerprints the decoded request so that formatting
+// differences between retries do not count as a different body.
+// An authorization and a transfer with the same body are different requests.
+func requestHash(req httpModel.TransferRequest, authorize bool) string {
+	body, _ := json.Marshal(struct {
+		httpModel.TransferRequest
+		Authorize bool `json:"authorize,omitempty"`
+	}{req, authorize})
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -12,16 +12,127 @@ import (
 
 // UserController
 type UserController struct {
-	service *service.TransferService
+	service *service.UserService
 }
 
 // NewUserController
-func NewUserController(service *service.TransferService) *UserController {
+func NewUserController(service *service.UserService) *UserController {
 	return &UserController{
 		service: service,
 	}
 }
 
+// CreateUserHandler godoc
+// @Summary Create a user
+// @Description Open an ACTIVE account with a zero balance in each requested currency
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body httpModel.CreateUserRequest true "User details"
+// @Success 201 {object} httpModel.UserResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users [post]
+func (c *UserController) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	user, err := c.service.CreateUser(service.CreateUserParams{
+		Name:       req.Name,
+		Tier:       model.AccountTier(req.Tier),
+		Currencies: req.Currencies,
+	})
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrInvalidUserName, model.ErrInvalidAccountTier, model.ErrUnsupportedCurrency:
+			statusCode = http.StatusBadRequest
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(httpModel.UserToResponse(user))
+}
+
+// UpdateUserHandler godoc
+// @Summary Update a user
+// @Description Change the name, tier or status of an account. Freezing blocks all transfers from and to the account.
+// @Description Closing requires a zero balance and no pending authorizations, deposits, withdrawals or card payments, or sweep_to to move the remaining funds to another user.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body httpModel.UpdateUserRequest true "Fields to change"
+// @Success 200 {object} httpModel.UserResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 403 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 409 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/users/{id} [patch]
+func (c *UserController) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var req httpModel.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	params := service.UpdateUserParams{
+		Name:    req.Name,
+		SweepTo: req.SweepTo,
+	}
+	if req.Tier != nil {
+		tier := model.AccountTier(*req.Tier)
+		params.Tier = &tier
+	}
+	if req.Status != nil {
+		status := model.UserStatus(*req.Status)
+		params.Status = &status
+	}
+
+	user, err := c.service.UpdateUser(id, params)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		switch err {
+		case model.ErrUserNotFound:
+			statusCode = http.StatusNotFound
+		case model.ErrInvalidUserName, model.ErrInvalidAccountTier, model.ErrInvalidSweep:
+			statusCode = http.StatusBadRequest
+		case model.ErrSameAccount, model.ErrCurrencyNotHeld:
+			statusCode = http.StatusBadRequest
+		case model.ErrInvalidUserStatus, model.ErrAccountClosed, model.ErrAccountNotEmpty:
+			statusCode = http.StatusConflict
+		case model.ErrAccountFrozen:
+			statusCode = http.StatusForbidden
+		}
+
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.UserToResponse(user))
+}
+
 // GetUserByIDHandler godoc
 // @Summary Get a specific user
 // @Description Get user details by ID
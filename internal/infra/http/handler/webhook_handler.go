@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IskenT/money-transfer/internal/app/service"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	httpModel "github.com/IskenT/money-transfer/internal/infra/http/model"
+	"github.com/gorilla/mux"
+)
+
+// WebhookController
+type WebhookController struct {
+	service *service.WebhookService
+}
+
+// NewWebhookController
+func NewWebhookController(service *service.WebhookService) *WebhookController {
+	return &WebhookController{
+		service: service,
+	}
+}
+
+// CreateWebhookHandler godoc
+// @Summary Subscribe to events
+// @Description Register a URL that events are POSTed to as a versioned JSON envelope. Each request carries X-Webhook-ID, X-Webhook-Timestamp and X-Webhook-Signature, "v1=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret.
+// @Description The secret is only returned here; one is generated when none is given.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body httpModel.CreateWebhookRequest true "Subscription"
+// @Success 201 {object} httpModel.WebhookResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks [post]
+func (c *WebhookController) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	subscription, err := c.service.CreateWebhook(service.CreateWebhookParams{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(httpModel.WebhookToResponse(subscription, true))
+}
+
+// ListWebhooksHandler godoc
+// @Summary List webhook subscriptions
+// @Description Get every webhook subscription, without secrets
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} httpModel.WebhookResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks [get]
+func (c *WebhookController) ListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	subscriptions, err := c.service.ListWebhooks()
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response := make([]*httpModel.WebhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, httpModel.WebhookToResponse(subscription, false))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetWebhookHandler godoc
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription by ID, without its secret
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} httpModel.WebhookResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks/{id} [get]
+func (c *WebhookController) GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	subscription, err := c.service.GetWebhook(vars["id"])
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.WebhookToResponse(subscription, false))
+}
+
+// UpdateWebhookHandler godoc
+// @Summary Update a webhook subscription
+// @Description Change the URL, event types or secret, or pause and resume deliveries. Deliveries of a paused subscription wait until it is resumed.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param webhook body httpModel.UpdateWebhookRequest true "Fields to change"
+// @Success 200 {object} httpModel.WebhookResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks/{id} [patch]
+func (c *WebhookController) UpdateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req httpModel.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	subscription, err := c.service.UpdateWebhook(vars["id"], service.UpdateWebhookParams{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		Active:     req.Active,
+	})
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(httpModel.WebhookToResponse(subscription, false))
+}
+
+// DeleteWebhookHandler godoc
+// @Summary Delete a webhook subscription
+// @Description Delete a subscription together with its deliveries and their attempt log
+// @Tags webhooks
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := c.service.DeleteWebhook(vars["id"]); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveriesHandler godoc
+// @Summary List webhook deliveries
+// @Description Get a subscription's deliveries newest first, each with the log of its attempts
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "PENDING, DELIVERED or FAILED; all deliveries when omitted"
+// @Param limit query int false "Maximum number of deliveries, 50 by default and at most 200"
+// @Success 200 {array} httpModel.WebhookDeliveryResponse
+// @Failure 400 {object} httpModel.ErrorResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	status := model.WebhookDeliveryStatus(strings.ToUpper(query.Get("status")))
+	vars := mux.Vars(r)
+
+	deliveries, err := c.service.ListDeliveries(vars["id"], status, limit)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response := make([]*httpModel.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, httpModel.WebhookDeliveryToResponse(delivery))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// RedeliverHandler godoc
+// @Summary Redeliver a webhook
+// @Description Send a delivery again on the next dispatch with a fresh set of attempts, whether it was delivered, failed or is still pending. The delivery keeps its ID, so receivers that drop duplicates should still answer 2xx.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} httpModel.WebhookDeliveryResponse
+// @Failure 404 {object} httpModel.ErrorResponse
+// @Failure 500 {object} httpModel.ErrorResponse
+// @Router /api/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (c *WebhookController) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	delivery, err := c.service.Redeliver(vars["id"], vars["delivery_id"])
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(httpModel.WebhookDeliveryToResponse(delivery))
+}
+
+// writeWebhookError
+func writeWebhookError(w http.ResponseWriter, err error) {
+	statusCode := http.StatusInternalServerError
+
+	switch err {
+	case model.ErrWebhookNotFound, model.ErrDeliveryNotFound:
+		statusCode = http.StatusNotFound
+	case model.ErrInvalidWebhookURL, model.ErrWebhookURLNotPublic, model.ErrInvalidEventTypes,
+		model.ErrInvalidWebhookSecret, model.ErrInvalidDeliveryStatus:
+		statusCode = http.StatusBadRequest
+	}
+
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(httpModel.ErrorResponse{Error: err.Error()})
+}
@@ -1,7 +1,6 @@
 package model
 
 import (
-	"fmt"
 	"time"
 
 	domainModel "github.com/IskenT/money-transfer/internal/domain/model"
@@ -12,25 +11,185 @@ type ErrorResponse struct {
 	Error string `json:"error" example:"insufficient funds"`
 }
 
+// BalanceResponse
+type BalanceResponse struct {
+	Currency           string `json:"currency" example:"USD"`
+	Amount             int    `json:"amount" example:"10000"`
+	AmountFormatted    string `json:"amount_formatted" example:"$100.00"`
+	Held               int    `json:"held" example:"2000"`
+	Available          int    `json:"available" example:"8000"`
+	AvailableFormatted string `json:"available_formatted" example:"$80.00"`
+}
+
 // UserResponse
 type UserResponse struct {
-	ID               string `json:"id" example:"1"`
-	Name             string `json:"name" example:"Mark"`
-	Balance          int    `json:"balance" example:"10000"`
-	BalanceFormatted string `json:"balance_formatted" example:"$100.00"`
+	ID       string             `json:"id" example:"1"`
+	Name     string             `json:"name" example:"Mark"`
+	Tier     string             `json:"tier" example:"STANDARD"`
+	Status   string             `json:"status" example:"ACTIVE"`
+	Balances []*BalanceResponse `json:"balances"`
+}
+
+// CreateUserRequest
+type CreateUserRequest struct {
+	Name       string   `json:"name" example:"Lucy" description:"Account holder name"`
+	Tier       string   `json:"tier,omitempty" example:"STANDARD" description:"STANDARD or PREMIUM, defaults to STANDARD"`
+	Currencies []string `json:"currencies,omitempty" example:"USD,EUR" description:"ISO-4217 currencies to open with a zero balance, defaults to USD"`
+}
+
+// UpdateUserRequest
+type UpdateUserRequest struct {
+	Name    *string `json:"name,omitempty" example:"Lucy" description:"New account holder name"`
+	Tier    *string `json:"tier,omitempty" example:"PREMIUM" description:"STANDARD or PREMIUM"`
+	Status  *string `json:"status,omitempty" example:"FROZEN" description:"ACTIVE, FROZEN or CLOSED; CLOSED is final"`
+	SweepTo string  `json:"sweep_to,omitempty" example:"2" description:"When closing, transfer every remaining balance to this user first"`
 }
 
 // TransactionResponse
 type TransactionResponse struct {
-	Stan            string `json:"stan" example:"TRX1647881234567"`
-	Amount          int    `json:"amount" example:"1000"`
-	AmountFormatted string `json:"amount_formatted" example:"$10.00"`
-	State           string `json:"state" example:"COMPLETED"`
-	TransactionType string `json:"transaction_type" example:"DEBIT"`
-	PaymentSource   string `json:"payment_source" example:"TRANSFER"`
-	Note            string `json:"note" example:"Transfer to Jane"`
-	CreatedAt       string `json:"created_at" example:"2023-04-10T12:34:56Z"`
-	UpdatedAt       string `json:"updated_at" example:"2023-04-10T12:34:56Z"`
+	Stan            string               `json:"stan" example:"TRX1647881234567"`
+	Amount          int                  `json:"amount" example:"1000"`
+	Currency        string               `json:"currency" example:"USD"`
+	AmountFormatted string               `json:"amount_formatted" example:"$10.00"`
+	Fee             int                  `json:"fee" example:"50"`
+	FXRate          string               `json:"fx_rate,omitempty" example:"0.91689250"`
+	FXSpreadBps     int                  `json:"fx_spread_bps,omitempty" example:"50"`
+	State           string               `json:"state" example:"COMPLETED"`
+	TransactionType string               `json:"transaction_type" example:"DEBIT"`
+	PaymentSource   string               `json:"payment_source" example:"TRANSFER"`
+	Note            string               `json:"note" example:"Transfer to Jane"`
+	Card            *CardPaymentResponse `json:"card,omitempty"`
+	CreatedAt       string               `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	UpdatedAt       string               `json:"updated_at" example:"2023-04-10T12:34:56Z"`
+}
+
+// CardPaymentResponse
+type CardPaymentResponse struct {
+	MaskedPAN    string `json:"masked_pan" example:"424242******4242"`
+	HolderName   string `json:"holder_name" example:"MARK SMITH"`
+	Expiry       string `json:"expiry" example:"12/29"`
+	TerminalID   string `json:"terminal_id" example:"MT000001"`
+	AuthCode     string `json:"auth_code,omitempty" example:"3FA9C1"`
+	ResponseCode string `json:"response_code,omitempty" example:"00"`
+}
+
+// CardRequest
+type CardRequest struct {
+	Number      string `json:"number" example:"4242424242424242" description:"Card number; only its masked form is stored"`
+	HolderName  string `json:"holder_name" example:"MARK SMITH" description:"Name on the card"`
+	ExpiryMonth int    `json:"expiry_month" example:"12" description:"Expiry month, 1-12"`
+	ExpiryYear  int    `json:"expiry_year" example:"2029" description:"Expiry year, four or two digits"`
+}
+
+// CardResponse
+type CardResponse struct {
+	ID         string `json:"id" example:"CRD7"`
+	UserID     string `json:"user_id" example:"1"`
+	MaskedPAN  string `json:"masked_pan" example:"424242******4242"`
+	Brand      string `json:"brand" example:"VISA"`
+	HolderName string `json:"holder_name" example:"MARK SMITH"`
+	Expiry     string `json:"expiry" example:"12/29"`
+	CreatedAt  string `json:"created_at" example:"2023-04-10T12:34:56Z"`
+}
+
+// LimitExceededResponse says which limit a transfer exceeded and when it resets
+type LimitExceededResponse struct {
+	Error    string `json:"error" example:"transfer limit exceeded: MAX_DAILY_AMOUNT is 1000000 in USD, resets at 2023-04-11T00:00:00Z"`
+	Limit    string `json:"limit" example:"MAX_DAILY_AMOUNT"`
+	Currency string `json:"currency" example:"USD"`
+	Max      int    `json:"max" example:"1000000"`
+	ResetsAt string `json:"resets_at,omitempty" example:"2023-04-11T00:00:00Z"`
+}
+
+// TransferLimitResponse
+type TransferLimitResponse struct {
+	UserID     string               `json:"user_id" example:"1"`
+	Currency   string               `json:"currency" example:"USD"`
+	LimitID    string               `json:"limit_id,omitempty" example:"LIM-USD-STANDARD"`
+	MaxSingle  int                  `json:"max_single" example:"500000"`
+	MaxDaily   int                  `json:"max_daily" example:"1000000"`
+	MaxMonthly int                  `json:"max_monthly" example:"5000000"`
+	MaxPerHour int                  `json:"max_per_hour" example:"10"`
+	Usage      []LimitUsageResponse `json:"usage"`
+}
+
+// LimitUsageResponse
+type LimitUsageResponse struct {
+	Period      string `json:"period" example:"DAY"`
+	PeriodStart string `json:"period_start" example:"2023-04-10T00:00:00Z"`
+	Amount      int    `json:"amount" example:"2500"`
+	Count       int    `json:"count" example:"2"`
+	ResetsAt    string `json:"resets_at" example:"2023-04-11T00:00:00Z"`
+}
+
+// ReviewRequest
+type ReviewRequest struct {
+	Reviewer string `json:"reviewer" example:"jane.doe" description:"Who made the decision"`
+	Note     string `json:"note,omitempty" example:"Confirmed with the customer by phone" description:"Reason for the decision"`
+}
+
+// RiskReviewResponse
+type RiskReviewResponse struct {
+	ID         string   `json:"id" example:"RRV42"`
+	TransferID string   `json:"transfer_id" example:"TRF1647881234567"`
+	Status     string   `json:"status" example:"PENDING"`
+	Reasons    []string `json:"reasons" example:"new_payee_large_amount: first transfer to this recipient is a large amount"`
+	Authorize  bool     `json:"authorize" example:"false"`
+	Reviewer   string   `json:"reviewer,omitempty" example:"jane.doe"`
+	Note       string   `json:"note,omitempty" example:"Confirmed with the customer by phone"`
+	CreatedAt  string   `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	ReviewedAt string   `json:"reviewed_at,omitempty" example:"2023-04-10T13:02:11Z"`
+}
+
+// CreateWebhookRequest
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" example:"https://example.com/hooks/money-transfer" description:"Absolute http or https URL the events are POSTed to"`
+	EventTypes []string `json:"event_types" example:"transfer_completed,deposit_*" description:"Event types, prefixes ending in * or * for every event"`
+	Secret     string   `json:"secret,omitempty" example:"whsec_3f9a1c0e7b2d4a6f8e0c1b3d5f7a9c2e" description:"16 to 100 characters signing the deliveries; generated when omitted"`
+}
+
+// UpdateWebhookRequest
+type UpdateWebhookRequest struct {
+	URL        *string  `json:"url,omitempty" example:"https://example.com/hooks/money-transfer" description:"New URL"`
+	EventTypes []string `json:"event_types,omitempty" example:"transfer_*" description:"New event types"`
+	Secret     *string  `json:"secret,omitempty" example:"whsec_3f9a1c0e7b2d4a6f8e0c1b3d5f7a9c2e" description:"New signing secret"`
+	Active     *bool    `json:"active,omitempty" example:"false" description:"Pause or resume deliveries"`
+}
+
+// WebhookResponse
+type WebhookResponse struct {
+	ID         string   `json:"id" example:"WHK42"`
+	URL        string   `json:"url" example:"https://example.com/hooks/money-transfer"`
+	EventTypes []string `json:"event_types" example:"transfer_completed,deposit_*"`
+	Secret     string   `json:"secret,omitempty" example:"whsec_3f9a1c0e7b2d4a6f8e0c1b3d5f7a9c2e"`
+	Active     bool     `json:"active" example:"true"`
+	CreatedAt  string   `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	UpdatedAt  string   `json:"updated_at" example:"2023-04-10T12:34:56Z"`
+}
+
+// WebhookDeliveryResponse
+type WebhookDeliveryResponse struct {
+	ID            string                    `json:"id" example:"WHD42"`
+	WebhookID     string                    `json:"webhook_id" example:"WHK42"`
+	EventID       int64                     `json:"event_id" example:"1234"`
+	EventType     string                    `json:"event_type" example:"transfer_completed"`
+	Status        string                    `json:"status" example:"PENDING"`
+	Attempts      int                       `json:"attempts" example:"2"`
+	NextAttemptAt string                    `json:"next_attempt_at,omitempty" example:"2023-04-10T12:36:56Z"`
+	LastError     string                    `json:"last_error,omitempty" example:"webhook answered 503 Service Unavailable"`
+	CreatedAt     string                    `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	DeliveredAt   string                    `json:"delivered_at,omitempty" example:"2023-04-10T12:36:57Z"`
+	AttemptLog    []*WebhookAttemptResponse `json:"attempt_log"`
+}
+
+// WebhookAttemptResponse
+type WebhookAttemptResponse struct {
+	AttemptedAt  string `json:"attempted_at" example:"2023-04-10T12:34:57Z"`
+	Success      bool   `json:"success" example:"false"`
+	StatusCode   int    `json:"status_code,omitempty" example:"503"`
+	Error        string `json:"error,omitempty" example:"webhook answered 503 Service Unavailable"`
+	ResponseBody string `json:"response_body,omitempty" example:"upstream unavailable"`
+	DurationMs   int64  `json:"duration_ms" example:"182"`
 }
 
 // TransferResponse
@@ -39,25 +198,289 @@ type TransferResponse struct {
 	FromUserID      string               `json:"from_user_id" example:"1"`
 	ToUserID        string               `json:"to_user_id" example:"2"`
 	Amount          int                  `json:"amount" example:"1000"`
+	Currency        string               `json:"currency" example:"USD"`
 	AmountFormatted string               `json:"amount_formatted" example:"$10.00"`
+	Fee             int                  `json:"fee" example:"50"`
+	FeeFormatted    string               `json:"fee_formatted" example:"$0.50"`
+	QuoteID         string               `json:"quote_id,omitempty" example:"QTE42"`
 	State           string               `json:"state" example:"COMPLETED"`
+	ReversalOf      string               `json:"reversal_of,omitempty" example:"TRF1647881234567"`
+	ReversedAmount  int                  `json:"reversed_amount,omitempty" example:"500"`
+	Reversals       []string             `json:"reversals,omitempty" example:"TRF1647881239999"`
+	StandingOrderID string               `json:"standing_order_id,omitempty" example:"STO42"`
 	DebitTx         *TransactionResponse `json:"debit_tx,omitempty"`
 	CreditTx        *TransactionResponse `json:"credit_tx,omitempty"`
 	CreatedAt       string               `json:"created_at" example:"2023-04-10T12:34:56Z"`
 	CompletedAt     string               `json:"completed_at,omitempty" example:"2023-04-10T12:34:56Z"`
 }
 
+// FundingRequest
+type FundingRequest struct {
+	UserID          string `json:"user_id" example:"1" description:"ID of the user whose balance is funded or withdrawn from"`
+	Amount          int    `json:"amount" example:"5000" description:"Amount in minor units of the currency"`
+	Currency        string `json:"currency,omitempty" example:"USD" description:"ISO-4217 currency code, defaults to USD"`
+	PaymentMethod   string `json:"payment_method" example:"CARD" description:"CARD or BANK"`
+	ExternalAccount string `json:"external_account" example:"card-4242" description:"Card or bank account reference at the funding provider"`
+}
+
+// FundingResponse
+type FundingResponse struct {
+	ID              string               `json:"id" example:"FND42"`
+	Type            string               `json:"type" example:"DEPOSIT"`
+	UserID          string               `json:"user_id" example:"1"`
+	Amount          int                  `json:"amount" example:"5000"`
+	Currency        string               `json:"currency" example:"USD"`
+	AmountFormatted string               `json:"amount_formatted" example:"$50.00"`
+	PaymentMethod   string               `json:"payment_method" example:"CARD"`
+	ExternalAccount string               `json:"external_account" example:"card-4242"`
+	ProviderRef     string               `json:"provider_ref,omitempty" example:"FAKE-FND42"`
+	State           string               `json:"state" example:"COMPLETED"`
+	FailureReason   string               `json:"failure_reason,omitempty" example:"declined by the provider"`
+	Transaction     *TransactionResponse `json:"transaction,omitempty"`
+	CreatedAt       string               `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	CompletedAt     string               `json:"completed_at,omitempty" example:"2023-04-10T12:34:56Z"`
+}
+
 // TransferRequest
 type TransferRequest struct {
 	FromUserID string `json:"from_user_id" example:"1" description:"ID of the sender"`
 	ToUserID   string `json:"to_user_id" example:"2" description:"ID of the recipient"`
-	Amount     int    `json:"amount" example:"1000" description:"Amount to transfer in cents (e.g., 1000 = $10.00)"`
+	Amount     int    `json:"amount" example:"1000" description:"Amount to transfer in minor units of the currency (e.g., 1000 = $10.00)"`
+	Currency   string `json:"currency,omitempty" example:"USD" description:"ISO-4217 currency code, defaults to USD"`
+	QuoteID    string `json:"quote_id,omitempty" example:"QTE42" description:"FX quote for a cross-currency transfer"`
+	ExecuteAt  string `json:"execute_at,omitempty" example:"2023-05-01T09:00:00Z" description:"RFC3339 time to execute the transfer at; schedules it instead of executing it now"`
+	CardID     string `json:"card_id,omitempty" example:"CRD7" description:"Pay with this card of the sender instead of their balance"`
+}
+
+// StandingOrderRequest
+type StandingOrderRequest struct {
+	FromUserID     string `json:"from_user_id" example:"1" description:"ID of the sender"`
+	ToUserID       string `json:"to_user_id" example:"2" description:"ID of the recipient"`
+	Amount         int    `json:"amount" example:"1000" description:"Amount of each transfer in minor units of the currency"`
+	Currency       string `json:"currency,omitempty" example:"USD" description:"ISO-4217 currency code, defaults to USD"`
+	Frequency      string `json:"frequency" example:"MONTHLY" description:"DAILY, WEEKLY, MONTHLY or LAST_BUSINESS_DAY"`
+	DayOfMonth     int    `json:"day_of_month,omitempty" example:"15" description:"Day of a MONTHLY order; the last day of shorter months is used"`
+	StartDate      string `json:"start_date,omitempty" example:"2023-05-01" description:"First possible run date, defaults to today"`
+	EndDate        string `json:"end_date,omitempty" example:"2023-12-31" description:"Last possible run date"`
+	MaxOccurrences int    `json:"max_occurrences,omitempty" example:"12" description:"Number of runs after which the order completes"`
+	CatchUp        string `json:"catch_up,omitempty" example:"SKIP" description:"SKIP or RUN_ONCE for runs missed while the scheduler was down, defaults to SKIP"`
+}
+
+// StandingOrderResponse
+type StandingOrderResponse struct {
+	ID                string   `json:"id" example:"STO42"`
+	FromUserID        string   `json:"from_user_id" example:"1"`
+	ToUserID          string   `json:"to_user_id" example:"2"`
+	Amount            int      `json:"amount" example:"1000"`
+	Currency          string   `json:"currency" example:"USD"`
+	AmountFormatted   string   `json:"amount_formatted" example:"$10.00"`
+	Frequency         string   `json:"frequency" example:"MONTHLY"`
+	DayOfMonth        int      `json:"day_of_month,omitempty" example:"15"`
+	StartDate         string   `json:"start_date" example:"2023-05-01"`
+	EndDate           string   `json:"end_date,omitempty" example:"2023-12-31"`
+	MaxOccurrences    int      `json:"max_occurrences,omitempty" example:"12"`
+	Occurrences       int      `json:"occurrences" example:"3"`
+	CatchUp           string   `json:"catch_up" example:"SKIP"`
+	Status            string   `json:"status" example:"ACTIVE"`
+	NextRunAt         string   `json:"next_run_at,omitempty" example:"2023-08-15T00:00:00Z"`
+	LastRunAt         string   `json:"last_run_at,omitempty" example:"2023-07-15T00:00:04Z"`
+	LastFailureReason string   `json:"last_failure_reason,omitempty" example:"insufficient funds"`
+	HeldTransferID    string   `json:"held_transfer_id,omitempty" example:"TRF1647881234567" description:"Transfer of the run waiting for risk review"`
+	TransferIDs       []string `json:"transfer_ids,omitempty" example:"TRF1647881234567"`
+	CreatedAt         string   `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	UpdatedAt         string   `json:"updated_at" example:"2023-04-10T12:34:56Z"`
+}
+
+// ScheduledTransferResponse
+type ScheduledTransferResponse struct {
+	ID              string `json:"id" example:"SCH42"`
+	FromUserID      string `json:"from_user_id" example:"1"`
+	ToUserID        string `json:"to_user_id" example:"2"`
+	Amount          int    `json:"amount" example:"1000"`
+	Currency        string `json:"currency" example:"USD"`
+	AmountFormatted string `json:"amount_formatted" example:"$10.00"`
+	ExecuteAt       string `json:"execute_at" example:"2023-05-01T09:00:00Z"`
+	Status          string `json:"status" example:"SCHEDULED"`
+	TransferID      string `json:"transfer_id,omitempty" example:"TRF1647881234567"`
+	FailureReason   string `json:"failure_reason,omitempty" example:"insufficient funds"`
+	CreatedAt       string `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	UpdatedAt       string `json:"updated_at" example:"2023-04-10T12:34:56Z"`
+	ExecutedAt      string `json:"executed_at,omitempty" example:"2023-05-01T09:00:04Z"`
+}
+
+// TransferListResponse
+type TransferListResponse struct {
+	Transfers  []*TransferResponse `json:"transfers"`
+	NextCursor string              `json:"next_cursor,omitempty" example:"eyJrIjoiMjAyMy0wNC0xMFQxMjozNDo1NloiLCJpZCI6NDJ9"`
+}
+
+// StatementResponse
+type StatementResponse struct {
+	UserID                  string                   `json:"user_id" example:"1"`
+	UserName                string                   `json:"user_name" example:"Mark"`
+	Currency                string                   `json:"currency" example:"USD"`
+	From                    string                   `json:"from" example:"2023-04-01T00:00:00Z"`
+	To                      string                   `json:"to" example:"2023-05-01T00:00:00Z"`
+	OpeningBalance          int                      `json:"opening_balance" example:"10000"`
+	OpeningBalanceFormatted string                   `json:"opening_balance_formatted" example:"$100.00"`
+	TotalDebits             int                      `json:"total_debits" example:"1025"`
+	TotalCredits            int                      `json:"total_credits" example:"0"`
+	ClosingBalance          int                      `json:"closing_balance" example:"8975"`
+	ClosingBalanceFormatted string                   `json:"closing_balance_formatted" example:"$89.75"`
+	Lines                   []*StatementLineResponse `json:"lines"`
+}
+
+// StatementLineResponse
+type StatementLineResponse struct {
+	PostedAt         string `json:"posted_at" example:"2023-04-10T12:34:56Z"`
+	TransferID       string `json:"transfer_id" example:"TRF1647881234567"`
+	ReversalOf       string `json:"reversal_of,omitempty" example:"TRF1647881234000"`
+	Stan             string `json:"stan" example:"TRX42"`
+	Type             string `json:"type" example:"DEBIT"`
+	CounterpartyID   string `json:"counterparty_id" example:"2"`
+	Description      string `json:"description" example:"Transfer to Jane"`
+	Amount           int    `json:"amount" example:"1000"`
+	Fee              int    `json:"fee" example:"25"`
+	Net              int    `json:"net" example:"-1025"`
+	NetFormatted     string `json:"net_formatted" example:"-$10.25"`
+	Balance          int    `json:"balance" example:"8975"`
+	BalanceFormatted string `json:"balance_formatted" example:"$89.75"`
+}
+
+// TransferBatchRequest
+type TransferBatchRequest struct {
+	Mode  string                     `json:"mode" example:"ATOMIC" description:"ATOMIC runs all items or none; BEST_EFFORT runs each item on its own"`
+	Items []TransferBatchItemRequest `json:"items" description:"Transfers to run, in order"`
+}
+
+// TransferBatchItemRequest
+type TransferBatchItemRequest struct {
+	FromUserID string `json:"from_user_id" example:"1" description:"ID of the sender"`
+	ToUserID   string `json:"to_user_id" example:"2" description:"ID of the recipient"`
+	Amount     int    `json:"amount" example:"1000" description:"Amount to transfer in minor units of the currency"`
+	Currency   string `json:"currency,omitempty" example:"USD" description:"ISO-4217 currency code, defaults to USD"`
 }
 
-// FormatMoney
-func FormatMoney(cents int) string {
-	dollars := float64(cents) / 100.0
-	return fmt.Sprintf("$%.2f", dollars)
+// TransferBatchResponse
+type TransferBatchResponse struct {
+	ID          string                       `json:"id" example:"BAT42"`
+	Mode        string                       `json:"mode" example:"ATOMIC"`
+	Status      string                       `json:"status" example:"COMPLETED"`
+	ItemCount   int                          `json:"item_count" example:"2"`
+	Succeeded   int                          `json:"succeeded" example:"2"`
+	Failed      int                          `json:"failed" example:"0"`
+	Items       []*TransferBatchItemResponse `json:"items"`
+	CreatedAt   string                       `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	UpdatedAt   string                       `json:"updated_at" example:"2023-04-10T12:34:57Z"`
+	CompletedAt string                       `json:"completed_at,omitempty" example:"2023-04-10T12:34:57Z"`
+}
+
+// TransferBatchItemResponse
+type TransferBatchItemResponse struct {
+	Index           int    `json:"index" example:"0"`
+	FromUserID      string `json:"from_user_id" example:"1"`
+	ToUserID        string `json:"to_user_id" example:"2"`
+	Amount          int    `json:"amount" example:"1000"`
+	Currency        string `json:"currency" example:"USD"`
+	AmountFormatted string `json:"amount_formatted" example:"$10.00"`
+	Status          string `json:"status" example:"COMPLETED"`
+	TransferID      string `json:"transfer_id,omitempty" example:"TRF1647881234567"`
+	Error           string `json:"error,omitempty" example:"insufficient funds"`
+}
+
+// ReversalRequest
+type ReversalRequest struct {
+	Amount int    `json:"amount,omitempty" example:"500" description:"Amount to take back from the recipient in the credited currency; omit to reverse the remaining amount"`
+	Force  bool   `json:"force,omitempty" example:"false" description:"Allow the recipient's balance to go negative"`
+	Reason string `json:"reason,omitempty" example:"Customer refund" description:"Reason recorded on the reversal transactions"`
+}
+
+// QuoteRequest
+type QuoteRequest struct {
+	FromCurrency string `json:"from_currency" example:"USD" description:"Currency debited from the sender"`
+	ToCurrency   string `json:"to_currency" example:"EUR" description:"Currency credited to the recipient"`
+}
+
+// QuoteResponse
+type QuoteResponse struct {
+	ID           string `json:"id" example:"QTE42"`
+	FromCurrency string `json:"from_currency" example:"USD"`
+	ToCurrency   string `json:"to_currency" example:"EUR"`
+	MidRate      string `json:"mid_rate" example:"0.92150000"`
+	Rate         string `json:"rate" example:"0.91689250"`
+	SpreadBps    int    `json:"spread_bps" example:"50"`
+	Used         bool   `json:"used" example:"false"`
+	CreatedAt    string `json:"created_at" example:"2023-04-10T12:34:56Z"`
+	ExpiresAt    string `json:"expires_at" example:"2023-04-10T12:35:26Z"`
+}
+
+// BalanceMismatchResponse
+type BalanceMismatchResponse struct {
+	UserID        string `json:"user_id" example:"1"`
+	Currency      string `json:"currency" example:"USD"`
+	CachedBalance int    `json:"cached_balance" example:"8000"`
+	LedgerBalance int    `json:"ledger_balance" example:"7000"`
+}
+
+// JournalImbalanceResponse
+type JournalImbalanceResponse struct {
+	JournalID string `json:"journal_id" example:"JRN42"`
+	Currency  string `json:"currency" example:"USD"`
+	Sum       int    `json:"sum" example:"100"`
+}
+
+// LedgerConsistencyResponse
+type LedgerConsistencyResponse struct {
+	Consistent        bool                        `json:"consistent" example:"true"`
+	CheckedAt         string                      `json:"checked_at" example:"2023-04-10T12:34:56Z"`
+	BalanceMismatches []*BalanceMismatchResponse  `json:"balance_mismatches"`
+	JournalImbalances []*JournalImbalanceResponse `json:"journal_imbalances"`
+}
+
+// FeeTierResponse
+type FeeTierResponse struct {
+	UpTo          int `json:"up_to,omitempty" example:"10000"`
+	FlatAmount    int `json:"flat_amount" example:"0"`
+	PercentageBps int `json:"percentage_bps" example:"50"`
+}
+
+// FeeScheduleResponse
+type FeeScheduleResponse struct {
+	ID            string             `json:"id" example:"FEE-USD-STANDARD"`
+	Name          string             `json:"name" example:"USD standard"`
+	Currency      string             `json:"currency" example:"USD"`
+	Type          string             `json:"type" example:"PERCENTAGE"`
+	FlatAmount    int                `json:"flat_amount" example:"0"`
+	PercentageBps int                `json:"percentage_bps" example:"100"`
+	Tiers         []*FeeTierResponse `json:"tiers,omitempty"`
+	MinFee        int                `json:"min_fee" example:"50"`
+	MaxFee        int                `json:"max_fee,omitempty" example:"500"`
+	AccountTier   string             `json:"account_tier,omitempty" example:"PREMIUM"`
+	PaymentMethod string             `json:"payment_method,omitempty" example:"TRANSFER"`
+}
+
+// FeeRevenueResponse
+type FeeRevenueResponse struct {
+	Currency        string `json:"currency" example:"USD"`
+	Amount          int    `json:"amount" example:"1250"`
+	AmountFormatted string `json:"amount_formatted" example:"$12.50"`
+	Transfers       int    `json:"transfers" example:"25"`
+}
+
+// FeeRevenueReportResponse
+type FeeRevenueReportResponse struct {
+	From    string                `json:"from" example:"2023-04-01T00:00:00Z"`
+	To      string                `json:"to" example:"2023-05-01T00:00:00Z"`
+	Revenue []*FeeRevenueResponse `json:"revenue"`
+}
+
+// FormatMoney renders an amount in minor units using the currency's exponent
+func FormatMoney(amount int, currency string) string {
+	c, err := domainModel.LookupCurrency(currency)
+	if err != nil {
+		c = domainModel.Currency{Code: currency}
+	}
+	return c.Format(amount)
 }
 
 // FormatTime
@@ -72,8 +495,16 @@ func TransferToResponse(t *domainModel.Transfer) *TransferResponse {
 		FromUserID:      t.FromUserID,
 		ToUserID:        t.ToUserID,
 		Amount:          t.Amount,
-		AmountFormatted: FormatMoney(t.Amount),
+		Currency:        t.Currency,
+		AmountFormatted: FormatMoney(t.Amount, t.Currency),
+		Fee:             t.Fee,
+		FeeFormatted:    FormatMoney(t.Fee, t.Currency),
+		QuoteID:         t.QuoteID,
 		State:           string(t.State),
+		ReversalOf:      t.ReversalOf,
+		ReversedAmount:  t.ReversedAmount,
+		Reversals:       t.Reversals,
+		StandingOrderID: t.StandingOrderID,
 		CreatedAt:       FormatTime(t.CreatedAt),
 	}
 
@@ -83,42 +514,449 @@ func TransferToResponse(t *domainModel.Transfer) *TransferResponse {
 	}
 
 	if t.DebitTx != nil {
-		res.DebitTx = &TransactionResponse{
-			Stan:            string(t.DebitTx.Stan),
-			Amount:          t.DebitTx.Amount,
-			AmountFormatted: FormatMoney(t.DebitTx.Amount),
-			State:           string(t.DebitTx.State),
-			TransactionType: string(t.DebitTx.TransactionType),
-			PaymentSource:   string(t.DebitTx.PaymentSource),
-			Note:            t.DebitTx.Note,
-			CreatedAt:       FormatTime(t.DebitTx.CreatedAt),
-			UpdatedAt:       FormatTime(t.DebitTx.UpdatedAt),
-		}
+		res.DebitTx = transactionToResponse(t.DebitTx)
 	}
 
 	if t.CreditTx != nil {
-		res.CreditTx = &TransactionResponse{
-			Stan:            string(t.CreditTx.Stan),
-			Amount:          t.CreditTx.Amount,
-			AmountFormatted: FormatMoney(t.CreditTx.Amount),
-			State:           string(t.CreditTx.State),
-			TransactionType: string(t.CreditTx.TransactionType),
-			PaymentSource:   string(t.CreditTx.PaymentSource),
-			Note:            t.CreditTx.Note,
-			CreatedAt:       FormatTime(t.CreditTx.CreatedAt),
-			UpdatedAt:       FormatTime(t.CreditTx.UpdatedAt),
+		res.CreditTx = transactionToResponse(t.CreditTx)
+	}
+
+	return res
+}
+
+// transactionToResponse
+func transactionToResponse(tx *domainModel.Transaction) *TransactionResponse {
+	return &TransactionResponse{
+		Stan:            string(tx.Stan),
+		Amount:          tx.Amount,
+		Currency:        tx.Currency,
+		AmountFormatted: FormatMoney(tx.Amount, tx.Currency),
+		Fee:             tx.Fee,
+		FXRate:          tx.FXRate,
+		FXSpreadBps:     tx.FXSpreadBps,
+		State:           string(tx.State),
+		TransactionType: string(tx.TransactionType),
+		PaymentSource:   string(tx.PaymentSource),
+		Note:            tx.Note,
+		Card:            cardPaymentToResponse(tx),
+		CreatedAt:       FormatTime(tx.CreatedAt),
+		UpdatedAt:       FormatTime(tx.UpdatedAt),
+	}
+}
+
+// cardPaymentToResponse; the card token stays internal
+func cardPaymentToResponse(tx *domainModel.Transaction) *CardPaymentResponse {
+	if tx.PaymentSource != domainModel.PaymentMethodTypeCard || tx.Card == "" {
+		return nil
+	}
+
+	return &CardPaymentResponse{
+		MaskedPAN:    tx.Card,
+		HolderName:   tx.CardName,
+		Expiry:       tx.Expiry,
+		TerminalID:   tx.InstanceID,
+		AuthCode:     tx.AuthCode,
+		ResponseCode: tx.ResponseCode,
+	}
+}
+
+// CardToResponse
+func CardToResponse(c *domainModel.Card) *CardResponse {
+	return &CardResponse{
+		ID:         c.ID,
+		UserID:     c.UserID,
+		MaskedPAN:  c.MaskedPAN,
+		Brand:      string(c.Brand),
+		HolderName: c.HolderName,
+		Expiry:     c.Expiry(),
+		CreatedAt:  FormatTime(c.CreatedAt),
+	}
+}
+
+// LimitExceededToResponse
+func LimitExceededToResponse(e *domainModel.LimitExceededError) *LimitExceededResponse {
+	res := &LimitExceededResponse{
+		Error:    e.Error(),
+		Limit:    string(e.Limit),
+		Currency: e.Currency,
+		Max:      e.Max,
+	}
+	if !e.ResetsAt.IsZero() {
+		res.ResetsAt = FormatTime(e.ResetsAt)
+	}
+	return res
+}
+
+// TransferLimitToResponse; a nil limit leaves every maximum at zero, no limit
+func TransferLimitToResponse(userID, currency string, l *domainModel.TransferLimit, usage []*domainModel.LimitUsage) *TransferLimitResponse {
+	res := &TransferLimitResponse{
+		UserID:   userID,
+		Currency: currency,
+		Usage:    make([]LimitUsageResponse, len(usage)),
+	}
+
+	if l != nil {
+		res.LimitID = l.ID
+		res.MaxSingle = l.MaxSingle
+		res.MaxDaily = l.MaxDaily
+		res.MaxMonthly = l.MaxMonthly
+		res.MaxPerHour = l.MaxPerHour
+	}
+
+	for i, u := range usage {
+		res.Usage[i] = LimitUsageResponse{
+			Period:      string(u.Period),
+			PeriodStart: FormatTime(u.PeriodStart),
+			Amount:      u.Amount,
+			Count:       u.Count,
+			ResetsAt:    FormatTime(u.ResetsAt()),
 		}
 	}
 
 	return res
 }
 
+// RiskReviewToResponse
+func RiskReviewToResponse(r *domainModel.RiskReview) *RiskReviewResponse {
+	res := &RiskReviewResponse{
+		ID:         r.ID,
+		TransferID: r.TransferID,
+		Status:     string(r.Status),
+		Reasons:    r.Reasons,
+		Authorize:  r.Authorize,
+		Reviewer:   r.Reviewer,
+		Note:       r.Note,
+		CreatedAt:  FormatTime(r.CreatedAt),
+	}
+	if !r.ReviewedAt.IsZero() {
+		res.ReviewedAt = FormatTime(r.ReviewedAt)
+	}
+	return res
+}
+
+// WebhookToResponse leaves the secret out unless withSecret is set
+func WebhookToResponse(s *domainModel.WebhookSubscription, withSecret bool) *WebhookResponse {
+	res := &WebhookResponse{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		Active:     s.Active,
+		CreatedAt:  FormatTime(s.CreatedAt),
+		UpdatedAt:  FormatTime(s.UpdatedAt),
+	}
+	if withSecret {
+		res.Secret = s.Secret
+	}
+	return res
+}
+
+// WebhookDeliveryToResponse
+func WebhookDeliveryToResponse(d *domainModel.WebhookDelivery) *WebhookDeliveryResponse {
+	res := &WebhookDeliveryResponse{
+		ID:         d.ID,
+		WebhookID:  d.SubscriptionID,
+		EventID:    d.EventID,
+		EventType:  d.EventType,
+		Status:     string(d.Status),
+		Attempts:   d.Attempts,
+		LastError:  d.LastError,
+		CreatedAt:  FormatTime(d.CreatedAt),
+		AttemptLog: make([]*WebhookAttemptResponse, 0, len(d.AttemptLog)),
+	}
+	if d.Status == domainModel.WebhookDeliveryStatusPending {
+		res.NextAttemptAt = FormatTime(d.NextAttemptAt)
+	}
+	if !d.DeliveredAt.IsZero() {
+		res.DeliveredAt = FormatTime(d.DeliveredAt)
+	}
+	for _, a := range d.AttemptLog {
+		res.AttemptLog = append(res.AttemptLog, &WebhookAttemptResponse{
+			AttemptedAt:  FormatTime(a.AttemptedAt),
+			Success:      a.Success,
+			StatusCode:   a.StatusCode,
+			Error:        a.Error,
+			ResponseBody: a.ResponseBody,
+			DurationMs:   a.Duration.Milliseconds(),
+		})
+	}
+	return res
+}
+
+// FundingToResponse
+func FundingToResponse(f *domainModel.Funding) *FundingResponse {
+	res := &FundingResponse{
+		ID:              f.ID,
+		Type:            string(f.Type),
+		UserID:          f.UserID,
+		Amount:          f.Amount,
+		Currency:        f.Currency,
+		AmountFormatted: FormatMoney(f.Amount, f.Currency),
+		PaymentMethod:   string(f.Method),
+		ExternalAccount: f.ExternalAccount,
+		ProviderRef:     f.ProviderRef,
+		State:           string(f.State),
+		FailureReason:   f.FailureReason,
+		CreatedAt:       FormatTime(f.CreatedAt),
+	}
+
+	// A card number given as the external account is never echoed in full
+	if f.Method == domainModel.PaymentMethodTypeCard && domainModel.ValidPAN(f.ExternalAccount) {
+		res.ExternalAccount = domainModel.MaskPAN(f.ExternalAccount)
+	}
+
+	if !f.CompletedAt.IsZero() {
+		res.CompletedAt = FormatTime(f.CompletedAt)
+	}
+
+	if f.Transaction != nil {
+		res.Transaction = transactionToResponse(f.Transaction)
+	}
+
+	return res
+}
+
 // UserToResponse
 func UserToResponse(u *domainModel.User) *UserResponse {
+	balances := make([]*BalanceResponse, 0, len(u.Balances))
+	for _, b := range u.Balances {
+		balances = append(balances, &BalanceResponse{
+			Currency:           b.Currency,
+			Amount:             b.Amount,
+			AmountFormatted:    FormatMoney(b.Amount, b.Currency),
+			Held:               b.Held,
+			Available:          b.Available(),
+			AvailableFormatted: FormatMoney(b.Available(), b.Currency),
+		})
+	}
+
 	return &UserResponse{
-		ID:               u.ID,
-		Name:             u.Name,
-		Balance:          u.Balance,
-		BalanceFormatted: FormatMoney(u.Balance),
+		ID:       u.ID,
+		Name:     u.Name,
+		Tier:     string(u.Tier),
+		Status:   string(u.Status),
+		Balances: balances,
+	}
+}
+
+// QuoteToResponse
+func QuoteToResponse(q *domainModel.FXQuote) *QuoteResponse {
+	return &QuoteResponse{
+		ID:           q.ID,
+		FromCurrency: q.FromCurrency,
+		ToCurrency:   q.ToCurrency,
+		MidRate:      domainModel.FormatRate(q.MidRate),
+		Rate:         domainModel.FormatRate(q.Rate),
+		SpreadBps:    q.SpreadBps,
+		Used:         q.Used(),
+		CreatedAt:    FormatTime(q.CreatedAt),
+		ExpiresAt:    FormatTime(q.ExpiresAt),
+	}
+}
+
+// LedgerConsistencyToResponse
+func LedgerConsistencyToResponse(r *domainModel.LedgerConsistencyReport) *LedgerConsistencyResponse {
+	res := &LedgerConsistencyResponse{
+		Consistent:        r.Consistent(),
+		CheckedAt:         FormatTime(r.CheckedAt),
+		BalanceMismatches: make([]*BalanceMismatchResponse, 0, len(r.BalanceMismatches)),
+		JournalImbalances: make([]*JournalImbalanceResponse, 0, len(r.JournalImbalances)),
+	}
+
+	for _, m := range r.BalanceMismatches {
+		res.BalanceMismatches = append(res.BalanceMismatches, &BalanceMismatchResponse{
+			UserID:        m.UserID,
+			Currency:      m.Currency,
+			CachedBalance: m.CachedBalance,
+			LedgerBalance: m.LedgerBalance,
+		})
+	}
+
+	for _, im := range r.JournalImbalances {
+		res.JournalImbalances = append(res.JournalImbalances, &JournalImbalanceResponse{
+			JournalID: im.JournalID,
+			Currency:  im.Currency,
+			Sum:       im.Sum,
+		})
+	}
+
+	return res
+}
+
+// FeeScheduleToResponse
+func FeeScheduleToResponse(fs *domainModel.FeeSchedule) *FeeScheduleResponse {
+	res := &FeeScheduleResponse{
+		ID:            fs.ID,
+		Name:          fs.Name,
+		Currency:      fs.Currency,
+		Type:          string(fs.Type),
+		FlatAmount:    fs.FlatAmount,
+		PercentageBps: fs.PercentageBps,
+		MinFee:        fs.MinFee,
+		MaxFee:        fs.MaxFee,
+		AccountTier:   string(fs.AccountTier),
+		PaymentMethod: string(fs.PaymentMethod),
+	}
+
+	for _, t := range fs.Tiers {
+		res.Tiers = append(res.Tiers, &FeeTierResponse{
+			UpTo:          t.UpTo,
+			FlatAmount:    t.FlatAmount,
+			PercentageBps: t.PercentageBps,
+		})
 	}
+
+	return res
+}
+
+// FeeRevenueToResponse
+func FeeRevenueToResponse(from, to time.Time, totals []*domainModel.AccountTotal) *FeeRevenueReportResponse {
+	res := &FeeRevenueReportResponse{
+		From:    FormatTime(from),
+		To:      FormatTime(to),
+		Revenue: make([]*FeeRevenueResponse, 0, len(totals)),
+	}
+
+	for _, t := range totals {
+		res.Revenue = append(res.Revenue, &FeeRevenueResponse{
+			Currency:        t.Currency,
+			Amount:          t.Amount,
+			AmountFormatted: FormatMoney(t.Amount, t.Currency),
+			Transfers:       t.Entries,
+		})
+	}
+
+	return res
+}
+
+// ScheduledTransferToResponse
+func ScheduledTransferToResponse(s *domainModel.ScheduledTransfer) *ScheduledTransferResponse {
+	res := &ScheduledTransferResponse{
+		ID:              s.ID,
+		FromUserID:      s.FromUserID,
+		ToUserID:        s.ToUserID,
+		Amount:          s.Amount,
+		Currency:        s.Currency,
+		AmountFormatted: FormatMoney(s.Amount, s.Currency),
+		ExecuteAt:       FormatTime(s.ExecuteAt),
+		Status:          string(s.Status),
+		TransferID:      s.TransferID,
+		FailureReason:   s.FailureReason,
+		CreatedAt:       FormatTime(s.CreatedAt),
+		UpdatedAt:       FormatTime(s.UpdatedAt),
+	}
+
+	if !s.ExecutedAt.IsZero() {
+		res.ExecutedAt = FormatTime(s.ExecutedAt)
+	}
+
+	return res
+}
+
+// StandingOrderToResponse
+func StandingOrderToResponse(o *domainModel.StandingOrder) *StandingOrderResponse {
+	res := &StandingOrderResponse{
+		ID:                o.ID,
+		FromUserID:        o.FromUserID,
+		ToUserID:          o.ToUserID,
+		Amount:            o.Amount,
+		Currency:          o.Currency,
+		AmountFormatted:   FormatMoney(o.Amount, o.Currency),
+		Frequency:         string(o.Frequency),
+		DayOfMonth:        o.DayOfMonth,
+		StartDate:         o.StartDate.Format(time.DateOnly),
+		MaxOccurrences:    o.MaxOccurrences,
+		Occurrences:       o.Occurrences,
+		CatchUp:           string(o.CatchUp),
+		Status:            string(o.Status),
+		LastFailureReason: o.LastFailureReason,
+		HeldTransferID:    o.HeldTransferID,
+		TransferIDs:       o.TransferIDs,
+		CreatedAt:         FormatTime(o.CreatedAt),
+		UpdatedAt:         FormatTime(o.UpdatedAt),
+	}
+
+	if !o.EndDate.IsZero() {
+		res.EndDate = o.EndDate.Format(time.DateOnly)
+	}
+	if !o.NextRunAt.IsZero() {
+		res.NextRunAt = FormatTime(o.NextRunAt)
+	}
+	if !o.LastRunAt.IsZero() {
+		res.LastRunAt = FormatTime(o.LastRunAt)
+	}
+
+	return res
+}
+
+// TransferBatchToResponse
+func TransferBatchToResponse(b *domainModel.TransferBatch) *TransferBatchResponse {
+	res := &TransferBatchResponse{
+		ID:        b.ID,
+		Mode:      string(b.Mode),
+		Status:    string(b.Status),
+		ItemCount: len(b.Items),
+		Succeeded: b.Count(domainModel.BatchItemStatusCompleted),
+		Failed:    b.Count(domainModel.BatchItemStatusFailed),
+		Items:     make([]*TransferBatchItemResponse, len(b.Items)),
+		CreatedAt: FormatTime(b.CreatedAt),
+		UpdatedAt: FormatTime(b.UpdatedAt),
+	}
+
+	for i, item := range b.Items {
+		res.Items[i] = &TransferBatchItemResponse{
+			Index:           item.Index,
+			FromUserID:      item.FromUserID,
+			ToUserID:        item.ToUserID,
+			Amount:          item.Amount,
+			Currency:        item.Currency,
+			AmountFormatted: FormatMoney(item.Amount, item.Currency),
+			Status:          string(item.Status),
+			TransferID:      item.TransferID,
+			Error:           item.Error,
+		}
+	}
+
+	if !b.CompletedAt.IsZero() {
+		res.CompletedAt = FormatTime(b.CompletedAt)
+	}
+
+	return res
+}
+
+// StatementToResponse
+func StatementToResponse(s *domainModel.Statement) *StatementResponse {
+	res := &StatementResponse{
+		UserID:                  s.UserID,
+		UserName:                s.UserName,
+		Currency:                s.Currency,
+		From:                    FormatTime(s.From),
+		To:                      FormatTime(s.To),
+		OpeningBalance:          s.OpeningBalance,
+		OpeningBalanceFormatted: FormatMoney(s.OpeningBalance, s.Currency),
+		TotalDebits:             s.TotalDebits,
+		TotalCredits:            s.TotalCredits,
+		ClosingBalance:          s.ClosingBalance,
+		ClosingBalanceFormatted: FormatMoney(s.ClosingBalance, s.Currency),
+		Lines:                   make([]*StatementLineResponse, len(s.Lines)),
+	}
+
+	for i, l := range s.Lines {
+		res.Lines[i] = &StatementLineResponse{
+			PostedAt:         FormatTime(l.PostedAt),
+			TransferID:       l.TransferID,
+			ReversalOf:       l.ReversalOf,
+			Stan:             string(l.Stan),
+			Type:             string(l.Type),
+			CounterpartyID:   l.CounterpartyID,
+			Description:      l.Note,
+			Amount:           l.Amount,
+			Fee:              l.Fee,
+			Net:              l.Net(),
+			NetFormatted:     FormatMoney(l.Net(), s.Currency),
+			Balance:          l.Balance,
+			BalanceFormatted: FormatMoney(l.Balance, s.Currency),
+		}
+	}
+
+	return res
 }
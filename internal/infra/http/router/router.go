@@ -26,17 +26,82 @@ func NewRouter(services *service.Services) *Router {
 
 // setupRoutes
 func (r *Router) setupRoutes() {
-	transferController := handler.NewTransferController(r.services.TransferService)
-	userController := handler.NewUserController(r.services.TransferService)
+	transferController := handler.NewTransferController(r.services.TransferService, r.services.ScheduledTransferService)
+	scheduledTransferController := handler.NewScheduledTransferController(r.services.ScheduledTransferService)
+	standingOrderController := handler.NewStandingOrderController(r.services.StandingOrderService)
+	transferBatchController := handler.NewTransferBatchController(r.services.TransferBatchService)
+	paymentInitiationController := handler.NewPaymentInitiationController(r.services.PaymentInitiationService)
+	fundingController := handler.NewFundingController(r.services.FundingService)
+	userController := handler.NewUserController(r.services.UserService)
+	cardController := handler.NewCardController(r.services.CardService)
+	limitController := handler.NewLimitController(r.services.TransferService)
+	statementController := handler.NewStatementController(r.services.StatementService)
+	fxController := handler.NewFXController(r.services.FXService)
+	ledgerController := handler.NewLedgerController(r.services.LedgerService)
+	feeController := handler.NewFeeController(r.services.FeeService)
+	riskReviewController := handler.NewRiskReviewController(r.services.TransferService)
+	webhookController := handler.NewWebhookController(r.services.WebhookService)
 
 	apiRouter := r.router.PathPrefix("/api").Subrouter()
 
 	apiRouter.HandleFunc("/transfers", transferController.CreateTransferHandler).Methods("POST")
 	apiRouter.HandleFunc("/transfers", transferController.ListTransfersHandler).Methods("GET")
+	apiRouter.HandleFunc("/transfers/authorize", transferController.AuthorizeTransferHandler).Methods("POST")
 	apiRouter.HandleFunc("/transfers/{id}", transferController.GetTransferByIDHandler).Methods("GET")
+	apiRouter.HandleFunc("/transfers/{id}/capture", transferController.CaptureTransferHandler).Methods("POST")
+	apiRouter.HandleFunc("/transfers/{id}/void", transferController.VoidTransferHandler).Methods("POST")
+	apiRouter.HandleFunc("/transfers/{id}/reverse", transferController.ReverseTransferHandler).Methods("POST")
 
+	apiRouter.HandleFunc("/transfer-batches", transferBatchController.CreateTransferBatchHandler).Methods("POST")
+	apiRouter.HandleFunc("/transfer-batches/{id}", transferBatchController.GetTransferBatchByIDHandler).Methods("GET")
+
+	apiRouter.HandleFunc("/payment-initiations", paymentInitiationController.CreatePaymentInitiationHandler).Methods("POST")
+
+	apiRouter.HandleFunc("/deposits", fundingController.CreateDepositHandler).Methods("POST")
+	apiRouter.HandleFunc("/deposits/{id}", fundingController.GetDepositByIDHandler).Methods("GET")
+	apiRouter.HandleFunc("/withdrawals", fundingController.CreateWithdrawalHandler).Methods("POST")
+	apiRouter.HandleFunc("/withdrawals/{id}", fundingController.GetWithdrawalByIDHandler).Methods("GET")
+
+	apiRouter.HandleFunc("/scheduled-transfers", scheduledTransferController.ListScheduledTransfersHandler).Methods("GET")
+	apiRouter.HandleFunc("/scheduled-transfers/{id}", scheduledTransferController.GetScheduledTransferByIDHandler).Methods("GET")
+	apiRouter.HandleFunc("/scheduled-transfers/{id}/cancel", scheduledTransferController.CancelScheduledTransferHandler).Methods("POST")
+
+	apiRouter.HandleFunc("/standing-orders", standingOrderController.CreateStandingOrderHandler).Methods("POST")
+	apiRouter.HandleFunc("/standing-orders", standingOrderController.ListStandingOrdersHandler).Methods("GET")
+	apiRouter.HandleFunc("/standing-orders/{id}", standingOrderController.GetStandingOrderByIDHandler).Methods("GET")
+	apiRouter.HandleFunc("/standing-orders/{id}/pause", standingOrderController.PauseStandingOrderHandler).Methods("POST")
+	apiRouter.HandleFunc("/standing-orders/{id}/resume", standingOrderController.ResumeStandingOrderHandler).Methods("POST")
+
+	apiRouter.HandleFunc("/users", userController.CreateUserHandler).Methods("POST")
 	apiRouter.HandleFunc("/users", userController.ListUsersHandler).Methods("GET")
 	apiRouter.HandleFunc("/users/{id}", userController.GetUserByIDHandler).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}", userController.UpdateUserHandler).Methods("PATCH")
+	apiRouter.HandleFunc("/users/{id}/cards", cardController.RegisterCardHandler).Methods("POST")
+	apiRouter.HandleFunc("/users/{id}/cards", cardController.ListCardsHandler).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}/limits", limitController.GetLimitsHandler).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}/statement", statementController.GetStatementHandler).Methods("GET")
+	apiRouter.HandleFunc("/users/{id}/statement/export", statementController.ExportStatementHandler).Methods("GET")
+
+	apiRouter.HandleFunc("/fx/quotes", fxController.CreateQuoteHandler).Methods("POST")
+	apiRouter.HandleFunc("/fx/quotes/{id}", fxController.GetQuoteByIDHandler).Methods("GET")
+
+	apiRouter.HandleFunc("/ledger/consistency", ledgerController.CheckConsistencyHandler).Methods("GET")
+
+	apiRouter.HandleFunc("/fee-schedules", feeController.ListFeeSchedulesHandler).Methods("GET")
+	apiRouter.HandleFunc("/reports/fee-revenue", feeController.FeeRevenueHandler).Methods("GET")
+
+	apiRouter.HandleFunc("/admin/risk-reviews", riskReviewController.ListRiskReviewsHandler).Methods("GET")
+	apiRouter.HandleFunc("/admin/transfers/{id}/review", riskReviewController.GetRiskReviewHandler).Methods("GET")
+	apiRouter.HandleFunc("/admin/transfers/{id}/approve", riskReviewController.ApproveTransferHandler).Methods("POST")
+	apiRouter.HandleFunc("/admin/transfers/{id}/reject", riskReviewController.RejectTransferHandler).Methods("POST")
+
+	apiRouter.HandleFunc("/webhooks", webhookController.CreateWebhookHandler).Methods("POST")
+	apiRouter.HandleFunc("/webhooks", webhookController.ListWebhooksHandler).Methods("GET")
+	apiRouter.HandleFunc("/webhooks/{id}", webhookController.GetWebhookHandler).Methods("GET")
+	apiRouter.HandleFunc("/webhooks/{id}", webhookController.UpdateWebhookHandler).Methods("PATCH")
+	apiRouter.HandleFunc("/webhooks/{id}", webhookController.DeleteWebhookHandler).Methods("DELETE")
+	apiRouter.HandleFunc("/webhooks/{id}/deliveries", webhookController.ListDeliveriesHandler).Methods("GET")
+	apiRouter.HandleFunc("/webhooks/{id}/deliveries/{delivery_id}/redeliver", webhookController.RedeliverHandler).Methods("POST")
 
 	r.router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
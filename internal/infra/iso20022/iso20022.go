@@ -0,0 +1,131 @@
+package iso20022
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Transaction and group statuses
+const (
+	StatusAccepted          = "ACCP"
+	StatusAcceptedTechnical = "ACTC"
+	StatusPendingReview     = "PDNG"
+	StatusPartial           = "PART"
+	StatusRejected          = "RJCT"
+	// StatusPending is internal to a report that is being filled in and is never written
+	StatusPending = ""
+)
+
+// ISO 20022 external status reason codes
+const (
+	ReasonIncorrectAccountNumber      = "AC01"
+	ReasonClosedAccountNumber         = "AC04"
+	ReasonBlockedAccount              = "AC06"
+	ReasonTransactionForbidden        = "AG01"
+	ReasonNotAllowedAmount            = "AM02"
+	ReasonCurrencyNotAllowed          = "AM03"
+	ReasonInsufficientFunds           = "AM04"
+	ReasonDuplication                 = "AM05"
+	ReasonInvalidControlSum           = "AM10"
+	ReasonInvalidAmount               = "AM12"
+	ReasonInvalidNumberOfTransactions = "AM18"
+	ReasonInvalidFileFormat           = "FF01"
+	ReasonNarrative                   = "NARR"
+)
+
+var (
+	ErrInvalidDocument = errors.New("not a pain.001 customer credit transfer initiation")
+	ErrInvalidAmount   = errors.New("invalid amount")
+)
+
+// CheckTotals compares a declared number of transactions and optional control
+// sum with the instructed amounts, returning the reason code of a mismatch
+func CheckTotals(declaredCount, declaredSum string, amounts []string) (string, string) {
+	count, err := strconv.Atoi(strings.TrimSpace(declaredCount))
+	if err != nil || count != len(amounts) {
+		return ReasonInvalidNumberOfTransactions,
+			fmt.Sprintf("declared %q transactions, found %d", declaredCount, len(amounts))
+	}
+
+	if strings.TrimSpace(declaredSum) == "" {
+		return "", ""
+	}
+
+	want, err := parseDecimal(declaredSum)
+	if err != nil {
+		return ReasonInvalidControlSum, fmt.Sprintf("invalid control sum %q", declaredSum)
+	}
+
+	sum := new(big.Rat)
+	for _, a := range amounts {
+		v, err := parseDecimal(a)
+		if err != nil {
+			return ReasonInvalidControlSum, fmt.Sprintf("invalid amount %q", a)
+		}
+		sum.Add(sum, v)
+	}
+
+	if sum.Cmp(want) != 0 {
+		return ReasonInvalidControlSum,
+			fmt.Sprintf("declared control sum %s, instructed amounts sum to %s", declaredSum,
+				strings.TrimSuffix(strings.TrimRight(sum.FloatString(5), "0"), "."))
+	}
+
+	return "", ""
+}
+
+// MinorUnits converts a positive decimal amount in major units to minor units
+// of a currency with the given exponent, rejecting excess fraction digits
+func MinorUnits(value string, exponent int) (int, error) {
+	whole, fraction, err := splitDecimal(value)
+	if err != nil || len(fraction) > exponent {
+		return 0, ErrInvalidAmount
+	}
+
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	n, err := strconv.Atoi(whole + fraction)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	return n, nil
+}
+
+// parseDecimal parses a control sum or amount with splitDecimal
+func parseDecimal(value string) (*big.Rat, error) {
+	whole, fraction, err := splitDecimal(value)
+	if err != nil {
+		return nil, err
+	}
+	r, _ := new(big.Rat).SetString(whole + "." + fraction)
+	return r, nil
+}
+
+// splitDecimal splits an unsigned decimal such as 10 or 10.50 into its whole
+// and fraction digits. Signs, exponents and fractions like 1/2, which
+// big.Rat would accept, are rejected so that control sums and amounts are
+// read alike.
+func splitDecimal(value string) (string, string, error) {
+	whole, fraction, hasPoint := strings.Cut(strings.TrimSpace(value), ".")
+	if !isDigits(whole) || (hasPoint && !isDigits(fraction)) {
+		return "", "", ErrInvalidAmount
+	}
+	return whole, fraction, nil
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package iso20022
+
+import "testing"
+
+func TestCheckTotals(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   string
+		sum     string
+		amounts []string
+		reason  string
+	}{
+		{"matching totals", "2", "35.00", []string{"10.5", "24.50"}, ""},
+		{"no control sum", "1", " ", []string{"1e3"}, ""},
+		{"whole amounts", "2", "30", []string{"10", "20.00"}, ""},
+		{"count mismatch", "3", "", []string{"1", "2"}, ReasonInvalidNumberOfTransactions},
+		{"invalid count", "two", "", []string{"1", "2"}, ReasonInvalidNumberOfTransactions},
+		{"sum mismatch", "2", "35.01", []string{"10.5", "24.50"}, ReasonInvalidControlSum},
+		{"exponent control sum", "1", "1e1", []string{"10"}, ReasonInvalidControlSum},
+		{"fraction control sum", "1", "21/2", []string{"10.5"}, ReasonInvalidControlSum},
+		{"signed control sum", "1", "+10", []string{"10"}, ReasonInvalidControlSum},
+		{"exponent amount", "1", "10", []string{"1e1"}, ReasonInvalidControlSum},
+		{"fraction amount", "1", "10.5", []string{"21/2"}, ReasonInvalidControlSum},
+		{"negative amount", "2", "0", []string{"10", "-10"}, ReasonInvalidControlSum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, info := CheckTotals(tt.count, tt.sum, tt.amounts)
+			if reason != tt.reason {
+				t.Errorf("CheckTotals(%q, %q, %q) = %q %q, want %q", tt.count, tt.sum, tt.amounts, reason, info, tt.reason)
+			}
+		})
+	}
+}
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		exponent int
+		want     int
+		wantErr  bool
+	}{
+		{"10.5", 2, 1050, false},
+		{" 10.50 ", 2, 1050, false},
+		{"7", 0, 7, false},
+		{"0.001", 3, 1, false},
+		{"10.555", 2, 0, true},
+		{"7.5", 0, 0, true},
+		{"0", 2, 0, true},
+		{"-1", 2, 0, true},
+		{"+1", 2, 0, true},
+		{"1e2", 2, 0, true},
+		{"1/2", 2, 0, true},
+		{".5", 2, 0, true},
+		{"5.", 2, 0, true},
+		{"", 2, 0, true},
+		{"99999999999999999999", 2, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := MinorUnits(tt.value, tt.exponent)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("MinorUnits(%q, %d) = %d, %v, want %d, error %t", tt.value, tt.exponent, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
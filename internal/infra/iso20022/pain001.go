@@ -0,0 +1,107 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Pain001 is a customer credit transfer initiation. Elements are matched by
+// local name, so any pain.001.001 version with this structure is accepted.
+type Pain001 struct {
+	XMLName    xml.Name                 `xml:"Document"`
+	Initiation CreditTransferInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+// CreditTransferInitiation
+type CreditTransferInitiation struct {
+	GroupHeader  GroupHeader   `xml:"GrpHdr"`
+	PaymentInfos []PaymentInfo `xml:"PmtInf"`
+}
+
+// GroupHeader
+type GroupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreationDateTime     string `xml:"CreDtTm"`
+	NumberOfTransactions string `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum"`
+}
+
+// PaymentInfo groups the credit transfers debited from one account
+type PaymentInfo struct {
+	PaymentInfoID        string           `xml:"PmtInfId"`
+	NumberOfTransactions string           `xml:"NbOfTxs"`
+	ControlSum           string           `xml:"CtrlSum"`
+	Debtor               Party            `xml:"Dbtr"`
+	DebtorAccount        Account          `xml:"DbtrAcct"`
+	Transactions         []CreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+// Party
+type Party struct {
+	Name string `xml:"Nm"`
+}
+
+// Account is identified by an IBAN or, in this system, by the user ID as a
+// proprietary identifier
+type Account struct {
+	IBAN     string `xml:"Id>IBAN"`
+	Other    string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+}
+
+// CreditTransfer
+type CreditTransfer struct {
+	InstructionID   string  `xml:"PmtId>InstrId"`
+	EndToEndID      string  `xml:"PmtId>EndToEndId"`
+	Amount          Amount  `xml:"Amt>InstdAmt"`
+	Creditor        Party   `xml:"Cdtr"`
+	CreditorAccount Account `xml:"CdtrAcct"`
+	RemittanceInfo  string  `xml:"RmtInf>Ustrd"`
+}
+
+// Amount
+type Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// ParsePain001
+func ParsePain001(r io.Reader) (*Pain001, error) {
+	var doc Pain001
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, ErrInvalidDocument
+	}
+
+	if doc.Initiation.GroupHeader.MessageID == "" {
+		return nil, ErrInvalidDocument
+	}
+
+	return &doc, nil
+}
+
+// MessageName is the message identifier from the namespace, e.g. pain.001.001.09
+func (d *Pain001) MessageName() string {
+	if i := strings.LastIndex(d.XMLName.Space, ":"); i >= 0 && strings.HasPrefix(d.XMLName.Space[i+1:], "pain.001") {
+		return d.XMLName.Space[i+1:]
+	}
+	return "pain.001"
+}
+
+// Amounts lists the instructed amounts of every transaction in the message
+func (d *Pain001) Amounts() []string {
+	var amounts []string
+	for _, p := range d.Initiation.PaymentInfos {
+		amounts = append(amounts, p.Amounts()...)
+	}
+	return amounts
+}
+
+// Amounts lists the instructed amounts of the payment information's transactions
+func (p *PaymentInfo) Amounts() []string {
+	amounts := make([]string, len(p.Transactions))
+	for i, t := range p.Transactions {
+		amounts[i] = t.Amount.Value
+	}
+	return amounts
+}
@@ -0,0 +1,261 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// pain002Namespace
+const pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.10"
+
+// StatusReport is the outcome of a pain.001 message, written as pain.002
+type StatusReport struct {
+	MessageID           string
+	CreatedAt           time.Time
+	OriginalMessageID   string
+	OriginalMessageName string
+	OriginalNbOfTxs     string
+	OriginalCtrlSum     string
+	Status              string
+	Reason              string
+	AdditionalInfo      string
+	PaymentInfos        []*PaymentInfoStatus
+}
+
+// PaymentInfoStatus
+type PaymentInfoStatus struct {
+	OriginalPaymentInfoID string
+	Status                string
+	Reason                string
+	AdditionalInfo        string
+	Transactions          []*TransactionStatus
+}
+
+// TransactionStatus
+type TransactionStatus struct {
+	OriginalInstructionID string
+	OriginalEndToEndID    string
+	Status                string
+	Reason                string
+	AdditionalInfo        string
+	// TransferID is the transfer created for an accepted transaction
+	TransferID string
+}
+
+// NewStatusReport prepares a report with one pending status per transaction
+func NewStatusReport(doc *Pain001, now time.Time) *StatusReport {
+	hdr := doc.Initiation.GroupHeader
+
+	// Message identifiers are at most 35 characters
+	messageID := fmt.Sprintf("STS-%s", hdr.MessageID)
+	if len(messageID) > 35 {
+		messageID = messageID[:35]
+	}
+
+	report := &StatusReport{
+		MessageID:           messageID,
+		CreatedAt:           now,
+		OriginalMessageID:   hdr.MessageID,
+		OriginalMessageName: doc.MessageName(),
+		OriginalNbOfTxs:     hdr.NumberOfTransactions,
+		OriginalCtrlSum:     hdr.ControlSum,
+		PaymentInfos:        make([]*PaymentInfoStatus, len(doc.Initiation.PaymentInfos)),
+	}
+
+	for i, p := range doc.Initiation.PaymentInfos {
+		status := &PaymentInfoStatus{
+			OriginalPaymentInfoID: p.PaymentInfoID,
+			Transactions:          make([]*TransactionStatus, len(p.Transactions)),
+		}
+
+		for j, t := range p.Transactions {
+			status.Transactions[j] = &TransactionStatus{
+				OriginalInstructionID: t.InstructionID,
+				OriginalEndToEndID:    t.EndToEndID,
+			}
+		}
+
+		report.PaymentInfos[i] = status
+	}
+
+	return report
+}
+
+// Reject marks the transaction rejected with a reason code and explanation
+func (t *TransactionStatus) Reject(reason, info string) {
+	t.Status = StatusRejected
+	t.Reason = reason
+	t.AdditionalInfo = info
+}
+
+// Reject rejects the payment information and every transaction in it
+func (p *PaymentInfoStatus) Reject(reason, info string) {
+	p.Status = StatusRejected
+	p.Reason = reason
+	p.AdditionalInfo = info
+	for _, t := range p.Transactions {
+		t.Reject(reason, info)
+	}
+}
+
+// Reject rejects the whole message
+func (r *StatusReport) Reject(reason, info string) {
+	r.Status = StatusRejected
+	r.Reason = reason
+	r.AdditionalInfo = info
+	for _, p := range r.PaymentInfos {
+		p.Reject(reason, info)
+	}
+}
+
+// Settle derives the payment information and group statuses that were not set
+// explicitly from the statuses of their transactions
+func (r *StatusReport) Settle() {
+	var all []*TransactionStatus
+	for _, p := range r.PaymentInfos {
+		if p.Status == StatusPending {
+			p.Status = combinedStatus(p.Transactions)
+		}
+		all = append(all, p.Transactions...)
+	}
+
+	if r.Status == StatusPending {
+		r.Status = combinedStatus(all)
+	}
+}
+
+// Count returns the number of transactions in the given status
+func (r *StatusReport) Count(status string) int {
+	n := 0
+	for _, p := range r.PaymentInfos {
+		for _, t := range p.Transactions {
+			if t.Status == status {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// combinedStatus is the shared status of the transactions, or PART when they differ
+func combinedStatus(transactions []*TransactionStatus) string {
+	if len(transactions) == 0 {
+		return StatusRejected
+	}
+
+	status := transactions[0].Status
+	for _, t := range transactions[1:] {
+		if t.Status != status {
+			return StatusPartial
+		}
+	}
+	return status
+}
+
+type pain002Document struct {
+	XMLName   xml.Name         `xml:"Document"`
+	Namespace string           `xml:"xmlns,attr"`
+	Report    pain002StsReport `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002StsReport struct {
+	MsgID      string              `xml:"GrpHdr>MsgId"`
+	CreDtTm    string              `xml:"GrpHdr>CreDtTm"`
+	OrgnlGrp   pain002OrgnlGrp     `xml:"OrgnlGrpInfAndSts"`
+	OrgnlPmtIn []pain002OrgnlPmtIn `xml:"OrgnlPmtInfAndSts"`
+}
+
+type pain002OrgnlGrp struct {
+	OrgnlMsgID   string            `xml:"OrgnlMsgId"`
+	OrgnlMsgNmID string            `xml:"OrgnlMsgNmId"`
+	OrgnlNbOfTxs string            `xml:"OrgnlNbOfTxs,omitempty"`
+	OrgnlCtrlSum string            `xml:"OrgnlCtrlSum,omitempty"`
+	GrpSts       string            `xml:"GrpSts"`
+	StsRsnInf    *pain002StsRsnInf `xml:"StsRsnInf,omitempty"`
+}
+
+type pain002OrgnlPmtIn struct {
+	OrgnlPmtInfID string            `xml:"OrgnlPmtInfId"`
+	PmtInfSts     string            `xml:"PmtInfSts"`
+	StsRsnInf     *pain002StsRsnInf `xml:"StsRsnInf,omitempty"`
+	TxInfAndSts   []pain002TxInf    `xml:"TxInfAndSts"`
+}
+
+type pain002TxInf struct {
+	OrgnlInstrID    string            `xml:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndID string            `xml:"OrgnlEndToEndId,omitempty"`
+	TxSts           string            `xml:"TxSts"`
+	StsRsnInf       *pain002StsRsnInf `xml:"StsRsnInf,omitempty"`
+	AcctSvcrRef     string            `xml:"AcctSvcrRef,omitempty"`
+}
+
+type pain002StsRsnInf struct {
+	Cd       string `xml:"Rsn>Cd"`
+	AddtlInf string `xml:"AddtlInf,omitempty"`
+}
+
+// reasonInfo
+func reasonInfo(reason, info string) *pain002StsRsnInf {
+	if reason == "" {
+		return nil
+	}
+	// AddtlInf is limited to 105 characters
+	if len(info) > 105 {
+		info = info[:105]
+	}
+	return &pain002StsRsnInf{Cd: reason, AddtlInf: info}
+}
+
+// WriteXML writes the report as a pain.002.001.10 customer payment status report
+func (r *StatusReport) WriteXML(w io.Writer) error {
+	doc := pain002Document{
+		Namespace: pain002Namespace,
+		Report: pain002StsReport{
+			MsgID:   r.MessageID,
+			CreDtTm: r.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			OrgnlGrp: pain002OrgnlGrp{
+				OrgnlMsgID:   r.OriginalMessageID,
+				OrgnlMsgNmID: r.OriginalMessageName,
+				OrgnlNbOfTxs: r.OriginalNbOfTxs,
+				OrgnlCtrlSum: r.OriginalCtrlSum,
+				GrpSts:       r.Status,
+				StsRsnInf:    reasonInfo(r.Reason, r.AdditionalInfo),
+			},
+		},
+	}
+
+	for _, p := range r.PaymentInfos {
+		pmtInf := pain002OrgnlPmtIn{
+			OrgnlPmtInfID: p.OriginalPaymentInfoID,
+			PmtInfSts:     p.Status,
+			StsRsnInf:     reasonInfo(p.Reason, p.AdditionalInfo),
+		}
+
+		for _, t := range p.Transactions {
+			pmtInf.TxInfAndSts = append(pmtInf.TxInfAndSts, pain002TxInf{
+				OrgnlInstrID:    t.OriginalInstructionID,
+				OrgnlEndToEndID: t.OriginalEndToEndID,
+				TxSts:           t.Status,
+				StsRsnInf:       reasonInfo(t.Reason, t.AdditionalInfo),
+				AcctSvcrRef:     t.TransferID,
+			})
+		}
+
+		doc.Report.OrgnlPmtIn = append(doc.Report.OrgnlPmtIn, pmtInf)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
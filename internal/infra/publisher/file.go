@@ -0,0 +1,58 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// FilePublisher appends each event as one JSON line to a file and syncs it
+// to disk before reporting success
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening event file: %w", err)
+	}
+
+	return &FilePublisher{
+		file: file,
+	}, nil
+}
+
+// Publish
+func (p *FilePublisher) Publish(ctx context.Context, event *model.Event) error {
+	line, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.file.Write(line); err != nil {
+		return fmt.Errorf("error writing event file: %w", err)
+	}
+	if err := p.file.Sync(); err != nil {
+		return fmt.Errorf("error syncing event file: %w", err)
+	}
+
+	return nil
+}
+
+// Close
+func (p *FilePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.file.Close()
+}
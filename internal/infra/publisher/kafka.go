@@ -0,0 +1,502 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// Kafka API keys and the versions used. Produce v3 is the oldest version
+// Kafka 4 still accepts and the first to carry v2 record batches.
+const (
+	kafkaAPIProduce       = 0
+	kafkaAPIMetadata      = 3
+	kafkaProduceVersion   = 3
+	kafkaMetadataVersion  = 1
+	kafkaAcksAll          = -1
+	kafkaMaxResponseSize  = 64 << 20
+	kafkaErrNone          = 0
+	kafkaRecordBatchMagic = 2
+	kafkaNoProducerID     = -1
+	kafkaNoProducerEpoch  = -1
+	kafkaNoSequence       = -1
+	kafkaNoLeaderEpoch    = -1
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// KafkaPublisher produces each event as one record to a topic over the Kafka
+// wire protocol, waiting for all in-sync replicas to acknowledge it. The
+// record key is the aggregate ID, so the events of one transfer land in the
+// same partition in order; partitions are picked with murmur2 like the Java
+// client's default partitioner.
+type KafkaPublisher struct {
+	brokers  []string
+	topic    string
+	clientID string
+	timeout  time.Duration
+
+	mu            sync.Mutex
+	correlationID int32
+	leaders       []string
+	conns         map[string]*kafkaConn
+}
+
+// kafkaConn
+type kafkaConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewKafkaPublisher
+func NewKafkaPublisher(brokers []string, topic, clientID string, timeout time.Duration) (*KafkaPublisher, error) {
+	var addrs []string
+	for _, broker := range brokers {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			addrs = append(addrs, broker)
+		}
+	}
+	brokers = addrs
+
+	if len(brokers) == 0 {
+		return nil, errors.New("no Kafka brokers configured")
+	}
+	if topic == "" {
+		return nil, errors.New("no Kafka topic configured")
+	}
+
+	return &KafkaPublisher{
+		brokers:  brokers,
+		topic:    topic,
+		clientID: clientID,
+		timeout:  timeout,
+		conns:    map[string]*kafkaConn{},
+	}, nil
+}
+
+// Publish
+func (p *KafkaPublisher) Publish(ctx context.Context, event *model.Event) error {
+	value, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+	key := []byte(event.AggregateID)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.produce(ctx, key, value, event); err != nil {
+		// Leadership may have moved; look it up again next time
+		p.reset()
+		return err
+	}
+
+	return nil
+}
+
+// produce
+func (p *KafkaPublisher) produce(ctx context.Context, key, value []byte, event *model.Event) error {
+	if p.leaders == nil {
+		if err := p.refreshMetadata(ctx); err != nil {
+			return err
+		}
+	}
+
+	partition := int32(murmur2(key)&0x7fffffff) % int32(len(p.leaders))
+	leader := p.leaders[partition]
+	if leader == "" {
+		return fmt.Errorf("partition %d of %s has no leader", partition, p.topic)
+	}
+
+	batch := kafkaRecordBatch(key, value, [][2]string{
+		{"event_type", event.EventType},
+		{"event_id", strconv.FormatInt(event.ID, 10)},
+	}, event.CreatedAt)
+
+	var req kafkaEncoder
+	req.nullableString(nil) // transactional_id
+	req.int16(kafkaAcksAll)
+	req.int32(int32(p.timeout / time.Millisecond))
+	req.int32(1)
+	req.string(p.topic)
+	req.int32(1)
+	req.int32(partition)
+	req.bytes(batch)
+
+	resp, err := p.roundTrip(ctx, leader, kafkaAPIProduce, kafkaProduceVersion, req.buf)
+	if err != nil {
+		return err
+	}
+
+	d := kafkaDecoder{buf: resp}
+	for topics := d.int32(); topics > 0; topics-- {
+		d.string()
+		for partitions := d.int32(); partitions > 0; partitions-- {
+			d.int32()
+			code := d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+			if d.err == nil && code != kafkaErrNone {
+				return fmt.Errorf("kafka produce to %s failed with error code %d", p.topic, code)
+			}
+		}
+	}
+	if d.err != nil {
+		return fmt.Errorf("error decoding kafka produce response: %w", d.err)
+	}
+
+	return nil
+}
+
+// refreshMetadata looks up the leader of every partition of the topic
+func (p *KafkaPublisher) refreshMetadata(ctx context.Context) error {
+	var req kafkaEncoder
+	req.int32(1)
+	req.string(p.topic)
+
+	var lastErr error
+	for _, broker := range p.brokers {
+		resp, err := p.roundTrip(ctx, broker, kafkaAPIMetadata, kafkaMetadataVersion, req.buf)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		leaders, err := p.parseMetadata(resp)
+		if err != nil {
+			return err
+		}
+		p.leaders = leaders
+		return nil
+	}
+
+	return fmt.Errorf("error getting kafka metadata: %w", lastErr)
+}
+
+// parseMetadata
+func (p *KafkaPublisher) parseMetadata(resp []byte) ([]string, error) {
+	d := kafkaDecoder{buf: resp}
+
+	brokers := map[int32]string{}
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.nullableString() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller_id
+
+	var leaders []string
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		code := d.int16()
+		name := d.string()
+		d.bool() // is_internal
+
+		var partitions []string
+		for m := d.int32(); m > 0 && d.err == nil; m-- {
+			d.int16() // partition error; a missing leader is reported when producing
+			index := d.int32()
+			leader := d.int32()
+			d.int32Array() // replicas
+			d.int32Array() // isr
+
+			if index < 0 || index > 1<<16 {
+				return nil, fmt.Errorf("invalid kafka partition index %d", index)
+			}
+			for int(index) >= len(partitions) {
+				partitions = append(partitions, "")
+			}
+			partitions[index] = brokers[leader]
+		}
+
+		if name != p.topic {
+			continue
+		}
+		if code != kafkaErrNone {
+			return nil, fmt.Errorf("kafka metadata for %s failed with error code %d", p.topic, code)
+		}
+		leaders = partitions
+	}
+
+	if d.err != nil {
+		return nil, fmt.Errorf("error decoding kafka metadata: %w", d.err)
+	}
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("kafka topic %s has no partitions", p.topic)
+	}
+
+	return leaders, nil
+}
+
+// roundTrip sends one request to a broker and returns the response body after
+// the correlation ID
+func (p *KafkaPublisher) roundTrip(ctx context.Context, broker string, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	c, err := p.conn(ctx, broker)
+	if err != nil {
+		return nil, err
+	}
+	c.conn.SetDeadline(deadline(ctx, p.timeout))
+
+	p.correlationID++
+	correlationID := p.correlationID
+
+	var req kafkaEncoder
+	req.int32(0) // size, filled in below
+	req.int16(apiKey)
+	req.int16(apiVersion)
+	req.int32(correlationID)
+	req.string(p.clientID)
+	req.buf = append(req.buf, body...)
+	binary.BigEndian.PutUint32(req.buf, uint32(len(req.buf)-4))
+
+	if _, err := c.conn.Write(req.buf); err != nil {
+		return nil, fmt.Errorf("error writing to kafka broker %s: %w", broker, err)
+	}
+
+	var header [8]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return nil, fmt.Errorf("error reading from kafka broker %s: %w", broker, err)
+	}
+
+	size := int32(binary.BigEndian.Uint32(header[:4]))
+	if size < 4 || size > kafkaMaxResponseSize {
+		return nil, fmt.Errorf("invalid kafka response size %d", size)
+	}
+	if got := int32(binary.BigEndian.Uint32(header[4:])); got != correlationID {
+		return nil, fmt.Errorf("kafka correlation ID %d does not match %d", got, correlationID)
+	}
+
+	resp := make([]byte, size-4)
+	if _, err := io.ReadFull(c.reader, resp); err != nil {
+		return nil, fmt.Errorf("error reading from kafka broker %s: %w", broker, err)
+	}
+
+	return resp, nil
+}
+
+// conn returns the open connection to a broker, dialing it if needed
+func (p *KafkaPublisher) conn(ctx context.Context, broker string) (*kafkaConn, error) {
+	if c, ok := p.conns[broker]; ok {
+		return c, nil
+	}
+
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to kafka broker %s: %w", broker, err)
+	}
+
+	c := &kafkaConn{conn: conn, reader: bufio.NewReader(conn)}
+	p.conns[broker] = c
+	return c, nil
+}
+
+// reset drops the connections and the partition leaders
+func (p *KafkaPublisher) reset() {
+	for broker, c := range p.conns {
+		c.conn.Close()
+		delete(p.conns, broker)
+	}
+	p.leaders = nil
+}
+
+// Close
+func (p *KafkaPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reset()
+	return nil
+}
+
+// kafkaRecordBatch encodes a v2 record batch holding one record
+func kafkaRecordBatch(key, value []byte, headers [][2]string, at time.Time) []byte {
+	var record kafkaEncoder
+	record.int8(0)   // attributes
+	record.varint(0) // timestamp_delta
+	record.varint(0) // offset_delta
+	record.varbytes(key)
+	record.varbytes(value)
+	record.varint(int64(len(headers)))
+	for _, h := range headers {
+		record.varbytes([]byte(h[0]))
+		record.varbytes([]byte(h[1]))
+	}
+
+	timestamp := at.UnixMilli()
+
+	var batch kafkaEncoder
+	batch.int64(0) // base_offset
+	batch.int32(0) // batch_length, filled in below
+	batch.int32(kafkaNoLeaderEpoch)
+	batch.int8(kafkaRecordBatchMagic)
+	batch.int32(0) // crc, filled in below
+	crcStart := len(batch.buf)
+	batch.int16(0) // attributes: no compression, create time
+	batch.int32(0) // last_offset_delta
+	batch.int64(timestamp)
+	batch.int64(timestamp)
+	batch.int64(kafkaNoProducerID)
+	batch.int16(kafkaNoProducerEpoch)
+	batch.int32(kafkaNoSequence)
+	batch.int32(1)
+	batch.varint(int64(len(record.buf)))
+	batch.buf = append(batch.buf, record.buf...)
+
+	binary.BigEndian.PutUint32(batch.buf[8:], uint32(len(batch.buf)-12))
+	binary.BigEndian.PutUint32(batch.buf[crcStart-4:], crc32.Checksum(batch.buf[crcStart:], crc32c))
+
+	return batch.buf
+}
+
+// murmur2 is the hash of the Java client's default partitioner
+func murmur2(data []byte) uint32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+
+	length := len(data)
+	h := uint32(seed) ^ uint32(length)
+
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
+
+// kafkaEncoder appends big-endian Kafka protocol primitives
+type kafkaEncoder struct {
+	buf []byte
+}
+
+func (e *kafkaEncoder) int8(v int8)   { e.buf = append(e.buf, byte(v)) }
+func (e *kafkaEncoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *kafkaEncoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *kafkaEncoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+
+func (e *kafkaEncoder) varint(v int64) { e.buf = binary.AppendVarint(e.buf, v) }
+
+func (e *kafkaEncoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *kafkaEncoder) nullableString(s *string) {
+	if s == nil {
+		e.int16(-1)
+		return
+	}
+	e.string(*s)
+}
+
+func (e *kafkaEncoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *kafkaEncoder) varbytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// kafkaDecoder reads big-endian Kafka protocol primitives. The first error
+// is kept and every read after it returns zero values.
+type kafkaDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *kafkaDecoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *kafkaDecoder) bool() bool {
+	b := d.take(1)
+	return b != nil && b[0] != 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) string() string {
+	return string(d.take(int(d.int16())))
+}
+
+func (d *kafkaDecoder) nullableString() {
+	if n := d.int16(); n > 0 {
+		d.take(int(n))
+	}
+}
+
+func (d *kafkaDecoder) int32Array() {
+	if n := d.int32(); n > 0 {
+		d.take(int(n) * 4)
+	}
+}
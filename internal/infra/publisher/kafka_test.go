@@ -0,0 +1,450 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// TestMurmur2 uses the vectors of the Java client's UtilsTest
+func TestMurmur2(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+
+	for _, tt := range tests {
+		if got := int32(murmur2([]byte(tt.key))); got != tt.want {
+			t.Errorf("murmur2(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+// fakeRecord is a record the fake broker received
+type fakeRecord struct {
+	partition int32
+	acks      int16
+	key       string
+	value     []byte
+	headers   map[string]string
+	timestamp int64
+}
+
+// fakeKafka is an in-process broker that leads every partition of one topic
+type fakeKafka struct {
+	t          *testing.T
+	listener   net.Listener
+	topic      string
+	partitions int
+	// leaderless is a partition whose leader is unknown, or -1
+	leaderless int32
+	topicError int16
+
+	mu               sync.Mutex
+	produceErrors    []int16
+	metadataRequests int
+	records          []fakeRecord
+}
+
+// newFakeKafka starts a broker after applying the setup functions
+func newFakeKafka(t *testing.T, topic string, partitions int, setup ...func(*fakeKafka)) *fakeKafka {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+
+	k := &fakeKafka{t: t, listener: listener, topic: topic, partitions: partitions, leaderless: -1}
+	for _, f := range setup {
+		f(k)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go k.serve(conn)
+		}
+	}()
+
+	return k
+}
+
+func (k *fakeKafka) addr() string {
+	return k.listener.Addr().String()
+}
+
+// serve answers Metadata and Produce requests until the client hangs up
+func (k *fakeKafka) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(reader, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(reader, req); err != nil {
+			return
+		}
+
+		d := kafkaDecoder{buf: req}
+		apiKey := d.int16()
+		apiVersion := d.int16()
+		correlationID := d.int32()
+		d.string() // client_id
+
+		var resp kafkaEncoder
+		resp.int32(0)
+		resp.int32(correlationID)
+
+		switch {
+		case apiKey == kafkaAPIMetadata && apiVersion == kafkaMetadataVersion:
+			k.metadata(&resp)
+		case apiKey == kafkaAPIProduce && apiVersion == kafkaProduceVersion:
+			k.produce(&d, &resp)
+		default:
+			k.t.Errorf("unexpected request: api key %d version %d", apiKey, apiVersion)
+			return
+		}
+		if d.err != nil {
+			k.t.Errorf("error decoding request: %v", d.err)
+			return
+		}
+
+		binary.BigEndian.PutUint32(resp.buf, uint32(len(resp.buf)-4))
+		if _, err := conn.Write(resp.buf); err != nil {
+			return
+		}
+	}
+}
+
+// metadata writes a v1 response naming this broker as the leader
+func (k *fakeKafka) metadata(resp *kafkaEncoder) {
+	k.mu.Lock()
+	k.metadataRequests++
+	k.mu.Unlock()
+
+	host, port, _ := net.SplitHostPort(k.addr())
+	portNumber, _ := strconv.Atoi(port)
+
+	resp.int32(1)
+	resp.int32(1) // node_id
+	resp.string(host)
+	resp.int32(int32(portNumber))
+	resp.nullableString(nil)
+	resp.int32(1) // controller_id
+
+	resp.int32(1)
+	resp.int16(k.topicError)
+	resp.string(k.topic)
+	resp.int8(0) // is_internal
+	resp.int32(int32(k.partitions))
+	for i := int32(0); i < int32(k.partitions); i++ {
+		leader := int32(1)
+		if i == k.leaderless {
+			leader = -1
+		}
+		resp.int16(0)
+		resp.int32(i)
+		resp.int32(leader)
+		resp.int32(1)
+		resp.int32(1) // replicas
+		resp.int32(1)
+		resp.int32(1) // isr
+	}
+}
+
+// produce records the request's single record and answers with the next
+// queued error code
+func (k *fakeKafka) produce(d *kafkaDecoder, resp *kafkaEncoder) {
+	d.nullableString() // transactional_id
+	acks := d.int16()
+	d.int32() // timeout_ms
+	if n := d.int32(); n != 1 {
+		k.t.Errorf("produce request has %d topics, want 1", n)
+	}
+	topic := d.string()
+	if n := d.int32(); n != 1 {
+		k.t.Errorf("produce request has %d partitions, want 1", n)
+	}
+	partition := d.int32()
+	batch := d.take(int(d.int32()))
+	if d.err != nil {
+		return
+	}
+
+	record := k.decodeBatch(batch)
+	record.partition = partition
+	record.acks = acks
+
+	k.mu.Lock()
+	code := int16(0)
+	if len(k.produceErrors) > 0 {
+		code, k.produceErrors = k.produceErrors[0], k.produceErrors[1:]
+	}
+	if code == 0 {
+		k.records = append(k.records, record)
+	}
+	k.mu.Unlock()
+
+	resp.int32(1)
+	resp.string(topic)
+	resp.int32(1)
+	resp.int32(partition)
+	resp.int16(code)
+	resp.int64(0)  // base_offset
+	resp.int64(-1) // log_append_time_ms
+	resp.int32(0)  // throttle_time_ms
+}
+
+// decodeBatch checks the framing and checksum of a v2 record batch holding
+// one record and returns that record
+func (k *fakeKafka) decodeBatch(batch []byte) fakeRecord {
+	t := k.t
+	d := kafkaDecoder{buf: batch}
+
+	d.int64() // base_offset
+	if length := d.int32(); int(length) != len(batch)-12 {
+		t.Errorf("batch_length = %d, want %d", length, len(batch)-12)
+	}
+	d.int32() // partition_leader_epoch
+	if magic := d.take(1); len(magic) == 1 && magic[0] != kafkaRecordBatchMagic {
+		t.Errorf("magic = %d, want %d", magic[0], kafkaRecordBatchMagic)
+	}
+	crc := uint32(d.int32())
+	if d.err == nil {
+		if want := crc32.Checksum(d.buf, crc32.MakeTable(crc32.Castagnoli)); crc != want {
+			t.Errorf("crc = %#x, want %#x", crc, want)
+		}
+	}
+	d.int16() // attributes
+	d.int32() // last_offset_delta
+	firstTimestamp := d.int64()
+	d.int64() // max_timestamp
+	d.int64() // producer_id
+	d.int16() // producer_epoch
+	d.int32() // base_sequence
+	if n := d.int32(); n != 1 {
+		t.Errorf("batch has %d records, want 1", n)
+	}
+
+	length := k.varint(&d)
+	if int(length) != len(d.buf) {
+		t.Errorf("record length = %d, want %d", length, len(d.buf))
+	}
+	d.take(1)    // attributes
+	k.varint(&d) // timestamp_delta
+	k.varint(&d) // offset_delta
+	record := fakeRecord{timestamp: firstTimestamp, headers: map[string]string{}}
+	record.key = string(k.varbytes(&d))
+	record.value = k.varbytes(&d)
+	for n := k.varint(&d); n > 0; n-- {
+		name := string(k.varbytes(&d))
+		record.headers[name] = string(k.varbytes(&d))
+	}
+
+	if d.err != nil {
+		t.Errorf("error decoding record batch: %v", d.err)
+	}
+	if len(d.buf) != 0 {
+		t.Errorf("%d bytes left after the record", len(d.buf))
+	}
+	return record
+}
+
+func (k *fakeKafka) varint(d *kafkaDecoder) int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (k *fakeKafka) varbytes(d *kafkaDecoder) []byte {
+	n := k.varint(d)
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+func (k *fakeKafka) metadataCount() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.metadataRequests
+}
+
+func (k *fakeKafka) received() []fakeRecord {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]fakeRecord(nil), k.records...)
+}
+
+func testEvent(id int64, eventType, aggregateID string) *model.Event {
+	return &model.Event{
+		ID:            id,
+		AggregateType: "transfer",
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       json.RawMessage(`{"schema_version":1,"transfer_id":"` + aggregateID + `"}`),
+		CreatedAt:     time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestKafkaPublisherPublish(t *testing.T) {
+	broker := newFakeKafka(t, "events", 3)
+
+	p, err := NewKafkaPublisher([]string{" ", broker.addr()}, "events", "test", time.Second)
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher: %v", err)
+	}
+	defer p.Close()
+
+	keys := []string{"TRF1", "TRF2", "TRF3", "TRF4", "TRF1"}
+	for i, key := range keys {
+		if err := p.Publish(context.Background(), testEvent(int64(i+1), "transfer_completed", key)); err != nil {
+			t.Fatalf("Publish %s: %v", key, err)
+		}
+	}
+
+	records := broker.received()
+	if len(records) != len(keys) {
+		t.Fatalf("broker received %d records, want %d", len(records), len(keys))
+	}
+	if n := broker.metadataCount(); n != 1 {
+		t.Errorf("metadata requested %d times, want 1", n)
+	}
+
+	for i, r := range records {
+		want := int32(murmur2([]byte(keys[i]))&0x7fffffff) % 3
+		if r.partition != want {
+			t.Errorf("record %d went to partition %d, want %d", i, r.partition, want)
+		}
+		if r.key != keys[i] {
+			t.Errorf("record %d key = %q, want %q", i, r.key, keys[i])
+		}
+		if r.acks != kafkaAcksAll {
+			t.Errorf("record %d acks = %d, want %d", i, r.acks, kafkaAcksAll)
+		}
+		if r.headers["event_type"] != "transfer_completed" || r.headers["event_id"] != strconv.Itoa(i+1) {
+			t.Errorf("record %d headers = %v", i, r.headers)
+		}
+		if r.timestamp != testEvent(0, "", "").CreatedAt.UnixMilli() {
+			t.Errorf("record %d timestamp = %d", i, r.timestamp)
+		}
+
+		var envelope Envelope
+		if err := json.Unmarshal(r.value, &envelope); err != nil {
+			t.Fatalf("record %d value is not an envelope: %v", i, err)
+		}
+		if envelope.ID != int64(i+1) || envelope.AggregateID != keys[i] || envelope.EventType != "transfer_completed" {
+			t.Errorf("record %d envelope = %+v", i, envelope)
+		}
+	}
+}
+
+func TestKafkaPublisherProduceError(t *testing.T) {
+	// NOT_LEADER_OR_FOLLOWER
+	broker := newFakeKafka(t, "events", 1, func(k *fakeKafka) { k.produceErrors = []int16{6} })
+
+	p, err := NewKafkaPublisher([]string{broker.addr()}, "events", "test", time.Second)
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher: %v", err)
+	}
+	defer p.Close()
+
+	err = p.Publish(context.Background(), testEvent(1, "transfer_completed", "TRF1"))
+	if err == nil || !strings.Contains(err.Error(), "error code 6") {
+		t.Fatalf("Publish error = %v, want error code 6", err)
+	}
+
+	// The leaders are looked up again after a failure
+	if err := p.Publish(context.Background(), testEvent(1, "transfer_completed", "TRF1")); err != nil {
+		t.Fatalf("Publish after failure: %v", err)
+	}
+	if n := broker.metadataCount(); n != 2 {
+		t.Errorf("metadata requested %d times, want 2", n)
+	}
+	if n := len(broker.received()); n != 1 {
+		t.Errorf("broker kept %d records, want 1", n)
+	}
+}
+
+func TestKafkaPublisherMetadataErrors(t *testing.T) {
+	t.Run("unknown topic", func(t *testing.T) {
+		// UNKNOWN_TOPIC_OR_PARTITION
+		broker := newFakeKafka(t, "events", 1, func(k *fakeKafka) { k.topicError = 3 })
+
+		p, _ := NewKafkaPublisher([]string{broker.addr()}, "events", "test", time.Second)
+		defer p.Close()
+
+		err := p.Publish(context.Background(), testEvent(1, "transfer_completed", "TRF1"))
+		if err == nil || !strings.Contains(err.Error(), "error code 3") {
+			t.Errorf("Publish error = %v, want error code 3", err)
+		}
+	})
+
+	t.Run("no leader", func(t *testing.T) {
+		broker := newFakeKafka(t, "events", 1, func(k *fakeKafka) { k.leaderless = 0 })
+
+		p, _ := NewKafkaPublisher([]string{broker.addr()}, "events", "test", time.Second)
+		defer p.Close()
+
+		err := p.Publish(context.Background(), testEvent(1, "transfer_completed", "TRF1"))
+		if err == nil || !strings.Contains(err.Error(), "has no leader") {
+			t.Errorf("Publish error = %v, want no leader", err)
+		}
+	})
+
+	t.Run("unreachable broker", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("error listening: %v", err)
+		}
+		addr := listener.Addr().String()
+		listener.Close()
+
+		p, _ := NewKafkaPublisher([]string{addr}, "events", "test", time.Second)
+		defer p.Close()
+
+		err = p.Publish(context.Background(), testEvent(1, "transfer_completed", "TRF1"))
+		if err == nil || !strings.Contains(err.Error(), "error getting kafka metadata") {
+			t.Errorf("Publish error = %v, want a metadata error", err)
+		}
+	})
+}
+
+func TestNewKafkaPublisher(t *testing.T) {
+	if _, err := NewKafkaPublisher([]string{" ", ""}, "events", "test", time.Second); err == nil {
+		t.Error("NewKafkaPublisher without brokers succeeded")
+	}
+	if _, err := NewKafkaPublisher([]string{"localhost:9092"}, "", "test", time.Second); err == nil {
+		t.Error("NewKafkaPublisher without a topic succeeded")
+	}
+}
@@ -0,0 +1,184 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// NATSPublisher publishes each event to "<subject prefix>.<event type>" over
+// the NATS client protocol. Every publish is followed by a PING, and only the
+// server's PONG counts as success, so an event is never reported as sent
+// while it may still be sitting in a buffer.
+type NATSPublisher struct {
+	addr          string
+	user          string
+	password      string
+	subjectPrefix string
+	timeout       time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATSPublisher takes a nats://[user:password@]host:port URL
+func NewNATSPublisher(rawURL, subjectPrefix string, timeout time.Duration) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q", rawURL)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	p := &NATSPublisher{
+		addr:          addr,
+		subjectPrefix: strings.TrimSuffix(subjectPrefix, "."),
+		timeout:       timeout,
+	}
+	if u.User != nil {
+		p.user = u.User.Username()
+		p.password, _ = u.User.Password()
+	}
+
+	return p, nil
+}
+
+// Publish
+func (p *NATSPublisher) Publish(ctx context.Context, event *model.Event) error {
+	payload, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := p.publish(ctx, p.subjectPrefix+"."+event.EventType, payload); err != nil {
+		// The connection is in an unknown state; start over next time
+		p.conn.Close()
+		p.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// connect dials the server and sends CONNECT
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS: %w", err)
+	}
+	conn.SetDeadline(deadline(ctx, p.timeout))
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error reading NATS INFO: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS greeting %q", strings.TrimSpace(line))
+	}
+
+	connectOptions := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "money-transfer",
+		"lang":     "go",
+		"version":  "1.0.0",
+		"protocol": 0,
+	}
+	if p.user != "" {
+		connectOptions["user"] = p.user
+		connectOptions["pass"] = p.password
+	}
+
+	options, err := json.Marshal(connectOptions)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", options); err != nil {
+		conn.Close()
+		return fmt.Errorf("error sending NATS CONNECT: %w", err)
+	}
+
+	p.conn = conn
+	p.reader = reader
+	return nil
+}
+
+// publish sends PUB and waits for the PONG answering the PING after it.
+// An authorization failure on CONNECT is reported here as -ERR.
+func (p *NATSPublisher) publish(ctx context.Context, subject string, payload []byte) error {
+	p.conn.SetDeadline(deadline(ctx, p.timeout))
+
+	msg := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload)
+	if _, err := p.conn.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("error publishing to NATS: %w", err)
+	}
+
+	for {
+		line, err := p.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("error reading from NATS: %w", err)
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("error answering NATS PING: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// +OK and INFO updates need no answer
+	}
+}
+
+// Close
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// deadline is the context's deadline if it is sooner than the timeout
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(d) {
+		return ctxDeadline
+	}
+	return d
+}
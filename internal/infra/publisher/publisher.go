@@ -0,0 +1,184 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+// Envelope is how every publisher writes an event
+type Envelope struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// encodeEvent
+func encodeEvent(event *model.Event) ([]byte, error) {
+	data, err := json.Marshal(Envelope{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		CreatedAt:     event.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event %d: %w", event.ID, err)
+	}
+	return data, nil
+}
+
+// Routes maps event type patterns to publisher names. A pattern is an event
+// type, a prefix ending in "*" such as "transfer_*", or "*" for any event.
+type Routes map[string][]string
+
+// ParseRoutes reads routes written as "pattern=name+name,pattern=name", for
+// example "transfer_completed=webhook+kafka,deposit_*=nats,*=file"
+func ParseRoutes(spec string) (Routes, error) {
+	routes := Routes{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pattern, names, ok := strings.Cut(entry, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid route %q, want pattern=publisher", entry)
+		}
+		if strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			return nil, fmt.Errorf("invalid route pattern %q, only a trailing * is allowed", pattern)
+		}
+		if _, ok := routes[pattern]; ok {
+			return nil, fmt.Errorf("route %q is defined twice", pattern)
+		}
+
+		var publishers []string
+		for _, name := range strings.Split(names, "+") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return nil, fmt.Errorf("route %q has an empty publisher name", pattern)
+			}
+			publishers = append(publishers, name)
+		}
+		routes[pattern] = publishers
+	}
+
+	return routes, nil
+}
+
+// Names returns every publisher the routes refer to
+func (r Routes) Names() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, publishers := range r {
+		for _, name := range publishers {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// match returns the publishers of the most specific pattern matching the
+// event type: the type itself, then the longest prefix
+func (r Routes) match(eventType string) []string {
+	if publishers, ok := r[eventType]; ok {
+		return publishers
+	}
+
+	best := ""
+	var publishers []string
+	for pattern, names := range r {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if !ok || !strings.HasPrefix(eventType, prefix) {
+			continue
+		}
+		if publishers == nil || len(prefix) > len(best) {
+			best, publishers = prefix, names
+		}
+	}
+	return publishers
+}
+
+// Router is the Publisher the outbox processor uses. It sends each event to
+// the publishers routed for its type; events without a route are dropped.
+type Router struct {
+	routes     Routes
+	publishers map[string]repository.Publisher
+}
+
+// NewRouter
+func NewRouter(routes Routes, publishers map[string]repository.Publisher) (*Router, error) {
+	for _, name := range routes.Names() {
+		if _, ok := publishers[name]; !ok {
+			return nil, fmt.Errorf("unknown publisher %q", name)
+		}
+	}
+
+	return &Router{
+		routes:     routes,
+		publishers: publishers,
+	}, nil
+}
+
+// Publish fails if any of the event's publishers fails. The event is then
+// published again to all of them.
+func (r *Router) Publish(ctx context.Context, event *model.Event) error {
+	for _, name := range r.routes.match(event.EventType) {
+		if err := r.publishers[name].Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Close
+func (r *Router) Close() error {
+	var errs []error
+	for name, p := range r.publishers {
+		if err := p.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Chain publishes each event to every publisher in turn and stops at the
+// first failure
+type Chain []repository.Publisher
+
+// Publish
+func (c Chain) Publish(ctx context.Context, event *model.Event) error {
+	for _, p := range c {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close
+func (c Chain) Close() error {
+	var errs []error
+	for _, p := range c {
+		if err := p.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
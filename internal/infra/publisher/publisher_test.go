@@ -0,0 +1,136 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+)
+
+func TestParseRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    Routes
+		wantErr bool
+	}{
+		{"empty", "", Routes{}, false},
+		{"one route", "transfer_completed=webhook", Routes{"transfer_completed": {"webhook"}}, false},
+		{
+			"several routes and publishers",
+			" transfer_completed = webhook + kafka , deposit_*=nats,*=file,",
+			Routes{"transfer_completed": {"webhook", "kafka"}, "deposit_*": {"nats"}, "*": {"file"}},
+			false,
+		},
+		{"missing publisher", "transfer_completed", nil, true},
+		{"missing pattern", "=file", nil, true},
+		{"empty publisher name", "transfer_completed=webhook++kafka", nil, true},
+		{"inner wildcard", "transfer_*_completed=file", nil, true},
+		{"leading wildcard", "*_completed=file", nil, true},
+		{"duplicate pattern", "*=file,*=nats", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoutes(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRoutes(%q) error = %v, want error %t", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRoutes(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutesMatch(t *testing.T) {
+	routes, err := ParseRoutes("transfer_completed=webhook+kafka,transfer_*=nats,transfer_rev*=kafka,*=file")
+	if err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+
+	tests := []struct {
+		eventType string
+		want      []string
+	}{
+		{"transfer_completed", []string{"webhook", "kafka"}},
+		{"transfer_failed", []string{"nats"}},
+		{"transfer_reversed", []string{"kafka"}},
+		{"deposit_settled", []string{"file"}},
+	}
+
+	for _, tt := range tests {
+		if got := routes.match(tt.eventType); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("match(%q) = %v, want %v", tt.eventType, got, tt.want)
+		}
+	}
+
+	if got := (Routes{"transfer_*": {"nats"}}).match("deposit_settled"); got != nil {
+		t.Errorf("match without a route = %v, want none", got)
+	}
+}
+
+func TestRoutesNames(t *testing.T) {
+	routes := Routes{"a": {"webhook", "kafka"}, "b*": {"kafka", "nats"}}
+
+	got := routes.Names()
+	sort.Strings(got)
+	if want := []string{"kafka", "nats", "webhook"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+// recordingPublisher remembers the events it was given and fails with err
+type recordingPublisher struct {
+	events []string
+	err    error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *model.Event) error {
+	p.events = append(p.events, event.EventType)
+	return p.err
+}
+
+func (p *recordingPublisher) Close() error {
+	return nil
+}
+
+func TestRouter(t *testing.T) {
+	routes, _ := ParseRoutes("transfer_*=kafka+nats,deposit_*=file")
+	kafka, nats, file := &recordingPublisher{}, &recordingPublisher{}, &recordingPublisher{}
+
+	if _, err := NewRouter(routes, map[string]repository.Publisher{"kafka": kafka}); err == nil {
+		t.Fatal("NewRouter with a missing publisher succeeded")
+	}
+
+	router, err := NewRouter(routes, map[string]repository.Publisher{"kafka": kafka, "nats": nats, "file": file})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	for _, eventType := range []string{"transfer_completed", "deposit_settled", "webhook_ping"} {
+		if err := router.Publish(context.Background(), &model.Event{EventType: eventType}); err != nil {
+			t.Fatalf("Publish %s: %v", eventType, err)
+		}
+	}
+
+	if !reflect.DeepEqual(kafka.events, []string{"transfer_completed"}) ||
+		!reflect.DeepEqual(nats.events, []string{"transfer_completed"}) ||
+		!reflect.DeepEqual(file.events, []string{"deposit_settled"}) {
+		t.Errorf("published kafka=%v nats=%v file=%v", kafka.events, nats.events, file.events)
+	}
+
+	// A failure stops the event at the failing publisher
+	kafka.err = errors.New("broker down")
+	err = router.Publish(context.Background(), &model.Event{EventType: "transfer_failed"})
+	if !errors.Is(err, kafka.err) || err.Error() != "kafka: broker down" {
+		t.Errorf("Publish error = %v, want kafka: broker down", err)
+	}
+	if len(nats.events) != 1 {
+		t.Errorf("nats got %d events after kafka failed, want 1", len(nats.events))
+	}
+}
@@ -29,3 +29,107 @@ func (f *Factory) CreateTransferRepository() (repository.TransferRepository, *po
 	pgRepo := postgresql.NewTransferRepository(f.txManager.DB())
 	return pgRepo, pgRepo
 }
+
+// CreateIdempotencyKeyRepository
+func (f *Factory) CreateIdempotencyKeyRepository() (repository.IdempotencyKeyRepository, *postgresql.IdempotencyKeyRepository) {
+	pgRepo := postgresql.NewIdempotencyKeyRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateFXRateProvider
+func (f *Factory) CreateFXRateProvider() repository.FXRateProvider {
+	return postgresql.NewFXRateRepository(f.txManager.DB())
+}
+
+// CreateFXQuoteRepository
+func (f *Factory) CreateFXQuoteRepository() (repository.FXQuoteRepository, *postgresql.FXQuoteRepository) {
+	pgRepo := postgresql.NewFXQuoteRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateLedgerRepository
+func (f *Factory) CreateLedgerRepository() (repository.LedgerRepository, *postgresql.LedgerRepository) {
+	pgRepo := postgresql.NewLedgerRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateStatementRepository
+func (f *Factory) CreateStatementRepository() repository.StatementRepository {
+	return postgresql.NewStatementRepository(f.txManager.DB())
+}
+
+// CreateFeeScheduleRepository
+func (f *Factory) CreateFeeScheduleRepository() repository.FeeScheduleRepository {
+	return postgresql.NewFeeScheduleRepository(f.txManager.DB())
+}
+
+// CreateHoldRepository
+func (f *Factory) CreateHoldRepository() (repository.HoldRepository, *postgresql.HoldRepository) {
+	pgRepo := postgresql.NewHoldRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateScheduledTransferRepository
+func (f *Factory) CreateScheduledTransferRepository() (repository.ScheduledTransferRepository, *postgresql.ScheduledTransferRepository) {
+	pgRepo := postgresql.NewScheduledTransferRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateStandingOrderRepository
+func (f *Factory) CreateStandingOrderRepository() (repository.StandingOrderRepository, *postgresql.StandingOrderRepository) {
+	pgRepo := postgresql.NewStandingOrderRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateTransferBatchRepository
+func (f *Factory) CreateTransferBatchRepository() (repository.TransferBatchRepository, *postgresql.TransferBatchRepository) {
+	pgRepo := postgresql.NewTransferBatchRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateFundingRepository
+func (f *Factory) CreateFundingRepository() (repository.FundingRepository, *postgresql.FundingRepository) {
+	pgRepo := postgresql.NewFundingRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateCardRepository
+func (f *Factory) CreateCardRepository() (repository.CardRepository, *postgresql.CardRepository) {
+	pgRepo := postgresql.NewCardRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateRiskReviewRepository
+func (f *Factory) CreateRiskReviewRepository() (repository.RiskReviewRepository, *postgresql.RiskReviewRepository) {
+	pgRepo := postgresql.NewRiskReviewRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateTransferLimitRepository
+func (f *Factory) CreateTransferLimitRepository() (repository.TransferLimitRepository, *postgresql.TransferLimitRepository) {
+	pgRepo := postgresql.NewTransferLimitRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateWebhookRepository
+func (f *Factory) CreateWebhookRepository() (repository.WebhookRepository, *postgresql.WebhookRepository) {
+	pgRepo := postgresql.NewWebhookRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateEventStore
+func (f *Factory) CreateEventStore() (repository.EventStore, *postgresql.EventStoreRepository) {
+	pgRepo := postgresql.NewEventStoreRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreateProjectionRepository
+func (f *Factory) CreateProjectionRepository() (repository.ProjectionRepository, *postgresql.ProjectionRepository) {
+	pgRepo := postgresql.NewProjectionRepository(f.txManager.DB())
+	return pgRepo, pgRepo
+}
+
+// CreatePaymentInitiationRepository
+func (f *Factory) CreatePaymentInitiationRepository() repository.PaymentInitiationRepository {
+	return postgresql.NewPaymentInitiationRepository(f.txManager.DB())
+}
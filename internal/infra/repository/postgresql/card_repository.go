@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBCard
+type DBCard struct {
+	ID          int64     `db:"id"`
+	CardCode    string    `db:"card_code"`
+	UserID      int64     `db:"user_id"`
+	Token       string    `db:"token"`
+	MaskedPAN   string    `db:"masked_pan"`
+	Brand       string    `db:"brand"`
+	HolderName  string    `db:"holder_name"`
+	ExpiryMonth int       `db:"expiry_month"`
+	ExpiryYear  int       `db:"expiry_year"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// cardColumns
+const cardColumns = `id, card_code, user_id, token, masked_pan, brand, holder_name, expiry_month, expiry_year, created_at`
+
+// CardRepository
+type CardRepository struct {
+	db *sqlx.DB
+}
+
+// NewCardRepository
+func NewCardRepository(db *sqlx.DB) *CardRepository {
+	return &CardRepository{
+		db: db,
+	}
+}
+
+// Create
+func (r *CardRepository) Create(card *model.Card) error {
+	ctx := context.Background()
+
+	var nextID int64
+	if err := r.db.GetContext(ctx, &nextID, `SELECT nextval('money_transfer.cards_id_seq')`); err != nil {
+		return fmt.Errorf("error generating card ID: %w", err)
+	}
+
+	var dbCard DBCard
+	err := r.db.GetContext(ctx, &dbCard, `
+		INSERT INTO money_transfer.cards (
+			id, card_code, user_id, token, masked_pan, brand, holder_name, expiry_month, expiry_year, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+		ON CONFLICT (user_id, token) DO UPDATE SET token = EXCLUDED.token
+		RETURNING `+cardColumns,
+		nextID,
+		fmt.Sprintf("CRD%d", nextID),
+		card.UserID,
+		card.Token,
+		card.MaskedPAN,
+		card.Brand,
+		card.HolderName,
+		card.ExpiryMonth,
+		card.ExpiryYear,
+		card.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting card: %w", err)
+	}
+
+	*card = *toCard(dbCard)
+	return nil
+}
+
+// GetByID
+func (r *CardRepository) GetByID(id string) (*model.Card, error) {
+	return r.getCard(context.Background(), r.db, id)
+}
+
+// GetTx
+func (r *CardRepository) GetTx(ctx context.Context, tx *sqlx.Tx, id string) (*model.Card, error) {
+	return r.getCard(ctx, tx, id)
+}
+
+// getCard
+func (r *CardRepository) getCard(ctx context.Context, q sqlx.QueryerContext, id string) (*model.Card, error) {
+	var dbCard DBCard
+
+	err := sqlx.GetContext(ctx, q, &dbCard, `
+		SELECT `+cardColumns+`
+		FROM money_transfer.cards
+		WHERE card_code = $1
+	`, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrCardNotFound
+		}
+		return nil, fmt.Errorf("error getting card: %w", err)
+	}
+
+	return toCard(dbCard), nil
+}
+
+// ListByUser
+func (r *CardRepository) ListByUser(userID string) ([]*model.Card, error) {
+	var dbCards []DBCard
+
+	err := r.db.Select(&dbCards, `
+		SELECT `+cardColumns+`
+		FROM money_transfer.cards
+		WHERE user_id = $1
+		ORDER BY id
+	`, userID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing cards: %w", err)
+	}
+
+	cards := make([]*model.Card, len(dbCards))
+	for i, c := range dbCards {
+		cards[i] = toCard(c)
+	}
+
+	return cards, nil
+}
+
+// NextStan issues the next trace number outside the caller's transaction, so
+// that concurrent card payments do not wait for each other. Numbers taken by
+// transactions that roll back are skipped.
+func (r *CardRepository) NextStan(ctx context.Context) (model.Stan, error) {
+	var n int64
+
+	err := r.db.GetContext(ctx, &n, `SELECT nextval('money_transfer.stan_seq')`)
+	if err != nil {
+		return "", fmt.Errorf("error issuing STAN: %w", err)
+	}
+
+	return model.StanFromSequence(n), nil
+}
+
+// toCard
+func toCard(c DBCard) *model.Card {
+	return &model.Card{
+		ID:          c.CardCode,
+		Ref:         uint32(c.ID),
+		UserID:      fmt.Sprintf("%d", c.UserID),
+		Token:       c.Token,
+		MaskedPAN:   c.MaskedPAN,
+		Brand:       model.CardBrand(c.Brand),
+		HolderName:  c.HolderName,
+		ExpiryMonth: c.ExpiryMonth,
+		ExpiryYear:  c.ExpiryYear,
+		CreatedAt:   c.CreatedAt,
+	}
+}
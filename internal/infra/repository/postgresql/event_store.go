@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// AnyVersion appends an event at the head of its stream, whatever its version
+const AnyVersion = -1
+
+// DBStoredEvent
+type DBStoredEvent struct {
+	ID            int64     `db:"id"`
+	AggregateType string    `db:"aggregate_type"`
+	AggregateID   string    `db:"aggregate_id"`
+	Version       int       `db:"version"`
+	EventType     string    `db:"event_type"`
+	Payload       []byte    `db:"payload"`
+	RecordedAt    time.Time `db:"recorded_at"`
+}
+
+// storedEventColumns
+const storedEventColumns = `id, aggregate_type, aggregate_id, version, event_type, payload, recorded_at`
+
+// EventStoreRepository
+type EventStoreRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventStoreRepository
+func NewEventStoreRepository(db *sqlx.DB) *EventStoreRepository {
+	return &EventStoreRepository{
+		db: db,
+	}
+}
+
+// AppendTx appends the event to its aggregate's stream and returns the
+// event's version. Unless expectedVersion is AnyVersion, the stream must be
+// at expectedVersion; otherwise ErrVersionConflict is returned.
+func (r *EventStoreRepository) AppendTx(ctx context.Context, tx *sqlx.Tx, expectedVersion int, e event.Event) (int, error) {
+	payload, err := event.Encode(e)
+	if err != nil {
+		return 0, err
+	}
+	return appendEventTx(ctx, tx, expectedVersion, e, payload)
+}
+
+// Load
+func (r *EventStoreRepository) Load(aggregateType, aggregateID string) ([]*model.StoredEvent, error) {
+	var dbEvents []DBStoredEvent
+
+	err := r.db.Select(&dbEvents, `
+		SELECT `+storedEventColumns+`
+		FROM money_transfer.event_store
+		WHERE aggregate_type = $1 AND aggregate_id = $2
+		ORDER BY version
+	`, aggregateType, aggregateID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error loading event stream: %w", err)
+	}
+
+	return toStoredEvents(dbEvents), nil
+}
+
+// ReadAll
+func (r *EventStoreRepository) ReadAll(after int64, limit int) ([]*model.StoredEvent, error) {
+	return readAllEvents(context.Background(), r.db, after, limit)
+}
+
+// ReadAllTx reads a page inside the transaction, so that successive pages
+// come from the transaction's snapshot
+func (r *EventStoreRepository) ReadAllTx(ctx context.Context, tx *sqlx.Tx, after int64, limit int) ([]*model.StoredEvent, error) {
+	return readAllEvents(ctx, tx, after, limit)
+}
+
+// readAllEvents
+func readAllEvents(ctx context.Context, q sqlx.QueryerContext, after int64, limit int) ([]*model.StoredEvent, error) {
+	var dbEvents []DBStoredEvent
+
+	err := sqlx.SelectContext(ctx, q, &dbEvents, `
+		SELECT `+storedEventColumns+`
+		FROM money_transfer.event_store
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`, after, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading event store: %w", err)
+	}
+
+	return toStoredEvents(dbEvents), nil
+}
+
+// appendEventTx inserts the encoded event after the head of its stream. Two
+// transactions appending to the same stream read the same head; the unique
+// version makes the second one fail with ErrVersionConflict.
+func appendEventTx(ctx context.Context, tx *sqlx.Tx, expectedVersion int, e event.Event, payload []byte) (int, error) {
+	schemaVersion, err := event.Default().Version(e.EventType())
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := streamVersion(ctx, tx, e.AggregateType(), e.AggregateID())
+	if err != nil {
+		return 0, err
+	}
+
+	if expectedVersion != AnyVersion && current != expectedVersion {
+		return 0, model.ErrVersionConflict
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.event_store (
+			aggregate_type, aggregate_id, version, event_type, schema_version, payload
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`, e.AggregateType(), e.AggregateID(), current+1, e.EventType(), schemaVersion, payload)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return 0, model.ErrVersionConflict
+		}
+		return 0, fmt.Errorf("error appending event: %w", err)
+	}
+
+	return current + 1, nil
+}
+
+// streamVersion returns the version of the aggregate's latest event, or 0
+// for an aggregate without events
+func streamVersion(ctx context.Context, q sqlx.QueryerContext, aggregateType, aggregateID string) (int, error) {
+	var version int
+	err := sqlx.GetContext(ctx, q, &version, `
+		SELECT COALESCE(MAX(version), 0)
+		FROM money_transfer.event_store
+		WHERE aggregate_type = $1 AND aggregate_id = $2
+	`, aggregateType, aggregateID)
+
+	if err != nil {
+		return 0, fmt.Errorf("error reading event stream version: %w", err)
+	}
+	return version, nil
+}
+
+// toStoredEvents
+func toStoredEvents(dbEvents []DBStoredEvent) []*model.StoredEvent {
+	events := make([]*model.StoredEvent, len(dbEvents))
+	for i, e := range dbEvents {
+		events[i] = &model.StoredEvent{
+			Position:      e.ID,
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			Version:       e.Version,
+			EventType:     e.EventType,
+			Payload:       e.Payload,
+			RecordedAt:    e.RecordedAt,
+		}
+	}
+	return events
+}
@@ -0,0 +1,137 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBFeeSchedule
+type DBFeeSchedule struct {
+	ID            int64          `db:"id"`
+	ScheduleCode  string         `db:"schedule_code"`
+	Name          string         `db:"name"`
+	Currency      string         `db:"currency"`
+	FeeType       string         `db:"fee_type"`
+	FlatAmount    int            `db:"flat_amount"`
+	PercentageBps int            `db:"percentage_bps"`
+	MinFee        int            `db:"min_fee"`
+	MaxFee        int            `db:"max_fee"`
+	AccountTier   sql.NullString `db:"account_tier"`
+	PaymentMethod sql.NullString `db:"payment_method"`
+}
+
+// DBFeeTier
+type DBFeeTier struct {
+	ScheduleID    int64         `db:"schedule_id"`
+	UpToAmount    sql.NullInt64 `db:"up_to_amount"`
+	FlatAmount    int           `db:"flat_amount"`
+	PercentageBps int           `db:"percentage_bps"`
+}
+
+// FeeScheduleRepository
+type FeeScheduleRepository struct {
+	db *sqlx.DB
+}
+
+// NewFeeScheduleRepository
+func NewFeeScheduleRepository(db *sqlx.DB) *FeeScheduleRepository {
+	return &FeeScheduleRepository{
+		db: db,
+	}
+}
+
+// List
+func (r *FeeScheduleRepository) List() ([]*model.FeeSchedule, error) {
+	var dbSchedules []DBFeeSchedule
+
+	err := r.db.Select(&dbSchedules, `
+		SELECT id, schedule_code, name, currency, fee_type, flat_amount, percentage_bps,
+		       min_fee, max_fee, account_tier, payment_method
+		FROM money_transfer.fee_schedules
+		ORDER BY currency, id
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing fee schedules: %w", err)
+	}
+
+	return r.withTiers(dbSchedules)
+}
+
+// ListByCurrency
+func (r *FeeScheduleRepository) ListByCurrency(currency string) ([]*model.FeeSchedule, error) {
+	var dbSchedules []DBFeeSchedule
+
+	err := r.db.Select(&dbSchedules, `
+		SELECT id, schedule_code, name, currency, fee_type, flat_amount, percentage_bps,
+		       min_fee, max_fee, account_tier, payment_method
+		FROM money_transfer.fee_schedules
+		WHERE currency = $1
+		ORDER BY id
+	`, currency)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing fee schedules by currency: %w", err)
+	}
+
+	return r.withTiers(dbSchedules)
+}
+
+// withTiers loads the tiers of all schedules in one query
+func (r *FeeScheduleRepository) withTiers(dbSchedules []DBFeeSchedule) ([]*model.FeeSchedule, error) {
+	schedules := make([]*model.FeeSchedule, len(dbSchedules))
+	if len(dbSchedules) == 0 {
+		return schedules, nil
+	}
+
+	ids := make([]int64, len(dbSchedules))
+	for i, s := range dbSchedules {
+		ids[i] = s.ID
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT schedule_id, up_to_amount, flat_amount, percentage_bps
+		FROM money_transfer.fee_schedule_tiers
+		WHERE schedule_id IN (?)
+		ORDER BY schedule_id, up_to_amount NULLS LAST
+	`, ids)
+
+	if err != nil {
+		return nil, fmt.Errorf("error preparing fee tier query: %w", err)
+	}
+
+	var dbTiers []DBFeeTier
+	if err := r.db.Select(&dbTiers, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error getting fee tiers: %w", err)
+	}
+
+	tiers := make(map[int64][]model.FeeTier)
+	for _, t := range dbTiers {
+		tiers[t.ScheduleID] = append(tiers[t.ScheduleID], model.FeeTier{
+			UpTo:          int(t.UpToAmount.Int64),
+			FlatAmount:    t.FlatAmount,
+			PercentageBps: t.PercentageBps,
+		})
+	}
+
+	for i, s := range dbSchedules {
+		schedules[i] = &model.FeeSchedule{
+			ID:            s.ScheduleCode,
+			Name:          s.Name,
+			Currency:      s.Currency,
+			Type:          model.FeeType(s.FeeType),
+			FlatAmount:    s.FlatAmount,
+			PercentageBps: s.PercentageBps,
+			Tiers:         tiers[s.ID],
+			MinFee:        s.MinFee,
+			MaxFee:        s.MaxFee,
+			AccountTier:   model.AccountTier(s.AccountTier.String),
+			PaymentMethod: model.PaymentMethodType(s.PaymentMethod.String),
+		}
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,248 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBFunding
+type DBFunding struct {
+	ID              int64          `db:"id"`
+	FundingCode     string         `db:"funding_code"`
+	FundingType     string         `db:"funding_type"`
+	UserID          int64          `db:"user_id"`
+	Amount          int            `db:"amount"`
+	Currency        string         `db:"currency"`
+	PaymentMethod   string         `db:"payment_method"`
+	ExternalAccount string         `db:"external_account"`
+	ProviderRef     sql.NullString `db:"provider_ref"`
+	State           string         `db:"state"`
+	FailureReason   sql.NullString `db:"failure_reason"`
+	TxID            int64          `db:"tx_id"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+	CompletedAt     sql.NullTime   `db:"completed_at"`
+}
+
+// fundingColumns
+const fundingColumns = `id, funding_code, funding_type, user_id, amount, currency, payment_method, external_account,
+		       provider_ref, state, failure_reason, tx_id, created_at, updated_at, completed_at`
+
+// FundingRepository
+type FundingRepository struct {
+	db *sqlx.DB
+}
+
+// NewFundingRepository
+func NewFundingRepository(db *sqlx.DB) *FundingRepository {
+	return &FundingRepository{
+		db: db,
+	}
+}
+
+// CreateTx inserts the funding operation and its transaction and sets its ID
+func (r *FundingRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, funding *model.Funding) error {
+	txID, err := insertTransactionTx(ctx, tx, funding.Transaction)
+	if err != nil {
+		return fmt.Errorf("error inserting funding transaction: %w", err)
+	}
+
+	var nextID int64
+	if err := tx.GetContext(ctx, &nextID, `SELECT nextval('money_transfer.fundings_id_seq')`); err != nil {
+		return fmt.Errorf("error generating funding ID: %w", err)
+	}
+
+	funding.ID = fmt.Sprintf("FND%d", nextID)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.fundings (
+			id, funding_code, funding_type, user_id, amount, currency, payment_method, external_account,
+			provider_ref, state, failure_reason, tx_id, created_at, updated_at, completed_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+		)
+	`,
+		nextID,
+		funding.ID,
+		funding.Type,
+		funding.UserID,
+		funding.Amount,
+		funding.Currency,
+		funding.Method,
+		funding.ExternalAccount,
+		nullString(funding.ProviderRef),
+		funding.State,
+		nullString(funding.FailureReason),
+		txID,
+		funding.CreatedAt,
+		funding.UpdatedAt,
+		nullTime(funding.CompletedAt),
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting funding: %w", err)
+	}
+
+	_, err = insertEventTx(ctx, tx, AnyVersion, event.NewFundingEvent(funding))
+	return err
+}
+
+// UpdateTx saves the state of the funding operation and its transaction.
+// Settling the operation also records an outbox event.
+func (r *FundingRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, funding *model.Funding) error {
+	var txID int64
+	err := tx.QueryRowxContext(ctx, `
+		UPDATE money_transfer.fundings
+		SET provider_ref = $1, state = $2, failure_reason = $3, updated_at = $4, completed_at = $5
+		WHERE funding_code = $6
+		RETURNING tx_id
+	`,
+		nullString(funding.ProviderRef),
+		funding.State,
+		nullString(funding.FailureReason),
+		funding.UpdatedAt,
+		nullTime(funding.CompletedAt),
+		funding.ID,
+	).Scan(&txID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ErrFundingNotFound
+		}
+		return fmt.Errorf("error updating funding: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE money_transfer.transactions
+		SET state = $1, updated_at = $2
+		WHERE id = $3
+	`, funding.Transaction.State, funding.Transaction.UpdatedAt, txID)
+
+	if err != nil {
+		return fmt.Errorf("error updating funding transaction: %w", err)
+	}
+
+	// A pending operation only learnt its provider reference
+	if funding.State == model.TransactionStatePending {
+		return nil
+	}
+
+	_, err = insertEventTx(ctx, tx, AnyVersion, event.NewFundingEvent(funding))
+	return err
+}
+
+// GetByID
+func (r *FundingRepository) GetByID(id string) (*model.Funding, error) {
+	return r.getFunding(context.Background(), r.db, `
+		SELECT `+fundingColumns+`
+		FROM money_transfer.fundings
+		WHERE funding_code = $1
+	`, id)
+}
+
+// GetForUpdate locks the funding operation so that only one caller settles it
+func (r *FundingRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*model.Funding, error) {
+	return r.getFunding(ctx, tx, `
+		SELECT `+fundingColumns+`
+		FROM money_transfer.fundings
+		WHERE funding_code = $1
+		FOR UPDATE
+	`, id)
+}
+
+// getFunding
+func (r *FundingRepository) getFunding(ctx context.Context, q sqlx.QueryerContext, query string, id string) (*model.Funding, error) {
+	var dbFunding DBFunding
+
+	err := sqlx.GetContext(ctx, q, &dbFunding, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrFundingNotFound
+		}
+		return nil, fmt.Errorf("error getting funding: %w", err)
+	}
+
+	var dbTx DBTransaction
+	err = sqlx.GetContext(ctx, q, &dbTx, `
+		SELECT `+transactionColumns+`
+		FROM money_transfer.transactions
+		WHERE id = $1
+	`, dbFunding.TxID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting funding transaction: %w", err)
+	}
+
+	funding := toFunding(dbFunding)
+	funding.Transaction = toTransaction(dbTx)
+	return funding, nil
+}
+
+// ListPending
+func (r *FundingRepository) ListPending(updatedBefore time.Time, limit int) ([]string, error) {
+	var ids []string
+
+	err := r.db.Select(&ids, `
+		SELECT funding_code
+		FROM money_transfer.fundings
+		WHERE state = 'PENDING' AND updated_at < $1
+		ORDER BY updated_at
+		LIMIT $2
+	`, updatedBefore, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing pending fundings: %w", err)
+	}
+
+	return ids, nil
+}
+
+// HasPendingTx reports whether the user has a deposit or withdrawal waiting for the provider
+func (r *FundingRepository) HasPendingTx(ctx context.Context, tx *sqlx.Tx, userID string) (bool, error) {
+	var pending bool
+
+	err := tx.GetContext(ctx, &pending, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM money_transfer.fundings
+			WHERE user_id = $1 AND state = 'PENDING'
+		)
+	`, userID)
+
+	if err != nil {
+		return false, fmt.Errorf("error checking pending fundings: %w", err)
+	}
+
+	return pending, nil
+}
+
+// toFunding
+func toFunding(f DBFunding) *model.Funding {
+	funding := &model.Funding{
+		ID:              f.FundingCode,
+		Type:            model.FundingType(f.FundingType),
+		UserID:          fmt.Sprintf("%d", f.UserID),
+		Amount:          f.Amount,
+		Currency:        f.Currency,
+		Method:          model.PaymentMethodType(f.PaymentMethod),
+		ExternalAccount: f.ExternalAccount,
+		ProviderRef:     f.ProviderRef.String,
+		State:           model.TransactionState(f.State),
+		FailureReason:   f.FailureReason.String,
+		CreatedAt:       f.CreatedAt,
+		UpdatedAt:       f.UpdatedAt,
+	}
+
+	if f.CompletedAt.Valid {
+		funding.CompletedAt = f.CompletedAt.Time
+	}
+
+	return funding
+}
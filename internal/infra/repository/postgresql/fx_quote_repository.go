@@ -0,0 +1,160 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBFXQuote
+type DBFXQuote struct {
+	ID           int64        `db:"id"`
+	QuoteCode    string       `db:"quote_code"`
+	FromCurrency string       `db:"from_currency"`
+	ToCurrency   string       `db:"to_currency"`
+	MidRate      string       `db:"mid_rate"`
+	Rate         string       `db:"rate"`
+	SpreadBps    int          `db:"spread_bps"`
+	CreatedAt    time.Time    `db:"created_at"`
+	ExpiresAt    time.Time    `db:"expires_at"`
+	UsedAt       sql.NullTime `db:"used_at"`
+}
+
+// FXQuoteRepository
+type FXQuoteRepository struct {
+	db *sqlx.DB
+}
+
+// NewFXQuoteRepository
+func NewFXQuoteRepository(db *sqlx.DB) *FXQuoteRepository {
+	return &FXQuoteRepository{
+		db: db,
+	}
+}
+
+// Create
+func (r *FXQuoteRepository) Create(quote *model.FXQuote) error {
+	var nextID int64
+	if err := r.db.Get(&nextID, `SELECT nextval('money_transfer.fx_quotes_id_seq')`); err != nil {
+		return fmt.Errorf("error generating quote ID: %w", err)
+	}
+
+	quote.ID = fmt.Sprintf("QTE%d", nextID)
+
+	_, err := r.db.Exec(`
+		INSERT INTO money_transfer.fx_quotes (
+			id, quote_code, from_currency, to_currency, mid_rate, rate, spread_bps, created_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5::text::numeric, $6::text::numeric, $7, $8, $9
+		)
+	`,
+		nextID,
+		quote.ID,
+		quote.FromCurrency,
+		quote.ToCurrency,
+		model.FormatRate(quote.MidRate),
+		model.FormatRate(quote.Rate),
+		quote.SpreadBps,
+		quote.CreatedAt,
+		quote.ExpiresAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting quote: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID
+func (r *FXQuoteRepository) GetByID(id string) (*model.FXQuote, error) {
+	var dbQuote DBFXQuote
+
+	err := r.db.Get(&dbQuote, `
+		SELECT id, quote_code, from_currency, to_currency, mid_rate::text AS mid_rate, rate::text AS rate,
+		       spread_bps, created_at, expires_at, used_at
+		FROM money_transfer.fx_quotes
+		WHERE quote_code = $1
+	`, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("error getting quote by ID: %w", err)
+	}
+
+	return toFXQuote(dbQuote)
+}
+
+// GetForUpdate
+func (r *FXQuoteRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*model.FXQuote, error) {
+	var dbQuote DBFXQuote
+
+	err := tx.GetContext(ctx, &dbQuote, `
+		SELECT id, quote_code, from_currency, to_currency, mid_rate::text AS mid_rate, rate::text AS rate,
+		       spread_bps, created_at, expires_at, used_at
+		FROM money_transfer.fx_quotes
+		WHERE quote_code = $1
+		FOR UPDATE
+	`, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrQuoteNotFound
+		}
+		return nil, fmt.Errorf("error getting quote by ID with lock: %w", err)
+	}
+
+	return toFXQuote(dbQuote)
+}
+
+// MarkUsedTx
+func (r *FXQuoteRepository) MarkUsedTx(ctx context.Context, tx *sqlx.Tx, quote *model.FXQuote) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.fx_quotes
+		SET used_at = $1
+		WHERE quote_code = $2
+	`, quote.UsedAt, quote.ID)
+
+	if err != nil {
+		return fmt.Errorf("error marking quote as used: %w", err)
+	}
+
+	return nil
+}
+
+// toFXQuote
+func toFXQuote(dbQuote DBFXQuote) (*model.FXQuote, error) {
+	midRate, err := model.ParseRate(dbQuote.MidRate)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := model.ParseRate(dbQuote.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &model.FXQuote{
+		ID:           dbQuote.QuoteCode,
+		FromCurrency: dbQuote.FromCurrency,
+		ToCurrency:   dbQuote.ToCurrency,
+		MidRate:      midRate,
+		Rate:         rate,
+		SpreadBps:    dbQuote.SpreadBps,
+		CreatedAt:    dbQuote.CreatedAt,
+		ExpiresAt:    dbQuote.ExpiresAt,
+	}
+
+	if dbQuote.UsedAt.Valid {
+		quote.UsedAt = dbQuote.UsedAt.Time
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,89 @@
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBFXRate
+type DBFXRate struct {
+	BaseCurrency  string    `db:"base_currency"`
+	QuoteCurrency string    `db:"quote_currency"`
+	Rate          string    `db:"rate"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// FXRateRepository is a DB-backed FXRateProvider
+type FXRateRepository struct {
+	db *sqlx.DB
+}
+
+// NewFXRateRepository
+func NewFXRateRepository(db *sqlx.DB) *FXRateRepository {
+	return &FXRateRepository{
+		db: db,
+	}
+}
+
+// GetRate looks up the pair directly and falls back to inverting the reverse pair
+func (r *FXRateRepository) GetRate(base, quote string) (*model.FXRate, error) {
+	dbRate, err := r.getRate(base, quote)
+	if err == nil {
+		rate, err := model.ParseRate(dbRate.Rate)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.FXRate{
+			Base:      base,
+			Quote:     quote,
+			Rate:      rate,
+			UpdatedAt: dbRate.UpdatedAt,
+		}, nil
+	}
+	if !errors.Is(err, model.ErrFXRateNotFound) {
+		return nil, err
+	}
+
+	dbRate, err = r.getRate(quote, base)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := model.ParseRate(dbRate.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.FXRate{
+		Base:      base,
+		Quote:     quote,
+		Rate:      model.InvertRate(rate),
+		UpdatedAt: dbRate.UpdatedAt,
+	}, nil
+}
+
+// getRate
+func (r *FXRateRepository) getRate(base, quote string) (*DBFXRate, error) {
+	var dbRate DBFXRate
+
+	err := r.db.Get(&dbRate, `
+		SELECT base_currency, quote_currency, rate::text AS rate, updated_at
+		FROM money_transfer.fx_rates
+		WHERE base_currency = $1 AND quote_currency = $2
+	`, base, quote)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrFXRateNotFound
+		}
+		return nil, fmt.Errorf("error getting exchange rate: %w", err)
+	}
+
+	return &dbRate, nil
+}
@@ -0,0 +1,189 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBHold
+type DBHold struct {
+	ID           int64        `db:"id"`
+	TransferCode string       `db:"transfer_code"`
+	UserID       int64        `db:"user_id"`
+	Currency     string       `db:"currency"`
+	Amount       int          `db:"amount"`
+	Status       string       `db:"status"`
+	CreatedAt    time.Time    `db:"created_at"`
+	ExpiresAt    time.Time    `db:"expires_at"`
+	ReleasedAt   sql.NullTime `db:"released_at"`
+}
+
+// holdColumns
+const holdColumns = `id, transfer_code, user_id, currency, amount, status, created_at, expires_at, released_at`
+
+// HoldRepository
+type HoldRepository struct {
+	db *sqlx.DB
+}
+
+// NewHoldRepository
+func NewHoldRepository(db *sqlx.DB) *HoldRepository {
+	return &HoldRepository{
+		db: db,
+	}
+}
+
+// GetByTransferID
+func (r *HoldRepository) GetByTransferID(transferID string) (*model.Hold, error) {
+	var dbHold DBHold
+
+	err := r.db.Get(&dbHold, `
+		SELECT `+holdColumns+`
+		FROM money_transfer.holds
+		WHERE transfer_code = $1
+	`, transferID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("error getting hold: %w", err)
+	}
+
+	return toHold(dbHold), nil
+}
+
+// ListExpired returns active holds whose authorization window has passed
+func (r *HoldRepository) ListExpired(now time.Time, limit int) ([]*model.Hold, error) {
+	var dbHolds []DBHold
+
+	err := r.db.Select(&dbHolds, `
+		SELECT `+holdColumns+`
+		FROM money_transfer.holds
+		WHERE status = 'ACTIVE' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+	`, now, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing expired holds: %w", err)
+	}
+
+	holds := make([]*model.Hold, len(dbHolds))
+	for i, h := range dbHolds {
+		holds[i] = toHold(h)
+	}
+
+	return holds, nil
+}
+
+// CreateTx
+func (r *HoldRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, hold *model.Hold) error {
+	err := tx.QueryRowxContext(ctx, `
+		INSERT INTO money_transfer.holds (
+			transfer_code, user_id, currency, amount, status, created_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		) RETURNING id
+	`,
+		hold.TransferID,
+		hold.UserID,
+		hold.Currency,
+		hold.Amount,
+		hold.Status,
+		hold.CreatedAt,
+		hold.ExpiresAt,
+	).Scan(&hold.ID)
+
+	if err != nil {
+		return fmt.Errorf("error inserting hold: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTransferIDForUpdate
+func (r *HoldRepository) GetByTransferIDForUpdate(ctx context.Context, tx *sqlx.Tx, transferID string) (*model.Hold, error) {
+	var dbHold DBHold
+
+	err := tx.GetContext(ctx, &dbHold, `
+		SELECT `+holdColumns+`
+		FROM money_transfer.holds
+		WHERE transfer_code = $1
+		FOR UPDATE
+	`, transferID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("error getting hold with lock: %w", err)
+	}
+
+	return toHold(dbHold), nil
+}
+
+// ReleaseTx records that the hold no longer reserves funds
+func (r *HoldRepository) ReleaseTx(ctx context.Context, tx *sqlx.Tx, hold *model.Hold) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.holds
+		SET status = $1, released_at = $2
+		WHERE id = $3 AND status = 'ACTIVE'
+	`, hold.Status, hold.ReleasedAt, hold.ID)
+
+	if err != nil {
+		return fmt.Errorf("error releasing hold: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error releasing hold: %w", err)
+	}
+
+	if rows == 0 {
+		return model.ErrTransferNotPending
+	}
+
+	return nil
+}
+
+// ExtendTx moves the expiry of an active hold
+func (r *HoldRepository) ExtendTx(ctx context.Context, tx *sqlx.Tx, hold *model.Hold) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.holds
+		SET expires_at = $1
+		WHERE id = $2 AND status = 'ACTIVE'
+	`, hold.ExpiresAt, hold.ID)
+
+	if err != nil {
+		return fmt.Errorf("error extending hold: %w", err)
+	}
+
+	return nil
+}
+
+// toHold
+func toHold(h DBHold) *model.Hold {
+	hold := &model.Hold{
+		ID:         h.ID,
+		TransferID: h.TransferCode,
+		UserID:     fmt.Sprintf("%d", h.UserID),
+		Currency:   h.Currency,
+		Amount:     h.Amount,
+		Status:     model.HoldStatus(h.Status),
+		CreatedAt:  h.CreatedAt,
+		ExpiresAt:  h.ExpiresAt,
+	}
+
+	if h.ReleasedAt.Valid {
+		hold.ReleasedAt = h.ReleasedAt.Time
+	}
+
+	return hold
+}
@@ -0,0 +1,126 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// pgUniqueViolation
+const pgUniqueViolation = "23505"
+
+// DBIdempotencyKey
+type DBIdempotencyKey struct {
+	Key            string        `db:"key"`
+	RequestHash    string        `db:"request_hash"`
+	TransferCode   string        `db:"transfer_code"`
+	ResponseStatus sql.NullInt32 `db:"response_status"`
+	ResponseBody   []byte        `db:"response_body"`
+	CreatedAt      time.Time     `db:"created_at"`
+	ExpiresAt      time.Time     `db:"expires_at"`
+}
+
+// IdempotencyKeyRepository
+type IdempotencyKeyRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyKeyRepository
+func NewIdempotencyKeyRepository(db *sqlx.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		db: db,
+	}
+}
+
+// GetByKey returns the key only while it has not expired
+func (r *IdempotencyKeyRepository) GetByKey(key string) (*model.IdempotencyKey, error) {
+	var dbKey DBIdempotencyKey
+
+	err := r.db.Get(&dbKey, `
+		SELECT key, request_hash, transfer_code, response_status, response_body, created_at, expires_at
+		FROM money_transfer.idempotency_keys
+		WHERE key = $1 AND expires_at > NOW()
+	`, key)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrIdempotencyKeyNotFound
+		}
+		return nil, fmt.Errorf("error getting idempotency key: %w", err)
+	}
+
+	idempotencyKey := &model.IdempotencyKey{
+		Key:         dbKey.Key,
+		RequestHash: dbKey.RequestHash,
+		TransferID:  dbKey.TransferCode,
+		CreatedAt:   dbKey.CreatedAt,
+		ExpiresAt:   dbKey.ExpiresAt,
+	}
+	if dbKey.ResponseStatus.Valid {
+		idempotencyKey.Response = &model.IdempotentResponse{
+			StatusCode: int(dbKey.ResponseStatus.Int32),
+			Body:       dbKey.ResponseBody,
+		}
+	}
+
+	return idempotencyKey, nil
+}
+
+// SaveResponse stores the response unless one was stored already, so the
+// first response is the one replayed
+func (r *IdempotencyKeyRepository) SaveResponse(key string, response *model.IdempotentResponse) error {
+	_, err := r.db.Exec(`
+		UPDATE money_transfer.idempotency_keys
+		SET response_status = $2, response_body = $3
+		WHERE key = $1 AND response_status IS NULL
+	`, key, response.StatusCode, response.Body)
+
+	if err != nil {
+		return fmt.Errorf("error saving idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTx claims the key inside the transfer transaction. A concurrent
+// request holding the same key blocks here until the first one finishes.
+func (r *IdempotencyKeyRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, key *model.IdempotencyKey) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM money_transfer.idempotency_keys
+		WHERE key = $1 AND expires_at <= NOW()
+	`, key.Key)
+
+	if err != nil {
+		return fmt.Errorf("error removing expired idempotency key: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.idempotency_keys (
+			key, request_hash, transfer_code, created_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)
+	`,
+		key.Key,
+		key.RequestHash,
+		key.TransferID,
+		key.CreatedAt,
+		key.ExpiresAt,
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return model.ErrIdempotencyKeyExists
+		}
+		return fmt.Errorf("error inserting idempotency key: %w", err)
+	}
+
+	return nil
+}
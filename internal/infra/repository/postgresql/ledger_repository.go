@@ -0,0 +1,252 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBLedgerEntry
+type DBLedgerEntry struct {
+	ID        int64         `db:"id"`
+	JournalID int64         `db:"journal_id"`
+	Account   string        `db:"account"`
+	UserID    sql.NullInt64 `db:"user_id"`
+	Currency  string        `db:"currency"`
+	Amount    int           `db:"amount"`
+	CreatedAt time.Time     `db:"created_at"`
+}
+
+// DBBalanceMismatch
+type DBBalanceMismatch struct {
+	UserID        int64  `db:"user_id"`
+	Currency      string `db:"currency"`
+	CachedBalance int    `db:"cached_balance"`
+	LedgerBalance int    `db:"ledger_balance"`
+}
+
+// DBJournalImbalance
+type DBJournalImbalance struct {
+	JournalCode string `db:"journal_code"`
+	Currency    string `db:"currency"`
+	Sum         int    `db:"sum"`
+}
+
+// DBAccountTotal
+type DBAccountTotal struct {
+	Currency string `db:"currency"`
+	Amount   int    `db:"amount"`
+	Entries  int    `db:"entries"`
+}
+
+// LedgerRepository
+type LedgerRepository struct {
+	db *sqlx.DB
+}
+
+// NewLedgerRepository
+func NewLedgerRepository(db *sqlx.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+// PostTx writes a balanced journal and applies its user postings to the
+// cached balances, emitting a balance_changed event for each. Callers must
+// hold the lock on every user involved and pass the locked users, whose
+// streams must still be at the version they were locked at.
+func (r *LedgerRepository) PostTx(ctx context.Context, tx *sqlx.Tx, journal *model.Journal, users map[string]*model.User) error {
+	if err := journal.Validate(); err != nil {
+		return err
+	}
+
+	var nextID int64
+	if err := tx.GetContext(ctx, &nextID, `SELECT nextval('money_transfer.ledger_journals_id_seq')`); err != nil {
+		return fmt.Errorf("error generating journal ID: %w", err)
+	}
+
+	journal.ID = fmt.Sprintf("JRN%d", nextID)
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.ledger_journals (
+			id, journal_code, transfer_code, funding_code, description, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`,
+		nextID,
+		journal.ID,
+		nullString(journal.TransferID),
+		nullString(journal.FundingID),
+		journal.Description,
+		journal.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting journal: %w", err)
+	}
+
+	for _, p := range journal.Postings {
+		userID, isUser := model.UserIDFromAccount(p.Account)
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO money_transfer.ledger_entries (
+				journal_id, account, user_id, currency, amount, created_at
+			) VALUES (
+				$1, $2, $3, $4, $5, $6
+			)
+		`,
+			nextID,
+			p.Account,
+			nullString(userID),
+			p.Currency,
+			p.Amount,
+			journal.CreatedAt,
+		)
+
+		if err != nil {
+			return fmt.Errorf("error inserting ledger entry: %w", err)
+		}
+
+		if !isUser {
+			continue
+		}
+
+		user, ok := users[userID]
+		if !ok {
+			return fmt.Errorf("error applying posting to balance: user %s is not locked", userID)
+		}
+
+		var balance int
+		err = tx.GetContext(ctx, &balance, `
+			UPDATE money_transfer.balances
+			SET amount = amount + $1, updated_at = NOW()
+			WHERE user_id = $2 AND currency = $3
+			RETURNING amount
+		`, p.Amount, userID, p.Currency)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrCurrencyNotHeld
+			}
+			return fmt.Errorf("error applying posting to balance: %w", err)
+		}
+
+		user.Version, err = insertEventTx(ctx, tx, user.Version, &event.BalanceChanged{
+			UserID:      userID,
+			Currency:    p.Currency,
+			Delta:       p.Amount,
+			Balance:     balance,
+			JournalID:   journal.ID,
+			TransferID:  journal.TransferID,
+			FundingID:   journal.FundingID,
+			Description: journal.Description,
+			ChangedAt:   journal.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BalanceMismatches compares every cached balance with the sum of its postings
+func (r *LedgerRepository) BalanceMismatches() ([]*model.BalanceMismatch, error) {
+	var dbMismatches []DBBalanceMismatch
+
+	err := r.db.Select(&dbMismatches, `
+		SELECT COALESCE(b.user_id, e.user_id) AS user_id,
+		       COALESCE(b.currency, e.currency) AS currency,
+		       COALESCE(b.amount, 0) AS cached_balance,
+		       COALESCE(e.total, 0) AS ledger_balance
+		FROM money_transfer.balances b
+		FULL OUTER JOIN (
+			SELECT user_id, currency, SUM(amount)::bigint AS total
+			FROM money_transfer.ledger_entries
+			WHERE user_id IS NOT NULL
+			GROUP BY user_id, currency
+		) e ON e.user_id = b.user_id AND e.currency = b.currency
+		WHERE COALESCE(b.amount, 0) <> COALESCE(e.total, 0)
+		ORDER BY 1, 2
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error comparing balances with ledger: %w", err)
+	}
+
+	mismatches := make([]*model.BalanceMismatch, len(dbMismatches))
+	for i, m := range dbMismatches {
+		mismatches[i] = &model.BalanceMismatch{
+			UserID:        fmt.Sprintf("%d", m.UserID),
+			Currency:      m.Currency,
+			CachedBalance: m.CachedBalance,
+			LedgerBalance: m.LedgerBalance,
+		}
+	}
+
+	return mismatches, nil
+}
+
+// JournalImbalances lists journals whose postings do not sum to zero
+func (r *LedgerRepository) JournalImbalances() ([]*model.JournalImbalance, error) {
+	var dbImbalances []DBJournalImbalance
+
+	err := r.db.Select(&dbImbalances, `
+		SELECT j.journal_code, e.currency, SUM(e.amount)::bigint AS sum
+		FROM money_transfer.ledger_entries e
+		JOIN money_transfer.ledger_journals j ON j.id = e.journal_id
+		GROUP BY j.journal_code, e.currency
+		HAVING SUM(e.amount) <> 0
+		ORDER BY j.journal_code, e.currency
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error checking journal balances: %w", err)
+	}
+
+	imbalances := make([]*model.JournalImbalance, len(dbImbalances))
+	for i, im := range dbImbalances {
+		imbalances[i] = &model.JournalImbalance{
+			JournalID: im.JournalCode,
+			Currency:  im.Currency,
+			Sum:       im.Sum,
+		}
+	}
+
+	return imbalances, nil
+}
+
+// AccountTotals sums the postings to an account per currency within [from, to)
+func (r *LedgerRepository) AccountTotals(account string, from, to time.Time) ([]*model.AccountTotal, error) {
+	var dbTotals []DBAccountTotal
+
+	err := r.db.Select(&dbTotals, `
+		SELECT currency, SUM(amount)::bigint AS amount, COUNT(*) AS entries
+		FROM money_transfer.ledger_entries
+		WHERE account = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY currency
+		ORDER BY currency
+	`, account, from, to)
+
+	if err != nil {
+		return nil, fmt.Errorf("error summing account postings: %w", err)
+	}
+
+	totals := make([]*model.AccountTotal, len(dbTotals))
+	for i, t := range dbTotals {
+		totals[i] = &model.AccountTotal{
+			Currency: t.Currency,
+			Amount:   t.Amount,
+			Entries:  t.Entries,
+		}
+	}
+
+	return totals, nil
+}
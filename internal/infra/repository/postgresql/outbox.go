@@ -0,0 +1,34 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/jmoiron/sqlx"
+)
+
+// insertEventTx writes the event to the outbox and appends it to its
+// aggregate's stream in the event store, in the caller's transaction. The
+// stream must be at expectedVersion, unless it is AnyVersion; the event's
+// version is returned.
+func insertEventTx(ctx context.Context, tx *sqlx.Tx, expectedVersion int, e event.Event) (int, error) {
+	payload, err := event.Encode(e)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.outbox_events (
+			aggregate_type, aggregate_id, event_type, payload
+		) VALUES (
+			$1, $2, $3, $4
+		)
+	`, e.AggregateType(), e.AggregateID(), e.EventType(), payload)
+
+	if err != nil {
+		return 0, fmt.Errorf("error inserting outbox event: %w", err)
+	}
+
+	return appendEventTx(ctx, tx, expectedVersion, e, payload)
+}
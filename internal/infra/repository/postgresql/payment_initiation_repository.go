@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PaymentInitiationRepository
+type PaymentInitiationRepository struct {
+	db *sqlx.DB
+}
+
+// NewPaymentInitiationRepository
+func NewPaymentInitiationRepository(db *sqlx.DB) *PaymentInitiationRepository {
+	return &PaymentInitiationRepository{
+		db: db,
+	}
+}
+
+// Claim inserts the pair; the primary key lets only one caller claim it
+func (r *PaymentInitiationRepository) Claim(messageID, paymentInfoID string) (bool, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO money_transfer.payment_initiations (message_id, payment_info_id)
+		VALUES ($1, $2)
+		ON CONFLICT (message_id, payment_info_id) DO NOTHING
+	`, messageID, paymentInfoID)
+
+	if err != nil {
+		return false, fmt.Errorf("error claiming payment initiation: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming payment initiation: %w", err)
+	}
+
+	return n == 1, nil
+}
+
+// Exists
+func (r *PaymentInitiationRepository) Exists(messageID, paymentInfoID string) (bool, error) {
+	var exists bool
+
+	err := r.db.Get(&exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM money_transfer.payment_initiations
+			WHERE message_id = $1 AND payment_info_id = $2
+		)
+	`, messageID, paymentInfoID)
+
+	if err != nil {
+		return false, fmt.Errorf("error checking payment initiation: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,247 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// projectionInsertBatch keeps each multi-row insert well under the bind parameter limit
+const projectionInsertBatch = 1000
+
+// DBProjectedTransfer is a row of transfers_projection. The JSON names are
+// the column names, for jsonb_populate_recordset.
+type DBProjectedTransfer struct {
+	TransferCode   string     `db:"transfer_code" json:"transfer_code"`
+	FromUserID     string     `db:"from_user_id" json:"from_user_id"`
+	ToUserID       string     `db:"to_user_id" json:"to_user_id"`
+	Amount         int        `db:"amount" json:"amount"`
+	Currency       string     `db:"currency" json:"currency"`
+	Fee            int        `db:"fee" json:"fee"`
+	QuoteCode      *string    `db:"quote_code" json:"quote_code"`
+	State          string     `db:"state" json:"state"`
+	ReversalOf     *string    `db:"reversal_of" json:"reversal_of"`
+	ReversedAmount int        `db:"reversed_amount" json:"reversed_amount"`
+	StandingOrder  *string    `db:"standing_order_code" json:"standing_order_code"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	CompletedAt    *time.Time `db:"completed_at" json:"completed_at"`
+	Version        int        `db:"version" json:"version"`
+}
+
+// DBProjectedBalance is a row of balances_projection
+type DBProjectedBalance struct {
+	UserID   string `db:"user_id" json:"user_id"`
+	Currency string `db:"currency" json:"currency"`
+	Amount   int    `db:"amount" json:"amount"`
+	Version  int    `db:"version" json:"version"`
+}
+
+// DBProjectionMismatch
+type DBProjectionMismatch struct {
+	TableName string `db:"table_name"`
+	Key       string `db:"key"`
+	Live      string `db:"live"`
+	Projected string `db:"projected"`
+}
+
+// ProjectionRepository
+type ProjectionRepository struct {
+	db *sqlx.DB
+}
+
+// NewProjectionRepository
+func NewProjectionRepository(db *sqlx.DB) *ProjectionRepository {
+	return &ProjectionRepository{
+		db: db,
+	}
+}
+
+// Replace truncates the projection tables and inserts the rebuilt rows in one transaction
+func (r *ProjectionRepository) Replace(transfers []*model.ProjectedTransfer, balances []*model.ProjectedBalance) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		TRUNCATE money_transfer.transfers_projection, money_transfer.balances_projection
+	`)
+	if err != nil {
+		return fmt.Errorf("error truncating projections: %w", err)
+	}
+
+	dbTransfers := toDBProjectedTransfers(transfers)
+	for start := 0; start < len(dbTransfers); start += projectionInsertBatch {
+		end := min(start+projectionInsertBatch, len(dbTransfers))
+		_, err = tx.NamedExecContext(ctx, `
+			INSERT INTO money_transfer.transfers_projection (
+				transfer_code, from_user_id, to_user_id, amount, currency, fee, quote_code, state,
+				reversal_of, reversed_amount, standing_order_code, created_at, completed_at, version
+			) VALUES (
+				:transfer_code, :from_user_id, :to_user_id, :amount, :currency, :fee, :quote_code, :state,
+				:reversal_of, :reversed_amount, :standing_order_code, :created_at, :completed_at, :version
+			)
+		`, dbTransfers[start:end])
+		if err != nil {
+			return fmt.Errorf("error inserting projected transfers: %w", err)
+		}
+	}
+
+	dbBalances := toDBProjectedBalances(balances)
+	for start := 0; start < len(dbBalances); start += projectionInsertBatch {
+		end := min(start+projectionInsertBatch, len(dbBalances))
+		_, err = tx.NamedExecContext(ctx, `
+			INSERT INTO money_transfer.balances_projection (user_id, currency, amount, version)
+			VALUES (:user_id, :currency, :amount, :version)
+		`, dbBalances[start:end])
+		if err != nil {
+			return fmt.Errorf("error inserting projected balances: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MismatchesTx lists the rows that are missing from, or differ between, a
+// live table and the given projected rows, each side rendered as JSON. It
+// only reads, so it can run in a read-only transaction together with the
+// reads the rows were projected from. A limit of 0 lists all.
+func (r *ProjectionRepository) MismatchesTx(ctx context.Context, tx *sqlx.Tx, transfers []*model.ProjectedTransfer, balances []*model.ProjectedBalance, limit int) ([]*model.ProjectionMismatch, error) {
+	var dbMismatches []DBProjectionMismatch
+
+	var rowLimit sql.NullInt64
+	if limit > 0 {
+		rowLimit = sql.NullInt64{Int64: int64(limit), Valid: true}
+	}
+
+	projectedTransfers, err := json.Marshal(toDBProjectedTransfers(transfers))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding projected transfers: %w", err)
+	}
+
+	projectedBalances, err := json.Marshal(toDBProjectedBalances(balances))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding projected balances: %w", err)
+	}
+
+	// The projected rows take the types of the projection tables' columns
+	err = tx.SelectContext(ctx, &dbMismatches, `
+		SELECT 'transfers' AS table_name,
+		       COALESCE(l.transfer_code, p.transfer_code) AS key,
+		       CASE WHEN l.transfer_code IS NULL THEN '' ELSE to_jsonb(l)::text END AS live,
+		       CASE WHEN p.transfer_code IS NULL THEN '' ELSE to_jsonb(p)::text END AS projected
+		FROM (
+			SELECT transfer_code, from_user_id, to_user_id, amount, currency, fee, quote_code, state,
+			       reversal_of, reversed_amount, standing_order_code, created_at, completed_at
+			FROM money_transfer.transfers
+		) l
+		FULL OUTER JOIN (
+			SELECT transfer_code, from_user_id, to_user_id, amount, currency, fee, quote_code, state,
+			       reversal_of, reversed_amount, standing_order_code, created_at, completed_at
+			FROM jsonb_populate_recordset(NULL::money_transfer.transfers_projection, $2::jsonb)
+		) p ON p.transfer_code = l.transfer_code
+		WHERE l.transfer_code IS NULL OR p.transfer_code IS NULL
+		   OR (l.from_user_id, l.to_user_id, l.amount, l.currency, l.fee, l.quote_code, l.state,
+		       l.reversal_of, l.reversed_amount, l.standing_order_code, l.created_at, l.completed_at)
+		      IS DISTINCT FROM
+		      (p.from_user_id, p.to_user_id, p.amount, p.currency, p.fee, p.quote_code, p.state,
+		       p.reversal_of, p.reversed_amount, p.standing_order_code, p.created_at, p.completed_at)
+		UNION ALL
+		SELECT 'balances' AS table_name,
+		       COALESCE(l.user_id, p.user_id)::text || '/' || COALESCE(l.currency, p.currency) AS key,
+		       CASE WHEN l.user_id IS NULL THEN '' ELSE to_jsonb(l)::text END AS live,
+		       CASE WHEN p.user_id IS NULL THEN '' ELSE to_jsonb(p)::text END AS projected
+		FROM (
+			SELECT user_id, currency, amount FROM money_transfer.balances
+		) l
+		FULL OUTER JOIN (
+			SELECT user_id, currency, amount
+			FROM jsonb_populate_recordset(NULL::money_transfer.balances_projection, $3::jsonb)
+		) p ON p.user_id = l.user_id AND p.currency = l.currency
+		WHERE l.user_id IS NULL OR p.user_id IS NULL OR l.amount <> p.amount
+		ORDER BY 1, 2
+		LIMIT $1
+	`, rowLimit, string(projectedTransfers), string(projectedBalances))
+
+	if err != nil {
+		return nil, fmt.Errorf("error comparing projections with live tables: %w", err)
+	}
+
+	mismatches := make([]*model.ProjectionMismatch, len(dbMismatches))
+	for i, m := range dbMismatches {
+		mismatches[i] = &model.ProjectionMismatch{
+			Table:     m.TableName,
+			Key:       m.Key,
+			Live:      m.Live,
+			Projected: m.Projected,
+		}
+	}
+
+	return mismatches, nil
+}
+
+// toDBProjectedTransfers
+func toDBProjectedTransfers(transfers []*model.ProjectedTransfer) []DBProjectedTransfer {
+	dbTransfers := make([]DBProjectedTransfer, len(transfers))
+	for i, p := range transfers {
+		t := p.Transfer
+		dbTransfers[i] = DBProjectedTransfer{
+			TransferCode:   t.ID,
+			FromUserID:     t.FromUserID,
+			ToUserID:       t.ToUserID,
+			Amount:         t.Amount,
+			Currency:       t.Currency,
+			Fee:            t.Fee,
+			QuoteCode:      optionalString(t.QuoteID),
+			State:          string(t.State),
+			ReversalOf:     optionalString(t.ReversalOf),
+			ReversedAmount: t.ReversedAmount,
+			StandingOrder:  optionalString(t.StandingOrderID),
+			CreatedAt:      t.CreatedAt,
+			Version:        p.Version,
+		}
+		if !t.CompletedAt.IsZero() {
+			completedAt := t.CompletedAt
+			dbTransfers[i].CompletedAt = &completedAt
+		}
+	}
+	return dbTransfers
+}
+
+// toDBProjectedBalances
+func toDBProjectedBalances(balances []*model.ProjectedBalance) []DBProjectedBalance {
+	dbBalances := make([]DBProjectedBalance, len(balances))
+	for i, b := range balances {
+		dbBalances[i] = DBProjectedBalance{
+			UserID:   b.UserID,
+			Currency: b.Currency,
+			Amount:   b.Amount,
+			Version:  b.Version,
+		}
+	}
+	return dbBalances
+}
+
+// optionalString is nil for an empty string, which encodes as NULL
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
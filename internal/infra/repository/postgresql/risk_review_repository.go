@@ -0,0 +1,199 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBRiskReview
+type DBRiskReview struct {
+	ID           int64          `db:"id"`
+	ReviewCode   string         `db:"review_code"`
+	TransferCode string         `db:"transfer_code"`
+	Status       string         `db:"status"`
+	Reasons      []byte         `db:"reasons"`
+	Authorize    bool           `db:"authorize"`
+	Reviewer     sql.NullString `db:"reviewer"`
+	Note         sql.NullString `db:"note"`
+	CreatedAt    time.Time      `db:"created_at"`
+	ReviewedAt   sql.NullTime   `db:"reviewed_at"`
+}
+
+// riskReviewColumns
+const riskReviewColumns = `id, review_code, transfer_code, status, reasons, authorize, reviewer, note, created_at, reviewed_at`
+
+// RiskReviewRepository
+type RiskReviewRepository struct {
+	db *sqlx.DB
+}
+
+// NewRiskReviewRepository
+func NewRiskReviewRepository(db *sqlx.DB) *RiskReviewRepository {
+	return &RiskReviewRepository{
+		db: db,
+	}
+}
+
+// GetByTransferID
+func (r *RiskReviewRepository) GetByTransferID(transferID string) (*model.RiskReview, error) {
+	return r.getByTransferID(context.Background(), r.db, transferID)
+}
+
+// GetByTransferIDTx
+func (r *RiskReviewRepository) GetByTransferIDTx(ctx context.Context, tx *sqlx.Tx, transferID string) (*model.RiskReview, error) {
+	return r.getByTransferID(ctx, tx, transferID)
+}
+
+// getByTransferID
+func (r *RiskReviewRepository) getByTransferID(ctx context.Context, q sqlx.QueryerContext, transferID string) (*model.RiskReview, error) {
+	var dbReview DBRiskReview
+
+	err := sqlx.GetContext(ctx, q, &dbReview, `
+		SELECT `+riskReviewColumns+`
+		FROM money_transfer.risk_reviews
+		WHERE transfer_code = $1
+	`, transferID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrTransferNotHeld
+		}
+		return nil, fmt.Errorf("error getting risk review: %w", err)
+	}
+
+	return toRiskReview(dbReview)
+}
+
+// List
+func (r *RiskReviewRepository) List(status model.RiskReviewStatus, limit int) ([]*model.RiskReview, error) {
+	var dbReviews []DBRiskReview
+
+	err := r.db.Select(&dbReviews, `
+		SELECT `+riskReviewColumns+`
+		FROM money_transfer.risk_reviews
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`, string(status), limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing risk reviews: %w", err)
+	}
+
+	return toRiskReviews(dbReviews)
+}
+
+// ListExpired
+func (r *RiskReviewRepository) ListExpired(createdBefore time.Time, limit int) ([]*model.RiskReview, error) {
+	var dbReviews []DBRiskReview
+
+	err := r.db.Select(&dbReviews, `
+		SELECT `+riskReviewColumns+`
+		FROM money_transfer.risk_reviews
+		WHERE status = 'PENDING' AND created_at < $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`, createdBefore, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing expired risk reviews: %w", err)
+	}
+
+	return toRiskReviews(dbReviews)
+}
+
+// CreateTx saves a PENDING review and sets its ID
+func (r *RiskReviewRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, review *model.RiskReview) error {
+	reasons, err := json.Marshal(review.Reasons)
+	if err != nil {
+		return fmt.Errorf("error marshaling review reasons: %w", err)
+	}
+
+	var nextID int64
+	if err := tx.GetContext(ctx, &nextID, `SELECT nextval('money_transfer.risk_reviews_id_seq')`); err != nil {
+		return fmt.Errorf("error generating risk review ID: %w", err)
+	}
+
+	reviewCode := fmt.Sprintf("RRV%d", nextID)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.risk_reviews (
+			id, review_code, transfer_code, status, reasons, authorize, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`,
+		nextID,
+		reviewCode,
+		review.TransferID,
+		review.Status,
+		reasons,
+		review.Authorize,
+		review.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting risk review: %w", err)
+	}
+
+	review.ID = reviewCode
+	return nil
+}
+
+// UpdateTx records the outcome of a review
+func (r *RiskReviewRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, review *model.RiskReview) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.risk_reviews
+		SET status = $1, reviewer = NULLIF($2, ''), note = NULLIF($3, ''), reviewed_at = $4
+		WHERE review_code = $5
+	`, review.Status, review.Reviewer, review.Note, review.ReviewedAt, review.ID)
+
+	if err != nil {
+		return fmt.Errorf("error updating risk review: %w", err)
+	}
+
+	return nil
+}
+
+// toRiskReviews
+func toRiskReviews(dbReviews []DBRiskReview) ([]*model.RiskReview, error) {
+	reviews := make([]*model.RiskReview, len(dbReviews))
+	for i, rv := range dbReviews {
+		review, err := toRiskReview(rv)
+		if err != nil {
+			return nil, err
+		}
+		reviews[i] = review
+	}
+
+	return reviews, nil
+}
+
+// toRiskReview
+func toRiskReview(rv DBRiskReview) (*model.RiskReview, error) {
+	review := &model.RiskReview{
+		ID:         rv.ReviewCode,
+		TransferID: rv.TransferCode,
+		Status:     model.RiskReviewStatus(rv.Status),
+		Authorize:  rv.Authorize,
+		Reviewer:   rv.Reviewer.String,
+		Note:       rv.Note.String,
+		CreatedAt:  rv.CreatedAt,
+	}
+
+	if err := json.Unmarshal(rv.Reasons, &review.Reasons); err != nil {
+		return nil, fmt.Errorf("error unmarshaling review reasons: %w", err)
+	}
+
+	if rv.ReviewedAt.Valid {
+		review.ReviewedAt = rv.ReviewedAt.Time
+	}
+
+	return review, nil
+}
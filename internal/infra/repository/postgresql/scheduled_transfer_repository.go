@@ -0,0 +1,290 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBScheduledTransfer
+type DBScheduledTransfer struct {
+	ID            int64          `db:"id"`
+	ScheduleCode  string         `db:"schedule_code"`
+	FromUserID    int64          `db:"from_user_id"`
+	ToUserID      int64          `db:"to_user_id"`
+	Amount        int            `db:"amount"`
+	Currency      string         `db:"currency"`
+	ExecuteAt     time.Time      `db:"execute_at"`
+	Status        string         `db:"status"`
+	TransferCode  sql.NullString `db:"transfer_code"`
+	FailureReason sql.NullString `db:"failure_reason"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+	ExecutedAt    sql.NullTime   `db:"executed_at"`
+}
+
+// scheduledTransferColumns
+const scheduledTransferColumns = `id, schedule_code, from_user_id, to_user_id, amount, currency, execute_at, status,
+		       transfer_code, failure_reason, created_at, updated_at, executed_at`
+
+// scheduledTransferDue matches rows that are due, or were claimed by a run that never finished
+const scheduledTransferDue = `((status = 'SCHEDULED' AND execute_at <= $1) OR (status = 'PROCESSING' AND updated_at < $2))`
+
+// ScheduledTransferRepository
+type ScheduledTransferRepository struct {
+	db *sqlx.DB
+}
+
+// NewScheduledTransferRepository
+func NewScheduledTransferRepository(db *sqlx.DB) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{
+		db: db,
+	}
+}
+
+// Create
+func (r *ScheduledTransferRepository) Create(schedule *model.ScheduledTransfer) error {
+	var nextID int64
+	if err := r.db.Get(&nextID, `SELECT nextval('money_transfer.scheduled_transfers_id_seq')`); err != nil {
+		return fmt.Errorf("error generating scheduled transfer ID: %w", err)
+	}
+
+	schedule.ID = fmt.Sprintf("SCH%d", nextID)
+
+	_, err := r.db.Exec(`
+		INSERT INTO money_transfer.scheduled_transfers (
+			id, schedule_code, from_user_id, to_user_id, amount, currency, execute_at, status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+	`,
+		nextID,
+		schedule.ID,
+		schedule.FromUserID,
+		schedule.ToUserID,
+		schedule.Amount,
+		schedule.Currency,
+		schedule.ExecuteAt,
+		schedule.Status,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID
+func (r *ScheduledTransferRepository) GetByID(id string) (*model.ScheduledTransfer, error) {
+	var dbSchedule DBScheduledTransfer
+
+	err := r.db.Get(&dbSchedule, `
+		SELECT `+scheduledTransferColumns+`
+		FROM money_transfer.scheduled_transfers
+		WHERE schedule_code = $1
+	`, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("error getting scheduled transfer: %w", err)
+	}
+
+	return toScheduledTransfer(dbSchedule), nil
+}
+
+// List
+func (r *ScheduledTransferRepository) List() ([]*model.ScheduledTransfer, error) {
+	var dbSchedules []DBScheduledTransfer
+
+	err := r.db.Select(&dbSchedules, `
+		SELECT `+scheduledTransferColumns+`
+		FROM money_transfer.scheduled_transfers
+		ORDER BY execute_at DESC, id DESC
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing scheduled transfers: %w", err)
+	}
+
+	return toScheduledTransfers(dbSchedules), nil
+}
+
+// ListDue
+func (r *ScheduledTransferRepository) ListDue(now, staleBefore time.Time, limit int) ([]*model.ScheduledTransfer, error) {
+	var dbSchedules []DBScheduledTransfer
+
+	err := r.db.Select(&dbSchedules, `
+		SELECT `+scheduledTransferColumns+`
+		FROM money_transfer.scheduled_transfers
+		WHERE `+scheduledTransferDue+`
+		ORDER BY execute_at, id
+		LIMIT $3
+	`, now, staleBefore, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing due scheduled transfers: %w", err)
+	}
+
+	return toScheduledTransfers(dbSchedules), nil
+}
+
+// Claim moves a due row to PROCESSING. Only one caller can claim a row,
+// so it reports false when another worker got there first.
+func (r *ScheduledTransferRepository) Claim(id string, now, staleBefore time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE money_transfer.scheduled_transfers
+		SET status = 'PROCESSING', updated_at = $1
+		WHERE schedule_code = $3 AND `+scheduledTransferDue+`
+	`, now, staleBefore, id)
+
+	if err != nil {
+		return false, fmt.Errorf("error claiming scheduled transfer: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming scheduled transfer: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Cancel
+func (r *ScheduledTransferRepository) Cancel(id string) error {
+	result, err := r.db.Exec(`
+		UPDATE money_transfer.scheduled_transfers
+		SET status = 'CANCELLED', updated_at = NOW()
+		WHERE schedule_code = $1 AND status = 'SCHEDULED'
+	`, id)
+
+	if err != nil {
+		return fmt.Errorf("error cancelling scheduled transfer: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error cancelling scheduled transfer: %w", err)
+	}
+
+	if rows > 0 {
+		return nil
+	}
+
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+
+	return model.ErrScheduleNotCancellable
+}
+
+// GetByTransferIDForUpdate locks the schedule that made the transfer
+func (r *ScheduledTransferRepository) GetByTransferIDForUpdate(ctx context.Context, tx *sqlx.Tx, transferID string) (*model.ScheduledTransfer, error) {
+	var dbSchedule DBScheduledTransfer
+
+	err := tx.GetContext(ctx, &dbSchedule, `
+		SELECT `+scheduledTransferColumns+`
+		FROM money_transfer.scheduled_transfers
+		WHERE transfer_code = $1
+		FOR UPDATE
+	`, transferID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("error getting scheduled transfer: %w", err)
+	}
+
+	return toScheduledTransfer(dbSchedule), nil
+}
+
+// Complete links the executed transfer to the schedule, which is COMPLETED,
+// or PENDING_REVIEW when the transfer was held
+func (r *ScheduledTransferRepository) Complete(schedule *model.ScheduledTransfer) error {
+	_, err := r.db.Exec(`
+		UPDATE money_transfer.scheduled_transfers
+		SET status = $1, transfer_code = $2, executed_at = $3, updated_at = $3
+		WHERE schedule_code = $4 AND status = 'PROCESSING'
+	`, schedule.Status, schedule.TransferID, schedule.ExecutedAt, schedule.ID)
+
+	if err != nil {
+		return fmt.Errorf("error completing scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteReviewedTx completes a schedule whose held transfer was approved
+func (r *ScheduledTransferRepository) CompleteReviewedTx(ctx context.Context, tx *sqlx.Tx, schedule *model.ScheduledTransfer) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.scheduled_transfers
+		SET status = $1, updated_at = NOW()
+		WHERE schedule_code = $2 AND status = 'PENDING_REVIEW'
+	`, schedule.Status, schedule.ID)
+
+	if err != nil {
+		return fmt.Errorf("error completing scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+// FailTx records why the schedule's transfer was rejected, when it ran or in
+// its risk review, and emits an outbox event
+func (r *ScheduledTransferRepository) FailTx(ctx context.Context, tx *sqlx.Tx, schedule *model.ScheduledTransfer) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.scheduled_transfers
+		SET status = $1, failure_reason = $2, executed_at = $3, updated_at = $3
+		WHERE schedule_code = $4 AND status IN ('PROCESSING', 'PENDING_REVIEW')
+	`, schedule.Status, schedule.FailureReason, schedule.ExecutedAt, schedule.ID)
+
+	if err != nil {
+		return fmt.Errorf("error failing scheduled transfer: %w", err)
+	}
+
+	_, err = insertEventTx(ctx, tx, AnyVersion, event.NewScheduledTransferFailed(schedule))
+	return err
+}
+
+// toScheduledTransfers
+func toScheduledTransfers(dbSchedules []DBScheduledTransfer) []*model.ScheduledTransfer {
+	schedules := make([]*model.ScheduledTransfer, len(dbSchedules))
+	for i, s := range dbSchedules {
+		schedules[i] = toScheduledTransfer(s)
+	}
+	return schedules
+}
+
+// toScheduledTransfer
+func toScheduledTransfer(s DBScheduledTransfer) *model.ScheduledTransfer {
+	schedule := &model.ScheduledTransfer{
+		ID:            s.ScheduleCode,
+		FromUserID:    fmt.Sprintf("%d", s.FromUserID),
+		ToUserID:      fmt.Sprintf("%d", s.ToUserID),
+		Amount:        s.Amount,
+		Currency:      s.Currency,
+		ExecuteAt:     s.ExecuteAt,
+		Status:        model.ScheduledTransferStatus(s.Status),
+		TransferID:    s.TransferCode.String,
+		FailureReason: s.FailureReason.String,
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
+	}
+
+	if s.ExecutedAt.Valid {
+		schedule.ExecutedAt = s.ExecutedAt.Time
+	}
+
+	return schedule
+}
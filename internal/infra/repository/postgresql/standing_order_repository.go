@@ -0,0 +1,312 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/event"
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBStandingOrder
+type DBStandingOrder struct {
+	ID                int64          `db:"id"`
+	OrderCode         string         `db:"order_code"`
+	FromUserID        int64          `db:"from_user_id"`
+	ToUserID          int64          `db:"to_user_id"`
+	Amount            int            `db:"amount"`
+	Currency          string         `db:"currency"`
+	Frequency         string         `db:"frequency"`
+	DayOfMonth        sql.NullInt64  `db:"day_of_month"`
+	StartDate         time.Time      `db:"start_date"`
+	EndDate           sql.NullTime   `db:"end_date"`
+	MaxOccurrences    sql.NullInt64  `db:"max_occurrences"`
+	Occurrences       int            `db:"occurrences"`
+	CatchUp           string         `db:"catch_up"`
+	Status            string         `db:"status"`
+	NextRunAt         sql.NullTime   `db:"next_run_at"`
+	LastRunAt         sql.NullTime   `db:"last_run_at"`
+	LastFailureReason sql.NullString `db:"last_failure_reason"`
+	HeldTransferCode  sql.NullString `db:"held_transfer_code"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+}
+
+// standingOrderColumns
+const standingOrderColumns = `id, order_code, from_user_id, to_user_id, amount, currency, frequency, day_of_month,
+		       start_date, end_date, max_occurrences, occurrences, catch_up, status, next_run_at, last_run_at,
+		       last_failure_reason, held_transfer_code, created_at, updated_at`
+
+// StandingOrderRepository
+type StandingOrderRepository struct {
+	db *sqlx.DB
+}
+
+// NewStandingOrderRepository
+func NewStandingOrderRepository(db *sqlx.DB) *StandingOrderRepository {
+	return &StandingOrderRepository{
+		db: db,
+	}
+}
+
+// Create
+func (r *StandingOrderRepository) Create(order *model.StandingOrder) error {
+	var nextID int64
+	if err := r.db.Get(&nextID, `SELECT nextval('money_transfer.standing_orders_id_seq')`); err != nil {
+		return fmt.Errorf("error generating standing order ID: %w", err)
+	}
+
+	order.ID = fmt.Sprintf("STO%d", nextID)
+
+	_, err := r.db.Exec(`
+		INSERT INTO money_transfer.standing_orders (
+			id, order_code, from_user_id, to_user_id, amount, currency, frequency, day_of_month,
+			start_date, end_date, max_occurrences, catch_up, status, next_run_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		)
+	`,
+		nextID,
+		order.ID,
+		order.FromUserID,
+		order.ToUserID,
+		order.Amount,
+		order.Currency,
+		order.Frequency,
+		nullInt(order.DayOfMonth, order.DayOfMonth > 0),
+		order.StartDate,
+		nullTime(order.EndDate),
+		nullInt(order.MaxOccurrences, order.MaxOccurrences > 0),
+		order.CatchUp,
+		order.Status,
+		nullTime(order.NextRunAt),
+		order.CreatedAt,
+		order.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting standing order: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID loads the order with the transfers its runs have made
+func (r *StandingOrderRepository) GetByID(id string) (*model.StandingOrder, error) {
+	var dbOrder DBStandingOrder
+
+	err := r.db.Get(&dbOrder, `
+		SELECT `+standingOrderColumns+`
+		FROM money_transfer.standing_orders
+		WHERE order_code = $1
+	`, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrStandingOrderNotFound
+		}
+		return nil, fmt.Errorf("error getting standing order: %w", err)
+	}
+
+	order := toStandingOrder(dbOrder)
+
+	err = r.db.Select(&order.TransferIDs, `
+		SELECT transfer_code
+		FROM money_transfer.transfers
+		WHERE standing_order_code = $1
+		ORDER BY id
+	`, order.ID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting standing order transfers: %w", err)
+	}
+
+	return order, nil
+}
+
+// List
+func (r *StandingOrderRepository) List() ([]*model.StandingOrder, error) {
+	var dbOrders []DBStandingOrder
+
+	err := r.db.Select(&dbOrders, `
+		SELECT `+standingOrderColumns+`
+		FROM money_transfer.standing_orders
+		ORDER BY id DESC
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing standing orders: %w", err)
+	}
+
+	return toStandingOrders(dbOrders), nil
+}
+
+// ListDue
+func (r *StandingOrderRepository) ListDue(now time.Time, limit int) ([]*model.StandingOrder, error) {
+	var dbOrders []DBStandingOrder
+
+	err := r.db.Select(&dbOrders, `
+		SELECT `+standingOrderColumns+`
+		FROM money_transfer.standing_orders
+		WHERE status = 'ACTIVE' AND next_run_at <= $1
+		ORDER BY next_run_at, id
+		LIMIT $2
+	`, now, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing due standing orders: %w", err)
+	}
+
+	return toStandingOrders(dbOrders), nil
+}
+
+// UpdateStatus changes the status and next run of an order that is still in the from status
+func (r *StandingOrderRepository) UpdateStatus(order *model.StandingOrder, from model.StandingOrderStatus) error {
+	result, err := r.db.Exec(`
+		UPDATE money_transfer.standing_orders
+		SET status = $1, next_run_at = $2, updated_at = $3
+		WHERE order_code = $4 AND status = $5
+	`, order.Status, nullTime(order.NextRunAt), order.UpdatedAt, order.ID, from)
+
+	if err != nil {
+		return fmt.Errorf("error updating standing order status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating standing order status: %w", err)
+	}
+
+	if rows == 0 {
+		return model.ErrInvalidStatusChange
+	}
+
+	return nil
+}
+
+// GetByHeldTransferIDForUpdate locks the order whose run is waiting for the
+// review of the transfer
+func (r *StandingOrderRepository) GetByHeldTransferIDForUpdate(ctx context.Context, tx *sqlx.Tx, transferID string) (*model.StandingOrder, error) {
+	var dbOrder DBStandingOrder
+
+	err := tx.GetContext(ctx, &dbOrder, `
+		SELECT `+standingOrderColumns+`
+		FROM money_transfer.standing_orders
+		WHERE held_transfer_code = $1
+		FOR UPDATE
+	`, transferID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrStandingOrderNotFound
+		}
+		return nil, fmt.Errorf("error getting standing order: %w", err)
+	}
+
+	return toStandingOrder(dbOrder), nil
+}
+
+// HoldRunTx parks the order that was due at due while its run's transfer is
+// held for review. It reports false when another worker has already run it.
+func (r *StandingOrderRepository) HoldRunTx(ctx context.Context, tx *sqlx.Tx, order *model.StandingOrder, due time.Time) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.standing_orders
+		SET status = 'PENDING_REVIEW', held_transfer_code = $1, updated_at = NOW()
+		WHERE order_code = $2 AND status = 'ACTIVE' AND next_run_at = $3
+	`, order.HeldTransferID, order.ID, due)
+
+	if err != nil {
+		return false, fmt.Errorf("error holding standing order run: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error holding standing order run: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// AdvanceTx records a run, or a skipped run, of the order that was due at due,
+// including a run whose held transfer has been reviewed. It reports false
+// when another worker has already advanced the order.
+func (r *StandingOrderRepository) AdvanceTx(ctx context.Context, tx *sqlx.Tx, order *model.StandingOrder, due time.Time) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.standing_orders
+		SET occurrences = $1, status = $2, next_run_at = $3, last_run_at = $4, last_failure_reason = $5,
+		    held_transfer_code = NULL, updated_at = NOW()
+		WHERE order_code = $6 AND status IN ('ACTIVE', 'PENDING_REVIEW') AND next_run_at = $7
+	`,
+		order.Occurrences,
+		order.Status,
+		nullTime(order.NextRunAt),
+		nullTime(order.LastRunAt),
+		nullString(order.LastFailureReason),
+		order.ID,
+		due,
+	)
+
+	if err != nil {
+		return false, fmt.Errorf("error advancing standing order: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error advancing standing order: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// CreateRunFailedEventTx emits an outbox event for a run whose transfer was rejected
+func (r *StandingOrderRepository) CreateRunFailedEventTx(ctx context.Context, tx *sqlx.Tx, order *model.StandingOrder, due time.Time) error {
+	_, err := insertEventTx(ctx, tx, AnyVersion, event.NewStandingOrderRunFailed(order, due))
+	return err
+}
+
+// toStandingOrders
+func toStandingOrders(dbOrders []DBStandingOrder) []*model.StandingOrder {
+	orders := make([]*model.StandingOrder, len(dbOrders))
+	for i, o := range dbOrders {
+		orders[i] = toStandingOrder(o)
+	}
+	return orders
+}
+
+// toStandingOrder
+func toStandingOrder(o DBStandingOrder) *model.StandingOrder {
+	order := &model.StandingOrder{
+		ID:                o.OrderCode,
+		FromUserID:        fmt.Sprintf("%d", o.FromUserID),
+		ToUserID:          fmt.Sprintf("%d", o.ToUserID),
+		Amount:            o.Amount,
+		Currency:          o.Currency,
+		Frequency:         model.Frequency(o.Frequency),
+		DayOfMonth:        int(o.DayOfMonth.Int64),
+		StartDate:         model.Day(o.StartDate),
+		MaxOccurrences:    int(o.MaxOccurrences.Int64),
+		Occurrences:       o.Occurrences,
+		CatchUp:           model.CatchUpPolicy(o.CatchUp),
+		Status:            model.StandingOrderStatus(o.Status),
+		LastFailureReason: o.LastFailureReason.String,
+		HeldTransferID:    o.HeldTransferCode.String,
+		CreatedAt:         o.CreatedAt,
+		UpdatedAt:         o.UpdatedAt,
+	}
+
+	if o.EndDate.Valid {
+		order.EndDate = model.Day(o.EndDate.Time)
+	}
+	if o.NextRunAt.Valid {
+		order.NextRunAt = o.NextRunAt.Time.UTC()
+	}
+	if o.LastRunAt.Valid {
+		order.LastRunAt = o.LastRunAt.Time
+	}
+
+	return order
+}
@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBStatementLine
+type DBStatementLine struct {
+	Reference       string         `db:"reference"`
+	ReversalOf      sql.NullString `db:"reversal_of"`
+	Stan            string         `db:"stan"`
+	TransactionType string         `db:"transaction_type"`
+	CounterpartyID  sql.NullInt64  `db:"counterparty_id"`
+	Note            sql.NullString `db:"note"`
+	Amount          int            `db:"amount"`
+	Fee             int            `db:"fee"`
+	PostedAt        time.Time      `db:"posted_at"`
+}
+
+// userPostings lists everything posted to the balance of user $1 in currency
+// $2: the user's side of each settled transfer, completed deposits, and
+// withdrawals, which are debited when requested and returned if they fail.
+// A card-funded transfer only touches the recipient's balance.
+const userPostings = `(
+		SELECT t.transfer_code AS reference, t.reversal_of, tx.stan,
+		       CASE WHEN t.from_user_id = $1 THEN 'DEBIT' ELSE 'CREDIT' END AS transaction_type,
+		       CASE WHEN t.from_user_id = $1 THEN t.to_user_id ELSE t.from_user_id END AS counterparty_id,
+		       tx.note, tx.amount, tx.fee, t.completed_at AS posted_at
+		FROM money_transfer.transfers t
+		JOIN money_transfer.transactions tx
+			ON tx.id = CASE WHEN t.from_user_id = $1 THEN t.debit_tx_id ELSE t.credit_tx_id END
+		WHERE (t.from_user_id = $1 OR t.to_user_id = $1)
+			AND t.state IN ('COMPLETED', 'PARTIALLY_REVERSED', 'REVERSED')
+			AND t.completed_at IS NOT NULL
+			AND tx.currency = $2
+			AND NOT (t.from_user_id = $1 AND tx.payment_source = 'CARD')
+		UNION ALL
+		SELECT f.funding_code, NULL, tx.stan, tx.transaction_type, NULL,
+		       tx.note, tx.amount, tx.fee,
+		       CASE WHEN f.funding_type = 'WITHDRAWAL' THEN f.created_at ELSE f.completed_at END
+		FROM money_transfer.fundings f
+		JOIN money_transfer.transactions tx ON tx.id = f.tx_id
+		WHERE f.user_id = $1 AND f.currency = $2
+			AND (f.funding_type = 'WITHDRAWAL' OR f.state = 'COMPLETED')
+		UNION ALL
+		SELECT f.funding_code || '-R', f.funding_code, tx.stan, 'CREDIT', NULL,
+		       'Returned: ' || tx.note, tx.amount, 0, f.completed_at
+		FROM money_transfer.fundings f
+		JOIN money_transfer.transactions tx ON tx.id = f.tx_id
+		WHERE f.user_id = $1 AND f.currency = $2
+			AND f.funding_type = 'WITHDRAWAL' AND f.state = 'FAILED'
+	) p`
+
+// StatementRepository
+type StatementRepository struct {
+	db *sqlx.DB
+}
+
+// NewStatementRepository
+func NewStatementRepository(db *sqlx.DB) *StatementRepository {
+	return &StatementRepository{
+		db: db,
+	}
+}
+
+// GetStatement reads the lines of the period and the balance at its end from
+// one snapshot. The closing balance is the cached balance less everything
+// posted since the end of the period.
+func (r *StatementRepository) GetStatement(userID, currency string, from, to time.Time) (*model.Statement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error beginning statement transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var balance int
+	err = tx.GetContext(ctx, &balance, `
+		SELECT amount
+		FROM money_transfer.balances
+		WHERE user_id = $1 AND currency = $2
+	`, userID, currency)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrCurrencyNotHeld
+		}
+		return nil, fmt.Errorf("error getting statement balance: %w", err)
+	}
+
+	var postedSince int
+	err = tx.GetContext(ctx, &postedSince, `
+		SELECT COALESCE(SUM(CASE WHEN p.transaction_type = 'DEBIT' THEN -(p.amount + p.fee) ELSE p.amount END), 0)
+		FROM `+userPostings+`
+		WHERE p.posted_at >= $3
+	`, userID, currency, to)
+
+	if err != nil {
+		return nil, fmt.Errorf("error summing postings after the statement period: %w", err)
+	}
+
+	var dbLines []DBStatementLine
+	err = tx.SelectContext(ctx, &dbLines, `
+		SELECT p.reference, p.reversal_of, p.stan, p.transaction_type, p.counterparty_id,
+		       p.note, p.amount, p.fee, p.posted_at
+		FROM `+userPostings+`
+		WHERE p.posted_at >= $3 AND p.posted_at < $4
+		ORDER BY p.posted_at, p.reference
+	`, userID, currency, from, to)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing statement lines: %w", err)
+	}
+
+	statement := &model.Statement{
+		UserID:         userID,
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		ClosingBalance: balance - postedSince,
+		Lines:          make([]*model.StatementLine, len(dbLines)),
+	}
+
+	for i, l := range dbLines {
+		statement.Lines[i] = toStatementLine(l)
+	}
+
+	return statement, nil
+}
+
+// toStatementLine
+func toStatementLine(l DBStatementLine) *model.StatementLine {
+	line := &model.StatementLine{
+		TransferID: l.Reference,
+		ReversalOf: l.ReversalOf.String,
+		Stan:       model.Stan(l.Stan),
+		Type:       model.TransactionType(l.TransactionType),
+		Note:       l.Note.String,
+		Amount:     l.Amount,
+		Fee:        l.Fee,
+		PostedAt:   l.PostedAt,
+	}
+
+	if l.CounterpartyID.Valid {
+		line.CounterpartyID = fmt.Sprintf("%d", l.CounterpartyID.Int64)
+	}
+
+	return line
+}
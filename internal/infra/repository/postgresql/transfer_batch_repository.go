@@ -0,0 +1,268 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBTransferBatch
+type DBTransferBatch struct {
+	ID          int64        `db:"id"`
+	BatchCode   string       `db:"batch_code"`
+	Mode        string       `db:"mode"`
+	Status      string       `db:"status"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at"`
+	CompletedAt sql.NullTime `db:"completed_at"`
+}
+
+// DBTransferBatchItem
+type DBTransferBatchItem struct {
+	ItemIndex    int            `db:"item_index"`
+	FromUserID   string         `db:"from_user_id"`
+	ToUserID     string         `db:"to_user_id"`
+	Amount       int            `db:"amount"`
+	Currency     string         `db:"currency"`
+	Status       string         `db:"status"`
+	TransferCode sql.NullString `db:"transfer_code"`
+	Error        sql.NullString `db:"error"`
+}
+
+// transferBatchOpen matches batches that were never picked up, or whose worker stopped
+const transferBatchOpen = `(status = 'PENDING' OR (status = 'PROCESSING' AND updated_at < $1))`
+
+// TransferBatchRepository
+type TransferBatchRepository struct {
+	db *sqlx.DB
+}
+
+// NewTransferBatchRepository
+func NewTransferBatchRepository(db *sqlx.DB) *TransferBatchRepository {
+	return &TransferBatchRepository{
+		db: db,
+	}
+}
+
+// Create
+func (r *TransferBatchRepository) Create(batch *model.TransferBatch) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var nextID int64
+	if err = tx.GetContext(ctx, &nextID, `SELECT nextval('money_transfer.transfer_batches_id_seq')`); err != nil {
+		return fmt.Errorf("error generating batch ID: %w", err)
+	}
+
+	batch.ID = fmt.Sprintf("BAT%d", nextID)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO money_transfer.transfer_batches (
+			id, batch_code, mode, status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`, nextID, batch.ID, batch.Mode, batch.Status, batch.CreatedAt, batch.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("error inserting batch: %w", err)
+	}
+
+	for _, item := range batch.Items {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO money_transfer.transfer_batch_items (
+				batch_id, item_index, from_user_id, to_user_id, amount, currency, status, updated_at
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8
+			)
+		`, nextID, item.Index, item.FromUserID, item.ToUserID, item.Amount, item.Currency, item.Status, batch.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("error inserting batch item: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID
+func (r *TransferBatchRepository) GetByID(id string) (*model.TransferBatch, error) {
+	return r.getBatch(context.Background(), r.db, `
+		SELECT id, batch_code, mode, status, created_at, updated_at, completed_at
+		FROM money_transfer.transfer_batches
+		WHERE batch_code = $1
+	`, id)
+}
+
+// GetForUpdate locks the batch so that only one worker settles it
+func (r *TransferBatchRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*model.TransferBatch, error) {
+	return r.getBatch(ctx, tx, `
+		SELECT id, batch_code, mode, status, created_at, updated_at, completed_at
+		FROM money_transfer.transfer_batches
+		WHERE batch_code = $1
+		FOR UPDATE
+	`, id)
+}
+
+// GetByTransferIDForUpdate locks the batch one of whose items made the transfer
+func (r *TransferBatchRepository) GetByTransferIDForUpdate(ctx context.Context, tx *sqlx.Tx, transferID string) (*model.TransferBatch, error) {
+	return r.getBatch(ctx, tx, `
+		SELECT id, batch_code, mode, status, created_at, updated_at, completed_at
+		FROM money_transfer.transfer_batches
+		WHERE id = (
+			SELECT batch_id FROM money_transfer.transfer_batch_items WHERE transfer_code = $1
+		)
+		FOR UPDATE
+	`, transferID)
+}
+
+// getBatch
+func (r *TransferBatchRepository) getBatch(ctx context.Context, q sqlx.QueryerContext, query string, id string) (*model.TransferBatch, error) {
+	var dbBatch DBTransferBatch
+
+	err := sqlx.GetContext(ctx, q, &dbBatch, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("error getting batch: %w", err)
+	}
+
+	var dbItems []DBTransferBatchItem
+	err = sqlx.SelectContext(ctx, q, &dbItems, `
+		SELECT item_index, from_user_id, to_user_id, amount, currency, status, transfer_code, error
+		FROM money_transfer.transfer_batch_items
+		WHERE batch_id = $1
+		ORDER BY item_index
+	`, dbBatch.ID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting batch items: %w", err)
+	}
+
+	batch := &model.TransferBatch{
+		ID:        dbBatch.BatchCode,
+		Mode:      model.BatchMode(dbBatch.Mode),
+		Status:    model.BatchStatus(dbBatch.Status),
+		Items:     make([]*model.TransferBatchItem, len(dbItems)),
+		CreatedAt: dbBatch.CreatedAt,
+		UpdatedAt: dbBatch.UpdatedAt,
+	}
+
+	if dbBatch.CompletedAt.Valid {
+		batch.CompletedAt = dbBatch.CompletedAt.Time
+	}
+
+	for i, item := range dbItems {
+		batch.Items[i] = &model.TransferBatchItem{
+			Index:      item.ItemIndex,
+			FromUserID: item.FromUserID,
+			ToUserID:   item.ToUserID,
+			Amount:     item.Amount,
+			Currency:   item.Currency,
+			Status:     model.BatchItemStatus(item.Status),
+			TransferID: item.TransferCode.String,
+			Error:      item.Error.String,
+		}
+	}
+
+	return batch, nil
+}
+
+// ListOpen returns the IDs of batches waiting for a worker
+func (r *TransferBatchRepository) ListOpen(staleBefore time.Time, limit int) ([]string, error) {
+	var ids []string
+
+	err := r.db.Select(&ids, `
+		SELECT batch_code
+		FROM money_transfer.transfer_batches
+		WHERE `+transferBatchOpen+`
+		ORDER BY id
+		LIMIT $2
+	`, staleBefore, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing open batches: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Claim moves an open batch to PROCESSING and reports whether this caller got it
+func (r *TransferBatchRepository) Claim(id string, now, staleBefore time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE money_transfer.transfer_batches
+		SET status = 'PROCESSING', updated_at = $2
+		WHERE batch_code = $3 AND `+transferBatchOpen+`
+	`, staleBefore, now, id)
+
+	if err != nil {
+		return false, fmt.Errorf("error claiming batch: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming batch: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// UpdateItemTx records an item's result and keeps the batch's claim fresh
+func (r *TransferBatchRepository) UpdateItemTx(ctx context.Context, tx *sqlx.Tx, batch *model.TransferBatch, item *model.TransferBatchItem) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.transfer_batch_items i
+		SET status = $1, transfer_code = $2, error = $3, updated_at = NOW()
+		FROM money_transfer.transfer_batches b
+		WHERE b.id = i.batch_id AND b.batch_code = $4 AND i.item_index = $5
+	`, item.Status, nullString(item.TransferID), nullString(item.Error), batch.ID, item.Index)
+
+	if err != nil {
+		return fmt.Errorf("error updating batch item: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE money_transfer.transfer_batches
+		SET updated_at = NOW()
+		WHERE batch_code = $1
+	`, batch.ID)
+
+	if err != nil {
+		return fmt.Errorf("error updating batch: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatusTx
+func (r *TransferBatchRepository) UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, batch *model.TransferBatch) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.transfer_batches
+		SET status = $1, updated_at = $2, completed_at = $3
+		WHERE batch_code = $4
+	`, batch.Status, batch.UpdatedAt, nullTime(batch.CompletedAt), batch.ID)
+
+	if err != nil {
+		return fmt.Errorf("error updating batch status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBTransferLimit
+type DBTransferLimit struct {
+	ID          int64          `db:"id"`
+	LimitCode   string         `db:"limit_code"`
+	Currency    string         `db:"currency"`
+	AccountTier sql.NullString `db:"account_tier"`
+	UserID      sql.NullInt64  `db:"user_id"`
+	MaxSingle   int            `db:"max_single"`
+	MaxDaily    int            `db:"max_daily"`
+	MaxMonthly  int            `db:"max_monthly"`
+	MaxPerHour  int            `db:"max_per_hour"`
+}
+
+// DBLimitUsage
+type DBLimitUsage struct {
+	Period        string    `db:"period"`
+	PeriodStart   time.Time `db:"period_start"`
+	Amount        int       `db:"amount"`
+	TransferCount int       `db:"transfer_count"`
+}
+
+// TransferLimitRepository
+type TransferLimitRepository struct {
+	db *sqlx.DB
+}
+
+// NewTransferLimitRepository
+func NewTransferLimitRepository(db *sqlx.DB) *TransferLimitRepository {
+	return &TransferLimitRepository{
+		db: db,
+	}
+}
+
+// ListByCurrency
+func (r *TransferLimitRepository) ListByCurrency(currency string) ([]*model.TransferLimit, error) {
+	var dbLimits []DBTransferLimit
+
+	err := r.db.Select(&dbLimits, `
+		SELECT id, limit_code, currency, account_tier, user_id,
+		       max_single, max_daily, max_monthly, max_per_hour
+		FROM money_transfer.transfer_limits
+		WHERE currency = $1
+		ORDER BY id
+	`, currency)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing transfer limits by currency: %w", err)
+	}
+
+	limits := make([]*model.TransferLimit, len(dbLimits))
+	for i, l := range dbLimits {
+		limits[i] = &model.TransferLimit{
+			ID:          l.LimitCode,
+			Currency:    l.Currency,
+			AccountTier: model.AccountTier(l.AccountTier.String),
+			MaxSingle:   l.MaxSingle,
+			MaxDaily:    l.MaxDaily,
+			MaxMonthly:  l.MaxMonthly,
+			MaxPerHour:  l.MaxPerHour,
+		}
+		if l.UserID.Valid {
+			limits[i].UserID = fmt.Sprintf("%d", l.UserID.Int64)
+		}
+	}
+
+	return limits, nil
+}
+
+// GetUsage
+func (r *TransferLimitRepository) GetUsage(userID, currency string, at time.Time) ([]*model.LimitUsage, error) {
+	var dbUsage []DBLimitUsage
+
+	starts := make([]time.Time, len(model.LimitPeriods))
+	for i, p := range model.LimitPeriods {
+		starts[i] = p.Start(at)
+	}
+
+	err := r.db.Select(&dbUsage, `
+		SELECT period, period_start, amount, transfer_count
+		FROM money_transfer.transfer_limit_usage
+		WHERE user_id = $1 AND currency = $2
+		  AND ((period = 'HOUR' AND period_start = $3)
+		    OR (period = 'DAY' AND period_start = $4)
+		    OR (period = 'MONTH' AND period_start = $5))
+	`, userID, currency, starts[0], starts[1], starts[2])
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting transfer limit usage: %w", err)
+	}
+
+	found := make(map[model.LimitPeriod]DBLimitUsage, len(dbUsage))
+	for _, u := range dbUsage {
+		found[model.LimitPeriod(u.Period)] = u
+	}
+
+	usage := make([]*model.LimitUsage, len(model.LimitPeriods))
+	for i, p := range model.LimitPeriods {
+		u := found[p]
+		usage[i] = &model.LimitUsage{
+			Period:      p,
+			PeriodStart: starts[i],
+			Amount:      u.Amount,
+			Count:       u.TransferCount,
+		}
+	}
+
+	return usage, nil
+}
+
+// AddUsageTx adds a transfer's amount and count to the sender's hourly, daily
+// and monthly usage and returns the new totals, hour first. The rows stay
+// locked until the transaction ends.
+func (r *TransferLimitRepository) AddUsageTx(ctx context.Context, tx *sqlx.Tx, userID, currency string, amount, count int, at time.Time) ([]*model.LimitUsage, error) {
+	usage := make([]*model.LimitUsage, len(model.LimitPeriods))
+
+	for i, p := range model.LimitPeriods {
+		var u DBLimitUsage
+
+		err := tx.GetContext(ctx, &u, `
+			INSERT INTO money_transfer.transfer_limit_usage (user_id, currency, period, period_start, amount, transfer_count)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id, currency, period, period_start) DO UPDATE
+			SET amount = money_transfer.transfer_limit_usage.amount + EXCLUDED.amount,
+			    transfer_count = money_transfer.transfer_limit_usage.transfer_count + EXCLUDED.transfer_count
+			RETURNING period, period_start, amount, transfer_count
+		`, userID, currency, p, p.Start(at), amount, count)
+
+		if err != nil {
+			return nil, fmt.Errorf("error updating transfer limit usage: %w", err)
+		}
+
+		usage[i] = &model.LimitUsage{
+			Period:      model.LimitPeriod(u.Period),
+			PeriodStart: u.PeriodStart,
+			Amount:      u.Amount,
+			Count:       u.TransferCount,
+		}
+	}
+
+	return usage, nil
+}
+
+// ReleaseUsageTx gives back the amount of a transfer made at the given time
+// that did not go through. It still counts towards the transfers per hour.
+func (r *TransferLimitRepository) ReleaseUsageTx(ctx context.Context, tx *sqlx.Tx, userID, currency string, amount int, at time.Time) error {
+	for _, p := range model.LimitPeriods {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE money_transfer.transfer_limit_usage
+			SET amount = GREATEST(amount - $5, 0)
+			WHERE user_id = $1 AND currency = $2 AND period = $3 AND period_start = $4
+		`, userID, currency, p, p.Start(at), amount)
+
+		if err != nil {
+			return fmt.Errorf("error releasing transfer limit usage: %w", err)
+		}
+	}
+
+	return nil
+}
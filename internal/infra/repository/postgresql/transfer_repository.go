@@ -3,40 +3,67 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/IskenT/money-transfer/internal/domain/event"
 	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/IskenT/money-transfer/internal/domain/repository"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
 // DBTransaction
 type DBTransaction struct {
-	ID              int64     `db:"id"`
-	Stan            string    `db:"stan"`
-	Amount          int       `db:"amount"`
-	State           string    `db:"state"`
-	TransactionType string    `db:"transaction_type"`
-	PaymentSource   string    `db:"payment_source"`
-	Note            string    `db:"note"`
-	CreatedAt       time.Time `db:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at"`
+	ID              int64          `db:"id"`
+	Stan            string         `db:"stan"`
+	Amount          int            `db:"amount"`
+	Currency        string         `db:"currency"`
+	FXRate          sql.NullString `db:"fx_rate"`
+	FXSpreadBps     sql.NullInt64  `db:"fx_spread_bps"`
+	Fee             int            `db:"fee"`
+	State           string         `db:"state"`
+	TransactionType string         `db:"transaction_type"`
+	PaymentSource   string         `db:"payment_source"`
+	Note            string         `db:"note"`
+	AppID           sql.NullInt64  `db:"app_id"`
+	ProfileID       sql.NullInt64  `db:"profile_id"`
+	CardID          sql.NullInt64  `db:"card_id"`
+	InstanceID      sql.NullString `db:"instance_id"`
+	Card            sql.NullString `db:"card"`
+	CardName        sql.NullString `db:"card_name"`
+	CardAcct        sql.NullString `db:"card_acct"`
+	BankC           sql.NullString `db:"bank_c"`
+	Expiry          sql.NullString `db:"expiry"`
+	AuthCode        sql.NullString `db:"auth_code"`
+	ResponseCode    sql.NullString `db:"response_code"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
 }
 
 // DBTransfer
 type DBTransfer struct {
-	ID           int64         `db:"id"`
-	TransferCode string        `db:"transfer_code"`
-	FromUserID   int64         `db:"from_user_id"`
-	ToUserID     int64         `db:"to_user_id"`
-	Amount       int           `db:"amount"`
-	State        string        `db:"state"`
-	DebitTxID    sql.NullInt64 `db:"debit_tx_id"`
-	CreditTxID   sql.NullInt64 `db:"credit_tx_id"`
-	CreatedAt    time.Time     `db:"created_at"`
-	CompletedAt  sql.NullTime  `db:"completed_at"`
+	ID             int64          `db:"id"`
+	TransferCode   string         `db:"transfer_code"`
+	FromUserID     int64          `db:"from_user_id"`
+	ToUserID       int64          `db:"to_user_id"`
+	Amount         int            `db:"amount"`
+	Currency       string         `db:"currency"`
+	Fee            int            `db:"fee"`
+	QuoteCode      sql.NullString `db:"quote_code"`
+	State          string         `db:"state"`
+	ReversalOf     sql.NullString `db:"reversal_of"`
+	ReversedAmount int            `db:"reversed_amount"`
+	StandingOrder  sql.NullString `db:"standing_order_code"`
+	DebitTxID      sql.NullInt64  `db:"debit_tx_id"`
+	CreditTxID     sql.NullInt64  `db:"credit_tx_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+	CompletedAt    sql.NullTime   `db:"completed_at"`
 }
 
 // DBOutboxEvent
@@ -90,46 +117,17 @@ func (r *TransferRepository) Create(transfer *model.Transfer) error {
 
 // CreateTx
 func (r *TransferRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
-	var debitTxID int64
-	err := tx.QueryRowxContext(ctx, `
-		INSERT INTO money_transfer.transactions (
-			stan, amount, state, transaction_type, payment_source, note, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8
-		) RETURNING id
-	`,
-		transfer.DebitTx.Stan,
-		transfer.DebitTx.Amount,
-		transfer.DebitTx.State,
-		transfer.DebitTx.TransactionType,
-		transfer.DebitTx.PaymentSource,
-		transfer.DebitTx.Note,
-		transfer.DebitTx.CreatedAt,
-		transfer.DebitTx.UpdatedAt,
-	).Scan(&debitTxID)
-
+	debitTxID, err := insertTransactionTx(ctx, tx, transfer.DebitTx)
 	if err != nil {
+		// The STANs have wrapped around within the business date
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "idx_transactions_card_stan" {
+			return model.ErrStanExhausted
+		}
 		return fmt.Errorf("error inserting debit transaction: %w", err)
 	}
 
-	var creditTxID int64
-	err = tx.QueryRowxContext(ctx, `
-		INSERT INTO money_transfer.transactions (
-			stan, amount, state, transaction_type, payment_source, note, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8
-		) RETURNING id
-	`,
-		transfer.CreditTx.Stan,
-		transfer.CreditTx.Amount,
-		transfer.CreditTx.State,
-		transfer.CreditTx.TransactionType,
-		transfer.CreditTx.PaymentSource,
-		transfer.CreditTx.Note,
-		transfer.CreditTx.CreatedAt,
-		transfer.CreditTx.UpdatedAt,
-	).Scan(&creditTxID)
-
+	creditTxID, err := insertTransactionTx(ctx, tx, transfer.CreditTx)
 	if err != nil {
 		return fmt.Errorf("error inserting credit transaction: %w", err)
 	}
@@ -143,17 +141,22 @@ func (r *TransferRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, transfer
 	var transferID int64
 	err = tx.QueryRowxContext(ctx, `
 		INSERT INTO money_transfer.transfers (
-			transfer_code, from_user_id, to_user_id, amount, state, 
-			debit_tx_id, credit_tx_id, created_at, completed_at
+			transfer_code, from_user_id, to_user_id, amount, currency, fee, quote_code, state,
+			reversal_of, standing_order_code, debit_tx_id, credit_tx_id, created_at, completed_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 		) RETURNING id
 	`,
 		transfer.ID,
 		transfer.FromUserID,
 		transfer.ToUserID,
 		transfer.Amount,
+		transfer.Currency,
+		transfer.Fee,
+		nullString(transfer.QuoteID),
 		transfer.State,
+		nullString(transfer.ReversalOf),
+		nullString(transfer.StandingOrderID),
 		debitTxID,
 		creditTxID,
 		transfer.CreatedAt,
@@ -164,46 +167,161 @@ func (r *TransferRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, transfer
 		return fmt.Errorf("error inserting transfer: %w", err)
 	}
 
-	payload, err := json.Marshal(map[string]interface{}{
-		"transfer_id":  transfer.ID,
-		"from_user_id": transfer.FromUserID,
-		"to_user_id":   transfer.ToUserID,
-		"amount":       transfer.Amount,
-		"state":        transfer.State,
-		"created_at":   transfer.CreatedAt,
-		"completed_at": transfer.CompletedAt,
-	})
+	// The transfer's stream starts with this event
+	transfer.Version, err = insertEventTx(ctx, tx, 0, event.NewTransferEvent(transfer))
+	return err
+}
 
-	if err != nil {
-		return fmt.Errorf("error marshaling outbox event payload: %w", err)
+// insertTransactionTx inserts one side of a transfer and returns its row ID.
+// A card transaction's STAN is unique to its terminal and business date.
+func insertTransactionTx(ctx context.Context, tx *sqlx.Tx, t *model.Transaction) (int64, error) {
+	stanDate := sql.NullTime{}
+	if t.InstanceID != "" {
+		stanDate = nullTime(model.BusinessDate(t.CreatedAt))
 	}
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO money_transfer.outbox_events (
-			aggregate_type, aggregate_id, event_type, payload
+	var id int64
+	err := tx.QueryRowxContext(ctx, `
+		INSERT INTO money_transfer.transactions (
+			stan, amount, currency, fx_rate, fx_spread_bps, fee, state, transaction_type, payment_source, note,
+			app_id, profile_id, card_id, instance_id, card, card_name, card_acct, bank_c, expiry, auth_code,
+			response_code, stan_date, created_at, updated_at
 		) VALUES (
-			'transfer', $1, 'transfer_completed', $2
-		)
-	`, transfer.ID, payload)
+			$1, $2, $3, $4::text::numeric, $5, $6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24
+		) RETURNING id
+	`,
+		t.Stan,
+		t.Amount,
+		t.Currency,
+		nullString(t.FXRate),
+		nullInt(t.FXSpreadBps, t.FXRate != ""),
+		t.Fee,
+		t.State,
+		t.TransactionType,
+		t.PaymentSource,
+		t.Note,
+		nullInt(t.AppID, t.AppID != 0),
+		nullInt(int(t.ProfileID), t.ProfileID != 0),
+		nullInt(int(t.CardID), t.CardID != 0),
+		nullString(t.InstanceID),
+		nullString(t.Card),
+		nullString(t.CardName),
+		nullString(t.CardAcct),
+		nullString(t.BankC),
+		nullString(t.Expiry),
+		nullString(t.AuthCode),
+		nullString(t.ResponseCode),
+		stanDate,
+		t.CreatedAt,
+		t.UpdatedAt,
+	).Scan(&id)
+
+	return id, err
+}
+
+// UpdateStateTx moves a transfer and its transactions to the transfer's
+// current state. It fails with ErrVersionConflict unless the transfer's stream
+// is still at the version the transfer was loaded at.
+func (r *TransferRepository) UpdateStateTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
+	completedAt := sql.NullTime{}
+	if !transfer.CompletedAt.IsZero() {
+		completedAt.Time = transfer.CompletedAt
+		completedAt.Valid = true
+	}
+
+	var debitTxID, creditTxID sql.NullInt64
+	err := tx.QueryRowxContext(ctx, `
+		UPDATE money_transfer.transfers
+		SET state = $1, completed_at = $2
+		WHERE transfer_code = $3
+		RETURNING debit_tx_id, credit_tx_id
+	`, transfer.State, completedAt, transfer.ID).Scan(&debitTxID, &creditTxID)
 
 	if err != nil {
-		return fmt.Errorf("error inserting outbox event: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ErrTransferNotFound
+		}
+		return fmt.Errorf("error updating transfer state: %w", err)
 	}
 
-	return nil
+	for _, t := range []struct {
+		id sql.NullInt64
+		tx *model.Transaction
+	}{
+		{debitTxID, transfer.DebitTx},
+		{creditTxID, transfer.CreditTx},
+	} {
+		if !t.id.Valid || t.tx == nil {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE money_transfer.transactions
+			SET state = $1, updated_at = $2, auth_code = $3, response_code = $4
+			WHERE id = $5
+		`, t.tx.State, t.tx.UpdatedAt, nullString(t.tx.AuthCode), nullString(t.tx.ResponseCode), t.id.Int64)
+
+		if err != nil {
+			return fmt.Errorf("error updating transaction state: %w", err)
+		}
+	}
+
+	transfer.Version, err = insertEventTx(ctx, tx, transfer.Version, event.NewTransferEvent(transfer))
+	return err
 }
 
+// transferColumns
+const transferColumns = `id, transfer_code, from_user_id, to_user_id, amount, currency, fee, quote_code, state,
+		       reversal_of, reversed_amount, standing_order_code, debit_tx_id, credit_tx_id, created_at, completed_at`
+
+// transactionColumns
+const transactionColumns = `id, stan, amount, currency, fx_rate::text AS fx_rate, fx_spread_bps, fee, state,
+		       transaction_type, payment_source, note, app_id, profile_id, card_id, instance_id, card, card_name,
+		       card_acct, bank_c, expiry, auth_code, response_code, created_at, updated_at`
+
 // GetByID
 func (r *TransferRepository) GetByID(id string) (*model.Transfer, error) {
-	var dbTransfer DBTransfer
+	return r.getTransfer(context.Background(), r.db, `
+		SELECT `+transferColumns+`
+		FROM money_transfer.transfers
+		WHERE transfer_code = $1
+	`, id)
+}
 
-	err := r.db.Get(&dbTransfer, `
-		SELECT id, transfer_code, from_user_id, to_user_id, amount, state, 
-		       debit_tx_id, credit_tx_id, created_at, completed_at
+// GetForUpdate locks the transfer row for the rest of the transaction
+func (r *TransferRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*model.Transfer, error) {
+	return r.getTransfer(ctx, tx, `
+		SELECT `+transferColumns+`
 		FROM money_transfer.transfers
 		WHERE transfer_code = $1
+		FOR UPDATE
 	`, id)
+}
 
+// UpdateReversalTx records how much of a transfer has been reversed. Like
+// UpdateStateTx, it appends at the transfer's version.
+func (r *TransferRepository) UpdateReversalTx(ctx context.Context, tx *sqlx.Tx, transfer *model.Transfer) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE money_transfer.transfers
+		SET state = $1, reversed_amount = $2
+		WHERE transfer_code = $3
+	`, transfer.State, transfer.ReversedAmount, transfer.ID)
+
+	if err != nil {
+		return fmt.Errorf("error updating transfer reversal: %w", err)
+	}
+
+	transfer.Version, err = insertEventTx(ctx, tx, transfer.Version, event.NewTransferEvent(transfer))
+	return err
+}
+
+// getTransfer
+func (r *TransferRepository) getTransfer(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) (*model.Transfer, error) {
+	var dbTransfer DBTransfer
+
+	err := sqlx.GetContext(ctx, q, &dbTransfer, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, model.ErrTransferNotFound
@@ -211,10 +329,12 @@ func (r *TransferRepository) GetByID(id string) (*model.Transfer, error) {
 		return nil, fmt.Errorf("error getting transfer by ID: %w", err)
 	}
 
-	var debitTx DBTransaction
+	transfer := toTransfer(dbTransfer)
+
 	if dbTransfer.DebitTxID.Valid {
-		err = r.db.Get(&debitTx, `
-			SELECT id, stan, amount, state, transaction_type, payment_source, note, created_at, updated_at
+		var debitTx DBTransaction
+		err = sqlx.GetContext(ctx, q, &debitTx, `
+			SELECT `+transactionColumns+`
 			FROM money_transfer.transactions
 			WHERE id = $1
 		`, dbTransfer.DebitTxID.Int64)
@@ -222,12 +342,15 @@ func (r *TransferRepository) GetByID(id string) (*model.Transfer, error) {
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("error getting debit transaction: %w", err)
 		}
+		if err == nil {
+			transfer.DebitTx = toTransaction(debitTx)
+		}
 	}
 
-	var creditTx DBTransaction
 	if dbTransfer.CreditTxID.Valid {
-		err = r.db.Get(&creditTx, `
-			SELECT id, stan, amount, state, transaction_type, payment_source, note, created_at, updated_at
+		var creditTx DBTransaction
+		err = sqlx.GetContext(ctx, q, &creditTx, `
+			SELECT `+transactionColumns+`
 			FROM money_transfer.transactions
 			WHERE id = $1
 		`, dbTransfer.CreditTxID.Int64)
@@ -235,65 +358,222 @@ func (r *TransferRepository) GetByID(id string) (*model.Transfer, error) {
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("error getting credit transaction: %w", err)
 		}
+		if err == nil {
+			transfer.CreditTx = toTransaction(creditTx)
+		}
 	}
 
-	transfer := &model.Transfer{
-		ID:         dbTransfer.TransferCode,
-		FromUserID: fmt.Sprintf("%d", dbTransfer.FromUserID),
-		ToUserID:   fmt.Sprintf("%d", dbTransfer.ToUserID),
-		Amount:     dbTransfer.Amount,
-		State:      model.TransactionState(dbTransfer.State),
-		CreatedAt:  dbTransfer.CreatedAt,
+	err = sqlx.SelectContext(ctx, q, &transfer.Reversals, `
+		SELECT transfer_code
+		FROM money_transfer.transfers
+		WHERE reversal_of = $1
+		ORDER BY id
+	`, transfer.ID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting transfer reversals: %w", err)
 	}
 
-	if dbTransfer.CompletedAt.Valid {
-		transfer.CompletedAt = dbTransfer.CompletedAt.Time
+	transfer.Version, err = streamVersion(ctx, q, "transfer", transfer.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	if dbTransfer.DebitTxID.Valid {
-		transfer.DebitTx = &model.Transaction{
-			Stan:            model.Stan(debitTx.Stan),
-			Amount:          debitTx.Amount,
-			State:           model.TransactionState(debitTx.State),
-			TransactionType: model.TransactionType(debitTx.TransactionType),
-			PaymentSource:   model.PaymentMethodType(debitTx.PaymentSource),
-			Note:            debitTx.Note,
-			CreatedAt:       debitTx.CreatedAt,
-			UpdatedAt:       debitTx.UpdatedAt,
+	return transfer, nil
+}
+
+// HasPaidTx reports whether the sender has completed a transfer to the recipient before
+func (r *TransferRepository) HasPaidTx(ctx context.Context, tx *sqlx.Tx, fromUserID, toUserID string) (bool, error) {
+	var paid bool
+
+	err := tx.GetContext(ctx, &paid, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM money_transfer.transfers
+			WHERE from_user_id = $1 AND to_user_id = $2
+				AND state IN ('COMPLETED', 'PARTIALLY_REVERSED', 'REVERSED')
+				AND reversal_of IS NULL
+		)
+	`, fromUserID, toUserID)
+
+	if err != nil {
+		return false, fmt.Errorf("error checking earlier transfers to payee: %w", err)
+	}
+
+	return paid, nil
+}
+
+// ListPendingCard
+func (r *TransferRepository) ListPendingCard(createdBefore time.Time, limit int) ([]string, error) {
+	var ids []string
+
+	err := r.db.Select(&ids, `
+		SELECT t.transfer_code
+		FROM money_transfer.transfers t
+		JOIN money_transfer.transactions tx ON tx.id = t.debit_tx_id
+		WHERE t.state = 'PENDING' AND t.created_at < $1 AND tx.payment_source = 'CARD'
+		ORDER BY t.created_at
+		LIMIT $2
+	`, createdBefore, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing pending card transfers: %w", err)
+	}
+
+	return ids, nil
+}
+
+// HasPendingCardTx reports whether a card-funded transfer to or from the user
+// is waiting for the acquirer or for risk review
+func (r *TransferRepository) HasPendingCardTx(ctx context.Context, tx *sqlx.Tx, userID string) (bool, error) {
+	var pending bool
+
+	err := tx.GetContext(ctx, &pending, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM money_transfer.transfers t
+			JOIN money_transfer.transactions tx ON tx.id = t.debit_tx_id
+			WHERE (t.from_user_id = $1 OR t.to_user_id = $1)
+				AND t.state IN ('PENDING', 'HELD') AND tx.payment_source = 'CARD'
+		)
+	`, userID)
+
+	if err != nil {
+		return false, fmt.Errorf("error checking pending card transfers: %w", err)
+	}
+
+	return pending, nil
+}
+
+// transferCursor is the sort key and id of the last transfer of a page
+type transferCursor struct {
+	Key string `json:"k"`
+	ID  int64  `json:"id"`
+}
+
+// List returns one page of transfers in keyset order. The transactions and
+// reversals of the whole page are loaded with one query each.
+func (r *TransferRepository) List(filter repository.ListFilter) (*repository.TransferPage, error) {
+	var conds []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.UserID != "" {
+		switch filter.Direction {
+		case repository.DirectionIncoming:
+			conds = append(conds, "to_user_id = "+arg(filter.UserID))
+		case repository.DirectionOutgoing:
+			conds = append(conds, "from_user_id = "+arg(filter.UserID))
+		default:
+			p := arg(filter.UserID)
+			conds = append(conds, "(from_user_id = "+p+" OR to_user_id = "+p+")")
 		}
 	}
 
-	if dbTransfer.CreditTxID.Valid {
-		transfer.CreditTx = &model.Transaction{
-			Stan:            model.Stan(creditTx.Stan),
-			Amount:          creditTx.Amount,
-			State:           model.TransactionState(creditTx.State),
-			TransactionType: model.TransactionType(creditTx.TransactionType),
-			PaymentSource:   model.PaymentMethodType(creditTx.PaymentSource),
-			Note:            creditTx.Note,
-			CreatedAt:       creditTx.CreatedAt,
-			UpdatedAt:       creditTx.UpdatedAt,
+	if len(filter.States) > 0 {
+		placeholders := make([]string, len(filter.States))
+		for i, state := range filter.States {
+			placeholders[i] = arg(string(state))
 		}
+		conds = append(conds, "state IN ("+strings.Join(placeholders, ", ")+")")
 	}
 
-	return transfer, nil
-}
+	if filter.MinAmount > 0 {
+		conds = append(conds, "amount >= "+arg(filter.MinAmount))
+	}
+	if filter.MaxAmount > 0 {
+		conds = append(conds, "amount <= "+arg(filter.MaxAmount))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		conds = append(conds, "created_at >= "+arg(filter.CreatedFrom))
+	}
+	if !filter.CreatedTo.IsZero() {
+		conds = append(conds, "created_at < "+arg(filter.CreatedTo))
+	}
 
-// List
-func (r *TransferRepository) List() ([]*model.Transfer, error) {
-	var dbTransfers []DBTransfer
+	sortColumn, direction, comparison := "created_at", "ASC", ">"
+	if filter.SortBy == repository.SortByAmount {
+		sortColumn = "amount"
+	}
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
 
-	err := r.db.Select(&dbTransfers, `
-		SELECT id, transfer_code, from_user_id, to_user_id, amount, state, 
-		       debit_tx_id, credit_tx_id, created_at, completed_at
-		FROM money_transfer.transfers
-		ORDER BY created_at DESC
-	`)
+	if filter.Cursor != "" {
+		cursor, err := decodeTransferCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
+		var key interface{}
+		if sortColumn == "amount" {
+			key, err = strconv.Atoi(cursor.Key)
+		} else {
+			key, err = time.Parse(time.RFC3339Nano, cursor.Key)
+		}
+		if err != nil {
+			return nil, model.ErrInvalidCursor
+		}
+
+		conds = append(conds, fmt.Sprintf("(%s, id) %s (%s, %s)", sortColumn, comparison, arg(key), arg(cursor.ID)))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT ` + transferColumns + `
+		FROM money_transfer.transfers`
+	if len(conds) > 0 {
+		query += `
+		WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += fmt.Sprintf(`
+		ORDER BY %[1]s %[2]s, id %[2]s
+		LIMIT %[3]s
+	`, sortColumn, direction, arg(limit+1))
+
+	var dbTransfers []DBTransfer
+	if err := r.db.Select(&dbTransfers, query, args...); err != nil {
 		return nil, fmt.Errorf("error listing transfers: %w", err)
 	}
 
+	page := &repository.TransferPage{}
+	if len(dbTransfers) > limit {
+		dbTransfers = dbTransfers[:limit]
+
+		last := dbTransfers[limit-1]
+		cursor := transferCursor{Key: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID}
+		if sortColumn == "amount" {
+			cursor.Key = strconv.Itoa(last.Amount)
+		}
+		page.NextCursor = encodeTransferCursor(cursor)
+	}
+
+	transfers := make([]*model.Transfer, len(dbTransfers))
+	for i, dbT := range dbTransfers {
+		transfers[i] = toTransfer(dbT)
+	}
+
+	if err := r.loadTransactions(dbTransfers, transfers); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadReversals(transfers); err != nil {
+		return nil, err
+	}
+
+	page.Transfers = transfers
+	return page, nil
+}
+
+// loadTransactions attaches the debit and credit transactions of the transfers
+func (r *TransferRepository) loadTransactions(dbTransfers []DBTransfer, transfers []*model.Transfer) error {
 	var txIDs []int64
 	for _, t := range dbTransfers {
 		if t.DebitTxID.Valid {
@@ -305,32 +585,17 @@ func (r *TransferRepository) List() ([]*model.Transfer, error) {
 	}
 
 	if len(txIDs) == 0 {
-		transfers := make([]*model.Transfer, len(dbTransfers))
-		for i, dbT := range dbTransfers {
-			transfers[i] = &model.Transfer{
-				ID:         dbT.TransferCode,
-				FromUserID: fmt.Sprintf("%d", dbT.FromUserID),
-				ToUserID:   fmt.Sprintf("%d", dbT.ToUserID),
-				Amount:     dbT.Amount,
-				State:      model.TransactionState(dbT.State),
-				CreatedAt:  dbT.CreatedAt,
-			}
-
-			if dbT.CompletedAt.Valid {
-				transfers[i].CompletedAt = dbT.CompletedAt.Time
-			}
-		}
-		return transfers, nil
+		return nil
 	}
 
 	query, args, err := sqlx.In(`
-		SELECT id, stan, amount, state, transaction_type, payment_source, note, created_at, updated_at
+		SELECT `+transactionColumns+`
 		FROM money_transfer.transactions
 		WHERE id IN (?)
 	`, txIDs)
 
 	if err != nil {
-		return nil, fmt.Errorf("error preparing transaction query: %w", err)
+		return fmt.Errorf("error preparing transaction query: %w", err)
 	}
 
 	query = r.db.Rebind(query)
@@ -338,38 +603,15 @@ func (r *TransferRepository) List() ([]*model.Transfer, error) {
 	err = r.db.Select(&dbTransactions, query, args...)
 
 	if err != nil {
-		return nil, fmt.Errorf("error getting transactions: %w", err)
+		return fmt.Errorf("error getting transactions: %w", err)
 	}
 
 	txMap := make(map[int64]*model.Transaction)
 	for _, tx := range dbTransactions {
-		txMap[tx.ID] = &model.Transaction{
-			Stan:            model.Stan(tx.Stan),
-			Amount:          tx.Amount,
-			State:           model.TransactionState(tx.State),
-			TransactionType: model.TransactionType(tx.TransactionType),
-			PaymentSource:   model.PaymentMethodType(tx.PaymentSource),
-			Note:            tx.Note,
-			CreatedAt:       tx.CreatedAt,
-			UpdatedAt:       tx.UpdatedAt,
-		}
+		txMap[tx.ID] = toTransaction(tx)
 	}
 
-	transfers := make([]*model.Transfer, len(dbTransfers))
 	for i, dbT := range dbTransfers {
-		transfers[i] = &model.Transfer{
-			ID:         dbT.TransferCode,
-			FromUserID: fmt.Sprintf("%d", dbT.FromUserID),
-			ToUserID:   fmt.Sprintf("%d", dbT.ToUserID),
-			Amount:     dbT.Amount,
-			State:      model.TransactionState(dbT.State),
-			CreatedAt:  dbT.CreatedAt,
-		}
-
-		if dbT.CompletedAt.Valid {
-			transfers[i].CompletedAt = dbT.CompletedAt.Time
-		}
-
 		if dbT.DebitTxID.Valid {
 			if tx, ok := txMap[dbT.DebitTxID.Int64]; ok {
 				transfers[i].DebitTx = tx
@@ -383,7 +625,124 @@ func (r *TransferRepository) List() ([]*model.Transfer, error) {
 		}
 	}
 
-	return transfers, nil
+	return nil
+}
+
+// loadReversals attaches the codes of the reversals of the transfers
+func (r *TransferRepository) loadReversals(transfers []*model.Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	byCode := make(map[string]*model.Transfer, len(transfers))
+	codes := make([]string, len(transfers))
+	for i, t := range transfers {
+		byCode[t.ID] = t
+		codes[i] = t.ID
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT reversal_of, transfer_code
+		FROM money_transfer.transfers
+		WHERE reversal_of IN (?)
+		ORDER BY id
+	`, codes)
+
+	if err != nil {
+		return fmt.Errorf("error preparing reversal query: %w", err)
+	}
+
+	var reversals []struct {
+		ReversalOf   string `db:"reversal_of"`
+		TransferCode string `db:"transfer_code"`
+	}
+
+	if err := r.db.Select(&reversals, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("error getting transfer reversals: %w", err)
+	}
+
+	for _, rev := range reversals {
+		if original, ok := byCode[rev.ReversalOf]; ok {
+			original.Reversals = append(original.Reversals, rev.TransferCode)
+		}
+	}
+
+	return nil
+}
+
+// encodeTransferCursor
+func encodeTransferCursor(c transferCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTransferCursor
+func decodeTransferCursor(s string) (transferCursor, error) {
+	var c transferCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, model.ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil || c.Key == "" {
+		return c, model.ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// toTransfer
+func toTransfer(dbT DBTransfer) *model.Transfer {
+	transfer := &model.Transfer{
+		ID:              dbT.TransferCode,
+		FromUserID:      fmt.Sprintf("%d", dbT.FromUserID),
+		ToUserID:        fmt.Sprintf("%d", dbT.ToUserID),
+		Amount:          dbT.Amount,
+		Currency:        dbT.Currency,
+		Fee:             dbT.Fee,
+		QuoteID:         dbT.QuoteCode.String,
+		State:           model.TransactionState(dbT.State),
+		ReversalOf:      dbT.ReversalOf.String,
+		ReversedAmount:  dbT.ReversedAmount,
+		StandingOrderID: dbT.StandingOrder.String,
+		CreatedAt:       dbT.CreatedAt,
+	}
+
+	if dbT.CompletedAt.Valid {
+		transfer.CompletedAt = dbT.CompletedAt.Time
+	}
+
+	return transfer
+}
+
+// toTransaction
+func toTransaction(tx DBTransaction) *model.Transaction {
+	return &model.Transaction{
+		Stan:            model.Stan(tx.Stan),
+		Amount:          tx.Amount,
+		Currency:        tx.Currency,
+		Fee:             tx.Fee,
+		FXRate:          tx.FXRate.String,
+		FXSpreadBps:     int(tx.FXSpreadBps.Int64),
+		State:           model.TransactionState(tx.State),
+		TransactionType: model.TransactionType(tx.TransactionType),
+		PaymentSource:   model.PaymentMethodType(tx.PaymentSource),
+		Note:            tx.Note,
+		AppID:           int(tx.AppID.Int64),
+		ProfileID:       uint32(tx.ProfileID.Int64),
+		CardID:          uint32(tx.CardID.Int64),
+		InstanceID:      tx.InstanceID.String,
+		Card:            tx.Card.String,
+		CardName:        tx.CardName.String,
+		CardAcct:        tx.CardAcct.String,
+		BankC:           tx.BankC.String,
+		Expiry:          tx.Expiry.String,
+		AuthCode:        tx.AuthCode.String,
+		ResponseCode:    tx.ResponseCode.String,
+		CreatedAt:       tx.CreatedAt,
+		UpdatedAt:       tx.UpdatedAt,
+	}
 }
 
 // GetTransferIDGenerator
@@ -407,3 +766,18 @@ func (r *TransferRepository) GetTransactionIDGenerator() (func() string, error)
 		return fmt.Sprintf("TRX%d", nextID)
 	}, nil
 }
+
+// nullString
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullInt
+func nullInt(n int, valid bool) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(n), Valid: valid}
+}
+
+// nullTime
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+func TestTransferCursorRoundTrip(t *testing.T) {
+	tests := []transferCursor{
+		{Key: "2024-04-10T12:34:56.123456Z", ID: 42},
+		{Key: "2024-04-10T14:34:56+02:00", ID: 1},
+		{Key: "150000", ID: 9007199254740993},
+	}
+
+	for _, want := range tests {
+		s := encodeTransferCursor(want)
+		got, err := decodeTransferCursor(s)
+		if err != nil || got != want {
+			t.Errorf("decodeTransferCursor(%q) = %+v, %v, want %+v", s, got, err, want)
+		}
+	}
+}
+
+func TestDecodeTransferCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"k":"1","id":1}`))},
+		{"not JSON", encode("k=1&id=1")},
+		{"truncated", encode(`{"k":"2024-04-10T12:34:56Z","id":4`)},
+		{"JSON array", encode(`["2024-04-10T12:34:56Z",42]`)},
+		{"null", encode("null")},
+		{"no key", encode(`{"id":42}`)},
+		{"numeric key", encode(`{"k":150000,"id":42}`)},
+		{"string id", encode(`{"k":"150000","id":"42"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeTransferCursor(tt.cursor); err != model.ErrInvalidCursor {
+				t.Errorf("decodeTransferCursor(%q) error = %v, want %v", tt.cursor, err, model.ErrInvalidCursor)
+			}
+		})
+	}
+}
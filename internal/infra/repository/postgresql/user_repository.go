@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/IskenT/money-transfer/internal/domain/event"
 	"github.com/IskenT/money-transfer/internal/domain/model"
 	"github.com/jmoiron/sqlx"
 )
@@ -15,11 +16,34 @@ import (
 type DBUser struct {
 	ID        int64     `db:"id"`
 	Name      string    `db:"name"`
-	Balance   int       `db:"balance"`
+	Tier      string    `db:"tier"`
+	Status    string    `db:"status"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
+// DBBalance
+type DBBalance struct {
+	UserID    int64     `db:"user_id"`
+	Currency  string    `db:"currency"`
+	Amount    int       `db:"amount"`
+	Held      int       `db:"held"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// balanceColumns
+const balanceColumns = `b.user_id, b.currency, b.amount, COALESCE(h.held, 0) AS held, b.created_at, b.updated_at`
+
+// balancesWithHolds joins each balance to the total of its active holds
+const balancesWithHolds = `money_transfer.balances b
+		LEFT JOIN (
+			SELECT user_id, currency, SUM(amount) AS held
+			FROM money_transfer.holds
+			WHERE status = 'ACTIVE'
+			GROUP BY user_id, currency
+		) h ON h.user_id = b.user_id AND h.currency = b.currency`
+
 // UserRepository
 type UserRepository struct {
 	db *sqlx.DB
@@ -37,8 +61,8 @@ func (r *UserRepository) GetByID(id string) (*model.User, error) {
 	var dbUser DBUser
 
 	err := r.db.Get(&dbUser, `
-		SELECT id, name, balance, created_at, updated_at 
-		FROM money_transfer.users 
+		SELECT id, name, tier, status, created_at, updated_at
+		FROM money_transfer.users
 		WHERE id = $1
 	`, id)
 
@@ -49,20 +73,86 @@ func (r *UserRepository) GetByID(id string) (*model.User, error) {
 		return nil, fmt.Errorf("error getting user by ID: %w", err)
 	}
 
-	return &model.User{
-		ID:      fmt.Sprintf("%d", dbUser.ID),
-		Name:    dbUser.Name,
-		Balance: dbUser.Balance,
-	}, nil
+	var dbBalances []DBBalance
+	err = r.db.Select(&dbBalances, `
+		SELECT `+balanceColumns+`
+		FROM `+balancesWithHolds+`
+		WHERE b.user_id = $1
+		ORDER BY b.currency
+	`, dbUser.ID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting user balances: %w", err)
+	}
+
+	return toUser(dbUser, dbBalances), nil
+}
+
+// Create inserts the user with a zero balance in each of its currencies, opening
+// each balance's stream in the event store, and sets its ID
+func (r *UserRepository) Create(user *model.User) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var id int64
+	err = tx.GetContext(ctx, &id, `
+		INSERT INTO money_transfer.users (name, tier, status)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, user.Name, user.Tier, user.Status)
+
+	if err != nil {
+		return fmt.Errorf("error inserting user: %w", err)
+	}
+
+	for i, b := range user.Balances {
+		var openedAt time.Time
+		err = tx.GetContext(ctx, &openedAt, `
+			INSERT INTO money_transfer.balances (user_id, currency, amount)
+			VALUES ($1, $2, $3)
+			RETURNING created_at
+		`, id, b.Currency, b.Amount)
+
+		if err != nil {
+			return fmt.Errorf("error inserting balance: %w", err)
+		}
+
+		_, err = insertEventTx(ctx, tx, i, &event.BalanceOpened{
+			UserID:   fmt.Sprintf("%d", id),
+			Currency: b.Currency,
+			Balance:  b.Amount,
+			OpenedAt: openedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	user.ID = fmt.Sprintf("%d", id)
+	return nil
 }
 
 // Update
 func (r *UserRepository) Update(user *model.User) error {
 	_, err := r.db.Exec(`
-		UPDATE money_transfer.users 
-		SET name = $1, balance = $2, updated_at = NOW() 
-		WHERE id = $3
-	`, user.Name, user.Balance, user.ID)
+		UPDATE money_transfer.users
+		SET name = $1, tier = $2, status = $3, updated_at = NOW(),
+			closed_at = CASE WHEN $3 = 'CLOSED' THEN COALESCE(closed_at, NOW()) END
+		WHERE id = $4
+	`, user.Name, user.Tier, user.Status, user.ID)
 
 	if err != nil {
 		return fmt.Errorf("error updating user: %w", err)
@@ -76,8 +166,8 @@ func (r *UserRepository) List() ([]*model.User, error) {
 	var dbUsers []DBUser
 
 	err := r.db.Select(&dbUsers, `
-		SELECT id, name, balance, created_at, updated_at 
-		FROM money_transfer.users 
+		SELECT id, name, tier, status, created_at, updated_at
+		FROM money_transfer.users
 		ORDER BY id
 	`)
 
@@ -85,25 +175,37 @@ func (r *UserRepository) List() ([]*model.User, error) {
 		return nil, fmt.Errorf("error listing users: %w", err)
 	}
 
+	var dbBalances []DBBalance
+	err = r.db.Select(&dbBalances, `
+		SELECT `+balanceColumns+`
+		FROM `+balancesWithHolds+`
+		ORDER BY b.user_id, b.currency
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing balances: %w", err)
+	}
+
+	balancesByUser := make(map[int64][]DBBalance)
+	for _, b := range dbBalances {
+		balancesByUser[b.UserID] = append(balancesByUser[b.UserID], b)
+	}
+
 	users := make([]*model.User, len(dbUsers))
 	for i, dbUser := range dbUsers {
-		users[i] = &model.User{
-			ID:      fmt.Sprintf("%d", dbUser.ID),
-			Name:    dbUser.Name,
-			Balance: dbUser.Balance,
-		}
+		users[i] = toUser(dbUser, balancesByUser[dbUser.ID])
 	}
 
 	return users, nil
 }
 
-// GetForUpdate
+// GetForUpdate locks the user row; every balance change takes this lock first
 func (r *UserRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*model.User, error) {
 	var dbUser DBUser
 
 	err := tx.GetContext(ctx, &dbUser, `
-		SELECT id, name, balance, created_at, updated_at 
-		FROM money_transfer.users 
+		SELECT id, name, tier, status, created_at, updated_at
+		FROM money_transfer.users
 		WHERE id = $1
 		FOR UPDATE
 	`, id)
@@ -115,20 +217,36 @@ func (r *UserRepository) GetForUpdate(ctx context.Context, tx *sqlx.Tx, id strin
 		return nil, fmt.Errorf("error getting user by ID with lock: %w", err)
 	}
 
-	return &model.User{
-		ID:      fmt.Sprintf("%d", dbUser.ID),
-		Name:    dbUser.Name,
-		Balance: dbUser.Balance,
-	}, nil
+	var dbBalances []DBBalance
+	err = tx.SelectContext(ctx, &dbBalances, `
+		SELECT `+balanceColumns+`
+		FROM `+balancesWithHolds+`
+		WHERE b.user_id = $1
+		ORDER BY b.currency
+	`, dbUser.ID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting user balances with lock: %w", err)
+	}
+
+	user := toUser(dbUser, dbBalances)
+
+	user.Version, err = streamVersion(ctx, tx, "user", user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
 }
 
-// UpdateTx
+// UpdateTx updates the user's details; balances only change through ledger postings
 func (r *UserRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, user *model.User) error {
 	_, err := tx.ExecContext(ctx, `
-		UPDATE money_transfer.users 
-		SET name = $1, balance = $2, updated_at = NOW() 
-		WHERE id = $3
-	`, user.Name, user.Balance, user.ID)
+		UPDATE money_transfer.users
+		SET name = $1, tier = $2, status = $3, updated_at = NOW(),
+			closed_at = CASE WHEN $3 = 'CLOSED' THEN COALESCE(closed_at, NOW()) END
+		WHERE id = $4
+	`, user.Name, user.Tier, user.Status, user.ID)
 
 	if err != nil {
 		return fmt.Errorf("error updating user in transaction: %w", err)
@@ -136,3 +254,23 @@ func (r *UserRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, user *model.
 
 	return nil
 }
+
+// toUser
+func toUser(dbUser DBUser, dbBalances []DBBalance) *model.User {
+	balances := make([]*model.Balance, len(dbBalances))
+	for i, b := range dbBalances {
+		balances[i] = &model.Balance{
+			Currency: b.Currency,
+			Amount:   b.Amount,
+			Held:     b.Held,
+		}
+	}
+
+	return &model.User{
+		ID:       fmt.Sprintf("%d", dbUser.ID),
+		Name:     dbUser.Name,
+		Tier:     model.AccountTier(dbUser.Tier),
+		Status:   model.UserStatus(dbUser.Status),
+		Balances: balances,
+	}
+}
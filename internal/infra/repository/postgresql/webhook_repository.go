@@ -0,0 +1,479 @@
+package postgresql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+// DBWebhookSubscription
+type DBWebhookSubscription struct {
+	ID               int64     `db:"id"`
+	SubscriptionCode string    `db:"subscription_code"`
+	URL              string    `db:"url"`
+	EventTypes       []byte    `db:"event_types"`
+	Secret           string    `db:"secret"`
+	Active           bool      `db:"active"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// DBWebhookDelivery
+type DBWebhookDelivery struct {
+	ID               int64          `db:"id"`
+	DeliveryCode     string         `db:"delivery_code"`
+	SubscriptionCode string         `db:"subscription_code"`
+	EventID          int64          `db:"event_id"`
+	EventType        string         `db:"event_type"`
+	Status           string         `db:"status"`
+	Attempts         int            `db:"attempts"`
+	NextAttemptAt    time.Time      `db:"next_attempt_at"`
+	LastError        sql.NullString `db:"last_error"`
+	CreatedAt        time.Time      `db:"created_at"`
+	DeliveredAt      sql.NullTime   `db:"delivered_at"`
+}
+
+// DBWebhookAttempt
+type DBWebhookAttempt struct {
+	DeliveryCode string         `db:"delivery_code"`
+	AttemptedAt  time.Time      `db:"attempted_at"`
+	Success      bool           `db:"success"`
+	StatusCode   sql.NullInt32  `db:"status_code"`
+	Error        sql.NullString `db:"error"`
+	ResponseBody sql.NullString `db:"response_body"`
+	DurationMs   int64          `db:"duration_ms"`
+}
+
+// webhookSubscriptionColumns
+const webhookSubscriptionColumns = `id, subscription_code, url, event_types, secret, active, created_at, updated_at`
+
+// webhookDeliveryColumns
+const webhookDeliveryColumns = `id, delivery_code, subscription_code, event_id, event_type, status, attempts, next_attempt_at, last_error, created_at, delivered_at`
+
+// WebhookRepository
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// CreateSubscription
+func (r *WebhookRepository) CreateSubscription(subscription *model.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return fmt.Errorf("error marshaling event types: %w", err)
+	}
+
+	var nextID int64
+	if err := r.db.Get(&nextID, `SELECT nextval('money_transfer.webhook_subscriptions_id_seq')`); err != nil {
+		return fmt.Errorf("error generating webhook subscription ID: %w", err)
+	}
+
+	subscriptionCode := fmt.Sprintf("WHK%d", nextID)
+	_, err = r.db.Exec(`
+		INSERT INTO money_transfer.webhook_subscriptions (
+			id, subscription_code, url, event_types, secret, active, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+	`,
+		nextID,
+		subscriptionCode,
+		subscription.URL,
+		eventTypes,
+		subscription.Secret,
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error inserting webhook subscription: %w", err)
+	}
+
+	subscription.ID = subscriptionCode
+	return nil
+}
+
+// GetSubscription
+func (r *WebhookRepository) GetSubscription(id string) (*model.WebhookSubscription, error) {
+	var dbSubscription DBWebhookSubscription
+
+	err := r.db.Get(&dbSubscription, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM money_transfer.webhook_subscriptions
+		WHERE subscription_code = $1
+	`, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("error getting webhook subscription: %w", err)
+	}
+
+	return toWebhookSubscription(dbSubscription)
+}
+
+// ListSubscriptions
+func (r *WebhookRepository) ListSubscriptions() ([]*model.WebhookSubscription, error) {
+	var dbSubscriptions []DBWebhookSubscription
+
+	err := r.db.Select(&dbSubscriptions, `
+		SELECT `+webhookSubscriptionColumns+`
+		FROM money_transfer.webhook_subscriptions
+		ORDER BY id
+	`)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]*model.WebhookSubscription, len(dbSubscriptions))
+	for i, s := range dbSubscriptions {
+		subscription, err := toWebhookSubscription(s)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions[i] = subscription
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateSubscription
+func (r *WebhookRepository) UpdateSubscription(subscription *model.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return fmt.Errorf("error marshaling event types: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE money_transfer.webhook_subscriptions
+		SET url = $1, event_types = $2, secret = $3, active = $4, updated_at = $5
+		WHERE subscription_code = $6
+	`,
+		subscription.URL,
+		eventTypes,
+		subscription.Secret,
+		subscription.Active,
+		subscription.UpdatedAt,
+		subscription.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error updating webhook subscription: %w", err)
+	}
+
+	return expectRow(result, model.ErrWebhookNotFound, "error updating webhook subscription")
+}
+
+// DeleteSubscription
+func (r *WebhookRepository) DeleteSubscription(id string) error {
+	result, err := r.db.Exec(`
+		DELETE FROM money_transfer.webhook_subscriptions
+		WHERE subscription_code = $1
+	`, id)
+
+	if err != nil {
+		return fmt.Errorf("error deleting webhook subscription: %w", err)
+	}
+
+	return expectRow(result, model.ErrWebhookNotFound, "error deleting webhook subscription")
+}
+
+// CreateDeliveries
+func (r *WebhookRepository) CreateDeliveries(event *model.Event, subscriptionIDs []string) error {
+	for _, subscriptionID := range subscriptionIDs {
+		_, err := r.db.Exec(`
+			INSERT INTO money_transfer.webhook_deliveries (
+				id, delivery_code, subscription_code, event_id, event_type, status, next_attempt_at, created_at
+			)
+			SELECT n, 'WHD' || n, $1, $2, $3, 'PENDING', NOW(), NOW()
+			FROM (SELECT nextval('money_transfer.webhook_deliveries_id_seq') AS n) seq
+			ON CONFLICT (subscription_code, event_id) DO NOTHING
+		`, subscriptionID, event.ID, event.EventType)
+
+		if err != nil {
+			return fmt.Errorf("error queueing event %d for webhook %s: %w", event.ID, subscriptionID, err)
+		}
+	}
+
+	return nil
+}
+
+// ClaimDue
+func (r *WebhookRepository) ClaimDue(now, leaseUntil time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	var dbDeliveries []DBWebhookDelivery
+
+	err := r.db.Select(&dbDeliveries, `
+		UPDATE money_transfer.webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT d.id
+			FROM money_transfer.webhook_deliveries d
+			JOIN money_transfer.webhook_subscriptions s ON s.subscription_code = d.subscription_code
+			WHERE d.status = 'PENDING' AND d.next_attempt_at <= $1 AND s.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns+`
+	`, now, leaseUntil, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error claiming webhook deliveries: %w", err)
+	}
+
+	return toWebhookDeliveries(dbDeliveries), nil
+}
+
+// RecordAttempt
+func (r *WebhookRepository) RecordAttempt(delivery *model.WebhookDelivery, attempt *model.WebhookAttempt) error {
+	var deliveredAt sql.NullTime
+	if !delivery.DeliveredAt.IsZero() {
+		deliveredAt = sql.NullTime{Time: delivery.DeliveredAt, Valid: true}
+	}
+
+	var statusCode sql.NullInt32
+	if attempt.StatusCode != 0 {
+		statusCode = sql.NullInt32{Int32: int32(attempt.StatusCode), Valid: true}
+	}
+
+	_, err := r.db.Exec(`
+		WITH attempt AS (
+			INSERT INTO money_transfer.webhook_delivery_attempts (
+				delivery_code, attempted_at, success, status_code, error, response_body, duration_ms
+			) VALUES (
+				$1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7
+			)
+		)
+		UPDATE money_transfer.webhook_deliveries
+		SET status = $8, attempts = $9, next_attempt_at = $10, last_error = NULLIF($11, ''), delivered_at = $12
+		WHERE delivery_code = $1
+	`,
+		delivery.ID,
+		attempt.AttemptedAt,
+		attempt.Success,
+		statusCode,
+		attempt.Error,
+		attempt.ResponseBody,
+		attempt.Duration.Milliseconds(),
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		deliveredAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error recording webhook attempt: %w", err)
+	}
+
+	return nil
+}
+
+// GetDelivery
+func (r *WebhookRepository) GetDelivery(subscriptionID, id string) (*model.WebhookDelivery, error) {
+	var dbDelivery DBWebhookDelivery
+
+	err := r.db.Get(&dbDelivery, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM money_transfer.webhook_deliveries
+		WHERE subscription_code = $1 AND delivery_code = $2
+	`, subscriptionID, id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("error getting webhook delivery: %w", err)
+	}
+
+	deliveries := toWebhookDeliveries([]DBWebhookDelivery{dbDelivery})
+	if err := r.loadAttempts(deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries[0], nil
+}
+
+// ListDeliveries
+func (r *WebhookRepository) ListDeliveries(subscriptionID string, status model.WebhookDeliveryStatus, limit int) ([]*model.WebhookDelivery, error) {
+	var dbDeliveries []DBWebhookDelivery
+
+	err := r.db.Select(&dbDeliveries, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM money_transfer.webhook_deliveries
+		WHERE subscription_code = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`, subscriptionID, string(status), limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook deliveries: %w", err)
+	}
+
+	deliveries := toWebhookDeliveries(dbDeliveries)
+	if err := r.loadAttempts(deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver
+func (r *WebhookRepository) Redeliver(subscriptionID, id string, now time.Time) error {
+	result, err := r.db.Exec(`
+		UPDATE money_transfer.webhook_deliveries
+		SET status = 'PENDING', attempts = 0, next_attempt_at = $3, last_error = NULL, delivered_at = NULL
+		WHERE subscription_code = $1 AND delivery_code = $2
+	`, subscriptionID, id, now)
+
+	if err != nil {
+		return fmt.Errorf("error redelivering webhook delivery: %w", err)
+	}
+
+	return expectRow(result, model.ErrDeliveryNotFound, "error redelivering webhook delivery")
+}
+
+// GetEvent
+func (r *WebhookRepository) GetEvent(id int64) (*model.Event, error) {
+	var dbEvent struct {
+		ID            int64     `db:"id"`
+		AggregateType string    `db:"aggregate_type"`
+		AggregateID   string    `db:"aggregate_id"`
+		EventType     string    `db:"event_type"`
+		Payload       []byte    `db:"payload"`
+		CreatedAt     time.Time `db:"created_at"`
+	}
+
+	err := r.db.Get(&dbEvent, `
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at
+		FROM money_transfer.outbox_events
+		WHERE id = $1
+	`, id)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting outbox event %d: %w", id, err)
+	}
+
+	return &model.Event{
+		ID:            dbEvent.ID,
+		AggregateType: dbEvent.AggregateType,
+		AggregateID:   dbEvent.AggregateID,
+		EventType:     dbEvent.EventType,
+		Payload:       dbEvent.Payload,
+		CreatedAt:     dbEvent.CreatedAt,
+	}, nil
+}
+
+// loadAttempts attaches the attempt log of the deliveries, oldest first
+func (r *WebhookRepository) loadAttempts(deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	byCode := make(map[string]*model.WebhookDelivery, len(deliveries))
+	codes := make([]string, len(deliveries))
+	for i, d := range deliveries {
+		byCode[d.ID] = d
+		codes[i] = d.ID
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT delivery_code, attempted_at, success, status_code, error, response_body, duration_ms
+		FROM money_transfer.webhook_delivery_attempts
+		WHERE delivery_code IN (?)
+		ORDER BY id
+	`, codes)
+
+	if err != nil {
+		return fmt.Errorf("error preparing webhook attempt query: %w", err)
+	}
+
+	var dbAttempts []DBWebhookAttempt
+	if err := r.db.Select(&dbAttempts, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("error getting webhook attempts: %w", err)
+	}
+
+	for _, a := range dbAttempts {
+		delivery, ok := byCode[a.DeliveryCode]
+		if !ok {
+			continue
+		}
+		delivery.AttemptLog = append(delivery.AttemptLog, &model.WebhookAttempt{
+			AttemptedAt:  a.AttemptedAt,
+			Success:      a.Success,
+			StatusCode:   int(a.StatusCode.Int32),
+			Error:        a.Error.String,
+			ResponseBody: a.ResponseBody.String,
+			Duration:     time.Duration(a.DurationMs) * time.Millisecond,
+		})
+	}
+
+	return nil
+}
+
+// expectRow returns notFound when the statement changed no row
+func expectRow(result sql.Result, notFound error, message string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	if rows == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// toWebhookSubscription
+func toWebhookSubscription(s DBWebhookSubscription) (*model.WebhookSubscription, error) {
+	subscription := &model.WebhookSubscription{
+		ID:        s.SubscriptionCode,
+		URL:       s.URL,
+		Secret:    s.Secret,
+		Active:    s.Active,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(s.EventTypes, &subscription.EventTypes); err != nil {
+		return nil, fmt.Errorf("error unmarshaling event types: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// toWebhookDeliveries
+func toWebhookDeliveries(dbDeliveries []DBWebhookDelivery) []*model.WebhookDelivery {
+	deliveries := make([]*model.WebhookDelivery, len(dbDeliveries))
+	for i, d := range dbDeliveries {
+		deliveries[i] = &model.WebhookDelivery{
+			ID:             d.DeliveryCode,
+			SubscriptionID: d.SubscriptionCode,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Status:         model.WebhookDeliveryStatus(d.Status),
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			LastError:      d.LastError.String,
+			CreatedAt:      d.CreatedAt,
+		}
+		if d.DeliveredAt.Valid {
+			deliveries[i].DeliveredAt = d.DeliveredAt.Time
+		}
+	}
+
+	return deliveries
+}
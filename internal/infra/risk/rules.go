@@ -0,0 +1,234 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+	"gopkg.in/yaml.v2"
+)
+
+// RuleType
+type RuleType string
+
+const (
+	// RuleTypeNewPayeeAmount matches a first transfer to a recipient of at least min_amount
+	RuleTypeNewPayeeAmount RuleType = "NEW_PAYEE_AMOUNT"
+	// RuleTypeVelocity matches a sender over max_per_hour transfers or max_daily_amount
+	RuleTypeVelocity RuleType = "VELOCITY"
+	// RuleTypeRoundAmount matches an amount that is a multiple of multiple_of
+	RuleTypeRoundAmount RuleType = "ROUND_AMOUNT"
+	// RuleTypeNightTime matches a transfer made between from and to in the rule's time zone
+	RuleTypeNightTime RuleType = "NIGHT_TIME"
+)
+
+// Rule is one entry of the rules file. Amounts are in minor units of the
+// currency they are listed under; a currency that is not listed never
+// matches an amount condition. min_amount narrows any rule to transfers of
+// at least that amount.
+type Rule struct {
+	Name           string             `yaml:"name"`
+	Description    string             `yaml:"description"`
+	Type           RuleType           `yaml:"type"`
+	Action         model.RiskDecision `yaml:"action"`
+	MinAmount      map[string]int     `yaml:"min_amount"`
+	MaxPerHour     int                `yaml:"max_per_hour"`
+	MaxDailyAmount map[string]int     `yaml:"max_daily_amount"`
+	MultipleOf     map[string]int     `yaml:"multiple_of"`
+	From           string             `yaml:"from"`
+	To             string             `yaml:"to"`
+	Timezone       string             `yaml:"timezone"`
+
+	location *time.Location
+	from, to int
+}
+
+// RuleSet
+type RuleSet struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Engine is the default RiskEngine: every rule that matches adds its reason,
+// and the strictest action among them is the decision
+type Engine struct {
+	rules []*Rule
+}
+
+// LoadEngine reads and validates a rules file
+func LoadEngine(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading risk rules: %w", err)
+	}
+
+	return ParseEngine(data)
+}
+
+// ParseEngine
+func ParseEngine(data []byte) (*Engine, error) {
+	var set RuleSet
+	if err := yaml.UnmarshalStrict(data, &set); err != nil {
+		return nil, fmt.Errorf("error parsing risk rules: %w", err)
+	}
+
+	names := make(map[string]bool, len(set.Rules))
+	for i, rule := range set.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("risk rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("risk rule %q is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("risk rule %q: %w", rule.Name, err)
+		}
+	}
+
+	return &Engine{rules: set.Rules}, nil
+}
+
+// Assess
+func (e *Engine) Assess(ctx context.Context, check *model.RiskCheck) (*model.RiskAssessment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	assessment := &model.RiskAssessment{
+		Decision: model.RiskDecisionAllow,
+		Reasons:  []string{},
+	}
+
+	for _, rule := range e.rules {
+		if !rule.matches(check) {
+			continue
+		}
+
+		assessment.Reasons = append(assessment.Reasons, rule.reason())
+		if rule.Action.Severity() > assessment.Decision.Severity() {
+			assessment.Decision = rule.Action
+		}
+	}
+
+	return assessment, nil
+}
+
+// validate
+func (r *Rule) validate() error {
+	if r.Action != model.RiskDecisionReview && r.Action != model.RiskDecisionDeny {
+		return fmt.Errorf("action must be REVIEW or DENY, not %q", r.Action)
+	}
+
+	for _, amounts := range []*map[string]int{&r.MinAmount, &r.MaxDailyAmount, &r.MultipleOf} {
+		if err := normalizeAmounts(amounts); err != nil {
+			return err
+		}
+	}
+
+	switch r.Type {
+	case RuleTypeNewPayeeAmount:
+		if len(r.MinAmount) == 0 {
+			return fmt.Errorf("min_amount is required")
+		}
+	case RuleTypeVelocity:
+		if r.MaxPerHour <= 0 && len(r.MaxDailyAmount) == 0 {
+			return fmt.Errorf("max_per_hour or max_daily_amount is required")
+		}
+	case RuleTypeRoundAmount:
+		if len(r.MultipleOf) == 0 {
+			return fmt.Errorf("multiple_of is required")
+		}
+	case RuleTypeNightTime:
+		var err error
+		if r.from, err = parseClock(r.From); err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+		if r.to, err = parseClock(r.To); err != nil {
+			return fmt.Errorf("to: %w", err)
+		}
+		if r.location, err = time.LoadLocation(r.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+
+	return nil
+}
+
+// matches
+func (r *Rule) matches(check *model.RiskCheck) bool {
+	if r.MinAmount != nil {
+		min, ok := r.MinAmount[check.Currency]
+		if !ok || check.Amount < min {
+			return false
+		}
+	}
+
+	switch r.Type {
+	case RuleTypeNewPayeeAmount:
+		return check.NewPayee
+	case RuleTypeVelocity:
+		if r.MaxPerHour > 0 && !check.Bulk && check.TransfersThisHour > r.MaxPerHour {
+			return true
+		}
+		max, ok := r.MaxDailyAmount[check.Currency]
+		return ok && check.AmountToday > max
+	case RuleTypeRoundAmount:
+		multiple, ok := r.MultipleOf[check.Currency]
+		return ok && check.Amount%multiple == 0
+	case RuleTypeNightTime:
+		local := check.At.In(r.location)
+		minute := local.Hour()*60 + local.Minute()
+		if r.from <= r.to {
+			return minute >= r.from && minute < r.to
+		}
+		// The window wraps past midnight
+		return minute >= r.from || minute < r.to
+	}
+
+	return false
+}
+
+// reason
+func (r *Rule) reason() string {
+	if r.Description == "" {
+		return r.Name
+	}
+	return r.Name + ": " + r.Description
+}
+
+// normalizeAmounts keys the amounts by upper-case currency code
+func normalizeAmounts(amounts *map[string]int) error {
+	if *amounts == nil {
+		return nil
+	}
+
+	normalized := make(map[string]int, len(*amounts))
+	for code, amount := range *amounts {
+		currency, err := model.LookupCurrency(code)
+		if err != nil {
+			return fmt.Errorf("%w %q", err, code)
+		}
+		if amount <= 0 {
+			return fmt.Errorf("amount for %s must be positive", currency.Code)
+		}
+		normalized[currency.Code] = amount
+	}
+
+	*amounts = normalized
+	return nil
+}
+
+// parseClock turns "HH:MM" into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
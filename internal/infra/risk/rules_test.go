@@ -0,0 +1,163 @@
+package risk
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	// Time zones resolve without the system's zoneinfo
+	_ "time/tzdata"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// assess runs the check through the rules, failing the test on bad rules
+func assess(t *testing.T, rules string, check *model.RiskCheck) *model.RiskAssessment {
+	t.Helper()
+
+	engine, err := ParseEngine([]byte(rules))
+	if err != nil {
+		t.Fatalf("ParseEngine: %v", err)
+	}
+
+	assessment, err := engine.Assess(context.Background(), check)
+	if err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	return assessment
+}
+
+func TestParseEngineErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		want  string
+	}{
+		{"not YAML", "rules: [", "error parsing risk rules"},
+		{"unknown field", "rules:\n- {name: a, type: ROUND_AMOUNT, action: REVIEW, multiple_of: {USD: 100}, colour: red}", "field colour not found"},
+		{"no name", "rules:\n- {type: ROUND_AMOUNT, action: REVIEW, multiple_of: {USD: 100}}", "risk rule 1 has no name"},
+		{"defined twice", "rules:\n- {name: a, type: ROUND_AMOUNT, action: REVIEW, multiple_of: {USD: 100}}\n- {name: a, type: ROUND_AMOUNT, action: DENY, multiple_of: {USD: 100}}", `risk rule "a" is defined twice`},
+		{"allow action", "rules:\n- {name: a, type: ROUND_AMOUNT, action: ALLOW, multiple_of: {USD: 100}}", "action must be REVIEW or DENY"},
+		{"unknown type", "rules:\n- {name: a, type: COLOUR, action: REVIEW}", `unknown type "COLOUR"`},
+		{"new payee without min_amount", "rules:\n- {name: a, type: NEW_PAYEE_AMOUNT, action: REVIEW}", "min_amount is required"},
+		{"velocity without limits", "rules:\n- {name: a, type: VELOCITY, action: REVIEW}", "max_per_hour or max_daily_amount is required"},
+		{"round amount without multiple_of", "rules:\n- {name: a, type: ROUND_AMOUNT, action: REVIEW}", "multiple_of is required"},
+		{"unknown currency", "rules:\n- {name: a, type: ROUND_AMOUNT, action: REVIEW, multiple_of: {XXX: 100}}", `"XXX"`},
+		{"zero amount", "rules:\n- {name: a, type: ROUND_AMOUNT, action: REVIEW, multiple_of: {USD: 0}}", "amount for USD must be positive"},
+		{"bad from", "rules:\n- {name: a, type: NIGHT_TIME, action: REVIEW, from: '25:00', to: '05:00'}", `from: invalid time "25:00"`},
+		{"missing to", "rules:\n- {name: a, type: NIGHT_TIME, action: REVIEW, from: '01:00'}", `to: invalid time ""`},
+		{"bad timezone", "rules:\n- {name: a, type: NIGHT_TIME, action: REVIEW, from: '01:00', to: '05:00', timezone: Mars/Olympus}", "timezone:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEngine([]byte(tt.rules))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseEngine error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEngineShippedRules(t *testing.T) {
+	if _, err := LoadEngine("../../../config/risk_rules.yaml"); err != nil {
+		t.Fatalf("LoadEngine: %v", err)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	const rules = `
+rules:
+  - name: new_payee
+    description: first transfer to this recipient
+    type: NEW_PAYEE_AMOUNT
+    action: REVIEW
+    min_amount: {usd: 1000}
+  - name: new_payee_large
+    type: NEW_PAYEE_AMOUNT
+    action: DENY
+    min_amount: {USD: 5000}
+  - name: velocity
+    type: VELOCITY
+    action: REVIEW
+    max_per_hour: 3
+    max_daily_amount: {EUR: 10000}
+  - name: round
+    type: ROUND_AMOUNT
+    action: REVIEW
+    multiple_of: {USD: 1000}
+    min_amount: {USD: 2000}
+`
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		check    model.RiskCheck
+		decision model.RiskDecision
+		reasons  []string
+	}{
+		{"nothing matches", model.RiskCheck{Amount: 999, Currency: "USD", At: at, TransfersThisHour: 1}, model.RiskDecisionAllow, nil},
+		{"known payee", model.RiskCheck{Amount: 1500, Currency: "USD", At: at}, model.RiskDecisionAllow, nil},
+		{"new payee", model.RiskCheck{Amount: 1500, Currency: "USD", At: at, NewPayee: true}, model.RiskDecisionReview, []string{"new_payee: first transfer to this recipient"}},
+		{"new payee below min_amount", model.RiskCheck{Amount: 999, Currency: "USD", At: at, NewPayee: true}, model.RiskDecisionAllow, nil},
+		{"currency not listed", model.RiskCheck{Amount: 99999, Currency: "JPY", At: at, NewPayee: true}, model.RiskDecisionAllow, nil},
+		{"strictest action wins", model.RiskCheck{Amount: 5001, Currency: "USD", At: at, NewPayee: true}, model.RiskDecisionDeny, []string{"new_payee: first transfer to this recipient", "new_payee_large"}},
+		{"transfers per hour", model.RiskCheck{Amount: 1, Currency: "USD", At: at, TransfersThisHour: 4}, model.RiskDecisionReview, []string{"velocity"}},
+		{"transfers per hour at the maximum", model.RiskCheck{Amount: 1, Currency: "USD", At: at, TransfersThisHour: 3}, model.RiskDecisionAllow, nil},
+		{"bulk items are not counted per hour", model.RiskCheck{Amount: 1, Currency: "USD", At: at, TransfersThisHour: 40, Bulk: true}, model.RiskDecisionAllow, nil},
+		{"amount per day", model.RiskCheck{Amount: 1, Currency: "EUR", At: at, AmountToday: 10001}, model.RiskDecisionReview, []string{"velocity"}},
+		{"bulk items count per day", model.RiskCheck{Amount: 1, Currency: "EUR", At: at, AmountToday: 10001, Bulk: true}, model.RiskDecisionReview, []string{"velocity"}},
+		{"round amount", model.RiskCheck{Amount: 3000, Currency: "USD", At: at}, model.RiskDecisionReview, []string{"round"}},
+		{"round amount below min_amount", model.RiskCheck{Amount: 1000, Currency: "USD", At: at}, model.RiskDecisionAllow, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := assess(t, rules, &tt.check)
+			if got.Decision != tt.decision {
+				t.Errorf("decision = %s, want %s", got.Decision, tt.decision)
+			}
+			if strings.Join(got.Reasons, "|") != strings.Join(tt.reasons, "|") {
+				t.Errorf("reasons = %q, want %q", got.Reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestNightTimeWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		timezone string
+		at       time.Time
+		want     bool
+	}{
+		{"before the window", "01:00", "05:00", "", time.Date(2024, 1, 1, 0, 59, 0, 0, time.UTC), false},
+		{"window opens", "01:00", "05:00", "", time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), true},
+		{"last minute", "01:00", "05:00", "", time.Date(2024, 1, 1, 4, 59, 0, 0, time.UTC), true},
+		{"window closes", "01:00", "05:00", "", time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC), false},
+		// 02:00 in UTC+9 is 17:00 UTC the day before
+		{"time given in another zone", "01:00", "05:00", "", time.Date(2024, 1, 2, 2, 0, 0, 0, time.FixedZone("UTC+9", 9*3600)), false},
+
+		// 22:00 to 06:00 in Tokyo is 13:00 to 21:00 UTC
+		{"wrapping, before midnight", "22:00", "06:00", "Asia/Tokyo", time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC), true},
+		{"wrapping, after midnight", "22:00", "06:00", "Asia/Tokyo", time.Date(2024, 1, 1, 20, 59, 0, 0, time.UTC), true},
+		{"wrapping, window closes", "22:00", "06:00", "Asia/Tokyo", time.Date(2024, 1, 1, 21, 0, 0, 0, time.UTC), false},
+		{"wrapping, afternoon", "22:00", "06:00", "Asia/Tokyo", time.Date(2024, 1, 1, 5, 0, 0, 0, time.UTC), false},
+		{"wrapping, window opens", "22:00", "06:00", "Asia/Tokyo", time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC), true},
+		// 03:30 UTC is 23:30 in New York in summer but 22:30 in winter
+		{"wrapping in summer time", "23:00", "06:00", "America/New_York", time.Date(2024, 7, 1, 3, 30, 0, 0, time.UTC), true},
+		{"wrapping in winter time", "23:00", "06:00", "America/New_York", time.Date(2024, 1, 15, 3, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := "rules:\n- {name: night, type: NIGHT_TIME, action: REVIEW, from: '" + tt.from + "', to: '" + tt.to + "', timezone: '" + tt.timezone + "'}"
+			got := assess(t, rules, &model.RiskCheck{Amount: 1, Currency: "USD", At: tt.at})
+			if matched := got.Decision == model.RiskDecisionReview; matched != tt.want {
+				t.Errorf("matched at %s = %t, want %t", tt.at, matched, tt.want)
+			}
+		})
+	}
+}
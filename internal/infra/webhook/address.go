@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"fmt"
+	"net/netip"
+	"syscall"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// reservedPrefixes are ranges that are not reachable on the internet, on top
+// of the loopback, private, link-local and multicast ones netip knows about
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// PublicAddress reports whether webhooks may be sent to the address. Loopback,
+// private, link-local (which includes cloud metadata at 169.254.169.254),
+// unspecified, multicast and reserved addresses are refused, also when they
+// are written as IPv4-mapped IPv6 addresses.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// controlDial is the net.Dialer Control of the sender. It runs after the host
+// name was resolved, on the address actually dialled, so a name that resolves
+// to a public address when the subscription is saved and to an internal one
+// later is still refused.
+func controlDial(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("error parsing dialled address %q: %w", address, err)
+	}
+
+	if !PublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", model.ErrWebhookURLNotPublic, addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.1.2.3", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::a9fe:a9fe", false},
+	}
+
+	for _, tt := range tests {
+		if got := PublicAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("PublicAddress(%s) = %t, want %t", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestSenderRefusesPrivateAddresses(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	// localhost resolves to a loopback address, which is only known when dialling
+	urls := []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)}
+
+	delivery := &model.WebhookDelivery{ID: "WHD1"}
+	event := &model.Event{ID: 1, EventType: "transfer_completed", Payload: json.RawMessage(`{}`)}
+
+	for _, allowPrivateNetworks := range []bool{false, true} {
+		s := NewSender(time.Second, allowPrivateNetworks)
+		defer s.Close()
+
+		for _, u := range urls {
+			attempt := s.Send(context.Background(), &model.WebhookSubscription{URL: u, Secret: "0123456789abcdef"}, delivery, event)
+
+			if allowPrivateNetworks && !attempt.Success {
+				t.Errorf("Send to %s with private networks allowed failed: %s", u, attempt.Error)
+			}
+			if !allowPrivateNetworks && (attempt.Success || !strings.Contains(attempt.Error, model.ErrWebhookURLNotPublic.Error())) {
+				t.Errorf("Send to %s = %+v, want it refused", u, attempt)
+			}
+		}
+	}
+
+	if received != len(urls) {
+		t.Errorf("receiver got %d requests, want %d", received, len(urls))
+	}
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IskenT/money-transfer/internal/domain/model"
+)
+
+// EnvelopeVersion is raised whenever the envelope changes incompatibly
+const EnvelopeVersion = 1
+
+// Signature headers
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBody is how much of the receiver's answer is kept in the attempt log
+const maxResponseBody = 1024
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrStaleTimestamp   = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// Envelope is the body of every webhook. ID identifies the delivery and stays
+// the same across retries and redeliveries, so receivers can drop duplicates.
+type Envelope struct {
+	Version       int             `json:"version"`
+	ID            string          `json:"id"`
+	EventID       int64           `json:"event_id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Sender POSTs signed envelopes. Redirects are not followed, and only a 2xx
+// answer counts as delivered. Unless private networks are allowed, only
+// public addresses are dialled, see PublicAddress.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender
+func NewSender(timeout time.Duration, allowPrivateNetworks bool) *Sender {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivateNetworks {
+		dialer.Control = controlDial
+	}
+
+	// No proxy, so that the address checked is the receiver's
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send
+func (s *Sender) Send(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery, event *model.Event) *model.WebhookAttempt {
+	start := time.Now()
+	attempt := &model.WebhookAttempt{AttemptedAt: start}
+
+	fail := func(err error) *model.WebhookAttempt {
+		attempt.Error = err.Error()
+		attempt.Duration = time.Since(start)
+		return attempt
+	}
+
+	body, err := json.Marshal(Envelope{
+		Version:       EnvelopeVersion,
+		ID:            delivery.ID,
+		EventID:       event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		CreatedAt:     event.CreatedAt,
+		Data:          event.Payload,
+	})
+	if err != nil {
+		return fail(fmt.Errorf("error encoding event %d: %w", event.ID, err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return fail(fmt.Errorf("error creating webhook request: %w", err))
+	}
+
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "money-transfer-webhooks/"+strconv.Itoa(EnvelopeVersion))
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, event.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	attempt.ResponseBody = strings.ToValidUTF8(string(answer), "")
+	attempt.Duration = time.Since(start)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("webhook answered %s", resp.Status)
+		return attempt
+	}
+
+	attempt.Success = true
+	return attempt
+}
+
+// Sign returns the X-Webhook-Signature of a body sent at the given Unix time:
+// "v1=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a received webhook the way receivers should: the signature
+// must match and the timestamp must be within tolerance of now, so a captured
+// request cannot be replayed later
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrStaleTimestamp
+	}
+
+	return nil
+}
+
+// Close
+func (s *Sender) Close() {
+	s.client.CloseIdleConnections()
+}
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE money_transfer.idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    transfer_code VARCHAR(50) NOT NULL REFERENCES money_transfer.transfers(transfer_code) DEFERRABLE INITIALLY DEFERRED,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX idx_idempotency_keys_expires_at ON money_transfer.idempotency_keys(expires_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.idempotency_keys;
@@ -0,0 +1,39 @@
+-- +migrate Up
+-- Balances table, one row per user and ISO-4217 currency
+CREATE TABLE money_transfer.balances (
+    user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency)
+);
+
+-- Existing balances were kept in cents of USD
+INSERT INTO money_transfer.balances (user_id, currency, amount)
+SELECT id, 'USD', balance FROM money_transfer.users;
+
+ALTER TABLE money_transfer.users DROP COLUMN balance;
+
+ALTER TABLE money_transfer.transactions ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE money_transfer.transfers ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Seed data for the new currencies
+INSERT INTO money_transfer.balances (user_id, currency, amount) VALUES
+    (1, 'EUR', 5000),
+    (2, 'EUR', 0),
+    (2, 'JPY', 20000),
+    (3, 'JPY', 0);
+
+-- +migrate Down
+ALTER TABLE money_transfer.transfers DROP COLUMN currency;
+ALTER TABLE money_transfer.transactions DROP COLUMN currency;
+
+ALTER TABLE money_transfer.users ADD COLUMN balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0);
+
+UPDATE money_transfer.users u
+SET balance = b.amount
+FROM money_transfer.balances b
+WHERE b.user_id = u.id AND b.currency = 'USD';
+
+DROP TABLE IF EXISTS money_transfer.balances;
@@ -0,0 +1,44 @@
+-- +migrate Up
+-- Mid-market exchange rates: one unit of base_currency buys rate units of quote_currency
+CREATE TABLE money_transfer.fx_rates (
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(24, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base_currency, quote_currency)
+);
+
+-- Quotes lock a customer rate for a short window and can be used once
+CREATE TABLE money_transfer.fx_quotes (
+    id BIGSERIAL PRIMARY KEY,
+    quote_code VARCHAR(50) UNIQUE NOT NULL,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    mid_rate NUMERIC(24, 8) NOT NULL,
+    rate NUMERIC(24, 8) NOT NULL,
+    spread_bps INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE money_transfer.transactions ADD COLUMN fx_rate NUMERIC(24, 8);
+ALTER TABLE money_transfer.transactions ADD COLUMN fx_spread_bps INT;
+ALTER TABLE money_transfer.transfers ADD COLUMN quote_code VARCHAR(50) REFERENCES money_transfer.fx_quotes(quote_code);
+
+-- Initial rates
+INSERT INTO money_transfer.fx_rates (base_currency, quote_currency, rate) VALUES
+    ('USD', 'EUR', 0.92150000),
+    ('USD', 'GBP', 0.79020000),
+    ('USD', 'JPY', 151.50000000),
+    ('USD', 'KZT', 480.25000000),
+    ('USD', 'KWD', 0.30750000),
+    ('EUR', 'JPY', 164.40000000),
+    ('EUR', 'GBP', 0.85750000);
+
+-- +migrate Down
+ALTER TABLE money_transfer.transfers DROP COLUMN quote_code;
+ALTER TABLE money_transfer.transactions DROP COLUMN fx_spread_bps;
+ALTER TABLE money_transfer.transactions DROP COLUMN fx_rate;
+DROP TABLE IF EXISTS money_transfer.fx_quotes;
+DROP TABLE IF EXISTS money_transfer.fx_rates;
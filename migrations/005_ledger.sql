@@ -0,0 +1,46 @@
+-- +migrate Up
+-- Journals group ledger entries that sum to zero per currency
+CREATE TABLE money_transfer.ledger_journals (
+    id BIGSERIAL PRIMARY KEY,
+    journal_code VARCHAR(50) UNIQUE NOT NULL,
+    transfer_code VARCHAR(50) REFERENCES money_transfer.transfers(transfer_code) DEFERRABLE INITIALLY DEFERRED,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_ledger_journals_transfer ON money_transfer.ledger_journals(transfer_code);
+
+-- Ledger entries; a positive amount moves money into the account
+CREATE TABLE money_transfer.ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    journal_id BIGINT NOT NULL REFERENCES money_transfer.ledger_journals(id),
+    account VARCHAR(100) NOT NULL,
+    user_id INT REFERENCES money_transfer.users(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_ledger_entries_journal ON money_transfer.ledger_entries(journal_id);
+CREATE INDEX idx_ledger_entries_account ON money_transfer.ledger_entries(account, currency);
+CREATE INDEX idx_ledger_entries_user ON money_transfer.ledger_entries(user_id, currency) WHERE user_id IS NOT NULL;
+
+-- Open the ledger with the balances held today
+INSERT INTO money_transfer.ledger_journals (journal_code, description)
+VALUES ('JRN-OPENING', 'Opening balances');
+
+INSERT INTO money_transfer.ledger_entries (journal_id, account, user_id, currency, amount)
+SELECT j.id, 'user:' || b.user_id, b.user_id, b.currency, b.amount
+FROM money_transfer.balances b
+CROSS JOIN money_transfer.ledger_journals j
+WHERE j.journal_code = 'JRN-OPENING' AND b.amount <> 0;
+
+INSERT INTO money_transfer.ledger_entries (journal_id, account, user_id, currency, amount)
+SELECT j.id, 'system:opening_balance', NULL, b.currency, -SUM(b.amount)
+FROM money_transfer.balances b
+CROSS JOIN money_transfer.ledger_journals j
+WHERE j.journal_code = 'JRN-OPENING'
+GROUP BY j.id, b.currency
+HAVING SUM(b.amount) <> 0;
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.ledger_entries;
+DROP TABLE IF EXISTS money_transfer.ledger_journals;
@@ -0,0 +1,54 @@
+-- +migrate Up
+ALTER TABLE money_transfer.users ADD COLUMN tier VARCHAR(20) NOT NULL DEFAULT 'STANDARD';
+
+-- Fee schedules; a NULL account_tier or payment_method matches any
+CREATE TABLE money_transfer.fee_schedules (
+    id SERIAL PRIMARY KEY,
+    schedule_code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    fee_type VARCHAR(20) NOT NULL CHECK (fee_type IN ('FLAT', 'PERCENTAGE', 'TIERED')),
+    flat_amount BIGINT NOT NULL DEFAULT 0 CHECK (flat_amount >= 0),
+    percentage_bps INT NOT NULL DEFAULT 0 CHECK (percentage_bps >= 0),
+    min_fee BIGINT NOT NULL DEFAULT 0 CHECK (min_fee >= 0),
+    max_fee BIGINT NOT NULL DEFAULT 0 CHECK (max_fee >= 0),
+    account_tier VARCHAR(20),
+    payment_method VARCHAR(20),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (currency, account_tier, payment_method)
+);
+
+-- Tiers of a TIERED schedule; up_to_amount NULL is the open-ended last tier
+CREATE TABLE money_transfer.fee_schedule_tiers (
+    schedule_id INT NOT NULL REFERENCES money_transfer.fee_schedules(id) ON DELETE CASCADE,
+    up_to_amount BIGINT,
+    flat_amount BIGINT NOT NULL DEFAULT 0 CHECK (flat_amount >= 0),
+    percentage_bps INT NOT NULL DEFAULT 0 CHECK (percentage_bps >= 0)
+);
+CREATE INDEX idx_fee_schedule_tiers_schedule ON money_transfer.fee_schedule_tiers(schedule_id);
+
+ALTER TABLE money_transfer.transactions ADD COLUMN fee BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE money_transfer.transfers ADD COLUMN fee BIGINT NOT NULL DEFAULT 0;
+
+-- Initial schedules
+UPDATE money_transfer.users SET tier = 'PREMIUM' WHERE id = 1;
+
+INSERT INTO money_transfer.fee_schedules
+    (schedule_code, name, currency, fee_type, flat_amount, percentage_bps, min_fee, max_fee, account_tier, payment_method)
+VALUES
+    ('FEE-USD-STANDARD', 'USD standard', 'USD', 'PERCENTAGE', 0, 100, 50, 500, NULL, NULL),
+    ('FEE-USD-PREMIUM', 'USD premium', 'USD', 'TIERED', 0, 0, 0, 1000, 'PREMIUM', NULL),
+    ('FEE-EUR-STANDARD', 'EUR standard', 'EUR', 'FLAT', 25, 0, 0, 0, NULL, NULL),
+    ('FEE-JPY-STANDARD', 'JPY standard', 'JPY', 'FLAT', 100, 0, 0, 0, NULL, NULL);
+
+INSERT INTO money_transfer.fee_schedule_tiers (schedule_id, up_to_amount, flat_amount, percentage_bps)
+SELECT id, 10000, 0, 0 FROM money_transfer.fee_schedules WHERE schedule_code = 'FEE-USD-PREMIUM'
+UNION ALL
+SELECT id, NULL, 0, 50 FROM money_transfer.fee_schedules WHERE schedule_code = 'FEE-USD-PREMIUM';
+
+-- +migrate Down
+ALTER TABLE money_transfer.transfers DROP COLUMN fee;
+ALTER TABLE money_transfer.transactions DROP COLUMN fee;
+DROP TABLE IF EXISTS money_transfer.fee_schedule_tiers;
+DROP TABLE IF EXISTS money_transfer.fee_schedules;
+ALTER TABLE money_transfer.users DROP COLUMN tier;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- A reversal is a compensating transfer linked back to the one it undoes
+ALTER TABLE money_transfer.transfers ADD COLUMN reversal_of VARCHAR(50) REFERENCES money_transfer.transfers(transfer_code);
+ALTER TABLE money_transfer.transfers ADD COLUMN reversed_amount BIGINT NOT NULL DEFAULT 0 CHECK (reversed_amount >= 0);
+CREATE INDEX idx_transfers_reversal_of ON money_transfer.transfers(reversal_of);
+
+-- A forced reversal may take the original recipient below zero;
+-- ordinary transfers still check for sufficient funds in the service
+ALTER TABLE money_transfer.balances DROP CONSTRAINT balances_amount_check;
+
+-- +migrate Down
+ALTER TABLE money_transfer.balances ADD CONSTRAINT balances_amount_check CHECK (amount >= 0);
+DROP INDEX IF EXISTS money_transfer.idx_transfers_reversal_of;
+ALTER TABLE money_transfer.transfers DROP COLUMN IF EXISTS reversed_amount;
+ALTER TABLE money_transfer.transfers DROP COLUMN IF EXISTS reversal_of;
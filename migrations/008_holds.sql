@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Funds reserved by an authorized transfer until it is captured, voided or expires.
+-- Active holds reduce the available balance; the ledger balance only changes on capture.
+CREATE TABLE money_transfer.holds (
+    id BIGSERIAL PRIMARY KEY,
+    transfer_code VARCHAR(50) UNIQUE NOT NULL REFERENCES money_transfer.transfers(transfer_code) DEFERRABLE INITIALLY DEFERRED,
+    user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'CAPTURED', 'VOIDED', 'EXPIRED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_holds_active_user ON money_transfer.holds(user_id, currency) WHERE status = 'ACTIVE';
+CREATE INDEX idx_holds_active_expiry ON money_transfer.holds(expires_at) WHERE status = 'ACTIVE';
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.holds;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Transfers submitted now and executed by the scheduler at execute_at.
+-- A row is claimed by moving it to PROCESSING before its transfer runs.
+CREATE TABLE money_transfer.scheduled_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    schedule_code VARCHAR(50) UNIQUE NOT NULL,
+    from_user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    to_user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    execute_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED' CHECK (status IN ('SCHEDULED', 'PROCESSING', 'COMPLETED', 'FAILED', 'CANCELLED')),
+    transfer_code VARCHAR(50) REFERENCES money_transfer.transfers(transfer_code),
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    executed_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_scheduled_transfers_due ON money_transfer.scheduled_transfers(execute_at) WHERE status IN ('SCHEDULED', 'PROCESSING');
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.scheduled_transfers;
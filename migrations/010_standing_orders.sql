@@ -0,0 +1,34 @@
+-- +migrate Up
+-- Recurring transfers. next_run_at is the start of the UTC day of the next run,
+-- NULL once the order has completed.
+CREATE TABLE money_transfer.standing_orders (
+    id BIGSERIAL PRIMARY KEY,
+    order_code VARCHAR(50) UNIQUE NOT NULL,
+    from_user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    to_user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY', 'LAST_BUSINESS_DAY')),
+    day_of_month INT CHECK (day_of_month BETWEEN 1 AND 31),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    max_occurrences INT CHECK (max_occurrences > 0),
+    occurrences INT NOT NULL DEFAULT 0,
+    catch_up VARCHAR(20) NOT NULL DEFAULT 'SKIP' CHECK (catch_up IN ('SKIP', 'RUN_ONCE')),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'PAUSED', 'COMPLETED')),
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (end_date IS NOT NULL OR max_occurrences IS NOT NULL)
+);
+CREATE INDEX idx_standing_orders_due ON money_transfer.standing_orders(next_run_at) WHERE status = 'ACTIVE';
+
+ALTER TABLE money_transfer.transfers ADD COLUMN standing_order_code VARCHAR(50) REFERENCES money_transfer.standing_orders(order_code);
+CREATE INDEX idx_transfers_standing_order ON money_transfer.transfers(standing_order_code);
+
+-- +migrate Down
+DROP INDEX IF EXISTS money_transfer.idx_transfers_standing_order;
+ALTER TABLE money_transfer.transfers DROP COLUMN IF EXISTS standing_order_code;
+DROP TABLE IF EXISTS money_transfer.standing_orders;
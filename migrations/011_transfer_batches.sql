@@ -0,0 +1,32 @@
+-- +migrate Up
+-- Batches of transfers submitted in one call and processed in the background
+CREATE TABLE money_transfer.transfer_batches (
+    id BIGSERIAL PRIMARY KEY,
+    batch_code VARCHAR(50) UNIQUE NOT NULL,
+    mode VARCHAR(20) NOT NULL CHECK (mode IN ('ATOMIC', 'BEST_EFFORT')),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'PARTIALLY_COMPLETED', 'FAILED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_transfer_batches_open ON money_transfer.transfer_batches(updated_at) WHERE status IN ('PENDING', 'PROCESSING');
+
+-- User IDs are not foreign keys so that an unknown user fails its item, not the whole batch
+CREATE TABLE money_transfer.transfer_batch_items (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id BIGINT NOT NULL REFERENCES money_transfer.transfer_batches(id) ON DELETE CASCADE,
+    item_index INT NOT NULL,
+    from_user_id VARCHAR(50) NOT NULL,
+    to_user_id VARCHAR(50) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'COMPLETED', 'FAILED', 'ROLLED_BACK')),
+    transfer_code VARCHAR(50) REFERENCES money_transfer.transfers(transfer_code),
+    error TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (batch_id, item_index)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.transfer_batch_items;
+DROP TABLE IF EXISTS money_transfer.transfer_batches;
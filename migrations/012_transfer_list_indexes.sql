@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE INDEX idx_transfers_created_at ON money_transfer.transfers(created_at, id);
+CREATE INDEX idx_transfers_from_user_created_at ON money_transfer.transfers(from_user_id, created_at, id);
+CREATE INDEX idx_transfers_to_user_created_at ON money_transfer.transfers(to_user_id, created_at, id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS money_transfer.idx_transfers_to_user_created_at;
+DROP INDEX IF EXISTS money_transfer.idx_transfers_from_user_created_at;
+DROP INDEX IF EXISTS money_transfer.idx_transfers_created_at;
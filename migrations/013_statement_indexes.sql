@@ -0,0 +1,8 @@
+-- +migrate Up
+-- Statements select the settled transfers of a user by completion time
+CREATE INDEX idx_transfers_from_user_completed_at ON money_transfer.transfers(from_user_id, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX idx_transfers_to_user_completed_at ON money_transfer.transfers(to_user_id, completed_at) WHERE completed_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS money_transfer.idx_transfers_to_user_completed_at;
+DROP INDEX IF EXISTS money_transfer.idx_transfers_from_user_completed_at;
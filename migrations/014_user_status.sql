@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE money_transfer.users
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED')),
+    ADD COLUMN closed_at TIMESTAMP WITH TIME ZONE;
+
+-- +migrate Down
+ALTER TABLE money_transfer.users
+    DROP COLUMN closed_at,
+    DROP COLUMN status;
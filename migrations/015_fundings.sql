@@ -0,0 +1,29 @@
+-- +migrate Up
+-- Deposits and withdrawals between user balances and external cards or bank accounts
+CREATE TABLE money_transfer.fundings (
+    id BIGSERIAL PRIMARY KEY,
+    funding_code VARCHAR(50) UNIQUE NOT NULL,
+    funding_type VARCHAR(20) NOT NULL CHECK (funding_type IN ('DEPOSIT', 'WITHDRAWAL')),
+    user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    payment_method VARCHAR(20) NOT NULL CHECK (payment_method IN ('CARD', 'BANK')),
+    external_account VARCHAR(100) NOT NULL,
+    provider_ref VARCHAR(100),
+    state VARCHAR(20) NOT NULL CHECK (state IN ('PENDING', 'COMPLETED', 'FAILED')),
+    failure_reason TEXT,
+    tx_id BIGINT NOT NULL REFERENCES money_transfer.transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_fundings_user ON money_transfer.fundings(user_id, currency);
+CREATE INDEX idx_fundings_pending ON money_transfer.fundings(updated_at) WHERE state = 'PENDING';
+
+ALTER TABLE money_transfer.ledger_journals
+    ADD COLUMN funding_code VARCHAR(50) REFERENCES money_transfer.fundings(funding_code) DEFERRABLE INITIALLY DEFERRED;
+CREATE INDEX idx_ledger_journals_funding ON money_transfer.ledger_journals(funding_code);
+
+-- +migrate Down
+ALTER TABLE money_transfer.ledger_journals DROP COLUMN funding_code;
+DROP TABLE IF EXISTS money_transfer.fundings;
@@ -0,0 +1,63 @@
+-- +migrate Up
+-- Cards registered to users; the acquirer's token stands for the card number,
+-- which is only kept masked
+CREATE TABLE money_transfer.cards (
+    id SERIAL PRIMARY KEY,
+    card_code VARCHAR(50) UNIQUE NOT NULL,
+    user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    token VARCHAR(100) NOT NULL,
+    masked_pan VARCHAR(19) NOT NULL,
+    brand VARCHAR(20) NOT NULL,
+    holder_name VARCHAR(100) NOT NULL,
+    expiry_month SMALLINT NOT NULL CHECK (expiry_month BETWEEN 1 AND 12),
+    expiry_year SMALLINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, token)
+);
+
+-- Last STAN issued per terminal and business day
+CREATE TABLE money_transfer.stan_counters (
+    terminal_id VARCHAR(50) NOT NULL,
+    business_date DATE NOT NULL,
+    last_stan INT NOT NULL CHECK (last_stan BETWEEN 1 AND 999999),
+    PRIMARY KEY (terminal_id, business_date)
+);
+
+ALTER TABLE money_transfer.transactions
+    ADD COLUMN app_id INT,
+    ADD COLUMN profile_id BIGINT,
+    ADD COLUMN card_id INT REFERENCES money_transfer.cards(id),
+    ADD COLUMN instance_id VARCHAR(50),
+    ADD COLUMN card VARCHAR(19),
+    ADD COLUMN card_name VARCHAR(100),
+    ADD COLUMN card_acct VARCHAR(100),
+    ADD COLUMN bank_c VARCHAR(11),
+    ADD COLUMN expiry VARCHAR(5),
+    ADD COLUMN auth_code VARCHAR(12),
+    ADD COLUMN response_code VARCHAR(2),
+    ADD COLUMN stan_date DATE;
+
+-- A STAN identifies one card payment per terminal and day
+CREATE UNIQUE INDEX idx_transactions_card_stan ON money_transfer.transactions(instance_id, stan_date, stan)
+    WHERE payment_source = 'CARD' AND transaction_type = 'DEBIT';
+
+CREATE INDEX idx_transfers_pending ON money_transfer.transfers(created_at) WHERE state = 'PENDING';
+
+-- +migrate Down
+DROP INDEX IF EXISTS money_transfer.idx_transfers_pending;
+DROP INDEX IF EXISTS money_transfer.idx_transactions_card_stan;
+ALTER TABLE money_transfer.transactions
+    DROP COLUMN stan_date,
+    DROP COLUMN response_code,
+    DROP COLUMN auth_code,
+    DROP COLUMN expiry,
+    DROP COLUMN bank_c,
+    DROP COLUMN card_acct,
+    DROP COLUMN card_name,
+    DROP COLUMN card,
+    DROP COLUMN instance_id,
+    DROP COLUMN card_id,
+    DROP COLUMN profile_id,
+    DROP COLUMN app_id;
+DROP TABLE IF EXISTS money_transfer.stan_counters;
+DROP TABLE IF EXISTS money_transfer.cards;
@@ -0,0 +1,44 @@
+-- +migrate Up
+-- Limits on a sender's outgoing transfers in one currency. A limit with a
+-- user_id applies to that user, one with an account_tier to the tier, and one
+-- with neither is the default. A zero maximum means no limit.
+CREATE TABLE money_transfer.transfer_limits (
+    id SERIAL PRIMARY KEY,
+    limit_code VARCHAR(50) UNIQUE NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    account_tier VARCHAR(20),
+    user_id INT REFERENCES money_transfer.users(id),
+    max_single BIGINT NOT NULL DEFAULT 0 CHECK (max_single >= 0),
+    max_daily BIGINT NOT NULL DEFAULT 0 CHECK (max_daily >= 0),
+    max_monthly BIGINT NOT NULL DEFAULT 0 CHECK (max_monthly >= 0),
+    max_per_hour INT NOT NULL DEFAULT 0 CHECK (max_per_hour >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (account_tier IS NULL OR user_id IS NULL),
+    UNIQUE (currency, account_tier, user_id)
+);
+
+-- What each sender has transferred per currency in each UTC hour, day and
+-- month. Transfers update these rows while the sender is locked, so
+-- concurrent transfers by the same sender conflict instead of both passing.
+CREATE TABLE money_transfer.transfer_limit_usage (
+    user_id INT NOT NULL REFERENCES money_transfer.users(id),
+    currency VARCHAR(3) NOT NULL,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('HOUR', 'DAY', 'MONTH')),
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount BIGINT NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    transfer_count INT NOT NULL DEFAULT 0 CHECK (transfer_count >= 0),
+    PRIMARY KEY (user_id, currency, period, period_start)
+);
+
+-- Initial limits
+INSERT INTO money_transfer.transfer_limits
+    (limit_code, currency, account_tier, max_single, max_daily, max_monthly, max_per_hour)
+VALUES
+    ('LIM-USD-STANDARD', 'USD', NULL, 500000, 1000000, 5000000, 10),
+    ('LIM-USD-PREMIUM', 'USD', 'PREMIUM', 2500000, 5000000, 25000000, 50),
+    ('LIM-EUR-STANDARD', 'EUR', NULL, 500000, 1000000, 5000000, 10),
+    ('LIM-JPY-STANDARD', 'JPY', NULL, 500000, 1000000, 5000000, 10);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.transfer_limit_usage;
+DROP TABLE IF EXISTS money_transfer.transfer_limits;
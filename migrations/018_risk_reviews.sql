@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Transfers the risk engine held for review, with the rules that matched and
+-- the reviewer's decision
+CREATE TABLE money_transfer.risk_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    review_code VARCHAR(50) UNIQUE NOT NULL,
+    transfer_code VARCHAR(50) UNIQUE NOT NULL REFERENCES money_transfer.transfers(transfer_code),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'EXPIRED')),
+    reasons JSONB NOT NULL DEFAULT '[]',
+    authorize BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewer VARCHAR(100),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_risk_reviews_status ON money_transfer.risk_reviews(status, created_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.risk_reviews;
@@ -0,0 +1,23 @@
+-- +migrate Up
+-- Delivery state of outbox events: a failed event is retried with backoff
+-- until it is processed or has failed too often and is dead-lettered
+ALTER TABLE money_transfer.outbox_events
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'PROCESSED', 'DEAD')),
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN last_error TEXT;
+
+UPDATE money_transfer.outbox_events SET status = 'PROCESSED' WHERE processed_at IS NOT NULL;
+
+DROP INDEX IF EXISTS money_transfer.idx_outbox_unprocessed;
+CREATE INDEX idx_outbox_due ON money_transfer.outbox_events(next_attempt_at) WHERE status = 'PENDING';
+
+-- +migrate Down
+DROP INDEX IF EXISTS money_transfer.idx_outbox_due;
+CREATE INDEX idx_outbox_unprocessed ON money_transfer.outbox_events(processed_at) WHERE processed_at IS NULL;
+
+ALTER TABLE money_transfer.outbox_events
+    DROP COLUMN last_error,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN attempts,
+    DROP COLUMN status;
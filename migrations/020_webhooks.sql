@@ -0,0 +1,47 @@
+-- +migrate Up
+-- Webhook subscriptions, one delivery per subscription and outbox event, and
+-- every attempt made to deliver it
+CREATE TABLE money_transfer.webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_code VARCHAR(50) UNIQUE NOT NULL,
+    url TEXT NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    secret VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE money_transfer.webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_code VARCHAR(50) UNIQUE NOT NULL,
+    subscription_code VARCHAR(50) NOT NULL REFERENCES money_transfer.webhook_subscriptions(subscription_code) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES money_transfer.outbox_events(id),
+    event_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (subscription_code, event_id)
+);
+CREATE INDEX idx_webhook_deliveries_due ON money_transfer.webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_deliveries_subscription ON money_transfer.webhook_deliveries(subscription_code, id);
+
+CREATE TABLE money_transfer.webhook_delivery_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_code VARCHAR(50) NOT NULL REFERENCES money_transfer.webhook_deliveries(delivery_code) ON DELETE CASCADE,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INT,
+    error TEXT,
+    response_body TEXT,
+    duration_ms INT NOT NULL
+);
+CREATE INDEX idx_webhook_delivery_attempts_delivery ON money_transfer.webhook_delivery_attempts(delivery_code, id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.webhook_delivery_attempts;
+DROP TABLE IF EXISTS money_transfer.webhook_deliveries;
+DROP TABLE IF EXISTS money_transfer.webhook_subscriptions;
@@ -0,0 +1,113 @@
+-- +migrate Up
+-- Append-only stream of every aggregate's events. The version of an event is
+-- its position in its aggregate's stream; the unique key makes concurrent
+-- appends at the same version fail instead of interleaving.
+CREATE TABLE money_transfer.event_store (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(50) NOT NULL,
+    version INT NOT NULL CHECK (version > 0),
+    event_type VARCHAR(50) NOT NULL,
+    schema_version INT NOT NULL,
+    payload JSONB NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (aggregate_type, aggregate_id, version)
+);
+
+-- +migrate StatementBegin
+CREATE FUNCTION money_transfer.event_store_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'event_store is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER event_store_append_only
+    BEFORE UPDATE OR DELETE ON money_transfer.event_store
+    FOR EACH ROW EXECUTE FUNCTION money_transfer.event_store_append_only();
+
+-- Start every existing transfer's stream with a snapshot of its current state
+INSERT INTO money_transfer.event_store (aggregate_type, aggregate_id, version, event_type, schema_version, payload)
+SELECT 'transfer', t.transfer_code, 1,
+       CASE
+           WHEN t.state = 'PENDING' AND d.payment_source = 'CARD' THEN 'transfer_initiated'
+           WHEN t.state = 'PENDING' THEN 'transfer_authorized'
+           WHEN t.state = 'FAILED' THEN 'transfer_failed'
+           WHEN t.state = 'VOIDED' THEN 'transfer_voided'
+           WHEN t.state = 'EXPIRED' THEN 'transfer_expired'
+           WHEN t.state = 'HELD' THEN 'transfer_held'
+           WHEN t.state = 'REJECTED' THEN 'transfer_rejected'
+           WHEN t.state IN ('PARTIALLY_REVERSED', 'REVERSED') THEN 'transfer_reversal_applied'
+           WHEN t.reversal_of IS NOT NULL THEN 'transfer_reversed'
+           ELSE 'transfer_completed'
+       END,
+       1,
+       jsonb_strip_nulls(jsonb_build_object(
+           'schema_version', 1,
+           'transfer_id', t.transfer_code,
+           'from_user_id', t.from_user_id::text,
+           'to_user_id', t.to_user_id::text,
+           'amount', t.amount,
+           'currency', t.currency,
+           'fee', t.fee,
+           'quote_id', t.quote_code,
+           'standing_order_id', t.standing_order_code,
+           'payment_source', COALESCE(d.payment_source, 'TRANSFER'),
+           'state', t.state,
+           'created_at', t.created_at,
+           'completed_at', t.completed_at,
+           'reversed_amount', NULLIF(t.reversed_amount, 0),
+           'reversal_of', t.reversal_of,
+           'response_code', CASE WHEN t.state = 'FAILED' THEN NULLIF(d.response_code, '') END
+       ))
+FROM money_transfer.transfers t
+LEFT JOIN money_transfer.transactions d ON d.id = t.debit_tx_id
+ORDER BY t.id;
+
+-- and every existing balance's stream with its current amount
+INSERT INTO money_transfer.event_store (aggregate_type, aggregate_id, version, event_type, schema_version, payload)
+SELECT 'user', b.user_id::text,
+       ROW_NUMBER() OVER (PARTITION BY b.user_id ORDER BY b.currency),
+       'balance_opened', 1,
+       jsonb_build_object(
+           'schema_version', 1,
+           'user_id', b.user_id::text,
+           'currency', b.currency,
+           'balance', b.amount,
+           'opened_at', b.created_at
+       )
+FROM money_transfer.balances b
+ORDER BY b.user_id, b.currency;
+
+-- Read models rebuilt from the event store by cmd/replay, to be compared
+-- with the live transfers and balances tables
+CREATE TABLE money_transfer.transfers_projection (
+    transfer_code VARCHAR(50) PRIMARY KEY,
+    from_user_id INT NOT NULL,
+    to_user_id INT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    fee BIGINT NOT NULL,
+    quote_code VARCHAR(50),
+    state VARCHAR(20) NOT NULL,
+    reversal_of VARCHAR(50),
+    reversed_amount BIGINT NOT NULL,
+    standing_order_code VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    version INT NOT NULL
+);
+
+CREATE TABLE money_transfer.balances_projection (
+    user_id INT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    version INT NOT NULL,
+    PRIMARY KEY (user_id, currency)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.balances_projection;
+DROP TABLE IF EXISTS money_transfer.transfers_projection;
+DROP TABLE IF EXISTS money_transfer.event_store;
+DROP FUNCTION IF EXISTS money_transfer.event_store_append_only();
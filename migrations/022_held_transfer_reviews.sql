@@ -0,0 +1,43 @@
+-- +migrate Up
+-- Batches, scheduled transfers and standing orders whose transfer risk
+-- screening held wait in PENDING_REVIEW until the review closes
+ALTER TABLE money_transfer.transfer_batches DROP CONSTRAINT IF EXISTS transfer_batches_status_check;
+ALTER TABLE money_transfer.transfer_batches ADD CONSTRAINT transfer_batches_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'PARTIALLY_COMPLETED', 'FAILED', 'PENDING_REVIEW'));
+
+ALTER TABLE money_transfer.transfer_batch_items DROP CONSTRAINT IF EXISTS transfer_batch_items_status_check;
+ALTER TABLE money_transfer.transfer_batch_items ADD CONSTRAINT transfer_batch_items_status_check
+    CHECK (status IN ('PENDING', 'COMPLETED', 'FAILED', 'ROLLED_BACK', 'HELD'));
+CREATE INDEX idx_transfer_batch_items_transfer ON money_transfer.transfer_batch_items(transfer_code);
+
+ALTER TABLE money_transfer.scheduled_transfers DROP CONSTRAINT IF EXISTS scheduled_transfers_status_check;
+ALTER TABLE money_transfer.scheduled_transfers ADD CONSTRAINT scheduled_transfers_status_check
+    CHECK (status IN ('SCHEDULED', 'PROCESSING', 'COMPLETED', 'FAILED', 'CANCELLED', 'PENDING_REVIEW'));
+CREATE INDEX idx_scheduled_transfers_transfer ON money_transfer.scheduled_transfers(transfer_code);
+
+ALTER TABLE money_transfer.standing_orders DROP CONSTRAINT IF EXISTS standing_orders_status_check;
+ALTER TABLE money_transfer.standing_orders ADD CONSTRAINT standing_orders_status_check
+    CHECK (status IN ('ACTIVE', 'PAUSED', 'COMPLETED', 'PENDING_REVIEW'));
+ALTER TABLE money_transfer.standing_orders ADD COLUMN held_transfer_code VARCHAR(50) REFERENCES money_transfer.transfers(transfer_code);
+CREATE INDEX idx_standing_orders_held_transfer ON money_transfer.standing_orders(held_transfer_code) WHERE held_transfer_code IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS money_transfer.idx_standing_orders_held_transfer;
+ALTER TABLE money_transfer.standing_orders DROP COLUMN IF EXISTS held_transfer_code;
+ALTER TABLE money_transfer.standing_orders DROP CONSTRAINT IF EXISTS standing_orders_status_check;
+ALTER TABLE money_transfer.standing_orders ADD CONSTRAINT standing_orders_status_check
+    CHECK (status IN ('ACTIVE', 'PAUSED', 'COMPLETED'));
+
+DROP INDEX IF EXISTS money_transfer.idx_scheduled_transfers_transfer;
+ALTER TABLE money_transfer.scheduled_transfers DROP CONSTRAINT IF EXISTS scheduled_transfers_status_check;
+ALTER TABLE money_transfer.scheduled_transfers ADD CONSTRAINT scheduled_transfers_status_check
+    CHECK (status IN ('SCHEDULED', 'PROCESSING', 'COMPLETED', 'FAILED', 'CANCELLED'));
+
+DROP INDEX IF EXISTS money_transfer.idx_transfer_batch_items_transfer;
+ALTER TABLE money_transfer.transfer_batch_items DROP CONSTRAINT IF EXISTS transfer_batch_items_status_check;
+ALTER TABLE money_transfer.transfer_batch_items ADD CONSTRAINT transfer_batch_items_status_check
+    CHECK (status IN ('PENDING', 'COMPLETED', 'FAILED', 'ROLLED_BACK'));
+
+ALTER TABLE money_transfer.transfer_batches DROP CONSTRAINT IF EXISTS transfer_batches_status_check;
+ALTER TABLE money_transfer.transfer_batches ADD CONSTRAINT transfer_batches_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'PARTIALLY_COMPLETED', 'FAILED'));
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Payment informations of pain.001 messages that were executed. Unlike the
+-- idempotency keys of their transfers these never expire, so a message
+-- resubmitted after IDEMPOTENCY_KEY_TTL is not paid twice.
+CREATE TABLE money_transfer.payment_initiations (
+    message_id TEXT NOT NULL,
+    payment_info_id TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, payment_info_id)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS money_transfer.payment_initiations;
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- The response first given to a request with the key, returned as is when
+-- the request is retried. Keys claimed before this migration have none and
+-- replay the transfer's current state.
+ALTER TABLE money_transfer.idempotency_keys
+    ADD COLUMN response_status INT,
+    ADD COLUMN response_body BYTEA;
+
+-- +migrate Down
+ALTER TABLE money_transfer.idempotency_keys
+    DROP COLUMN IF EXISTS response_body,
+    DROP COLUMN IF EXISTS response_status;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- STANs come from a sequence instead of a counter row per terminal and day,
+-- which every card payment locked until its transaction ended. The sequence
+-- never blocks and goes on from today's last STAN; numbers wrap after 999999.
+CREATE SEQUENCE money_transfer.stan_seq;
+
+SELECT setval('money_transfer.stan_seq', COALESCE(MAX(last_stan), 0) + 1, false)
+FROM money_transfer.stan_counters
+WHERE business_date = (NOW() AT TIME ZONE 'UTC')::date;
+
+DROP TABLE money_transfer.stan_counters;
+
+-- +migrate Down
+CREATE TABLE money_transfer.stan_counters (
+    terminal_id VARCHAR(50) NOT NULL,
+    business_date DATE NOT NULL,
+    last_stan INT NOT NULL CHECK (last_stan BETWEEN 1 AND 999999),
+    PRIMARY KEY (terminal_id, business_date)
+);
+DROP SEQUENCE IF EXISTS money_transfer.stan_seq;